@@ -0,0 +1,96 @@
+// Command keyring mengelola kunci penandatangan token PASETO.
+//
+//	go run ./cmd/keyring list
+//	go run ./cmd/keyring rotate [-activate-in 5m] [-grace 24h]
+//	go run ./cmd/keyring retire [-at 2026-01-02T15:04:05Z] <kid>
+//
+// Rotasi membuat pasangan kunci baru dengan watoken.GenerateKey. Kunci baru langsung diterima untuk
+// verifikasi, tetapi baru dipakai menandatangani setelah -activate-in agar semua instance sempat
+// memuat ulang key ring. Kunci lama dijadwalkan pensiun -grace setelah kunci baru aktif.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/keystore"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	db, err := config.LoadConfig().GetMongoDatabase()
+	if err != nil {
+		log.Fatalf("Database connection failed: %v", err)
+	}
+
+	switch os.Args[1] {
+	case "list":
+		keys, err := keystore.List(db)
+		if err != nil {
+			log.Fatalf("Failed to list keys: %v", err)
+		}
+		now := time.Now()
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "KID\tSTATUS\tSOURCE\tACTIVATES AT\tRETIRE AT")
+		for _, k := range keys {
+			retireAt := "-"
+			if k.RetireAt != nil {
+				retireAt = k.RetireAt.Format(time.RFC3339)
+			}
+			activatesAt := "-"
+			if !k.ActivatesAt.IsZero() {
+				activatesAt = k.ActivatesAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", k.KeyID, keystore.Status(k, now), k.Source, activatesAt, retireAt)
+		}
+		tw.Flush()
+
+	case "rotate":
+		fs := flag.NewFlagSet("rotate", flag.ExitOnError)
+		activateIn := fs.Duration("activate-in", 5*time.Minute, "delay before the new key signs tokens")
+		grace := fs.Duration("grace", 24*time.Hour, "how long the old key stays valid after the new key activates")
+		fs.Parse(os.Args[2:])
+
+		key, err := keystore.Rotate(db, *activateIn, *grace)
+		if err != nil {
+			log.Fatalf("Failed to rotate keys: %v", err)
+		}
+		fmt.Printf("New signing key %s activates at %s\n", key.KeyID, key.ActivatesAt.Format(time.RFC3339))
+		fmt.Printf("Previous key retires at %s\n", key.ActivatesAt.Add(*grace).Format(time.RFC3339))
+
+	case "retire":
+		fs := flag.NewFlagSet("retire", flag.ExitOnError)
+		at := fs.String("at", "", "retirement time in RFC 3339 (default: now)")
+		fs.Parse(os.Args[2:])
+		if fs.NArg() != 1 {
+			usage()
+		}
+
+		when := time.Now()
+		if *at != "" {
+			if when, err = time.Parse(time.RFC3339, *at); err != nil {
+				log.Fatalf("Invalid -at: %v", err)
+			}
+		}
+		if err := keystore.Retire(db, fs.Arg(0), when); err != nil {
+			log.Fatalf("Failed to retire key: %v", err)
+		}
+		fmt.Printf("Key %s retires at %s\n", fs.Arg(0), when.Format(time.RFC3339))
+
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: keyring list | rotate [-activate-in 5m] [-grace 24h] | retire [-at RFC3339] <kid>")
+	os.Exit(2)
+}
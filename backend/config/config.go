@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,7 +25,13 @@ type Config struct {
 	
 	// Google Cloud Platform Configuration
 	GCP           *GCPConfig `json:"gcp"`
+
+	// Object Storage Configuration
+	Storage       *StorageConfig `json:"storage"`
 	
+	// Mail Configuration
+	Mail          *MailConfig `json:"mail"`
+
 	// Server Configuration
 	Server        *ServerConfig `json:"server"`
 	
@@ -49,7 +56,16 @@ type AuthConfig struct {
 	PrivateKey      string `json:"private_key"`
 	PublicKey       string `json:"public_key"`
 	JWTSecret       string `json:"jwt_secret"`
-	TokenExpiration time.Duration `json:"token_expiration"`
+	TokenExpiration time.Duration `json:"token_expiration"` // masa berlaku access token
+	RefreshTokenExpiration time.Duration `json:"refresh_token_expiration"`
+	KeyEncryptionKey string `json:"-"` // mengenkripsi private key hasil rotasi di database
+
+	// Password hashing: "argon2id" (default) atau "bcrypt"
+	PasswordHash      string `json:"password_hash"`
+	Argon2Memory      uint32 `json:"argon2_memory"` // dalam KiB
+	Argon2Iterations  uint32 `json:"argon2_iterations"`
+	Argon2Parallelism uint8  `json:"argon2_parallelism"`
+	BcryptCost        int    `json:"bcrypt_cost"`
 }
 
 // GCPConfig konfigurasi untuk Google Cloud Platform
@@ -60,6 +76,43 @@ type GCPConfig struct {
 	ServiceAccount  string `json:"service_account"`
 }
 
+// StorageConfig konfigurasi penyimpanan objek (file upload, gambar, laporan)
+type StorageConfig struct {
+	// Backend adalah "gcs", "local", atau "s3" (S3-compatible, mis. MinIO)
+	Backend string `json:"backend"`
+
+	// Local filesystem
+	LocalPath  string `json:"local_path"`
+	PublicURL  string `json:"public_url"` // URL dasar endpoint /files untuk backend local
+	SigningKey string `json:"-"`          // kunci HMAC signed URL backend local
+
+	// S3-compatible
+	S3Endpoint  string `json:"s3_endpoint"`
+	S3Region    string `json:"s3_region"`
+	S3Bucket    string `json:"s3_bucket"`
+	S3AccessKey string `json:"-"`
+	S3SecretKey string `json:"-"`
+	S3PathStyle bool   `json:"s3_path_style"`
+	S3PublicURL string `json:"s3_public_url"` // opsional, mis. CDN di depan bucket
+}
+
+// MailConfig konfigurasi pengiriman email (verifikasi email dan reset password)
+type MailConfig struct {
+	// Driver adalah "smtp", "file" (menulis .eml ke Dir), atau "log"
+	Driver       string `json:"driver"`
+	From         string `json:"from"`
+	SMTPHost     string `json:"smtp_host"`
+	SMTPPort     string `json:"smtp_port"`
+	SMTPUsername string `json:"smtp_username"`
+	SMTPPassword string `json:"-"`
+	Dir          string `json:"dir"`
+
+	// AppURL adalah URL frontend untuk tautan di email
+	AppURL          string        `json:"app_url"`
+	VerificationTTL time.Duration `json:"verification_ttl"`
+	ResetTTL        time.Duration `json:"reset_ttl"`
+}
+
 // ServerConfig konfigurasi untuk server
 type ServerConfig struct {
 	Port             string        `json:"port"`
@@ -77,6 +130,8 @@ type AppConfig struct {
 	Debug            bool   `json:"debug"`
 	LogLevel         string `json:"log_level"`
 	AllowedOrigins   []string `json:"allowed_origins"`
+	AdminEmails      []string `json:"-"` // admin platform: mengelola semua institusi
+	AuditRetention   time.Duration `json:"audit_retention"` // audit log lebih tua dari ini dihapus
 }
 
 // Global configuration instance
@@ -129,7 +184,14 @@ func loadFromEnvironment() *Config {
 			PrivateKey:      getEnv("PRIVATEKEY", ""),
 			PublicKey:       getEnv("PUBLICKEY", ""),
 			JWTSecret:       getEnv("JWT_SECRET", "default-jwt-secret-change-in-production"),
-			TokenExpiration: 24 * time.Hour,
+			TokenExpiration: getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenExpiration: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+			KeyEncryptionKey: getEnv("KEY_ENCRYPTION_KEY", ""),
+			PasswordHash:      getEnv("PASSWORD_HASH", "argon2id"),
+			Argon2Memory:      uint32(getEnvInt("ARGON2_MEMORY", 64*1024)),
+			Argon2Iterations:  uint32(getEnvInt("ARGON2_ITERATIONS", 3)),
+			Argon2Parallelism: uint8(getEnvInt("ARGON2_PARALLELISM", 2)),
+			BcryptCost:        getEnvInt("BCRYPT_COST", 12),
 		},
 		GCP: &GCPConfig{
 			ProjectID:       getEnv("GCP_PROJECT_ID", ""),
@@ -137,6 +199,31 @@ func loadFromEnvironment() *Config {
 			VertexAIRegion:  getEnv("VERTEXAI_REGION", "asia-southeast1"),
 			ServiceAccount:  getEnv("GOOGLE_APPLICATION_CREDENTIALS", ""),
 		},
+		Storage: &StorageConfig{
+			Backend:     getEnv("STORAGE_BACKEND", ""),
+			LocalPath:   getEnv("STORAGE_LOCAL_PATH", "./data/storage"),
+			PublicURL:   getEnv("STORAGE_PUBLIC_URL", ""),
+			SigningKey:  getEnv("STORAGE_SIGNING_KEY", ""),
+			S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", ""),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle: getEnv("S3_PATH_STYLE", "true") != "false",
+			S3PublicURL: getEnv("S3_PUBLIC_URL", ""),
+		},
+		Mail: &MailConfig{
+			Driver:          getEnv("MAIL_DRIVER", ""),
+			From:            getEnv("MAIL_FROM", "Research Data Analysis <no-reply@localhost>"),
+			SMTPHost:        getEnv("SMTP_HOST", ""),
+			SMTPPort:        getEnv("SMTP_PORT", "587"),
+			SMTPUsername:    getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:    getEnv("SMTP_PASSWORD", ""),
+			Dir:             getEnv("MAIL_DIR", "./data/mail"),
+			AppURL:          getEnv("APP_URL", defaultOrigins[0]),
+			VerificationTTL: getEnvDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour),
+			ResetTTL:        getEnvDuration("PASSWORD_RESET_TTL", time.Hour),
+		},
 		Server: &ServerConfig{
 			Port:            getEnv("PORT", "8080"),
 			ReadTimeout:     30 * time.Second,
@@ -151,6 +238,8 @@ func loadFromEnvironment() *Config {
 			Debug:        !isProduction,
 			LogLevel:     getEnv("LOG_LEVEL", "info"),
 			AllowedOrigins: defaultOrigins,
+			AdminEmails:  getEnvList("ADMIN_EMAILS"),
+			AuditRetention: getEnvDuration("AUDIT_RETENTION", 365*24*time.Hour),
 		},
 		isProduction: isProduction,
 	}
@@ -166,6 +255,45 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt mendapat environment variable berupa angka; nilai tidak valid memakai default
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("WARNING: %s must be a number, using default %d", key, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// getEnvList mendapat environment variable berupa daftar dipisah koma, dalam huruf kecil
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.ToLower(strings.TrimSpace(item)); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvDuration mendapat environment variable berupa durasi (mis. "15m", "720h"); nilai tidak valid memakai default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("WARNING: %s must be a positive duration such as 15m or 720h, using default %s", key, defaultValue)
+		return defaultValue
+	}
+	return d
+}
+
 // getDefaultMongoString mendapat default MongoDB connection string
 func getDefaultMongoString(environment string) string {
 	if environment == "development" {
@@ -248,7 +376,81 @@ func (c *Config) Validate() error {
 	if c.Server.Port == "" {
 		c.Server.Port = "8080"
 	}
+
+	if c.Auth.KeyEncryptionKey == "" {
+		c.Auth.KeyEncryptionKey = c.Auth.JWTSecret
+	}
+
+	// Validasi masa berlaku token
+	if c.Auth.RefreshTokenExpiration < c.Auth.TokenExpiration {
+		return fmt.Errorf("REFRESH_TOKEN_TTL must not be shorter than ACCESS_TOKEN_TTL")
+	}
+
+	// Validasi password hashing
+	switch c.Auth.PasswordHash {
+	case "argon2id":
+		if c.Auth.Argon2Memory < 8*1024 || c.Auth.Argon2Iterations < 1 || c.Auth.Argon2Parallelism < 1 {
+			return fmt.Errorf("ARGON2_MEMORY must be at least 8192 KiB, ARGON2_ITERATIONS and ARGON2_PARALLELISM at least 1")
+		}
+	case "bcrypt":
+		if c.Auth.BcryptCost < 10 || c.Auth.BcryptCost > 31 {
+			return fmt.Errorf("BCRYPT_COST must be between 10 and 31, got: %d", c.Auth.BcryptCost)
+		}
+	default:
+		return fmt.Errorf("PASSWORD_HASH must be argon2id or bcrypt, got: %s", c.Auth.PasswordHash)
+	}
+
+	// Validasi Storage: default GCS jika bucket diisi, selain itu filesystem lokal
+	if c.Storage.Backend == "" {
+		if c.GCP.GCSBucket != "" {
+			c.Storage.Backend = "gcs"
+		} else {
+			log.Printf("WARNING: STORAGE_BACKEND and GCS_BUCKET not set, storing files in %s", c.Storage.LocalPath)
+			c.Storage.Backend = "local"
+		}
+	}
+	if c.Storage.PublicURL == "" {
+		c.Storage.PublicURL = "http://localhost:" + c.Server.Port + "/files"
+	}
+	if c.Storage.SigningKey == "" {
+		c.Storage.SigningKey = c.Auth.JWTSecret
+	}
+	switch c.Storage.Backend {
+	case "gcs":
+		if c.GCP.GCSBucket == "" {
+			log.Printf("WARNING: STORAGE_BACKEND is gcs but GCS_BUCKET is not set")
+		}
+	case "s3":
+		if c.Storage.S3Endpoint == "" || c.Storage.S3Bucket == "" {
+			log.Printf("WARNING: STORAGE_BACKEND is s3 but S3_ENDPOINT or S3_BUCKET is not set")
+		}
+	case "local":
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be gcs, local, or s3, got: %s", c.Storage.Backend)
+	}
 	
+	// Validasi Mail: default SMTP jika host diisi, selain itu hanya dicatat di log
+	if c.Mail.Driver == "" {
+		if c.Mail.SMTPHost != "" {
+			c.Mail.Driver = "smtp"
+		} else {
+			if c.isProduction {
+				log.Printf("WARNING: SMTP_HOST not set in production, emails will not be sent (only recipient and subject are logged)")
+			}
+			c.Mail.Driver = "log"
+		}
+	}
+	switch c.Mail.Driver {
+	case "smtp":
+		if c.Mail.SMTPHost == "" {
+			return fmt.Errorf("MAIL_DRIVER is smtp but SMTP_HOST is not set")
+		}
+	case "file", "log":
+	default:
+		return fmt.Errorf("MAIL_DRIVER must be smtp, file, or log, got: %s", c.Mail.Driver)
+	}
+	c.Mail.AppURL = strings.TrimSuffix(c.Mail.AppURL, "/")
+
 	// Validasi Port format
 	if _, err := strconv.Atoi(c.Server.Port); err != nil {
 		return fmt.Errorf("PORT must be a valid number, got: %s", c.Server.Port)
@@ -0,0 +1,319 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/mail"
+	"github.com/research-data-analysis/helper/password"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Tujuan token sekali pakai
+const (
+	tokenVerifyEmail   = "verify_email"
+	tokenResetPassword = "reset_password"
+)
+
+// minPasswordLength sama dengan validasi form registrasi di frontend
+const minPasswordLength = 8
+
+// VerifyEmail handler untuk mengonfirmasi email dengan token dari tautan verifikasi
+func VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req model.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Token is required",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	token, err := consumeUserToken(mongoDB, req.Token, tokenVerifyEmail)
+	if err != nil {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Email bisa sudah diganti setelah tautan dikirim
+	now := time.Now()
+	result, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": token.UserID, "email": token.Email}, bson.M{
+		"email_verified":    true,
+		"email_verified_at": now,
+		"updated_at":        now,
+	})
+	if err != nil || result.MatchedCount == 0 {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid or expired token",
+		})
+		return
+	}
+	joinInstitutionByDomain(mongoDB, token.UserID, token.Email)
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Email verified successfully",
+	})
+}
+
+// ResendVerification handler untuk mengirim ulang email verifikasi ke pengguna yang login
+func ResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		Response(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+	if user.EmailVerified {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Email already verified",
+		})
+		return
+	}
+
+	if err := sendAccountEmail(r.Context(), mongoDB, user, tokenVerifyEmail, user.Language); err != nil {
+		log.Printf("WARNING: Failed to send verification email to user %s: %v", user.ID.Hex(), err)
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to send verification email",
+		})
+		return
+	}
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Verification email sent",
+	})
+}
+
+// ForgotPassword handler untuk mengirim tautan reset password.
+// Respons selalu sama agar tidak membocorkan email yang terdaftar.
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req model.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Email) == "" {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Email is required",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"email": strings.TrimSpace(req.Email)})
+	if err == nil && user.Email != "" {
+		lang := i18n.Resolve(req.Language, user.Language)
+		if err := sendAccountEmail(r.Context(), mongoDB, user, tokenResetPassword, lang); err != nil {
+			log.Printf("WARNING: Failed to send password reset email to user %s: %v", user.ID.Hex(), err)
+		}
+	}
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword handler untuk mengganti password dengan token reset; semua sesi lama dicabut
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req model.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Token is required",
+		})
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Password must be at least 8 characters",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	hashed, err := password.Hash(req.Password, password.FromConfig(config.GetConfig().Auth))
+	if err != nil {
+		log.Printf("WARNING: Failed to hash password: %v", err)
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to reset password",
+		})
+		return
+	}
+
+	token, err := consumeUserToken(mongoDB, req.Token, tokenResetPassword)
+	if err != nil {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Tautan reset membuktikan kepemilikan email, sehingga email ikut terverifikasi
+	now := time.Now()
+	result, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": token.UserID, "email": token.Email}, bson.M{
+		"password":          hashed,
+		"email_verified":    true,
+		"email_verified_at": now,
+		"updated_at":        now,
+	})
+	if err != nil || result.MatchedCount == 0 {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid or expired token",
+		})
+		return
+	}
+
+	if _, err := atdb.UpdateManyDoc(mongoDB, "sessions", bson.M{"user_id": token.UserID, "revoked_at": nil}, bson.M{"revoked_at": now}); err != nil {
+		log.Printf("WARNING: Failed to revoke sessions after password reset for user %s: %v", token.UserID.Hex(), err)
+	}
+	// API key ikut dicabut agar pengambil alih akun tidak tetap punya akses lewat key yang dibuatnya
+	details := ""
+	if result, err := atdb.UpdateManyDoc(mongoDB, "api_keys", bson.M{"user_id": token.UserID, "revoked_at": nil}, bson.M{"revoked_at": now}); err != nil {
+		log.Printf("WARNING: Failed to revoke API keys after password reset for user %s: %v", token.UserID.Hex(), err)
+	} else if result.ModifiedCount > 0 {
+		details = fmt.Sprintf("%d API keys revoked", result.ModifiedCount)
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: token.UserID, Action: auditPasswordReset, Resource: "user", ResourceID: token.UserID, Details: details})
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Password reset successfully, please log in again and create new API keys if needed",
+	})
+}
+
+// sendAccountEmail membuat token sekali pakai lalu mengirim email verifikasi atau reset password
+func sendAccountEmail(ctx context.Context, mongoDB *mongo.Database, user model.User, purpose, lang string) error {
+	if _, err := netmail.ParseAddress(user.Email); err != nil {
+		return err
+	}
+
+	cfg := config.GetConfig().Mail
+	kind, page, ttl := mail.KindVerifyEmail, "verify-email", cfg.VerificationTTL
+	if purpose == tokenResetPassword {
+		kind, page, ttl = mail.KindResetPassword, "reset-password", cfg.ResetTTL
+	}
+
+	raw, err := issueUserToken(mongoDB, user, purpose, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := cfg.AppURL + "/#" + page + "?token=" + url.QueryEscape(raw)
+	msg, err := mail.Render(kind, i18n.Resolve(lang), user.Email, mail.TemplateData{
+		Name:      user.FullName,
+		Link:      link,
+		ExpiresIn: ttl,
+	})
+	if err != nil {
+		return err
+	}
+	return mail.Send(ctx, msg)
+}
+
+// issueUserToken membuat token sekali pakai dan membatalkan token lama dengan tujuan yang sama
+func issueUserToken(mongoDB *mongo.Database, user model.User, purpose string, ttl time.Duration) (string, error) {
+	raw, err := newSecretToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if _, err := atdb.UpdateManyDoc(mongoDB, "user_tokens", bson.M{"user_id": user.ID, "purpose": purpose, "used_at": nil}, bson.M{"used_at": now}); err != nil {
+		return "", err
+	}
+	_, err = atdb.InsertOneDoc(mongoDB, "user_tokens", model.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: hashSecretToken(raw),
+		Email:     user.Email,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// consumeUserToken memvalidasi token lalu menandainya terpakai; filter used_at mencegah pemakaian ganda
+func consumeUserToken(mongoDB *mongo.Database, raw, purpose string) (model.UserToken, error) {
+	invalid := errors.New("Invalid or expired token")
+
+	token, err := atdb.GetOneDoc[model.UserToken](mongoDB, "user_tokens", bson.M{"token_hash": hashSecretToken(raw), "purpose": purpose})
+	if err != nil || token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return token, invalid
+	}
+
+	result, err := atdb.UpdateOneDoc(mongoDB, "user_tokens", bson.M{"_id": token.ID, "used_at": nil}, bson.M{"used_at": time.Now()})
+	if err != nil || result.MatchedCount == 0 {
+		return token, invalid
+	}
+	return token, nil
+}
@@ -0,0 +1,840 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/report"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Batas jumlah item feed aktivitas institusi
+const (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 200
+)
+
+// GetInstitutions handler untuk daftar institusi: semua bagi admin platform, institusinya sendiri bagi admin institusi
+func GetInstitutions(w http.ResponseWriter, r *http.Request) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	filter := bson.M{"_id": user.InstitutionID}
+	if isPlatformAdmin(user) {
+		filter = bson.M{}
+	} else if user.InstitutionID.IsZero() || user.InstitutionRole != institutionAdmin {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Admin access required",
+		})
+		return
+	}
+
+	institutions, err := atdb.GetAllDocWithSort[model.Institution](mongoDB, "institutions", filter, bson.D{{Key: "name", Value: 1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve institutions",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institutions retrieved successfully",
+		Data:    institutions,
+	})
+}
+
+// CreateInstitution handler untuk membuat institusi baru (khusus admin platform), opsional dengan admin pertamanya
+func CreateInstitution(w http.ResponseWriter, r *http.Request) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	if !isPlatformAdmin(user) {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Platform admin access required",
+		})
+		return
+	}
+
+	var req model.InstitutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Institution name is required",
+		})
+		return
+	}
+
+	now := time.Now()
+	institution := model.Institution{
+		Name:      req.Name,
+		CreatedBy: user.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyInstitutionRequest(mongoDB, &institution, req, true); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Admin pertama harus sudah terdaftar dan belum menjadi anggota institusi lain
+	var admin model.User
+	if req.AdminEmail != "" {
+		var err error
+		admin, err = atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"email": normalizeEmail(req.AdminEmail)})
+		if err != nil {
+			at.WriteJSON(w, http.StatusNotFound, model.Response{
+				Status:  "error",
+				Message: "Admin user not found",
+			})
+			return
+		}
+		if !admin.InstitutionID.IsZero() {
+			at.WriteJSON(w, http.StatusConflict, model.Response{
+				Status:  "error",
+				Message: "Admin user already belongs to an institution",
+			})
+			return
+		}
+	}
+
+	institutionID, err := atdb.InsertOneDoc(mongoDB, "institutions", institution)
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to create institution",
+		})
+		return
+	}
+	institution.ID = institutionID
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditInstitutionCreate, Resource: "institution", ResourceID: institutionID, Details: institution.Name})
+
+	if !admin.ID.IsZero() {
+		if err := setInstitutionMembership(mongoDB, admin.ID, institution, institutionAdmin, true); err != nil {
+			log.Printf("WARNING: Failed to assign admin %s to institution %s: %v", admin.ID.Hex(), institutionID.Hex(), err)
+		}
+	}
+
+	at.WriteJSON(w, http.StatusCreated, model.Response{
+		Status:  "success",
+		Message: "Institution created successfully",
+		Data:    institution,
+	})
+}
+
+// GetInstitution handler untuk detail institusi
+func GetInstitution(w http.ResponseWriter, r *http.Request, institutionIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution retrieved successfully",
+		Data:    institution,
+	})
+}
+
+// UpdateInstitution handler untuk mengubah kuota dan pengaturan bawaan institusi.
+// Nama dan domain email hanya dapat diubah admin platform.
+func UpdateInstitution(w http.ResponseWriter, r *http.Request, institutionIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	var req model.InstitutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	platform := isPlatformAdmin(user)
+	if !platform && (req.Name != "" || req.Domains != nil) {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Only platform admins can change the institution name or email domains",
+		})
+		return
+	}
+
+	renamed := false
+	if name := strings.TrimSpace(req.Name); name != "" && name != institution.Name {
+		institution.Name = name
+		renamed = true
+	}
+	if err := applyInstitutionRequest(mongoDB, &institution, req, platform); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	institution.UpdatedAt = time.Now()
+
+	if _, err := atdb.ReplaceOneDoc(mongoDB, "institutions", bson.M{"_id": institution.ID}, institution); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to update institution",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditInstitutionUpdate, Resource: "institution", ResourceID: institution.ID, Details: institution.Name})
+	// Nama institusi di profil anggota mengikuti nama tenant
+	if renamed {
+		if _, err := atdb.UpdateManyDoc(mongoDB, "users", bson.M{"institution_id": institution.ID}, bson.M{"institution": institution.Name}); err != nil {
+			log.Printf("WARNING: Failed to rename institution on member profiles of %s: %v", institution.ID.Hex(), err)
+		}
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution updated successfully",
+		Data:    institution,
+	})
+}
+
+// DeleteInstitution handler untuk menghapus institusi (khusus admin platform); anggotanya menjadi pengguna tanpa institusi
+func DeleteInstitution(w http.ResponseWriter, r *http.Request, institutionIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	if !isPlatformAdmin(user) {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Platform admin access required",
+		})
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	if _, err := atdb.DeleteOneDoc(mongoDB, "institutions", bson.M{"_id": institution.ID}); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to delete institution",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditInstitutionDelete, Resource: "institution", ResourceID: institution.ID, Details: institution.Name})
+	if _, err := atdb.UpdateManyDoc(mongoDB, "users", bson.M{"institution_id": institution.ID}, bson.M{
+		"institution_id":   nil,
+		"institution_role": nil,
+		"updated_at":       time.Now(),
+	}); err != nil {
+		log.Printf("WARNING: Failed to detach members of institution %s: %v", institution.ID.Hex(), err)
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution deleted successfully",
+	})
+}
+
+// GetInstitutionUsers handler untuk daftar anggota institusi beserta pemakaian kuotanya
+func GetInstitutionUsers(w http.ResponseWriter, r *http.Request, institutionIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	users, err := atdb.GetAllDocWithSort[model.User](mongoDB, "users", bson.M{"institution_id": institution.ID}, bson.D{{Key: "full_name", Value: 1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve institution users",
+		})
+		return
+	}
+
+	list := make([]map[string]interface{}, 0, len(users))
+	for _, u := range users {
+		list = append(list, map[string]interface{}{
+			"_id":            u.ID,
+			"email":          u.Email,
+			"full_name":      u.FullName,
+			"role":           u.InstitutionRole,
+			"email_verified": u.EmailVerified,
+			"created_at":     u.CreatedAt,
+			"usage":          memberUsage(mongoDB, u.ID),
+		})
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution users retrieved successfully",
+		Data:    list,
+	})
+}
+
+// AddInstitutionUser handler untuk menambahkan pengguna terdaftar ke institusi berdasarkan email
+func AddInstitutionUser(w http.ResponseWriter, r *http.Request, institutionIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	var req model.InstitutionMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	if req.Role == "" {
+		req.Role = institutionMember
+	}
+	if !validInstitutionRole(req.Role) {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Role must be admin or member",
+		})
+		return
+	}
+
+	member, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"email": normalizeEmail(req.Email)})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+	if !member.InstitutionID.IsZero() {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "User already belongs to an institution",
+		})
+		return
+	}
+
+	if err := setInstitutionMembership(mongoDB, member.ID, institution, req.Role, true); err != nil {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "User already belongs to an institution",
+		})
+		return
+	}
+
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditInstitutionUserAdd, Resource: "institution", ResourceID: institution.ID, Details: member.Email + " as " + req.Role})
+
+	at.WriteJSON(w, http.StatusCreated, model.Response{
+		Status:  "success",
+		Message: "User added to institution",
+		Data: map[string]interface{}{
+			"_id":       member.ID,
+			"email":     member.Email,
+			"full_name": member.FullName,
+			"role":      req.Role,
+		},
+	})
+}
+
+// UpdateInstitutionUser handler untuk mengubah peran anggota institusi (admin atau member)
+func UpdateInstitutionUser(w http.ResponseWriter, r *http.Request, institutionIDStr, memberIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	var req model.InstitutionMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !validInstitutionRole(req.Role) {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Role must be admin or member",
+		})
+		return
+	}
+
+	member, ok := requireInstitutionUser(w, mongoDB, institution, memberIDStr)
+	if !ok {
+		return
+	}
+	if member.InstitutionRole == institutionAdmin && req.Role != institutionAdmin && lastInstitutionAdmin(mongoDB, institution.ID) {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "An institution must keep at least one admin",
+		})
+		return
+	}
+
+	if err := setInstitutionMembership(mongoDB, member.ID, institution, req.Role, false); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to update institution user",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditInstitutionUserUpdate, Resource: "institution", ResourceID: institution.ID, Details: member.Email + " as " + req.Role})
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution user updated successfully",
+		Data: map[string]interface{}{
+			"_id":  member.ID,
+			"role": req.Role,
+		},
+	})
+}
+
+// RemoveInstitutionUser handler untuk mengeluarkan anggota dari institusi; project miliknya tidak terhapus
+func RemoveInstitutionUser(w http.ResponseWriter, r *http.Request, institutionIDStr, memberIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	member, ok := requireInstitutionUser(w, mongoDB, institution, memberIDStr)
+	if !ok {
+		return
+	}
+	if member.InstitutionRole == institutionAdmin && lastInstitutionAdmin(mongoDB, institution.ID) {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "An institution must keep at least one admin",
+		})
+		return
+	}
+
+	if _, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": member.ID, "institution_id": institution.ID}, bson.M{
+		"institution_id":   nil,
+		"institution_role": nil,
+		"updated_at":       time.Now(),
+	}); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to remove institution user",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditInstitutionUserRemove, Resource: "institution", ResourceID: institution.ID, Details: member.Email})
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "User removed from institution",
+	})
+}
+
+// GetInstitutionStats handler untuk statistik agregat institusi: anggota, project, data, analysis, dan metode terpopuler
+func GetInstitutionStats(w http.ResponseWriter, r *http.Request, institutionIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	users, err := atdb.GetAllDoc[model.User](mongoDB, "users", bson.M{"institution_id": institution.ID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve institution statistics",
+		})
+		return
+	}
+	memberIDs := make([]primitive.ObjectID, 0, len(users))
+	admins, verified := 0, 0
+	for _, u := range users {
+		memberIDs = append(memberIDs, u.ID)
+		if u.InstitutionRole == institutionAdmin {
+			admins++
+		}
+		if u.EmailVerified {
+			verified++
+		}
+	}
+
+	projects, _ := atdb.GetAllDoc[model.Project](mongoDB, "projects", bson.M{"user_id": bson.M{"$in": memberIDs}})
+	projectIDs := make([]primitive.ObjectID, 0, len(projects))
+	for _, p := range projects {
+		projectIDs = append(projectIDs, p.ID)
+	}
+	uploads, _ := atdb.GetAllDoc[model.Upload](mongoDB, "uploads", bson.M{"project_id": bson.M{"$in": projectIDs}})
+	var storageBytes int64
+	for _, u := range uploads {
+		storageBytes += u.FileSize
+	}
+
+	analyses, _ := atdb.GetAllDoc[model.Analysis](mongoDB, "analyses", bson.M{"project_id": bson.M{"$in": projectIDs}})
+	start := monthStart(time.Now())
+	thisMonth := 0
+	byStatus := map[string]int{}
+	byReview := map[string]int{}
+	methodCounts := map[string]int{}
+	for _, a := range analyses {
+		if !a.CreatedAt.Before(start) {
+			thisMonth++
+		}
+		byStatus[a.Status]++
+		review := a.ReviewStatus
+		if review == "" {
+			review = reviewDraft
+		}
+		byReview[review]++
+		for _, result := range a.Results {
+			if result.Error == "" {
+				methodCounts[result.Method]++
+			}
+		}
+	}
+
+	type methodCount struct {
+		Method string `json:"method"`
+		Count  int    `json:"count"`
+	}
+	topMethods := make([]methodCount, 0, len(methodCounts))
+	for method, count := range methodCounts {
+		topMethods = append(topMethods, methodCount{method, count})
+	}
+	sort.Slice(topMethods, func(i, j int) bool {
+		if topMethods[i].Count != topMethods[j].Count {
+			return topMethods[i].Count > topMethods[j].Count
+		}
+		return topMethods[i].Method < topMethods[j].Method
+	})
+	if len(topMethods) > 10 {
+		topMethods = topMethods[:10]
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution statistics retrieved successfully",
+		Data: map[string]interface{}{
+			"members": map[string]int{
+				"total":    len(users),
+				"admins":   admins,
+				"verified": verified,
+			},
+			"projects":      len(projects),
+			"uploads":       len(uploads),
+			"storage_bytes": storageBytes,
+			"analyses": map[string]interface{}{
+				"total":      len(analyses),
+				"this_month": thisMonth,
+				"by_status":  byStatus,
+				"by_review":  byReview,
+			},
+			"top_methods": topMethods,
+		},
+	})
+}
+
+// GetInstitutionActivity handler untuk aktivitas terbaru seluruh anggota institusi: project, upload, dan analysis
+// baru, terbaru lebih dulu. Parameter ?limit (maks 200) dan ?before (RFC3339) untuk halaman berikutnya.
+func GetInstitutionActivity(w http.ResponseWriter, r *http.Request, institutionIDStr string) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+	institution, ok := requireInstitutionAdmin(w, mongoDB, user, institutionIDStr)
+	if !ok {
+		return
+	}
+
+	limit := defaultActivityLimit
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		limit = min(n, maxActivityLimit)
+	}
+	before := time.Now()
+	if raw := r.URL.Query().Get("before"); raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "before must be an RFC3339 timestamp",
+			})
+			return
+		}
+		before = t
+	}
+
+	users, _ := atdb.GetAllDoc[model.User](mongoDB, "users", bson.M{"institution_id": institution.ID})
+	names := make(map[primitive.ObjectID]string, len(users))
+	memberIDs := make([]primitive.ObjectID, 0, len(users))
+	for _, u := range users {
+		names[u.ID] = u.FullName
+		memberIDs = append(memberIDs, u.ID)
+	}
+	projects, _ := atdb.GetAllDoc[model.Project](mongoDB, "projects", bson.M{"user_id": bson.M{"$in": memberIDs}})
+	byID := make(map[primitive.ObjectID]model.Project, len(projects))
+	projectIDs := make([]primitive.ObjectID, 0, len(projects))
+	for _, p := range projects {
+		byID[p.ID] = p
+		projectIDs = append(projectIDs, p.ID)
+	}
+
+	type activity struct {
+		Type         string             `json:"type"`
+		UserID       primitive.ObjectID `json:"user_id"`
+		UserName     string             `json:"user_name"`
+		ProjectID    primitive.ObjectID `json:"project_id"`
+		ProjectTitle string             `json:"project_title"`
+		ResourceID   primitive.ObjectID `json:"resource_id"`
+		Detail       string             `json:"detail,omitempty"`
+		CreatedAt    time.Time          `json:"created_at"`
+	}
+	items := []activity{}
+	add := func(kind string, projectID, resourceID primitive.ObjectID, detail string, createdAt time.Time) {
+		if !createdAt.Before(before) {
+			return
+		}
+		p := byID[projectID]
+		items = append(items, activity{kind, p.UserID, names[p.UserID], projectID, p.Title, resourceID, detail, createdAt})
+	}
+
+	for _, p := range projects {
+		add("project_created", p.ID, p.ID, p.ResearchType, p.CreatedAt)
+	}
+	window := bson.M{"$lt": before}
+	uploads, _ := atdb.GetAllDocWithSort[model.Upload](mongoDB, "uploads", bson.M{"project_id": bson.M{"$in": projectIDs}, "uploaded_at": window}, bson.D{{Key: "uploaded_at", Value: -1}})
+	for _, u := range uploads {
+		add("upload_created", u.ProjectID, u.ID, u.FileName, u.UploadedAt)
+	}
+	analyses, _ := atdb.GetAllDocWithSort[model.Analysis](mongoDB, "analyses", bson.M{"project_id": bson.M{"$in": projectIDs}, "created_at": window}, bson.D{{Key: "created_at", Value: -1}})
+	for _, a := range analyses {
+		add("analysis_created", a.ProjectID, a.ID, a.Status, a.CreatedAt)
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].CreatedAt.After(items[j].CreatedAt) })
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution activity retrieved successfully",
+		Data:    items,
+	})
+}
+
+// requireCurrentUser memuat pengguna yang login dan menulis respons error jika gagal
+func requireCurrentUser(w http.ResponseWriter, r *http.Request) (*mongo.Database, model.User, bool) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return nil, model.User{}, false
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return nil, model.User{}, false
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return nil, model.User{}, false
+	}
+	return mongoDB, user, true
+}
+
+// requireInstitutionAdmin memuat institusi dan memastikan pengguna adalah admin institusi tersebut atau admin platform.
+// Pengguna di luar institusi mendapat 404 agar keberadaan institusi tidak bocor.
+func requireInstitutionAdmin(w http.ResponseWriter, mongoDB *mongo.Database, user model.User, institutionIDStr string) (model.Institution, bool) {
+	institutionID, err := primitive.ObjectIDFromHex(institutionIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid institution ID",
+		})
+		return model.Institution{}, false
+	}
+
+	institution, err := atdb.GetOneDoc[model.Institution](mongoDB, "institutions", bson.M{"_id": institutionID})
+	if err != nil || (!isPlatformAdmin(user) && user.InstitutionID != institutionID) {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Institution not found",
+		})
+		return model.Institution{}, false
+	}
+	if !isPlatformAdmin(user) && user.InstitutionRole != institutionAdmin {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Admin access required",
+		})
+		return model.Institution{}, false
+	}
+	return institution, true
+}
+
+// requireInstitutionUser memuat anggota institusi berdasarkan ID dan menulis respons error jika tidak ditemukan
+func requireInstitutionUser(w http.ResponseWriter, mongoDB *mongo.Database, institution model.Institution, memberIDStr string) (model.User, bool) {
+	memberID, err := primitive.ObjectIDFromHex(memberIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid user ID",
+		})
+		return model.User{}, false
+	}
+	member, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": memberID, "institution_id": institution.ID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Institution user not found",
+		})
+		return model.User{}, false
+	}
+	return member, true
+}
+
+// setInstitutionMembership menetapkan institusi dan peran pengguna. Jika onlyUnassigned,
+// pengguna yang sudah menjadi anggota institusi lain tidak ditimpa.
+func setInstitutionMembership(mongoDB *mongo.Database, userID primitive.ObjectID, institution model.Institution, role string, onlyUnassigned bool) error {
+	filter := bson.M{"_id": userID, "institution_id": institution.ID}
+	if onlyUnassigned {
+		filter["institution_id"] = nil
+	}
+	result, err := atdb.UpdateOneDoc(mongoDB, "users", filter, bson.M{
+		"institution_id":   institution.ID,
+		"institution_role": role,
+		"institution":      institution.Name,
+		"updated_at":       time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("user membership changed")
+	}
+	return nil
+}
+
+// lastInstitutionAdmin mengecek apakah institusi hanya memiliki satu admin
+func lastInstitutionAdmin(mongoDB *mongo.Database, institutionID primitive.ObjectID) bool {
+	admins, _ := atdb.CountDoc(mongoDB, "users", bson.M{"institution_id": institutionID, "institution_role": institutionAdmin})
+	return admins <= 1
+}
+
+// validInstitutionRole mengecek peran anggota institusi
+func validInstitutionRole(role string) bool {
+	return role == institutionAdmin || role == institutionMember
+}
+
+// applyInstitutionRequest memvalidasi lalu menerapkan domain (jika allowDomains), kuota, dan pengaturan bawaan ke institution
+func applyInstitutionRequest(mongoDB *mongo.Database, institution *model.Institution, req model.InstitutionRequest, allowDomains bool) error {
+	if allowDomains && req.Domains != nil {
+		domains := []string{}
+		for _, d := range req.Domains {
+			d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "@")
+			if d == "" {
+				continue
+			}
+			if strings.ContainsAny(d, "@ /") || !strings.Contains(d, ".") {
+				return errors.New("Invalid email domain: " + d)
+			}
+			// Satu domain hanya boleh dimiliki satu institusi
+			if other, err := atdb.GetOneDoc[model.Institution](mongoDB, "institutions", bson.M{"domains": d}); err == nil && other.ID != institution.ID {
+				return errors.New("Email domain " + d + " is already used by another institution")
+			}
+			if !containsString(domains, d) {
+				domains = append(domains, d)
+			}
+		}
+		institution.Domains = domains
+	}
+
+	if req.Quota != nil {
+		if req.Quota.MaxProjects < 0 || req.Quota.MaxStorageBytes < 0 || req.Quota.MaxAnalysesPerMonth < 0 {
+			return errors.New("Quota values must be zero (unlimited) or positive")
+		}
+		institution.Quota = *req.Quota
+	}
+
+	if req.Defaults != nil {
+		defaults := *req.Defaults
+		if defaults.Language != "" {
+			defaults.Language = i18n.Normalize(defaults.Language)
+			if defaults.Language == "" {
+				return errors.New("Unsupported language. Supported: id, en")
+			}
+		}
+		if defaults.Alpha < 0 || defaults.Alpha >= 1 {
+			return errors.New("Significance level must be between 0 and 1")
+		}
+		if len(defaults.ReportSections) > 0 {
+			if _, err := report.ParseSections(defaults.ReportSections); err != nil {
+				return err
+			}
+		}
+		institution.Defaults = defaults
+	}
+
+	if req.Require2FA != nil {
+		institution.Require2FA = *req.Require2FA
+	}
+	return nil
+}
@@ -1,19 +1,33 @@
 package controller
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/research-data-analysis/helper/at"
 	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/chart"
+	"github.com/research-data-analysis/helper/dataset"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/report"
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/helper/storage"
 	"github.com/research-data-analysis/helper/vertexai"
 	"github.com/research-data-analysis/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// maxRecommendationAttempts adalah jumlah percobaan maksimal (termasuk prompt korektif) untuk rekomendasi AI
+const maxRecommendationAttempts = 3
+
 // GetRecommendations handler untuk mendapat rekomendasi berdasarkan mode analysis
 func GetRecommendations(w http.ResponseWriter, r *http.Request, projectIDStr string) {
 	userID, err := getUserIDFromToken(r)
@@ -44,27 +58,28 @@ func GetRecommendations(w http.ResponseWriter, r *http.Request, projectIDStr str
 		return
 	}
 
-	project, err := atdb.GetOneDoc[model.Project](
-		mongoDB,
-		"projects",
-		bson.M{"_id": projectID, "user_id": userID},
-	)
-	if err != nil {
-		at.WriteJSON(w, http.StatusNotFound, model.Response{
+	// Rekomendasi membuat analysis baru, sehingga minimal editor
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleEditor)
+	if !ok {
+		return
+	}
+	if err := checkQuota(mongoDB, project.UserID, quotaAnalyses, 0); err != nil {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
 			Status:  "error",
-			Message: "Project not found or unauthorized",
+			Message: err.Error(),
 		})
 		return
 	}
 
 	var req model.RecommendRequest
 	json.NewDecoder(r.Body).Decode(&req)
+	lang := requestLanguage(r, mongoDB, userID, req.Language)
 
 	// Ambil upload data jika ada
 	var uploadData model.Upload
 	if req.UploadID != "" {
 		uploadID, _ := primitive.ObjectIDFromHex(req.UploadID)
-		uploadData, _ = atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": uploadID})
+		uploadData, _ = atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": uploadID, "project_id": projectID})
 	} else {
 		// Ambil upload terbaik dari project
 		uploads, _ := atdb.GetAllDocWithSort[model.Upload](
@@ -78,45 +93,47 @@ func GetRecommendations(w http.ResponseWriter, r *http.Request, projectIDStr str
 		}
 	}
 
-	// Prepare context untuk rekomendasi
-	context := fmt.Sprintf("Project: %s\nDescription: %s\nUpload Data: %s",
-		project.Title, project.Description, uploadData.FileName)
+	// Rekomendasi berbasis aturan dari skala pengukuran, jumlah kelompok, dan peran variabel
+	var ruleRecommendations []model.Recommendation
+	if uploadData.DataSummary.Rows > 0 {
+		ruleRecommendations = stats.Recommend(project, uploadData.DataSummary, lang)
+	}
 
-	// Generate rekomendasi menggunakan Vertex AI
-	recommendations, err := vertexai.GenerateResearchRecommendations(context)
-	if err != nil {
-		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
-			Status:  "error",
-			Message: "Failed to generate recommendations",
-		})
-		return
+	// Prepare context untuk rekomendasi, di-grounding dengan profil data dan rekomendasi berbasis aturan
+	context := fmt.Sprintf("Project: %s\nDescription: %s\nResearch Type: %s\nHypothesis: %s\nUpload Data: %s",
+		project.Title, project.Description, project.ResearchType, project.Hypothesis, uploadData.FileName)
+	if uploadData.DataSummary.Rows > 0 {
+		context += "\n\n" + stats.DescribeDataContext(project, uploadData.DataSummary, ruleRecommendations, lang)
 	}
 
-	// Parse recommendations JSON menjadi array Recommendation
-	var parsedRecommendations []model.Recommendation
-	// Simplified - untuk production perlu proper JSON parsing
-	parsedRecommendations = []model.Recommendation{
-		{
-			Method:      "Descriptive Statistics",
-			Category:    "descriptive",
-			Reasoning:   "Basic statistical analysis of data",
-			Priority:    1,
-			Assumptions: "Data should be normally distributed",
-		},
+	// Generate rekomendasi menggunakan Vertex AI, di-parse dan divalidasi terhadap katalog engine
+	source := stats.SourceAI
+	recommendations, rawRecommendations, err := vertexai.GenerateValidatedRecommendations(
+		lang,
+		context,
+		stats.MethodNames(),
+		recommendedMethodValidator(lang),
+		maxRecommendationAttempts,
+	)
+	if err != nil {
+		fmt.Printf("Recommendation generation error: %v\n", err)
+		if len(ruleRecommendations) == 0 {
+			at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+				Status:  "error",
+				Message: "Failed to generate recommendations",
+			})
+			return
+		}
+		// Fallback ke rekomendasi berbasis aturan jika Vertex AI gagal
+		source = stats.SourceRules
+		recommendations = ruleRecommendations
 	}
+	parsedRecommendations := dedupeRecommendations(recommendations)
 
 	// Simpan hasil analisis
-	analysis := model.Analysis{
-		ProjectID:       projectID,
-		UploadID:        uploadData.ID,
-		Iteration:       1,
-		Status:          "completed",
-		Recommendations: parsedRecommendations,
-		SelectedMethods: []string{"AI_Recommendation"},
-		Results:         []model.MethodResult{},
-		Summary:         recommendations,
-		CreatedAt:       time.Now(),
-	}
+	// Teks mentah model hanya dikembalikan sebagai raw_response; ringkasan disusun dari rekomendasi tervalidasi
+	analysis := newRecommendationAnalysis(projectID, uploadData.ID, lang, parsedRecommendations)
+	analysis.Summary = recommendationSummary(parsedRecommendations, source, lang)
 
 	analysisID, err := atdb.InsertOneDoc(mongoDB, "analyses", analysis)
 	if err != nil {
@@ -126,15 +143,20 @@ func GetRecommendations(w http.ResponseWriter, r *http.Request, projectIDStr str
 		})
 		return
 	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisCreate, Resource: "analysis", ResourceID: analysisID, Details: "recommendations for project " + projectIDStr})
 
 	// Return hasil
 	at.WriteJSON(w, http.StatusOK, model.Response{
 		Status:  "success",
 		Message: "Recommendations generated successfully",
 		Data: map[string]interface{}{
-			"recommendations": recommendations,
+			"recommendations": parsedRecommendations,
+			"rule_based":      ruleRecommendations,
+			"source":          source,
+			"raw_response":    rawRecommendations,
 			"analysis_id":     analysisID,
 			"project_id":      projectIDStr,
+			"language":        lang,
 		},
 	})
 }
@@ -159,6 +181,18 @@ func ProcessAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr strin
 		return
 	}
 
+	// Body opsional: metode terpilih dan opsi per metode
+	var req model.ProcessRequest
+	if r.Body != nil {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid request body",
+			})
+			return
+		}
+	}
+
 	mongoDB := getMongoDB()
 	if mongoDB == nil {
 		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
@@ -178,71 +212,100 @@ func ProcessAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr strin
 		return
 	}
 
-	// Ambil project data
-	project, err := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": analysis.ProjectID})
+	// Ambil project data; menjalankan analisis minimal editor
+	project, ok := requireProject(w, r, mongoDB, analysis.ProjectID, userID, roleEditor)
+	if !ok {
+		return
+	}
+
+	// Hasil yang sedang ditinjau atau sudah disetujui tidak boleh berubah; gunakan refine untuk iterasi baru
+	if analysis.ReviewStatus == reviewSubmitted || analysis.ReviewStatus == reviewApproved {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "Analysis is under review or approved; refine it to create a new iteration",
+		})
+		return
+	}
+
+	methods := selectedMethods(req.SelectedMethods, analysis)
+	if len(methods) == 0 {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "No analysis methods selected",
+		})
+		return
+	}
+	options := analysis.MethodOptions
+	if req.Options != nil {
+		options = methodOptions(req.Options)
+	}
+
+	// Ambil upload data beserta file mentahnya
+	uploadData, err := atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": analysis.UploadID})
 	if err != nil {
 		at.WriteJSON(w, http.StatusNotFound, model.Response{
 			Status:  "error",
-			Message: "Project not found",
+			Message: "Upload not found",
+		})
+		return
+	}
+	data, err := loadDataset(r.Context(), uploadData)
+	if err != nil {
+		at.WriteJSON(w, http.StatusUnprocessableEntity, model.Response{
+			Status:  "error",
+			Message: "Failed to load uploaded data: " + err.Error(),
 		})
 		return
 	}
 
 	// Update status analysis
-	_, err = atdb.UpdateOneDoc(
-		mongoDB,
-		"analyses",
-		bson.M{"_id": analysisID},
-		bson.M{"$set": bson.M{
-			"status":     "processing",
-			"updated_at": time.Now(),
-		}},
-	)
-
-	// Ambil upload data
-	uploadData, _ := atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": analysis.UploadID})
+	atdb.UpdateOneDoc(mongoDB, "analyses", bson.M{"_id": analysisID}, bson.M{
+		"status":           "processing",
+		"selected_methods": methods,
+		"method_options":   options,
+	})
 
-	// Simulate processing (untuk production, implementasi actual analysis logic)
-	time.Sleep(2 * time.Second)
+	lang := requestLanguage(r, mongoDB, userID, req.Language)
+	results := runMethods(data, project, uploadData, methods, options, lang)
+	summary, status := summarizeResults(project, uploadData, results, lang)
+	figures := renderFigures(r.Context(), analysisID, data, results, lang)
 
-	// Generate interpretation using Vertex AI
-	interpretation, err := vertexai.GenerateAnalysisInterpretation("Analysis", "Processing completed")
-	if err != nil {
-		interpretation = "Analysis completed but interpretation failed"
+	// Update analysis dengan hasil final
+	completedAt := time.Now()
+	update := bson.M{
+		"results":      results,
+		"figures":      figures,
+		"summary":      summary,
+		"status":       status,
+		"language":     lang,
+		"completed_at": completedAt,
 	}
-
-	// Create MethodResult
-	methodResult := model.MethodResult{
-		Method:         "Processed Analysis",
-		RawOutput:      map[string]interface{}{"status": "completed", "interpretation": interpretation},
-		Interpretation: interpretation,
-		Conclusion:     "Analysis processed successfully",
+	if status == "failed" {
+		update["error"] = "All selected methods failed"
 	}
-
-	// Update analysis dengan hasil final
-	_, err = atdb.UpdateOneDoc(
-		mongoDB,
-		"analyses",
-		bson.M{"_id": analysisID},
-		bson.M{"$set": bson.M{
-			"results":      []model.MethodResult{methodResult},
-			"summary":      fmt.Sprintf("Analysis completed for project: %s\nFile: %s", project.Title, uploadData.FileName),
-			"status":       "completed",
-			"completed_at": time.Now(),
-			"updated_at":   time.Now(),
-		}},
-	)
+	if _, err := atdb.UpdateOneDoc(mongoDB, "analyses", bson.M{"_id": analysisID}, update); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to save analysis results",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisProcess, Resource: "analysis", ResourceID: analysisID, Details: status + ": " + strings.Join(methods, ", ")})
 
 	// Return hasil
 	at.WriteJSON(w, http.StatusOK, model.Response{
 		Status:  "success",
 		Message: "Analysis processed successfully",
 		Data: map[string]interface{}{
-			"analysis_id":    analysisID,
-			"project_id":     project.ID,
-			"interpretation": interpretation,
-			"method_result":  methodResult,
-			"status":         "completed",
+			"analysis_id":      analysisID,
+			"project_id":       project.ID,
+			"selected_methods": methods,
+			"results":          results,
+			"figures":          signFigures(r.Context(), figures),
+			"summary":          summary,
+			"status":           status,
+			"language":         lang,
+			"completed_at":     completedAt,
 		},
 	})
 }
@@ -285,13 +348,9 @@ func GetAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string) {
 		return
 	}
 
-	// Ambil project data
-	project, err := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": analysis.ProjectID})
-	if err != nil {
-		at.WriteJSON(w, http.StatusNotFound, model.Response{
-			Status:  "error",
-			Message: "Project not found",
-		})
+	// Ambil project data sekaligus memastikan user anggota project
+	project, ok := requireProject(w, r, mongoDB, analysis.ProjectID, userID, roleViewer)
+	if !ok {
 		return
 	}
 
@@ -301,6 +360,10 @@ func GetAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string) {
 		uploadData, _ = atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": analysis.UploadID})
 	}
 
+	// Pelaporan APA 7 dari hasil terstruktur
+	apa := report.FormatAPAAll(analysis.Results, reportLanguage(r, mongoDB, userID, analysis))
+	analysis.Figures = signFigures(r.Context(), analysis.Figures)
+
 	// Return detail analysis
 	at.WriteJSON(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -309,6 +372,7 @@ func GetAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string) {
 			"analysis": analysis,
 			"project":  project,
 			"upload":   uploadData,
+			"apa":      apa,
 		},
 	})
 }
@@ -342,13 +406,9 @@ func GetAllAnalyses(w http.ResponseWriter, r *http.Request, projectIDStr string)
 		return
 	}
 
-	// Verify project exists and belongs to user
-	project, err := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID, "user_id": userID})
-	if err != nil {
-		at.WriteJSON(w, http.StatusNotFound, model.Response{
-			Status:  "error",
-			Message: "Project not found or unauthorized",
-		})
+	// Verify project exists and user is a member
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer)
+	if !ok {
 		return
 	}
 
@@ -361,6 +421,9 @@ func GetAllAnalyses(w http.ResponseWriter, r *http.Request, projectIDStr string)
 		})
 		return
 	}
+	for i := range analyses {
+		analyses[i].Figures = signFigures(r.Context(), analyses[i].Figures)
+	}
 
 	// Return semua analyses
 	at.WriteJSON(w, http.StatusOK, model.Response{
@@ -416,6 +479,18 @@ func UpdateAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 		return
 	}
 
+	analysis, err := atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": analysisID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Analysis not found",
+		})
+		return
+	}
+	if _, ok := requireProject(w, r, mongoDB, analysis.ProjectID, userID, roleEditor); !ok {
+		return
+	}
+
 	// Update analysis
 	updateFields := bson.M{
 		"updated_at": time.Now(),
@@ -432,7 +507,7 @@ func UpdateAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 		mongoDB,
 		"analyses",
 		bson.M{"_id": analysisID},
-		bson.M{"$set": updateFields},
+		updateFields,
 	)
 
 	if err != nil {
@@ -442,6 +517,7 @@ func UpdateAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 		})
 		return
 	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisUpdate, Resource: "analysis", ResourceID: analysisID})
 
 	at.WriteJSON(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -482,16 +558,28 @@ func DeleteAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 		return
 	}
 
+	analysis, err := atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": analysisID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Analysis not found",
+		})
+		return
+	}
+	if _, ok := requireProject(w, r, mongoDB, analysis.ProjectID, userID, roleEditor); !ok {
+		return
+	}
+
 	// Delete analysis (dalam production, mungkin soft delete)
 	_, err = atdb.UpdateOneDoc(
 		mongoDB,
 		"analyses",
 		bson.M{"_id": analysisID},
-		bson.M{"$set": bson.M{
+		bson.M{
 			"status":     "deleted",
 			"error":      "Deleted by user",
 			"updated_at": time.Now(),
-		}},
+		},
 	)
 
 	if err != nil {
@@ -501,6 +589,7 @@ func DeleteAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 		})
 		return
 	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisDelete, Resource: "analysis", ResourceID: analysisID})
 
 	at.WriteJSON(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -512,7 +601,7 @@ func DeleteAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 	})
 }
 
-// RefineAnalysis handler untuk refined analysis
+// RefineAnalysis handler untuk refined analysis: melanjutkan percakapan refinement dan membuat iterasi baru
 func RefineAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string) {
 	userID, err := getUserIDFromToken(r)
 	if err != nil || userID == primitive.NilObjectID {
@@ -532,24 +621,35 @@ func RefineAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 		return
 	}
 
-	var refinementRequest struct {
-		Instructions string `json:"instructions"`
+	var req model.RefineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
 	}
-
-	mongoDB := getMongoDB()
-	if mongoDB == nil {
-		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+	instruction := strings.TrimSpace(req.Feedback)
+	if instruction == "" {
+		instruction = strings.TrimSpace(req.Instructions)
+	}
+	if req.Adjustments != "" {
+		instruction = strings.TrimSpace(instruction + "\n" + req.Adjustments)
+	}
+	rerun := req.Rerun || len(req.NewMethods) > 0 || len(req.Options) > 0
+	if instruction == "" && !rerun {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
 			Status:  "error",
-			Message: "Database connection failed",
+			Message: "Feedback or methods to re-run are required",
 		})
 		return
 	}
 
-	err = json.NewDecoder(r.Body).Decode(&refinementRequest)
-	if err != nil {
-		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
 			Status:  "error",
-			Message: "Invalid request body",
+			Message: "Database connection failed",
 		})
 		return
 	}
@@ -564,47 +664,212 @@ func RefineAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string
 		return
 	}
 
-	// Generate refined content
-	refinedPrompt := fmt.Sprintf("Please refine the following analysis based on these instructions:\n\nInstructions: %s\n\nOriginal Analysis: %s\n\nPlease provide a refined version that addresses the instructions.",
-		refinementRequest.Instructions, originalAnalysis.Summary)
-
-	refinedResults, err := vertexai.GenerateContent(refinedPrompt)
-	if err != nil {
-		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+	project, ok := requireProject(w, r, mongoDB, originalAnalysis.ProjectID, userID, roleEditor)
+	if !ok {
+		return
+	}
+	if err := checkQuota(mongoDB, project.UserID, quotaAnalyses, 0); err != nil {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
 			Status:  "error",
-			Message: "Failed to refine analysis",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	// Create refined analysis sebagai analysis baru
+	lang := requestLanguage(r, mongoDB, userID, req.Language)
 	newAnalysis := model.Analysis{
-		ProjectID:    originalAnalysis.ProjectID,
-		UploadID:     originalAnalysis.UploadID,
-		Iteration:    originalAnalysis.Iteration + 1,
-		Status:       "completed",
-		Summary:      refinedResults,
-		UserFeedback: "Refined version: " + refinementRequest.Instructions,
-		CreatedAt:    time.Now(),
+		ID:              primitive.NewObjectID(),
+		ProjectID:       originalAnalysis.ProjectID,
+		UploadID:        originalAnalysis.UploadID,
+		ParentID:        originalAnalysis.ID,
+		Iteration:       originalAnalysis.Iteration + 1,
+		Language:        lang,
+		Recommendations: originalAnalysis.Recommendations,
+		SelectedMethods: originalAnalysis.SelectedMethods,
+		MethodOptions:   originalAnalysis.MethodOptions,
+		Results:         originalAnalysis.Results,
+		Figures:         originalAnalysis.Figures,
+		UserFeedback:    instruction,
+		CreatedAt:       time.Now(),
+	}
+
+	// Jalankan ulang metode dengan opsi baru bila diminta
+	var rerunMethods []string
+	var changedOptions map[string]map[string]interface{}
+	var uploadData model.Upload
+	if rerun {
+		rerunMethods = selectedMethods(req.NewMethods, originalAnalysis)
+		if len(rerunMethods) == 0 {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "No analysis methods to re-run",
+			})
+			return
+		}
+		changedOptions = methodOptions(req.Options)
+
+		uploadData, err = atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": originalAnalysis.UploadID})
+		if err != nil {
+			at.WriteJSON(w, http.StatusNotFound, model.Response{
+				Status:  "error",
+				Message: "Upload not found",
+			})
+			return
+		}
+		data, err := loadDataset(r.Context(), uploadData)
+		if err != nil {
+			at.WriteJSON(w, http.StatusUnprocessableEntity, model.Response{
+				Status:  "error",
+				Message: "Failed to load uploaded data: " + err.Error(),
+			})
+			return
+		}
+
+		newAnalysis.MethodOptions = mergeMethodOptions(originalAnalysis.MethodOptions, changedOptions)
+		rerunResults := runMethods(data, project, uploadData, rerunMethods, newAnalysis.MethodOptions, lang)
+		newAnalysis.Results = mergeResults(originalAnalysis.Results, rerunResults)
+		newAnalysis.SelectedMethods = mergeMethods(originalAnalysis.SelectedMethods, rerunMethods)
+		newAnalysis.Figures = renderFigures(r.Context(), newAnalysis.ID, data, newAnalysis.Results, lang)
+	}
+
+	if instruction == "" {
+		instruction = i18n.T(lang, "refine.rerun_instruction", strings.Join(rerunMethods, ", "))
 	}
 
-	newAnalysisID, err := atdb.InsertOneDoc(mongoDB, "analyses", newAnalysis)
+	// Generate balasan dengan riwayat percakapan dan hasil terstruktur sebagai konteks
+	reply, err := vertexai.GenerateRefinement(lang, interpretationContext(project, lang), originalAnalysis.Conversation, newAnalysis.Results, instruction)
 	if err != nil {
+		if !rerun {
+			at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+				Status:  "error",
+				Message: "Failed to refine analysis",
+			})
+			return
+		}
+		// Hasil yang sudah dihitung ulang dan gambar yang sudah diunggah tetap disimpan dengan ringkasan dari output komputasi
+		log.Printf("WARNING: refinement reply for analysis %s failed, saving computed summary: %v", analysisIDStr, err)
+		reply, newAnalysis.SummaryVerification = fallbackRefinement(project, uploadData, newAnalysis.Results, err, lang)
+	}
+
+	now := time.Now()
+	newAnalysis.Status = "completed"
+	newAnalysis.Summary = reply
+	newAnalysis.CompletedAt = &now
+	newAnalysis.Conversation = append(append([]model.ConversationTurn{}, originalAnalysis.Conversation...),
+		model.ConversationTurn{
+			Role:       "user",
+			Content:    instruction,
+			AnalysisID: newAnalysis.ID,
+			Rerun:      rerunMethods,
+			Options:    changedOptions,
+			CreatedAt:  newAnalysis.CreatedAt,
+		},
+		model.ConversationTurn{
+			Role:       "assistant",
+			Content:    reply,
+			AnalysisID: newAnalysis.ID,
+			CreatedAt:  now,
+		},
+	)
+
+	if _, err := atdb.InsertOneDoc(mongoDB, "analyses", newAnalysis); err != nil {
 		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
 			Status:  "error",
 			Message: "Failed to save refined analysis",
 		})
 		return
 	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisRefine, Resource: "analysis", ResourceID: newAnalysis.ID, Details: "iteration of " + analysisIDStr})
 
 	at.WriteJSON(w, http.StatusOK, model.Response{
 		Status:  "success",
 		Message: "Analysis refined successfully",
 		Data: map[string]interface{}{
 			"original_analysis_id": analysisID,
-			"refined_analysis_id":  newAnalysisID,
-			"refined_results":      refinedResults,
-			"instructions":         refinementRequest.Instructions,
+			"refined_analysis_id":  newAnalysis.ID,
+			"parent_id":            newAnalysis.ParentID,
+			"iteration":            newAnalysis.Iteration,
+			"refined_results":      reply,
+			"summary_verification": newAnalysis.SummaryVerification,
+			"results":              newAnalysis.Results,
+			"figures":              signFigures(r.Context(), newAnalysis.Figures),
+			"rerun_methods":        rerunMethods,
+			"conversation":         newAnalysis.Conversation,
+			"instructions":         instruction,
+		},
+	})
+}
+
+// GetAnalysisLineage handler untuk menelusuri rantai iterasi refinement (leluhur dan turunan) sebuah analysis
+func GetAnalysisLineage(w http.ResponseWriter, r *http.Request, analysisIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID == primitive.NilObjectID {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	analysisID, err := primitive.ObjectIDFromHex(analysisIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid analysis ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	analysis, err := atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": analysisID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Analysis not found",
+		})
+		return
+	}
+	if _, ok := requireProject(w, r, mongoDB, analysis.ProjectID, userID, roleViewer); !ok {
+		return
+	}
+
+	// Telusuri leluhur hingga iterasi awal (dibatasi untuk mencegah siklus)
+	ancestors := []map[string]interface{}{}
+	parentID := analysis.ParentID
+	for depth := 0; !parentID.IsZero() && depth < 100; depth++ {
+		parent, err := atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": parentID})
+		if err != nil {
+			break
+		}
+		ancestors = append([]map[string]interface{}{lineageNode(parent)}, ancestors...)
+		parentID = parent.ParentID
+	}
+
+	children, err := atdb.GetAllDocWithSort[model.Analysis](mongoDB, "analyses", bson.M{"parent_id": analysisID}, bson.D{{Key: "created_at", Value: 1}})
+	if err != nil {
+		children = nil
+	}
+	descendants := make([]map[string]interface{}, 0, len(children))
+	for _, child := range children {
+		descendants = append(descendants, lineageNode(child))
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Analysis lineage retrieved successfully",
+		Data: map[string]interface{}{
+			"analysis":     lineageNode(analysis),
+			"ancestors":    ancestors,
+			"children":     descendants,
+			"conversation": analysis.Conversation,
 		},
 	})
 }
@@ -657,12 +922,8 @@ func GenerateSummary(w http.ResponseWriter, r *http.Request, projectIDStr string
 	}
 
 	// Ambil project info
-	project, err := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID, "user_id": userID})
-	if err != nil {
-		at.WriteJSON(w, http.StatusNotFound, model.Response{
-			Status:  "error",
-			Message: "Project not found or unauthorized",
-		})
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer)
+	if !ok {
 		return
 	}
 
@@ -675,7 +936,8 @@ func GenerateSummary(w http.ResponseWriter, r *http.Request, projectIDStr string
 	}
 
 	// Generate summary menggunakan Vertex AI
-	summary, err := vertexai.GenerateResearchSummary(analysisContext)
+	lang := requestLanguage(r, mongoDB, userID, "")
+	summary, err := vertexai.GenerateResearchSummary(lang, analysisContext)
 	if err != nil {
 		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
 			Status:  "error",
@@ -689,6 +951,7 @@ func GenerateSummary(w http.ResponseWriter, r *http.Request, projectIDStr string
 		ProjectID:    projectID,
 		Iteration:    len(analyses) + 1,
 		Status:       "completed",
+		Language:     lang,
 		Summary:      summary,
 		UserFeedback: fmt.Sprintf("Auto-generated summary from %d analyses", len(analyses)),
 		CreatedAt:    time.Now(),
@@ -702,6 +965,7 @@ func GenerateSummary(w http.ResponseWriter, r *http.Request, projectIDStr string
 		})
 		return
 	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisCreate, Resource: "analysis", ResourceID: summaryID, Details: "summary for project " + projectIDStr})
 
 	at.WriteJSON(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -714,3 +978,399 @@ func GenerateSummary(w http.ResponseWriter, r *http.Request, projectIDStr string
 		},
 	})
 }
+
+// recommendedMethodValidator memastikan metode yang direkomendasikan AI dapat dijalankan engine,
+// melengkapi asumsi dalam bahasa lang bila kosong
+func recommendedMethodValidator(lang string) vertexai.RecommendationValidator {
+	return func(rec model.Recommendation) (model.Recommendation, error) {
+		return validateRecommendedMethod(rec, lang)
+	}
+}
+
+// validateRecommendedMethod memastikan metode yang direkomendasikan AI dapat dijalankan engine
+func validateRecommendedMethod(rec model.Recommendation, lang string) (model.Recommendation, error) {
+	method, ok := stats.LookupMethod(rec.Method)
+	if !ok {
+		return rec, fmt.Errorf("method %q is not supported by the analysis engine", rec.Method)
+	}
+
+	rec.Method = method.Name
+	rec.MethodID = method.ID
+	rec.Source = stats.SourceAI
+	if !stats.IsValidCategory(rec.Category) {
+		rec.Category = method.Category
+	}
+	if rec.Assumptions == "" {
+		rec.Assumptions = method.AssumptionsIn(lang)
+	}
+	return rec, nil
+}
+
+// dedupeRecommendations membuang metode ganda dan menomori ulang prioritas
+func dedupeRecommendations(recommendations []model.Recommendation) []model.Recommendation {
+	seen := make(map[string]bool)
+	result := make([]model.Recommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		if seen[rec.MethodID] {
+			continue
+		}
+		seen[rec.MethodID] = true
+		rec.Priority = len(result) + 1
+		result = append(result, rec)
+	}
+	return result
+}
+
+// newRecommendationAnalysis membuat analysis iterasi pertama dari rekomendasi tervalidasi.
+// Metode terpilih diisi nama metode rekomendasi agar ProcessAnalysis tanpa body langsung bisa menjalankannya.
+func newRecommendationAnalysis(projectID, uploadID primitive.ObjectID, lang string, recommendations []model.Recommendation) model.Analysis {
+	methods := make([]string, 0, len(recommendations))
+	for _, rec := range recommendations {
+		methods = append(methods, rec.Method)
+	}
+	return model.Analysis{
+		ProjectID:       projectID,
+		UploadID:        uploadID,
+		Iteration:       1,
+		Language:        lang,
+		Status:          "completed",
+		Recommendations: recommendations,
+		SelectedMethods: methods,
+		Results:         []model.MethodResult{},
+		CreatedAt:       time.Now(),
+	}
+}
+
+// recommendationSummary menyusun ringkasan singkat berisi metode dan alasan tiap rekomendasi.
+// Pada fallback berbasis aturan, alasan diambil dari recommender sehingga tidak bertentangan dengan teks AI yang ditolak.
+func recommendationSummary(recommendations []model.Recommendation, source, lang string) string {
+	if len(recommendations) == 0 {
+		return ""
+	}
+	header := "recommend.summary"
+	if source == stats.SourceRules {
+		header = "recommend.summary_rules"
+	}
+	lines := []string{i18n.T(lang, header)}
+	for _, rec := range recommendations {
+		line := fmt.Sprintf("%d. %s", rec.Priority, rec.Method)
+		if rec.Reasoning != "" {
+			line += ": " + rec.Reasoning
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// maxInterpretationAttempts adalah jumlah percobaan interpretasi AI (percobaan kedua memakai prompt korektif)
+const maxInterpretationAttempts = 2
+
+// selectedMethods menentukan metode yang dijalankan: dari request, pilihan tersimpan, atau rekomendasi
+func selectedMethods(requested []string, analysis model.Analysis) []string {
+	candidates := requested
+	if len(candidates) == 0 {
+		candidates = analysis.SelectedMethods
+	}
+	if len(candidates) == 0 {
+		for _, rec := range analysis.Recommendations {
+			candidates = append(candidates, rec.Method)
+		}
+	}
+
+	var methods []string
+	seen := make(map[string]bool)
+	for _, name := range candidates {
+		key := name
+		if method, ok := stats.LookupMethod(name); ok {
+			key = method.ID
+			name = method.Name
+		}
+		if !seen[key] {
+			seen[key] = true
+			methods = append(methods, name)
+		}
+	}
+	return methods
+}
+
+// methodOptions menormalkan kunci opsi (nama atau ID metode) menjadi ID metode
+func methodOptions(options map[string]map[string]interface{}) map[string]map[string]interface{} {
+	normalized := make(map[string]map[string]interface{}, len(options))
+	for key, opts := range options {
+		if method, ok := stats.LookupMethod(key); ok {
+			key = method.ID
+		}
+		normalized[key] = opts
+	}
+	return normalized
+}
+
+// loadDataset mengunduh dan mem-parse file mentah upload
+func loadDataset(ctx context.Context, upload model.Upload) (*dataset.Dataset, error) {
+	if upload.StoragePath == "" {
+		return nil, fmt.Errorf("raw file for %s is not stored; please upload the data again", upload.FileName)
+	}
+	content, err := storage.DownloadFile(ctx, upload.StoragePath)
+	if err != nil {
+		return nil, err
+	}
+	return dataset.Parse(upload.FileName, content)
+}
+
+// runMethods menjalankan setiap metode pada data lalu menghasilkan interpretasi yang terverifikasi
+func runMethods(data *dataset.Dataset, project model.Project, upload model.Upload, methods []string, options map[string]map[string]interface{}, lang string) []model.MethodResult {
+	researchContext := interpretationContext(project, lang)
+	alpha := institutionDefaults(getMongoDB(), project.UserID).Alpha
+	results := make([]model.MethodResult, 0, len(methods))
+	for _, name := range methods {
+		result := model.MethodResult{Method: name}
+		method, ok := stats.LookupMethod(name)
+		if !ok {
+			result.Error = fmt.Sprintf("method %q is not supported by the analysis engine", name)
+			results = append(results, result)
+			continue
+		}
+		result.Method, result.MethodID = method.Name, method.ID
+
+		raw, err := stats.Run(data, stats.Request{
+			Method:    method.ID,
+			Variables: project.Variables,
+			Summary:   upload.DataSummary,
+			Options:   withDefaultAlpha(options[method.ID], alpha),
+		})
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.RawOutput = raw
+		interpretResult(&result, researchContext, lang)
+		results = append(results, result)
+	}
+	return results
+}
+
+// renderFigures menggambar chart setiap hasil metode lalu menyimpannya (PNG dan SVG) ke storage.
+// Chart yang gagal disimpan dilewati agar hasil analisis tetap tersimpan.
+func renderFigures(ctx context.Context, analysisID primitive.ObjectID, data *dataset.Dataset, results []model.MethodResult, lang string) []model.Figure {
+	figures := []model.Figure{}
+	for _, result := range results {
+		for _, ch := range chart.ForResult(data, result, lang) {
+			base := fmt.Sprintf("figures/%s/%s", analysisID.Hex(), ch.ID)
+			content, err := ch.PNG()
+			if err != nil {
+				log.Printf("WARNING: failed to render figure %s: %v", base, err)
+				continue
+			}
+			pngURL, err := storage.UploadFile(ctx, base+".png", bytes.NewReader(content), "image/png")
+			if err != nil {
+				log.Printf("WARNING: failed to store figure %s: %v", base, err)
+				continue
+			}
+			figure := model.Figure{ID: ch.ID, Title: ch.Title, Type: ch.Type, StorageURL: pngURL}
+			if svgURL, err := storage.UploadFile(ctx, base+".svg", bytes.NewReader(ch.SVG()), "image/svg+xml"); err != nil {
+				log.Printf("WARNING: failed to store figure %s: %v", base+".svg", err)
+			} else {
+				figure.SVGURL = svgURL
+			}
+			figures = append(figures, figure)
+		}
+	}
+	return figures
+}
+
+// interpretResult mengisi interpretasi dari AI yang didasarkan pada RawOutput, dengan fallback deterministik
+func interpretResult(result *model.MethodResult, researchContext, lang string) {
+	interp, verification, err := vertexai.GenerateVerifiedInterpretation(lang, result.Method, result.RawOutput, researchContext, maxInterpretationAttempts)
+	if err != nil {
+		result.Interpretation = fallbackInterpretation(result.Method, result.RawOutput, lang)
+		result.Conclusion = result.Interpretation
+		result.EffectSize = fallbackEffectSize(result.RawOutput)
+		result.Verification = &model.Verification{
+			Verified: false,
+			Fallback: true,
+			Attempts: verification.Attempts,
+			Issues:   []string{i18n.T(lang, "result.ai_unavailable", err.Error())},
+		}
+		return
+	}
+
+	result.Interpretation = interp.Interpretation
+	if interp.PracticalImplications != "" {
+		result.Interpretation += "\n\n" + interp.PracticalImplications
+	}
+	result.EffectSize = interp.EffectSize
+	result.Conclusion = interp.Conclusion
+	result.Verification = &verification
+}
+
+// interpretationContext menyusun konteks penelitian untuk prompt interpretasi
+func interpretationContext(project model.Project, lang string) string {
+	lines := []string{i18n.T(lang, "context.title", project.Title)}
+	if project.ResearchType != "" {
+		lines = append(lines, i18n.T(lang, "context.research_type", project.ResearchType))
+	}
+	if project.Hypothesis != "" {
+		lines = append(lines, i18n.T(lang, "context.hypothesis", project.Hypothesis))
+	}
+	if len(project.Variables.Independent) > 0 {
+		lines = append(lines, i18n.T(lang, "context.independent", strings.Join(project.Variables.Independent, ", ")))
+	}
+	if len(project.Variables.Dependent) > 0 {
+		lines = append(lines, i18n.T(lang, "context.dependent", strings.Join(project.Variables.Dependent, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fallbackInterpretation menyusun pernyataan hasil langsung dari output statistik
+func fallbackInterpretation(method string, raw map[string]interface{}, lang string) string {
+	statName := stats.String(raw, stats.KeyStatisticName)
+	statistic, hasStat := stats.Number(raw, stats.KeyStatistic)
+	p, hasP := stats.Number(raw, stats.KeyPValue)
+	if !hasStat || !hasP {
+		return i18n.T(lang, "result.see_tables", method)
+	}
+
+	text := fmt.Sprintf("%s: %s = %.3f, p = %.3f", method, statName, statistic, p)
+	if p < 0.001 {
+		text = fmt.Sprintf("%s: %s = %.3f, p < .001", method, statName, statistic)
+	}
+	if significant, ok := raw[stats.KeySignificant].(bool); ok {
+		alpha, _ := stats.Number(raw, stats.KeyAlpha)
+		if significant {
+			text += i18n.T(lang, "result.significant", alpha)
+		} else {
+			text += i18n.T(lang, "result.not_significant", alpha)
+		}
+	}
+	return text
+}
+
+// fallbackEffectSize menyusun keterangan ukuran efek dari output statistik
+func fallbackEffectSize(raw map[string]interface{}) string {
+	value, ok := stats.Number(raw, stats.KeyEffectSize)
+	if !ok {
+		return ""
+	}
+	text := fmt.Sprintf("%s = %.3f", stats.String(raw, stats.KeyEffectSizeName), value)
+	if label := stats.String(raw, stats.KeyEffectSizeLabel); label != "" {
+		text += " (" + label + ")"
+	}
+	return text
+}
+
+// summarizeResults menyusun ringkasan singkat dan status akhir analisis
+func summarizeResults(project model.Project, upload model.Upload, results []model.MethodResult, lang string) (string, string) {
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "result.header", project.Title, upload.FileName))
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+			fmt.Fprintf(&b, "\n%s: %s", result.Method, i18n.T(lang, "result.failed", result.Error))
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s: %s", result.Method, result.Conclusion)
+	}
+
+	status := "completed"
+	if failed == len(results) {
+		status = "failed"
+	}
+	return b.String(), status
+}
+
+// fallbackRefinement menyusun balasan refinement dari output komputasi saat AI gagal; balasan ditandai belum diverifikasi
+func fallbackRefinement(project model.Project, upload model.Upload, results []model.MethodResult, cause error, lang string) (string, *model.Verification) {
+	summary, _ := summarizeResults(project, upload, results, lang)
+	return summary, &model.Verification{
+		Verified: false,
+		Fallback: true,
+		Issues:   []string{i18n.T(lang, "result.ai_unavailable", cause.Error())},
+	}
+}
+
+// lineageNode meringkas analysis untuk navigasi lineage
+func lineageNode(analysis model.Analysis) map[string]interface{} {
+	return map[string]interface{}{
+		"_id":              analysis.ID,
+		"parent_id":        analysis.ParentID,
+		"iteration":        analysis.Iteration,
+		"status":           analysis.Status,
+		"selected_methods": analysis.SelectedMethods,
+		"user_feedback":    analysis.UserFeedback,
+		"created_at":       analysis.CreatedAt,
+	}
+}
+
+// withDefaultAlpha menambahkan tingkat signifikansi bawaan institusi jika opsi metode tidak mengaturnya
+func withDefaultAlpha(options map[string]interface{}, alpha float64) map[string]interface{} {
+	if alpha <= 0 {
+		return options
+	}
+	if _, ok := options["alpha"]; ok {
+		return options
+	}
+	merged := make(map[string]interface{}, len(options)+1)
+	for k, v := range options {
+		merged[k] = v
+	}
+	merged["alpha"] = alpha
+	return merged
+}
+
+// mergeMethodOptions menimpa opsi lama dengan opsi baru per metode
+func mergeMethodOptions(base, changes map[string]map[string]interface{}) map[string]map[string]interface{} {
+	merged := make(map[string]map[string]interface{}, len(base)+len(changes))
+	for method, opts := range base {
+		merged[method] = opts
+	}
+	for method, opts := range changes {
+		combined := make(map[string]interface{}, len(merged[method])+len(opts))
+		for k, v := range merged[method] {
+			combined[k] = v
+		}
+		for k, v := range opts {
+			combined[k] = v
+		}
+		merged[method] = combined
+	}
+	return merged
+}
+
+// mergeResults mengganti hasil metode yang dijalankan ulang dan menambahkan metode baru
+func mergeResults(base, rerun []model.MethodResult) []model.MethodResult {
+	merged := append([]model.MethodResult{}, base...)
+	for _, result := range rerun {
+		replaced := false
+		for i := range merged {
+			if (result.MethodID != "" && merged[i].MethodID == result.MethodID) || merged[i].Method == result.Method {
+				merged[i] = result
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, result)
+		}
+	}
+	return merged
+}
+
+// mergeMethods menambahkan metode yang belum ada ke daftar metode terpilih
+func mergeMethods(base, added []string) []string {
+	merged := append([]string{}, base...)
+	for _, name := range added {
+		exists := false
+		for _, existing := range merged {
+			if existing == name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
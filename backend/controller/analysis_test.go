@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/research-data-analysis/helper/dataset"
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sampleDataset membuat data dua kelompok dengan satu variabel terikat metrik
+func sampleDataset(t *testing.T) *dataset.Dataset {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("group,score,age\n")
+	for i := range 40 {
+		group := "control"
+		if i%2 == 1 {
+			group = "treatment"
+		}
+		fmt.Fprintf(&b, "%s,%d,%d\n", group, 60+(i*7)%25+(i%2)*5, 20+i%15)
+	}
+	data, err := dataset.Parse("sample.csv", []byte(b.String()))
+	if err != nil {
+		t.Fatalf("dataset.Parse: %v", err)
+	}
+	return data
+}
+
+// Alur ProcessAnalysis tanpa body setelah GetRecommendations: metode diambil dari analysis tersimpan
+func TestProcessAfterRecommendationsWithoutBody(t *testing.T) {
+	data := sampleDataset(t)
+	summary := dataset.Summarize(data)
+	project := model.Project{
+		Title:     "Sample",
+		Variables: model.Variables{Independent: []string{"group"}, Dependent: []string{"score"}},
+	}
+
+	tests := []struct {
+		name            string
+		recommendations []model.Recommendation
+		minMethods      int
+	}{
+		{"rule-based", stats.Recommend(project, summary, "en"), 1},
+		{"ai", []model.Recommendation{
+			{Method: "Independent Samples t-Test", MethodID: stats.MethodIndependentTTest},
+			{Method: "Descriptive Statistics", MethodID: stats.MethodDescriptive},
+		}, 2},
+		{"none", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := newRecommendationAnalysis(primitive.NewObjectID(), primitive.NewObjectID(), "en", dedupeRecommendations(tt.recommendations))
+			if analysis.SelectedMethods == nil {
+				t.Fatal("SelectedMethods is nil, want an empty slice")
+			}
+
+			methods := selectedMethods(nil, analysis)
+			if len(methods) < tt.minMethods || len(methods) != len(analysis.Recommendations) {
+				t.Fatalf("selectedMethods = %v, want one method per recommendation %v", methods, analysis.Recommendations)
+			}
+			for _, name := range methods {
+				method, ok := stats.LookupMethod(name)
+				if !ok {
+					t.Errorf("method %q is not supported by the analysis engine", name)
+					continue
+				}
+				if _, err := stats.Run(data, stats.Request{Method: method.ID, Variables: project.Variables, Summary: summary}); err != nil {
+					t.Errorf("stats.Run(%s): %v", method.ID, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRecommendationSummary(t *testing.T) {
+	recommendations := dedupeRecommendations([]model.Recommendation{
+		{Method: "Independent Samples t-Test", MethodID: stats.MethodIndependentTTest, Reasoning: "Two groups on a ratio scale."},
+		{Method: "Mann-Whitney U Test", MethodID: stats.MethodMannWhitney},
+	})
+	tests := []struct {
+		name            string
+		recommendations []model.Recommendation
+		source          string
+		want            string
+	}{
+		{"none", nil, stats.SourceAI, ""},
+		{"ai", recommendations, stats.SourceAI, "Recommended methods:\n1. Independent Samples t-Test: Two groups on a ratio scale.\n2. Mann-Whitney U Test"},
+		{"rule-based fallback", recommendations, stats.SourceRules, "AI recommendations could not be validated; these methods were chosen by rules from the data profile:\n1. Independent Samples t-Test: Two groups on a ratio scale.\n2. Mann-Whitney U Test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recommendationSummary(tt.recommendations, tt.source, "en"); got != tt.want {
+				t.Errorf("recommendationSummary =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbackRefinement(t *testing.T) {
+	project := model.Project{Title: "Sample"}
+	upload := model.Upload{FileName: "sample.csv"}
+	results := []model.MethodResult{
+		{Method: "Independent Samples t-Test", Conclusion: "The groups differ."},
+		{Method: "Mann-Whitney U Test", Error: "not enough observations"},
+	}
+
+	summary, verification := fallbackRefinement(project, upload, results, errors.New("vertex unavailable"), "en")
+	for _, want := range []string{"Sample", "sample.csv", "The groups differ.", "not enough observations"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary %q does not contain %q", summary, want)
+		}
+	}
+	if verification == nil || verification.Verified || !verification.Fallback {
+		t.Fatalf("verification = %+v, want unverified fallback", verification)
+	}
+	if len(verification.Issues) != 1 || !strings.Contains(verification.Issues[0], "vertex unavailable") {
+		t.Errorf("issues = %v, want the AI error", verification.Issues)
+	}
+}
@@ -0,0 +1,361 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// apiKeyPrefix membedakan API key dari token PASETO di header Authorization
+const apiKeyPrefix = "rda_"
+
+// Scope API key
+const (
+	scopeRead   = "read"
+	scopeWrite  = "write"
+	scopeExport = "export"
+)
+
+// Batas API key per pengguna dan masa berlakunya
+const (
+	maxAPIKeys           = 20
+	defaultAPIKeyDays    = 90
+	maxAPIKeyDays        = 365
+	apiKeyPrefixLength   = len(apiKeyPrefix) + 8
+	apiKeyLastUsedWindow = time.Minute
+)
+
+// apiKeyContextKey menyimpan API key yang sudah diverifikasi di context request
+type apiKeyContextKey struct{}
+
+// GetAPIKeys handler untuk daftar API key pengguna (tanpa nilai kuncinya)
+func GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	keys, err := atdb.GetAllDocWithSort[model.APIKey](mongoDB, "api_keys", bson.M{"user_id": userID}, bson.D{{Key: "created_at", Value: -1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve API keys",
+		})
+		return
+	}
+	if keys == nil {
+		keys = []model.APIKey{}
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "API keys retrieved successfully",
+		Data:    keys,
+	})
+}
+
+// CreateAPIKey handler untuk membuat API key. Nilai kunci hanya dikembalikan sekali pada respons ini.
+func CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	var req model.APIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "API key name is required",
+		})
+		return
+	}
+	scopes := []string{}
+	for _, scope := range req.Scopes {
+		if scope != scopeRead && scope != scopeWrite && scope != scopeExport {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Scopes must be read, write, or export",
+			})
+			return
+		}
+		if !containsString(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "At least one scope is required",
+		})
+		return
+	}
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultAPIKeyDays
+	}
+	if days < 0 || days > maxAPIKeyDays {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "expires_in_days must be between 1 and 365",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	// Batas project hanya untuk project yang dapat diakses pengguna saat ini
+	projectIDs := []primitive.ObjectID{}
+	for _, raw := range req.ProjectIDs {
+		projectID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid project ID",
+			})
+			return
+		}
+		if _, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer); !ok {
+			return
+		}
+		if !containsObjectID(projectIDs, projectID) {
+			projectIDs = append(projectIDs, projectID)
+		}
+	}
+
+	now := time.Now()
+	active, _ := atdb.CountDoc(mongoDB, "api_keys", bson.M{"user_id": userID, "revoked_at": nil, "expires_at": bson.M{"$gt": now}})
+	if active >= maxAPIKeys {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "Too many active API keys; revoke an unused key first",
+		})
+		return
+	}
+
+	secret, err := newSecretToken()
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate API key",
+		})
+		return
+	}
+	raw := apiKeyPrefix + secret
+	key := model.APIKey{
+		UserID:     userID,
+		Name:       req.Name,
+		Prefix:     raw[:apiKeyPrefixLength],
+		KeyHash:    hashSecretToken(raw),
+		Scopes:     scopes,
+		ProjectIDs: projectIDs,
+		CreatedAt:  now,
+		ExpiresAt:  now.AddDate(0, 0, days),
+	}
+	keyID, err := atdb.InsertOneDoc(mongoDB, "api_keys", key)
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to save API key",
+		})
+		return
+	}
+	key.ID = keyID
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAPIKeyCreate, Resource: "api_key", ResourceID: keyID, Details: key.Name + " [" + strings.Join(scopes, ", ") + "]"})
+
+	at.WriteJSON(w, http.StatusCreated, model.Response{
+		Status:  "success",
+		Message: "API key created; copy it now, it will not be shown again",
+		Data: map[string]interface{}{
+			"api_key": key,
+			"key":     raw,
+		},
+	})
+}
+
+// RevokeAPIKey handler untuk mencabut API key milik pengguna
+func RevokeAPIKey(w http.ResponseWriter, r *http.Request, keyIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	keyID, err := primitive.ObjectIDFromHex(keyIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid API key ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	result, err := atdb.UpdateOneDoc(mongoDB, "api_keys", bson.M{"_id": keyID, "user_id": userID, "revoked_at": nil}, bson.M{"revoked_at": time.Now()})
+	if err != nil || result.MatchedCount == 0 {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "API key not found",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAPIKeyRevoke, Resource: "api_key", ResourceID: keyID})
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "API key revoked",
+	})
+}
+
+// AuthenticateAPIKey memverifikasi API key di header Authorization sebelum routing: masa berlaku, scope
+// untuk method dan path request, serta endpoint yang tidak boleh diakses API key. Request dengan token
+// PASETO dikembalikan apa adanya. Jika valid, API key disimpan di context request yang dikembalikan.
+func AuthenticateAPIKey(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !strings.HasPrefix(token, apiKeyPrefix) {
+		return r, true
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return r, false
+	}
+
+	now := time.Now()
+	key, err := atdb.GetOneDoc[model.APIKey](mongoDB, "api_keys", bson.M{"key_hash": hashSecretToken(token), "revoked_at": nil})
+	if err != nil || !now.Before(key.ExpiresAt) {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid or expired API key",
+		})
+		return r, false
+	}
+
+	// Pengelolaan akun, sesi, API key, dan admin hanya lewat login interaktif
+	path := r.URL.Path
+	if strings.HasPrefix(path, "/api/keys") || strings.HasPrefix(path, "/api/admin/") ||
+		(strings.HasPrefix(path, "/auth/") && !(r.Method == http.MethodGet && path == "/auth/profile")) {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "API keys cannot access this endpoint",
+		})
+		return r, false
+	}
+	if scope := requiredScope(r); !containsString(key.Scopes, scope) {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "API key is missing the " + scope + " scope",
+		})
+		return r, false
+	}
+	if len(key.ProjectIDs) > 0 && r.Method == http.MethodPost && path == "/api/project" {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "API keys limited to specific projects cannot create projects",
+		})
+		return r, false
+	}
+
+	// Catat pemakaian terakhir tanpa menulis ke database di setiap request beruntun
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyLastUsedWindow {
+		atdb.UpdateOneDoc(mongoDB, "api_keys", bson.M{"_id": key.ID}, bson.M{
+			"last_used_at": now,
+			"last_used_ip": at.GetClientIP(r),
+		})
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)), true
+}
+
+// requiredScope menentukan scope API key untuk request: export untuk endpoint export,
+// read untuk GET/HEAD, dan write untuk method lainnya
+func requiredScope(r *http.Request) string {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/export/"):
+		return scopeExport
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return scopeRead
+	default:
+		return scopeWrite
+	}
+}
+
+// apiKeyFromRequest mengembalikan API key yang sudah diverifikasi AuthenticateAPIKey untuk request ini
+func apiKeyFromRequest(r *http.Request) (model.APIKey, bool) {
+	key, ok := r.Context().Value(apiKeyContextKey{}).(model.APIKey)
+	return key, ok
+}
+
+// apiKeyAllowsProject mengecek batas project API key; request tanpa API key selalu diizinkan
+func apiKeyAllowsProject(r *http.Request, projectID primitive.ObjectID) bool {
+	key, ok := apiKeyFromRequest(r)
+	return !ok || len(key.ProjectIDs) == 0 || containsObjectID(key.ProjectIDs, projectID)
+}
+
+// filterAPIKeyProjects menyisakan project yang diizinkan API key pada request
+func filterAPIKeyProjects(r *http.Request, projects []model.Project) []model.Project {
+	if _, ok := apiKeyFromRequest(r); !ok {
+		return projects
+	}
+	allowed := []model.Project{}
+	for _, p := range projects {
+		if apiKeyAllowsProject(r, p.ID) {
+			allowed = append(allowed, p)
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Aksi audit log, berformat "<resource>.<aksi>"
+const (
+	auditLogin                  = "auth.login"
+	auditLoginFailed            = "auth.login_failed"
+	auditLogout                 = "auth.logout"
+	auditLogoutAll              = "auth.logout_all"
+	auditRegister               = "auth.register"
+	auditPasswordReset          = "auth.password_reset"
+	auditTwoFactorEnable        = "auth.two_factor_enable"
+	auditTwoFactorDisable       = "auth.two_factor_disable"
+	auditTwoFactorFailed        = "auth.two_factor_failed"
+	auditTwoFactorRecovery      = "auth.two_factor_recovery_used"
+	auditTwoFactorRecoveryReset = "auth.two_factor_recovery_reset"
+	auditProjectCreate          = "project.create"
+	auditProjectUpdate          = "project.update"
+	auditProjectDelete          = "project.delete"
+	auditUploadCreate           = "upload.create"
+	auditUploadDelete           = "upload.delete"
+	auditAnalysisCreate         = "analysis.create"
+	auditAnalysisProcess        = "analysis.process"
+	auditAnalysisRefine         = "analysis.refine"
+	auditAnalysisUpdate         = "analysis.update"
+	auditAnalysisDelete         = "analysis.delete"
+	auditAnalysisReview         = "analysis.review"
+	auditAnalysisExport         = "analysis.export"
+	auditMemberInvite           = "member.invite"
+	auditMemberUpdate           = "member.update"
+	auditMemberRemove           = "member.remove"
+	auditMemberAccept           = "member.accept"
+	auditMemberDecline          = "member.decline"
+	auditInstitutionCreate      = "institution.create"
+	auditInstitutionUpdate      = "institution.update"
+	auditInstitutionDelete      = "institution.delete"
+	auditInstitutionUserAdd     = "institution.user_add"
+	auditInstitutionUserUpdate  = "institution.user_update"
+	auditInstitutionUserRemove  = "institution.user_remove"
+	auditAPIKeyCreate           = "apikey.create"
+	auditAPIKeyRevoke           = "apikey.revoke"
+)
+
+// Batas halaman daftar audit log
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+)
+
+// auditPruneInterval membatasi seberapa sering audit log kedaluwarsa dihapus
+const auditPruneInterval = time.Hour
+
+var (
+	auditPruneMu sync.Mutex
+	auditPruned  time.Time
+)
+
+// GetAuditLogs handler untuk audit log, terbaru lebih dulu. Pengguna melihat aktivitasnya sendiri, admin institusi
+// melihat aktivitas anggota institusinya, dan admin platform melihat semuanya. Filter: user_id, action, resource,
+// resource_id, from, to (RFC3339); halaman lewat page dan limit.
+func GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return
+	}
+
+	// Cakupan pengguna yang boleh dilihat; nil berarti semua
+	var scope []primitive.ObjectID
+	switch {
+	case isPlatformAdmin(user):
+	case user.InstitutionRole == institutionAdmin && !user.InstitutionID.IsZero():
+		members, _ := atdb.GetAllDoc[model.User](mongoDB, "users", bson.M{"institution_id": user.InstitutionID})
+		scope = []primitive.ObjectID{user.ID}
+		for _, m := range members {
+			scope = append(scope, m.ID)
+		}
+	default:
+		scope = []primitive.ObjectID{user.ID}
+	}
+
+	query := r.URL.Query()
+	filter := bson.M{}
+	if raw := query.Get("user_id"); raw != "" {
+		requested, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid user ID",
+			})
+			return
+		}
+		if scope != nil && !containsObjectID(scope, requested) {
+			at.WriteJSON(w, http.StatusForbidden, model.Response{
+				Status:  "error",
+				Message: "You can only view activity you have access to",
+			})
+			return
+		}
+		filter["user_id"] = requested
+	} else if scope != nil {
+		filter["user_id"] = bson.M{"$in": scope}
+	}
+	for _, key := range []string{"action", "resource"} {
+		if value := query.Get(key); value != "" {
+			filter[key] = value
+		}
+	}
+	if raw := query.Get("resource_id"); raw != "" {
+		resourceID, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid resource ID",
+			})
+			return
+		}
+		filter["resource_id"] = resourceID
+	}
+	window := bson.M{}
+	for key, op := range map[string]string{"from": "$gte", "to": "$lt"} {
+		raw := query.Get(key)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: key + " must be an RFC3339 timestamp",
+			})
+			return
+		}
+		window[op] = t
+	}
+	if len(window) > 0 {
+		filter["created_at"] = window
+	}
+
+	page, limit := 1, defaultAuditLimit
+	if n, err := strconv.Atoi(query.Get("page")); err == nil && n > 0 {
+		page = n
+	}
+	if n, err := strconv.Atoi(query.Get("limit")); err == nil && n > 0 {
+		limit = min(n, maxAuditLimit)
+	}
+
+	pruneAuditLogs(mongoDB)
+	logs, err := atdb.GetPageDoc[model.AuditLog](mongoDB, "audit_logs", filter, bson.D{{Key: "created_at", Value: -1}}, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve audit logs",
+		})
+		return
+	}
+	total, _ := atdb.CountDoc(mongoDB, "audit_logs", filter)
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Audit logs retrieved successfully",
+		Data: map[string]interface{}{
+			"logs":  logs,
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// recordAudit menyimpan entry audit log dengan IP dan user agent dari request; kegagalan hanya dicatat
+func recordAudit(r *http.Request, mongoDB *mongo.Database, entry model.AuditLog) {
+	if mongoDB == nil {
+		return
+	}
+	entry.IP = at.GetClientIP(r)
+	entry.UserAgent = r.UserAgent()
+	entry.CreatedAt = time.Now()
+	if key, ok := apiKeyFromRequest(r); ok {
+		entry.Details = strings.TrimSpace(entry.Details + " (via API key " + key.Prefix + ")")
+	}
+	if _, err := atdb.InsertOneDoc(mongoDB, "audit_logs", entry); err != nil {
+		log.Printf("WARNING: Failed to record audit event %s for user %s: %v", entry.Action, entry.UserID.Hex(), err)
+	}
+	pruneAuditLogs(mongoDB)
+}
+
+// pruneAuditLogs menghapus audit log yang lebih tua dari AUDIT_RETENTION, paling sering sekali per auditPruneInterval
+func pruneAuditLogs(mongoDB *mongo.Database) {
+	auditPruneMu.Lock()
+	if time.Since(auditPruned) < auditPruneInterval {
+		auditPruneMu.Unlock()
+		return
+	}
+	auditPruned = time.Now()
+	auditPruneMu.Unlock()
+
+	cutoff := time.Now().Add(-config.GetConfig().App.AuditRetention)
+	if _, err := atdb.DeleteManyDoc(mongoDB, "audit_logs", bson.M{"created_at": bson.M{"$lt": cutoff}}); err != nil {
+		log.Printf("WARNING: Failed to prune audit logs: %v", err)
+	}
+}
+
+// truncate memotong s menjadi maksimal n rune agar input pengguna tidak membengkakkan audit log
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
+
+// containsObjectID mengecek apakah id ada di list
+func containsObjectID(list []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, item := range list {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
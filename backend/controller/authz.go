@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Peran kolaborator proyek. Reviewer (pembimbing) berhak akses seperti viewer
+// dan satu-satunya peran yang dapat menyetujui atau meminta perbaikan analysis.
+const (
+	roleViewer   = "viewer"
+	roleReviewer = "reviewer"
+	roleEditor   = "editor"
+	roleOwner    = "owner"
+)
+
+// Status keanggotaan proyek
+const (
+	memberPending = "pending"
+	memberActive  = "active"
+)
+
+// roleRank mengurutkan peran; peran lebih tinggi mencakup izin peran di bawahnya
+var roleRank = map[string]int{
+	roleViewer:   1,
+	roleReviewer: 1,
+	roleEditor:   2,
+	roleOwner:    3,
+}
+
+var (
+	errProjectNotFound  = errors.New("project not found")
+	errProjectForbidden = errors.New("insufficient project role")
+)
+
+// projectRole mengembalikan peran userID pada project, atau string kosong jika bukan anggota
+func projectRole(mongoDB *mongo.Database, project model.Project, userID primitive.ObjectID) string {
+	if project.UserID == userID {
+		return roleOwner
+	}
+	member, err := atdb.GetOneDoc[model.ProjectMember](mongoDB, "project_members", bson.M{
+		"project_id": project.ID,
+		"user_id":    userID,
+		"status":     memberActive,
+	})
+	if err != nil {
+		return ""
+	}
+	return member.Role
+}
+
+// authorizeProject memuat project dan memastikan userID minimal berperan minRole.
+// Non-anggota mendapat errProjectNotFound agar keberadaan project tidak bocor.
+func authorizeProject(mongoDB *mongo.Database, projectID, userID primitive.ObjectID, minRole string) (model.Project, error) {
+	project, err := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID})
+	if err != nil {
+		return model.Project{}, errProjectNotFound
+	}
+	role := projectRole(mongoDB, project, userID)
+	if role == "" {
+		return model.Project{}, errProjectNotFound
+	}
+	project.Role = role
+	if roleRank[role] < roleRank[minRole] {
+		return project, errProjectForbidden
+	}
+	return project, nil
+}
+
+// requireProject menjalankan authorizeProject dan menulis respons error jika akses ditolak.
+// Project di luar batas API key pada request diperlakukan seperti tidak ada.
+func requireProject(w http.ResponseWriter, r *http.Request, mongoDB *mongo.Database, projectID, userID primitive.ObjectID, minRole string) (model.Project, bool) {
+	project, err := authorizeProject(mongoDB, projectID, userID, minRole)
+	if err == nil && !apiKeyAllowsProject(r, projectID) {
+		err = errProjectNotFound
+	}
+	if err == nil {
+		return project, true
+	}
+	if errors.Is(err, errProjectForbidden) {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Your role on this project does not allow this action",
+		})
+		return project, false
+	}
+	at.WriteJSON(w, http.StatusNotFound, model.Response{
+		Status:  "error",
+		Message: "Project not found",
+	})
+	return project, false
+}
+
+// accessibleProjects mengembalikan semua project milik userID atau tempat userID menjadi anggota aktif
+func accessibleProjects(mongoDB *mongo.Database, userID primitive.ObjectID) ([]model.Project, error) {
+	members, err := atdb.GetAllDoc[model.ProjectMember](mongoDB, "project_members", bson.M{"user_id": userID, "status": memberActive})
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[primitive.ObjectID]string, len(members))
+	ids := make([]primitive.ObjectID, 0, len(members))
+	for _, m := range members {
+		roles[m.ProjectID] = m.Role
+		ids = append(ids, m.ProjectID)
+	}
+
+	projects, err := atdb.GetAllDoc[model.Project](mongoDB, "projects", bson.M{"$or": []bson.M{
+		{"user_id": userID},
+		{"_id": bson.M{"$in": ids}},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for i := range projects {
+		if projects[i].UserID == userID {
+			projects[i].Role = roleOwner
+		} else {
+			projects[i].Role = roles[projects[i].ID]
+		}
+	}
+	return projects, nil
+}
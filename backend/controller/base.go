@@ -1,15 +1,23 @@
 package controller
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"path"
 	"time"
 
 	"github.com/research-data-analysis/config"
 	"github.com/research-data-analysis/helper/at"
 	"github.com/research-data-analysis/helper/atdb"
-	"github.com/research-data-analysis/helper/watoken"
+	"github.com/research-data-analysis/helper/dataset"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/password"
+	"github.com/research-data-analysis/helper/storage"
 	"github.com/research-data-analysis/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -44,8 +52,8 @@ func GetAllProjects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get all projects for this user
-	projects, err := atdb.GetAllDoc[model.Project](mongoDB, "projects", bson.M{"user_id": userID})
+	// Project milik user beserta project tempat user menjadi kolaborator
+	projects, err := accessibleProjects(mongoDB, userID)
 	if err != nil {
 		Response(w, http.StatusInternalServerError, model.Response{
 			Status:  "error",
@@ -53,6 +61,7 @@ func GetAllProjects(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	projects = filterAPIKeyProjects(r, projects)
 
 	Response(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -94,12 +103,8 @@ func GetProject(w http.ResponseWriter, r *http.Request, projectIDStr string) {
 	}
 
 	// Get the project
-	project, err := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusNotFound, model.Response{
-			Status:  "error",
-			Message: "Project not found",
-		})
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer)
+	if !ok {
 		return
 	}
 
@@ -142,6 +147,14 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := checkQuota(mongoDB, userID, quotaProjects, 0); err != nil {
+		Response(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	// Create new project
 	newProject := model.Project{
 		UserID:       userID,
@@ -167,6 +180,8 @@ func CreateProject(w http.ResponseWriter, r *http.Request) {
 
 	// Set the ID for response
 	newProject.ID = projectID
+	newProject.Role = roleOwner
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditProjectCreate, Resource: "project", ResourceID: projectID, Details: newProject.Title})
 
 	Response(w, http.StatusCreated, model.Response{
 		Status:  "success",
@@ -217,13 +232,9 @@ func UpdateProject(w http.ResponseWriter, r *http.Request, projectIDStr string)
 		return
 	}
 
-	// Check if project exists and belongs to user
-	_, err = atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusNotFound, model.Response{
-			Status:  "error",
-			Message: "Project not found",
-		})
+	// Editor boleh mengubah project
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleEditor)
+	if !ok {
 		return
 	}
 
@@ -246,8 +257,11 @@ func UpdateProject(w http.ResponseWriter, r *http.Request, projectIDStr string)
 		return
 	}
 
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditProjectUpdate, Resource: "project", ResourceID: projectID, Details: projectReq.Title})
+
 	// Get updated project
 	updatedProject, _ := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID})
+	updatedProject.Role = project.Role
 
 	Response(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -288,13 +302,8 @@ func DeleteProject(w http.ResponseWriter, r *http.Request, projectIDStr string)
 		return
 	}
 
-	// Check if project exists and belongs to user
-	_, err = atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusNotFound, model.Response{
-			Status:  "error",
-			Message: "Project not found",
-		})
+	// Hanya owner yang boleh menghapus project
+	if _, ok := requireProject(w, r, mongoDB, projectID, userID, roleOwner); !ok {
 		return
 	}
 
@@ -308,6 +317,15 @@ func DeleteProject(w http.ResponseWriter, r *http.Request, projectIDStr string)
 		return
 	}
 
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditProjectDelete, Resource: "project", ResourceID: projectID})
+
+	// Keanggotaan, undangan, dan komentar ikut dihapus bersama project
+	for _, collection := range []string{"project_members", "comments"} {
+		if _, err := atdb.DeleteManyDoc(mongoDB, collection, bson.M{"project_id": projectID}); err != nil {
+			log.Printf("WARNING: Failed to delete %s of project %s: %v", collection, projectIDStr, err)
+		}
+	}
+
 	Response(w, http.StatusOK, model.Response{
 		Status:  "success",
 		Message: "Project deleted successfully",
@@ -379,8 +397,27 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Hash password (in production, use proper password hashing)
-	hashedPassword := registerReq.Password // In production, hash this
+	language := i18n.Default
+	if registerReq.Language != "" {
+		if language = i18n.Normalize(registerReq.Language); language == "" {
+			Response(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Unsupported language. Supported: id, en",
+			})
+			return
+		}
+	}
+
+	// Hash password dengan parameter dari konfigurasi auth
+	hashedPassword, err := password.Hash(registerReq.Password, password.FromConfig(config.GetConfig().Auth))
+	if err != nil {
+		log.Printf("WARNING: Failed to hash password: %v", err)
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to register user",
+		})
+		return
+	}
 
 	// Create new user
 	newUser := model.User{
@@ -389,6 +426,7 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		FullName:      registerReq.FullName,
 		Institution:   registerReq.Institution,
 		ResearchField: registerReq.ResearchField,
+		Language:      language,
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
@@ -405,20 +443,15 @@ func Register(w http.ResponseWriter, r *http.Request) {
 
 	// Set the ID for response
 	newUser.ID = userID
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditRegister, Resource: "user", ResourceID: userID})
 
-	// Generate PASETO token
-	privateKey := config.GetConfig().Auth.PrivateKey
-
-	// Debug log for development
-	fmt.Printf("Generating token with private key length: %d\n", len(privateKey))
-	fmt.Printf("User ID: %s, User Name: %s\n", newUser.ID.Hex(), newUser.FullName)
+	// Kirim email verifikasi; kegagalan tidak membatalkan registrasi karena bisa dikirim ulang
+	if err := sendAccountEmail(r.Context(), mongoDB, newUser, tokenVerifyEmail, newUser.Language); err != nil {
+		log.Printf("WARNING: Failed to send verification email to user %s: %v", newUser.ID.Hex(), err)
+	}
 
-	token, err := watoken.EncodeforHours(
-		newUser.ID.Hex(),
-		newUser.FullName,
-		privateKey,
-		24, // 24 hours expiration
-	)
+	// Buat sesi login: access token berumur pendek dan refresh token
+	tokens, err := issueSession(mongoDB, r, newUser)
 	if err != nil {
 		fmt.Printf("Token generation error: %v\n", err)
 		Response(w, http.StatusInternalServerError, model.Response{
@@ -428,8 +461,6 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Printf("Token generated successfully\n")
-
 	// Remove password from response
 	newUser.Password = ""
 
@@ -437,8 +468,11 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		Status:  "success",
 		Message: "User registered successfully",
 		Data: map[string]interface{}{
-			"user":  newUser,
-			"token": token,
+			"user":               newUser,
+			"token":              tokens.AccessToken,
+			"refresh_token":      tokens.RefreshToken,
+			"expires_in":         tokens.ExpiresIn,
+			"refresh_expires_at": tokens.RefreshExpiresAt,
 		},
 	})
 }
@@ -468,6 +502,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 	// Find user by email
 	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"email": loginReq.Email})
 	if err != nil || user.Email == "" {
+		recordAudit(r, mongoDB, model.AuditLog{Action: auditLoginFailed, Resource: "user", Details: "unknown email " + truncate(loginReq.Email, 254)})
 		Response(w, http.StatusUnauthorized, model.Response{
 			Status:  "error",
 			Message: "Invalid email or password",
@@ -475,8 +510,20 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check password (in production, use proper password verification)
-	if user.Password != loginReq.Password { // In production, compare hashed passwords
+	// Verifikasi password; record lama yang masih plaintext dicek sekali lalu di-hash ulang
+	params := password.FromConfig(config.GetConfig().Auth)
+	hashed := password.IsHashed(user.Password)
+	valid := false
+	if hashed {
+		valid, err = password.Verify(loginReq.Password, user.Password)
+		if err != nil {
+			log.Printf("WARNING: Failed to verify password for user %s: %v", user.ID.Hex(), err)
+		}
+	} else {
+		valid = password.VerifyLegacy(loginReq.Password, user.Password)
+	}
+	if !valid {
+		recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditLoginFailed, Resource: "user", ResourceID: user.ID, Details: "wrong password"})
 		Response(w, http.StatusUnauthorized, model.Response{
 			Status:  "error",
 			Message: "Invalid email or password",
@@ -484,19 +531,44 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Generate PASETO token
-	privateKey := config.GetConfig().Auth.PrivateKey
+	if !hashed || password.NeedsRehash(user.Password, params) {
+		if rehashed, err := password.Hash(loginReq.Password, params); err != nil {
+			log.Printf("WARNING: Failed to rehash password for user %s: %v", user.ID.Hex(), err)
+		} else if _, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID}, bson.M{"password": rehashed, "updated_at": time.Now()}); err != nil {
+			log.Printf("WARNING: Failed to store rehashed password for user %s: %v", user.ID.Hex(), err)
+		}
+	}
 
-	// Debug log for development
-	fmt.Printf("Login - Generating token with private key length: %d\n", len(privateKey))
-	fmt.Printf("Login - User ID: %s, User Name: %s\n", user.ID.Hex(), user.FullName)
+	// Two-factor authentication: sesi baru dibuat setelah code diverifikasi (atau 2FA didaftarkan jika diwajibkan institusi)
+	if step := twoFactorStep(mongoDB, user); step != "" {
+		mfaToken, err := issueUserToken(mongoDB, user, step, twoFactorTokenTTL)
+		if err != nil {
+			log.Printf("WARNING: Failed to issue two-factor token for user %s: %v", user.ID.Hex(), err)
+			Response(w, http.StatusInternalServerError, model.Response{
+				Status:  "error",
+				Message: "Failed to generate token",
+			})
+			return
+		}
+		message := "Two-factor authentication code required"
+		if step == tokenTwoFactorSetup {
+			message = "Your institution requires two-factor authentication; set it up to continue"
+		}
+		Response(w, http.StatusOK, model.Response{
+			Status:  "success",
+			Message: message,
+			Data: map[string]interface{}{
+				"two_factor_required":       step == tokenTwoFactorLogin,
+				"two_factor_setup_required": step == tokenTwoFactorSetup,
+				"mfa_token":                 mfaToken,
+				"expires_in":                int(twoFactorTokenTTL.Seconds()),
+			},
+		})
+		return
+	}
 
-	token, err := watoken.EncodeforHours(
-		user.ID.Hex(),
-		user.FullName,
-		privateKey,
-		24, // 24 hours expiration
-	)
+	// Buat sesi login: access token berumur pendek dan refresh token
+	tokens, err := issueSession(mongoDB, r, user)
 	if err != nil {
 		fmt.Printf("Login - Token generation error: %v\n", err)
 		Response(w, http.StatusInternalServerError, model.Response{
@@ -506,7 +578,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fmt.Printf("Login - Token generated successfully\n")
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditLogin, Resource: "user", ResourceID: user.ID})
 
 	// Remove password from response
 	user.Password = ""
@@ -515,8 +587,11 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		Status:  "success",
 		Message: "User logged in successfully",
 		Data: map[string]interface{}{
-			"token": token,
-			"user":  user,
+			"token":              tokens.AccessToken,
+			"refresh_token":      tokens.RefreshToken,
+			"expires_in":         tokens.ExpiresIn,
+			"refresh_expires_at": tokens.RefreshExpiresAt,
+			"user":               user,
 		},
 	})
 }
@@ -563,6 +638,97 @@ func GetProfile(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// UpdateProfile handler untuk memperbarui profil pengguna, termasuk preferensi bahasa
+func UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from token
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	var profileReq model.ProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&profileReq); err != nil {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+
+	update := bson.M{"updated_at": time.Now()}
+	if profileReq.FullName != "" {
+		update["full_name"] = profileReq.FullName
+	}
+	if profileReq.Institution != "" {
+		update["institution"] = profileReq.Institution
+	}
+	if profileReq.ResearchField != "" {
+		update["research_field"] = profileReq.ResearchField
+	}
+	if profileReq.Language != "" {
+		language := i18n.Normalize(profileReq.Language)
+		if language == "" {
+			Response(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Unsupported language. Supported: id, en",
+			})
+			return
+		}
+		update["language"] = language
+	}
+
+	// Get MongoDB connection
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	// Nama institusi anggota tenant mengikuti institusinya dan hanya diubah lewat admin
+	if profileReq.Institution != "" {
+		if user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID}); err == nil && !user.InstitutionID.IsZero() && profileReq.Institution != user.Institution {
+			Response(w, http.StatusForbidden, model.Response{
+				Status:  "error",
+				Message: "Institution is managed by your institution admin",
+			})
+			return
+		}
+	}
+
+	if _, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": userID}, update); err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to update profile",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		Response(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+
+	// Remove password from response
+	user.Password = ""
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Profile updated successfully",
+		Data:    user,
+	})
+}
+
 // UploadData handler untuk file upload
 func UploadData(w http.ResponseWriter, r *http.Request, projectIDStr string) {
 	// Get user ID from token
@@ -595,13 +761,9 @@ func UploadData(w http.ResponseWriter, r *http.Request, projectIDStr string) {
 		return
 	}
 
-	// Check if project exists and belongs to user
-	_, err = atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": projectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusNotFound, model.Response{
-			Status:  "error",
-			Message: "Project not found",
-		})
+	// Editor boleh mengunggah data
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleEditor)
+	if !ok {
 		return
 	}
 
@@ -624,29 +786,64 @@ func UploadData(w http.ResponseWriter, r *http.Request, projectIDStr string) {
 	}
 	defer file.Close()
 
-	// In production, you would:
-	// 1. Save the file to Google Cloud Storage
-	// 2. Process the file to extract data summary
-	// 3. Store the file metadata in MongoDB
+	// Kuota storage dihitung untuk pemilik project, bukan kolaborator yang mengunggah
+	if err := checkQuota(mongoDB, project.UserID, quotaStorage, handler.Size); err != nil {
+		Response(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Baca dan parse file untuk menghasilkan ringkasan data (tipe kolom, skala, level, statistik)
+	content, err := io.ReadAll(file)
+	if err != nil {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Failed to read uploaded file",
+		})
+		return
+	}
+
+	parsed, err := dataset.Parse(handler.Filename, content)
+	if err != nil {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Failed to parse data file: " + err.Error(),
+		})
+		return
+	}
 
-	// For now, create a placeholder upload record
 	newUpload := model.Upload{
-		ProjectID:  projectID,
-		FileName:   handler.Filename,
-		FileType:   handler.Header.Get("Content-Type"),
-		FileSize:   handler.Size,
-		StorageURL: "placeholder-storage-url", // In production, actual GCS URL
-		DataSummary: model.DataSummary{
-			Rows:        100,                                              // Placeholder
-			Columns:     5,                                                // Placeholder
-			ColumnNames: []string{"col1", "col2", "col3", "col4", "col5"}, // Placeholder
-		},
-		UploadedAt: time.Now(),
+		ProjectID:   projectID,
+		FileName:    handler.Filename,
+		FileType:    handler.Header.Get("Content-Type"),
+		FileSize:    handler.Size,
+		DataSummary: dataset.Summarize(parsed),
+		UploadedAt:  time.Now(),
 	}
 
+	// Simpan file mentah agar analisis dapat dihitung ulang dari data aslinya
+	objectName := fmt.Sprintf("uploads/%s/%d_%s", projectID.Hex(), newUpload.UploadedAt.Unix(), path.Base(handler.Filename))
+	storageURL, err := storage.UploadFile(r.Context(), objectName, bytes.NewReader(content), newUpload.FileType)
+	if err != nil {
+		// Upload tanpa file mentah tidak bisa dianalisis, jadi tidak disimpan
+		log.Printf("WARNING: failed to store uploaded file %s: %v", objectName, err)
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to store uploaded file",
+		})
+		return
+	}
+	newUpload.StorageURL = storageURL
+	newUpload.StoragePath = objectName
+
 	// Insert upload record into database
 	uploadID, err := atdb.InsertOneDoc(mongoDB, "uploads", newUpload)
 	if err != nil {
+		if err := storage.DeleteFile(r.Context(), objectName); err != nil {
+			log.Printf("WARNING: failed to delete orphaned upload %s: %v", objectName, err)
+		}
 		Response(w, http.StatusInternalServerError, model.Response{
 			Status:  "error",
 			Message: "Failed to save upload record",
@@ -656,6 +853,7 @@ func UploadData(w http.ResponseWriter, r *http.Request, projectIDStr string) {
 
 	// Set the ID for response
 	newUpload.ID = uploadID
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditUploadCreate, Resource: "upload", ResourceID: uploadID, Details: fmt.Sprintf("%s (%d bytes) in project %s", newUpload.FileName, newUpload.FileSize, projectIDStr)})
 
 	Response(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -706,13 +904,8 @@ func GetDataPreview(w http.ResponseWriter, r *http.Request, uploadIDStr string)
 		return
 	}
 
-	// Check if user has access to this upload (through project ownership)
-	_, err = atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": upload.ProjectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusForbidden, model.Response{
-			Status:  "error",
-			Message: "Access denied",
-		})
+	// Check if user has access to this upload (through project membership)
+	if _, ok := requireProject(w, r, mongoDB, upload.ProjectID, userID, roleViewer); !ok {
 		return
 	}
 
@@ -779,13 +972,8 @@ func GetDataStats(w http.ResponseWriter, r *http.Request, uploadIDStr string) {
 		return
 	}
 
-	// Check if user has access to this upload (through project ownership)
-	_, err = atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": upload.ProjectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusForbidden, model.Response{
-			Status:  "error",
-			Message: "Access denied",
-		})
+	// Check if user has access to this upload (through project membership)
+	if _, ok := requireProject(w, r, mongoDB, upload.ProjectID, userID, roleViewer); !ok {
 		return
 	}
 
@@ -839,8 +1027,8 @@ func GetUploads(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get all projects for this user
-	projects, err := atdb.GetAllDoc[model.Project](mongoDB, "projects", bson.M{"user_id": userID})
+	// Project milik user beserta project tempat user menjadi kolaborator
+	projects, err := accessibleProjects(mongoDB, userID)
 	if err != nil {
 		Response(w, http.StatusInternalServerError, model.Response{
 			Status:  "error",
@@ -848,6 +1036,7 @@ func GetUploads(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	projects = filterAPIKeyProjects(r, projects)
 
 	// Get project IDs
 	var projectIDs []primitive.ObjectID
@@ -931,13 +1120,8 @@ func GetUpload(w http.ResponseWriter, r *http.Request, uploadIDStr string) {
 		return
 	}
 
-	// Check if user has access to this upload (through project ownership)
-	_, err = atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": upload.ProjectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusForbidden, model.Response{
-			Status:  "error",
-			Message: "Access denied",
-		})
+	// Check if user has access to this upload (through project membership)
+	if _, ok := requireProject(w, r, mongoDB, upload.ProjectID, userID, roleViewer); !ok {
 		return
 	}
 
@@ -990,17 +1174,17 @@ func DeleteUpload(w http.ResponseWriter, r *http.Request, uploadIDStr string) {
 		return
 	}
 
-	// Check if user has access to this upload (through project ownership)
-	_, err = atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": upload.ProjectID, "user_id": userID})
-	if err != nil {
-		Response(w, http.StatusForbidden, model.Response{
-			Status:  "error",
-			Message: "Access denied",
-		})
+	// Editor boleh menghapus upload
+	if _, ok := requireProject(w, r, mongoDB, upload.ProjectID, userID, roleEditor); !ok {
 		return
 	}
 
-	// In production, you would also delete the file from Google Cloud Storage
+	// Hapus file dari storage; kegagalan hanya dicatat agar record tetap bisa dihapus
+	if upload.StoragePath != "" {
+		if err := storage.DeleteFile(r.Context(), upload.StoragePath); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("WARNING: Failed to delete %s from storage: %v", upload.StoragePath, err)
+		}
+	}
 
 	// Delete upload record from database
 	_, err = atdb.DeleteOneDoc(mongoDB, "uploads", bson.M{"_id": uploadID})
@@ -1011,6 +1195,7 @@ func DeleteUpload(w http.ResponseWriter, r *http.Request, uploadIDStr string) {
 		})
 		return
 	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditUploadDelete, Resource: "upload", ResourceID: uploadID, Details: upload.FileName})
 
 	Response(w, http.StatusOK, model.Response{
 		Status:  "success",
@@ -0,0 +1,435 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxCommentLength membatasi panjang isi komentar
+const maxCommentLength = 5000
+
+// GetComments handler untuk komentar project dalam bentuk thread.
+// Query analysis_id dan method membatasi ke komentar pada analysis atau hasil metode tertentu.
+func GetComments(w http.ResponseWriter, r *http.Request, projectIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+
+	filter := bson.M{"project_id": projectID}
+	if v := r.URL.Query().Get("analysis_id"); v != "" {
+		analysisID, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid analysis ID",
+			})
+			return
+		}
+		filter["analysis_id"] = analysisID
+	}
+	if v := r.URL.Query().Get("method"); v != "" {
+		filter["method"] = v
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	if _, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer); !ok {
+		return
+	}
+
+	comments, err := atdb.GetAllDocWithSort[model.Comment](mongoDB, "comments", filter, bson.D{{Key: "created_at", Value: 1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve comments",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Comments retrieved successfully",
+		Data:    commentThreads(comments),
+	})
+}
+
+// CreateComment handler untuk menambah komentar atau balasan; semua anggota project boleh berkomentar
+func CreateComment(w http.ResponseWriter, r *http.Request, projectIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+
+	var req model.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	body, ok := validCommentBody(w, req.Body)
+	if !ok {
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer)
+	if !ok {
+		return
+	}
+
+	comment := model.Comment{
+		ProjectID: projectID,
+		UserID:    userID,
+		Body:      body,
+	}
+
+	// Balasan mewarisi jangkar komentar induknya
+	var parent model.Comment
+	if req.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(req.ParentID)
+		if err == nil {
+			parent, err = atdb.GetOneDoc[model.Comment](mongoDB, "comments", bson.M{"_id": parentID, "project_id": projectID})
+		}
+		if err != nil {
+			at.WriteJSON(w, http.StatusNotFound, model.Response{
+				Status:  "error",
+				Message: "Parent comment not found",
+			})
+			return
+		}
+		comment.ParentID = parent.ID
+		comment.AnalysisID = parent.AnalysisID
+		comment.Method = parent.Method
+	} else if req.AnalysisID != "" {
+		analysisID, err := primitive.ObjectIDFromHex(req.AnalysisID)
+		var analysis model.Analysis
+		if err == nil {
+			analysis, err = atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": analysisID, "project_id": projectID})
+		}
+		if err != nil {
+			at.WriteJSON(w, http.StatusNotFound, model.Response{
+				Status:  "error",
+				Message: "Analysis not found",
+			})
+			return
+		}
+		comment.AnalysisID = analysis.ID
+		if req.Method != "" {
+			method, found := resultMethodKey(analysis, req.Method)
+			if !found {
+				at.WriteJSON(w, http.StatusBadRequest, model.Response{
+					Status:  "error",
+					Message: "Method result not found in analysis",
+				})
+				return
+			}
+			comment.Method = method
+		}
+	} else if req.Method != "" {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "analysis_id is required when commenting on a method result",
+		})
+		return
+	}
+
+	if user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID}); err == nil {
+		comment.AuthorName = user.FullName
+	}
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+
+	commentID, err := atdb.InsertOneDoc(mongoDB, "comments", comment)
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to create comment",
+		})
+		return
+	}
+	comment.ID = commentID
+
+	// Penulis komentar induk mendapat notifikasi balasan; komentar reviewer
+	// juga diberitahukan ke pengaju analysis (atau pembuat project)
+	n := model.Notification{
+		ActorID:    userID,
+		ProjectID:  projectID,
+		AnalysisID: comment.AnalysisID,
+		CommentID:  commentID,
+	}
+	if !parent.UserID.IsZero() {
+		n.Type = notifyCommentReply
+		notify(r.Context(), mongoDB, []primitive.ObjectID{parent.UserID}, n, project, "")
+	}
+	if project.Role == roleReviewer {
+		target := project.UserID
+		if !comment.AnalysisID.IsZero() {
+			if analysis, err := atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": comment.AnalysisID}); err == nil {
+				if submitter := lastSubmitter(analysis.ReviewHistory); !submitter.IsZero() {
+					target = submitter
+				}
+			}
+		}
+		if target != parent.UserID {
+			n.Type = notifyComment
+			notify(r.Context(), mongoDB, []primitive.ObjectID{target}, n, project, "")
+		}
+	}
+
+	at.WriteJSON(w, http.StatusCreated, model.Response{
+		Status:  "success",
+		Message: "Comment created successfully",
+		Data:    comment,
+	})
+}
+
+// UpdateComment handler untuk mengubah isi komentar sendiri
+func UpdateComment(w http.ResponseWriter, r *http.Request, commentIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	commentID, err := primitive.ObjectIDFromHex(commentIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid comment ID",
+		})
+		return
+	}
+
+	var req model.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	body, ok := validCommentBody(w, req.Body)
+	if !ok {
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	comment, err := atdb.GetOneDoc[model.Comment](mongoDB, "comments", bson.M{"_id": commentID, "deleted_at": nil})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Comment not found",
+		})
+		return
+	}
+	// Anggota yang sudah dikeluarkan tidak dapat mengubah komentar lamanya
+	if _, ok := requireProject(w, r, mongoDB, comment.ProjectID, userID, roleViewer); !ok {
+		return
+	}
+	if comment.UserID != userID {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Only the author can edit this comment",
+		})
+		return
+	}
+
+	comment.Body = body
+	comment.UpdatedAt = time.Now()
+	if _, err := atdb.UpdateOneDoc(mongoDB, "comments", bson.M{"_id": commentID}, bson.M{"body": comment.Body, "updated_at": comment.UpdatedAt}); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to update comment",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Comment updated successfully",
+		Data:    comment,
+	})
+}
+
+// DeleteComment handler untuk menghapus komentar (penulis atau owner project).
+// Isi dikosongkan tanpa menghapus dokumen agar balasan tetap pada thread-nya.
+func DeleteComment(w http.ResponseWriter, r *http.Request, commentIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	commentID, err := primitive.ObjectIDFromHex(commentIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid comment ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	comment, err := atdb.GetOneDoc[model.Comment](mongoDB, "comments", bson.M{"_id": commentID, "deleted_at": nil})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Comment not found",
+		})
+		return
+	}
+	project, ok := requireProject(w, r, mongoDB, comment.ProjectID, userID, roleViewer)
+	if !ok {
+		return
+	}
+	if comment.UserID != userID && project.Role != roleOwner {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Your role on this project does not allow this action",
+		})
+		return
+	}
+
+	now := time.Now()
+	if _, err := atdb.UpdateOneDoc(mongoDB, "comments", bson.M{"_id": commentID}, bson.M{"body": "", "deleted_at": now, "updated_at": now}); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to delete comment",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Comment deleted successfully",
+		Data: map[string]interface{}{
+			"comment_id": commentID,
+			"deleted_at": now,
+		},
+	})
+}
+
+// validCommentBody merapikan isi komentar dan menulis respons error jika kosong atau terlalu panjang
+func validCommentBody(w http.ResponseWriter, body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Comment body is required",
+		})
+		return "", false
+	}
+	if len([]rune(body)) > maxCommentLength {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Comment is too long",
+		})
+		return "", false
+	}
+	return body, true
+}
+
+// resultMethodKey mencari hasil metode berdasarkan ID atau nama dan mengembalikan kunci jangkarnya (ID metode jika ada)
+func resultMethodKey(analysis model.Analysis, method string) (string, bool) {
+	for _, result := range analysis.Results {
+		if result.MethodID == method || strings.EqualFold(result.Method, method) {
+			if result.MethodID != "" {
+				return result.MethodID, true
+			}
+			return result.Method, true
+		}
+	}
+	return "", false
+}
+
+// commentThreads menyusun komentar (urut waktu) menjadi thread; balasan yang induknya tidak ada ditampilkan di akar
+func commentThreads(comments []model.Comment) []*model.Comment {
+	byID := make(map[primitive.ObjectID]*model.Comment, len(comments))
+	for i := range comments {
+		byID[comments[i].ID] = &comments[i]
+	}
+	roots := make([]*model.Comment, 0, len(comments))
+	for i := range comments {
+		c := &comments[i]
+		if parent, ok := byID[c.ParentID]; ok && !c.ParentID.IsZero() {
+			parent.Replies = append(parent.Replies, c)
+			continue
+		}
+		roots = append(roots, c)
+	}
+	return roots
+}
@@ -2,16 +2,30 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"path"
+	"time"
 
+	"github.com/research-data-analysis/config"
 	"github.com/research-data-analysis/helper/at"
 	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/bundle"
+	"github.com/research-data-analysis/helper/dataset"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/report"
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/helper/storage"
 	"github.com/research-data-analysis/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // ExportResults handler untuk mengekspor hasil analisis
@@ -34,8 +48,39 @@ func ExportResults(w http.ResponseWriter, r *http.Request, analysisIDStr string)
 		return
 	}
 
-	// Get export format from query parameter
-	format := r.URL.Query().Get("format")
+	// Format, bagian, dan template dari query parameter; body JSON (POST) menimpa nilai query
+	query := r.URL.Query()
+	req := model.ExportRequest{
+		Format:     query.Get("format"),
+		Language:   query.Get("lang"),
+		TemplateID: query.Get("template"),
+	}
+	if sections := query.Get("sections"); sections != "" {
+		req.Sections = []string{sections}
+	}
+	if r.Method == http.MethodPost && r.ContentLength != 0 {
+		var body model.ExportRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid request body",
+			})
+			return
+		}
+		if body.Format != "" {
+			req.Format = body.Format
+		}
+		if len(body.Sections) > 0 {
+			req.Sections = body.Sections
+		}
+		if body.Language != "" {
+			req.Language = body.Language
+		}
+		if body.TemplateID != "" {
+			req.TemplateID = body.TemplateID
+		}
+	}
+	format := req.Format
 	if format == "" {
 		format = "pdf"
 	}
@@ -58,17 +103,9 @@ func ExportResults(w http.ResponseWriter, r *http.Request, analysisIDStr string)
 		return
 	}
 
-	// Verify ownership
-	project, err := atdb.GetOneDoc[model.Project](
-		mongoDB,
-		"projects",
-		bson.M{"_id": analysis.ProjectID, "user_id": userID},
-	)
-	if err != nil {
-		at.WriteJSON(w, http.StatusForbidden, model.Response{
-			Status:  "error",
-			Message: "Access denied",
-		})
+	// Semua anggota project boleh mengekspor
+	project, ok := requireProject(w, r, mongoDB, analysis.ProjectID, userID, roleViewer)
+	if !ok {
 		return
 	}
 
@@ -80,65 +117,199 @@ func ExportResults(w http.ResponseWriter, r *http.Request, analysisIDStr string)
 		return
 	}
 
+	sel, templateLang, err := exportSelection(mongoDB, project, req)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	lang := i18n.Normalize(req.Language)
+	if lang == "" {
+		lang = templateLang
+	}
+	if lang == "" {
+		lang = reportLanguage(r, mongoDB, userID, analysis)
+	}
+
+	if _, known := reportFormats[format]; known || format == "csv" || format == "json" || format == "bundle" {
+		recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisExport, Resource: "analysis", ResourceID: analysisID, Details: format})
+	}
+
 	switch format {
-	case "pdf":
-		exportPDF(w, project, analysis)
 	case "csv":
-		exportCSV(w, project, analysis)
+		exportCSV(w, project, selectResults(analysis, sel))
 	case "json":
-		exportJSON(w, project, analysis)
+		exportJSON(w, project, selectResults(analysis, sel))
+	case "bundle":
+		exportBundle(w, r.Context(), mongoDB, project, analysis, lang, sel)
 	default:
-		at.WriteJSON(w, http.StatusBadRequest, model.Response{
-			Status:  "error",
-			Message: "Invalid format. Supported: pdf, csv, json",
+		rf, ok := reportFormats[format]
+		if !ok {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid format. Supported: pdf, docx, xlsx, apa, latex, markdown, r, python, spss, scripts, bundle, csv, json",
+			})
+			return
+		}
+
+		doc := report.Build(project, analysis, lang)
+		if !analysis.UploadID.IsZero() {
+			if upload, err := atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": analysis.UploadID}); err == nil {
+				doc.AttachUpload(upload)
+			}
+		}
+		doc.Apply(sel)
+		if rf.figures {
+			loadFigures(r.Context(), &doc)
+		}
+		if rf.figureLinks {
+			linkFigures(r.Context(), &doc)
+		}
+		exportReport(w, doc, rf)
+	}
+}
+
+// exportSelection menentukan bagian laporan: sections eksplisit, template yang dipilih,
+// template default proyek, template bawaan institusi, atau laporan lengkap. Bahasa template dikembalikan bila diisi.
+func exportSelection(mongoDB *mongo.Database, project model.Project, req model.ExportRequest) (report.Selection, string, error) {
+	if len(req.Sections) > 0 {
+		sel, err := report.ParseSections(req.Sections)
+		return sel, "", err
+	}
+
+	var template model.ReportTemplate
+	if req.TemplateID != "" {
+		templateID, err := primitive.ObjectIDFromHex(req.TemplateID)
+		if err != nil {
+			return report.Selection{}, "", errors.New("Invalid template ID")
+		}
+		template, err = atdb.GetOneDoc[model.ReportTemplate](mongoDB, "report_templates", bson.M{"_id": templateID, "project_id": project.ID})
+		if err != nil {
+			return report.Selection{}, "", errors.New("Report template not found")
+		}
+	} else {
+		found, err := atdb.GetOneDoc[model.ReportTemplate](mongoDB, "report_templates", bson.M{"project_id": project.ID, "is_default": true})
+		if err != nil {
+			// Tanpa template default proyek, pakai template laporan bawaan institusi pemilik project
+			sections := institutionDefaults(mongoDB, project.UserID).ReportSections
+			if len(sections) == 0 {
+				return report.Selection{}, "", nil
+			}
+			sel, err := report.ParseSections(sections)
+			return sel, "", err
+		}
+		template = found
+	}
+
+	sel, err := report.ParseSections(template.Sections)
+	if err != nil {
+		return report.Selection{}, "", fmt.Errorf("report template %q: %w", template.Name, err)
+	}
+	return sel, template.Language, nil
+}
+
+// selectResults membatasi hasil analysis untuk export csv/json sesuai bagian yang dipilih
+func selectResults(analysis model.Analysis, sel report.Selection) model.Analysis {
+	if sel.Full() {
+		return analysis
+	}
+	results := []model.MethodResult{}
+	for i, r := range analysis.Results {
+		methodID := r.MethodID
+		if methodID == "" {
+			methodID = stats.String(stats.NormalizeOutput(r.RawOutput), stats.KeyMethod)
+		}
+		switch {
+		case sel.Has(report.SectionMethods) && sel.HasMethod(i+1, methodID):
+		case !sel.Has(report.SectionMethods) && sel.Has(report.SectionInterpretation):
+			// Hanya interpretasi: output mentah tidak disertakan
+			r.RawOutput = nil
+		default:
+			continue
+		}
+		if !sel.Has(report.SectionInterpretation) {
+			r.Interpretation, r.EffectSize, r.Conclusion, r.Verification = "", "", "", nil
+		}
+		results = append(results, r)
+	}
+	analysis.Results = results
+	if !sel.Has(report.SectionSummary) {
+		analysis.Summary = ""
+	}
+	if !sel.Has(report.SectionFigures) {
+		analysis.Figures = nil
+	}
+	return analysis
+}
+
+// reportFormat menjelaskan satu format export yang dirender dari report.Document
+type reportFormat struct {
+	render      func(report.Document, io.Writer) error
+	contentType string
+	fileName    string // berisi %s untuk ID analysis
+	figures     bool   // gambar diunduh dari storage untuk disematkan
+	figureLinks bool   // gambar ditautkan lewat signed URL
+}
+
+var reportFormats = map[string]reportFormat{
+	"pdf":      {report.RenderPDF, "application/pdf", "analysis_report_%s.pdf", true, false},
+	"docx":     {report.RenderDOCX, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "analysis_report_%s.docx", true, false},
+	"xlsx":     {report.RenderXLSX, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "analysis_results_%s.xlsx", false, false},
+	"apa":      {report.RenderAPAText, "text/plain; charset=utf-8", "analysis_apa_%s.txt", false, false},
+	"latex":    {report.RenderLaTeX, "application/zip", "analysis_report_%s_latex.zip", true, false},
+	"markdown": {report.RenderMarkdown, "text/markdown; charset=utf-8", "analysis_report_%s.md", false, true},
+	"md":       {report.RenderMarkdown, "text/markdown; charset=utf-8", "analysis_report_%s.md", false, true},
+	"r":        {report.RenderRScript, "text/plain; charset=utf-8", "analysis_%s.R", false, false},
+	"python":   {report.RenderPythonScript, "text/x-python; charset=utf-8", "analysis_%s.py", false, false},
+	"spss":     {report.RenderSPSSSyntax, "text/plain; charset=utf-8", "analysis_%s.sps", false, false},
+	"scripts":  {report.RenderScripts, "application/zip", "analysis_%s_scripts.zip", false, false},
+}
+
+// exportReport merender report.Document sesuai format lalu mengirimkannya sebagai lampiran
+func exportReport(w http.ResponseWriter, doc report.Document, rf reportFormat) {
+	var buf bytes.Buffer
+	if err := rf.render(doc, &buf); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:   "error",
+			Message:  "Failed to generate report",
+			Response: err.Error(),
 		})
+		return
 	}
+
+	w.Header().Set("Content-Type", rf.contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\""+rf.fileName+"\"", doc.AnalysisID))
+	w.Write(buf.Bytes())
 }
 
-func exportPDF(w http.ResponseWriter, project model.Project, analysis model.Analysis) {
-	// Generate PDF content (simplified - in production use a PDF library like gofpdf)
-	content := fmt.Sprintf(`
-LAPORAN ANALISIS PENELITIAN
-===========================
-
-Judul: %s
-Deskripsi: %s
-Jenis Penelitian: %s
-Hipotesis: %s
-
-VARIABEL PENELITIAN
--------------------
-Independen: %v
-Dependen: %v
-
-HASIL ANALISIS
---------------
-`, project.Title, project.Description, project.ResearchType, project.Hypothesis,
-		project.Variables.Independent, project.Variables.Dependent)
-
-	for i, result := range analysis.Results {
-		content += fmt.Sprintf(`
-%d. %s
-   Interpretasi: %s
-   Kesimpulan: %s
-`, i+1, result.Method, result.Interpretation, result.Conclusion)
-	}
-
-	content += fmt.Sprintf(`
-
-RINGKASAN
----------
-%s
-
----
-Dibuat pada: %v
-Iterasi ke: %d
-`, analysis.Summary, analysis.CompletedAt, analysis.Iteration)
-
-	// Send as text/plain for now (in production, generate actual PDF)
-	w.Header().Set("Content-Type", "application/pdf")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"analysis_report_%s.pdf\"", analysis.ID.Hex()))
-	w.Write([]byte(content))
+// reportFigureURLTTL adalah masa berlaku signed URL gambar di export Markdown; 7 hari adalah batas GCS dan S3
+const reportFigureURLTTL = 7 * 24 * time.Hour
+
+// linkFigures menautkan gambar laporan ke signed URL agar bisa dibuka dari file export
+func linkFigures(ctx context.Context, doc *report.Document) {
+	for i := range doc.Figures {
+		doc.Figures[i].Link = signedFileURL(ctx, doc.Figures[i].StorageURL, reportFigureURLTTL)
+	}
+}
+
+// loadFigures mengunduh gambar analysis dari storage agar dapat disematkan ke laporan; gambar yang gagal dilewati
+func loadFigures(ctx context.Context, doc *report.Document) {
+	for i := range doc.Figures {
+		f := &doc.Figures[i]
+		if f.StorageURL == "" {
+			continue
+		}
+		data, err := storage.DownloadFile(ctx, storage.ObjectName(f.StorageURL))
+		if err != nil {
+			log.Printf("WARNING: failed to load figure %s: %v", f.ID, err)
+			continue
+		}
+		f.Data = data
+		f.ContentType = http.DetectContentType(data)
+	}
 }
 
 func exportCSV(w http.ResponseWriter, project model.Project, analysis model.Analysis) {
@@ -166,7 +337,28 @@ func exportCSV(w http.ResponseWriter, project model.Project, analysis model.Anal
 }
 
 func exportJSON(w http.ResponseWriter, project model.Project, analysis model.Analysis) {
-	exportData := map[string]interface{}{
+	jsonData, err := json.MarshalIndent(exportPayload(project, analysis), "", "  ")
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate JSON export",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"analysis_export_%s.json\"", analysis.ID.Hex()))
+	w.Write(jsonData)
+}
+
+// exportPayload menyusun data export JSON; RawOutput dinormalkan agar dokumen bersarang dari Mongo tetap berupa objek JSON
+func exportPayload(project model.Project, analysis model.Analysis) map[string]interface{} {
+	results := make([]model.MethodResult, len(analysis.Results))
+	for i, r := range analysis.Results {
+		r.RawOutput = stats.NormalizeOutput(r.RawOutput)
+		results[i] = r
+	}
+	return map[string]interface{}{
 		"project": map[string]interface{}{
 			"id":            project.ID.Hex(),
 			"title":         project.Title,
@@ -179,23 +371,248 @@ func exportJSON(w http.ResponseWriter, project model.Project, analysis model.Ana
 			"id":           analysis.ID.Hex(),
 			"iteration":    analysis.Iteration,
 			"status":       analysis.Status,
-			"results":      analysis.Results,
+			"results":      results,
 			"summary":      analysis.Summary,
 			"created_at":   analysis.CreatedAt,
 			"completed_at": analysis.CompletedAt,
 		},
 	}
+}
 
-	jsonData, err := json.MarshalIndent(exportData, "", "  ")
-	if err != nil {
-		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
-			Status:  "error",
-			Message: "Failed to generate JSON export",
-		})
+// bundleReports adalah laporan yang disertakan dalam bundle beserta path-nya
+var bundleReports = []struct {
+	format string
+	path   string
+}{
+	{"pdf", "reports/report.pdf"},
+	{"docx", "reports/report.docx"},
+	{"xlsx", "reports/results.xlsx"},
+	{"apa", "reports/apa.txt"},
+	{"markdown", "reports/report.md"},
+	{"latex", "reports/report_latex.zip"},
+}
+
+// exportBundle mengalirkan zip reproduksibilitas: dataset asli dan hasil parsing, konfigurasi lengkap,
+// hasil mentah, gambar, laporan, skrip, serta manifest berisi checksum SHA-256 setiap file
+func exportBundle(w http.ResponseWriter, ctx context.Context, mongoDB *mongo.Database, project model.Project, analysis model.Analysis, lang string, sel report.Selection) {
+	doc := report.Build(project, analysis, lang)
+	var upload model.Upload
+	if !analysis.UploadID.IsZero() {
+		if u, err := atdb.GetOneDoc[model.Upload](mongoDB, "uploads", bson.M{"_id": analysis.UploadID}); err == nil {
+			upload = u
+			doc.AttachUpload(upload)
+		}
+	}
+	loadFigures(ctx, &doc)
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"analysis_bundle_%s.zip\"", analysis.ID.Hex()))
+
+	b := bundle.NewWriter(w, analysis.ID.Hex(), time.Now())
+	fail := func(err error) {
+		// Header sudah terkirim sehingga kegagalan tulis hanya dapat dicatat
+		log.Printf("WARNING: failed to write bundle for analysis %s: %v", analysis.ID.Hex(), err)
+	}
+
+	// Dataset asli dan dataset hasil parsing yang benar-benar dibaca engine
+	var derived []map[string]string
+	dataPath := ""
+	if upload.StoragePath != "" {
+		content, err := storage.DownloadFile(ctx, upload.StoragePath)
+		if err != nil {
+			log.Printf("WARNING: failed to load dataset %s for bundle: %v", upload.FileName, err)
+			b.Missing("data/"+upload.FileName, err.Error())
+		} else {
+			dataPath = "data/" + path.Base(upload.FileName)
+			if err := b.Add(dataPath, content); err != nil {
+				fail(err)
+				return
+			}
+			if parsed, err := dataset.Parse(upload.FileName, content); err == nil {
+				var buf bytes.Buffer
+				cw := csv.NewWriter(&buf)
+				cw.Write(parsed.Columns)
+				cw.WriteAll(parsed.Rows)
+				if err := b.Add("data/analysis_data.csv", buf.Bytes()); err != nil {
+					fail(err)
+					return
+				}
+				derived = append(derived, map[string]string{
+					"path":        "data/analysis_data.csv",
+					"description": "Parsed table as read by the engine: UTF-8, comma-delimited, normalized header",
+				})
+			}
+		}
+	} else if upload.FileName != "" {
+		b.Missing("data/"+upload.FileName, "raw file is not stored")
+	}
+
+	cfg := bundleConfig(project, analysis, upload, dataPath, derived, doc.Lang)
+	cfg["report_sections"] = sel.Names()
+	if err := b.AddJSON("config.json", cfg); err != nil {
+		fail(err)
+		return
+	}
+	if err := b.AddJSON("results.json", exportPayload(project, analysis)); err != nil {
+		fail(err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"analysis_export_%s.json\"", analysis.ID.Hex()))
-	w.Write(jsonData)
+	for i, f := range doc.Figures {
+		if f.Data != nil {
+			if err := b.Add("figures/"+f.ID+".png", f.Data); err != nil {
+				fail(err)
+				return
+			}
+		}
+		if i >= len(analysis.Figures) || analysis.Figures[i].SVGURL == "" {
+			continue
+		}
+		svg, err := storage.DownloadFile(ctx, storage.ObjectName(analysis.Figures[i].SVGURL))
+		if err != nil {
+			log.Printf("WARNING: failed to load figure %s: %v", f.ID, err)
+			b.Missing("figures/"+f.ID+".svg", err.Error())
+			continue
+		}
+		if err := b.Add("figures/"+f.ID+".svg", svg); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	// Laporan mengikuti bagian yang dipilih; data, hasil mentah, gambar, dan skrip tetap lengkap
+	reportDoc := doc
+	reportDoc.Apply(sel)
+	// Laporan Markdown di reports/ menautkan gambar PNG yang ikut dalam bundle
+	reportDoc.Figures = append([]report.Figure(nil), reportDoc.Figures...)
+	for i, f := range reportDoc.Figures {
+		if f.Data != nil {
+			reportDoc.Figures[i].Link = "../figures/" + f.ID + ".png"
+		}
+	}
+	for _, rep := range bundleReports {
+		var buf bytes.Buffer
+		if err := reportFormats[rep.format].render(reportDoc, &buf); err != nil {
+			log.Printf("WARNING: failed to render %s for bundle: %v", rep.format, err)
+			b.Missing(rep.path, err.Error())
+			continue
+		}
+		if err := b.Add(rep.path, buf.Bytes()); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	// Skrip diletakkan di root bundle dan membaca dataset asli dari folder data/
+	scriptDoc := doc
+	if dataPath != "" {
+		scriptDoc.DataFile = dataPath
+	}
+	for _, s := range []struct {
+		name   string
+		render func(report.Document, io.Writer) error
+	}{
+		{"analysis.R", report.RenderRScript},
+		{"analysis.py", report.RenderPythonScript},
+		{"analysis.sps", report.RenderSPSSSyntax},
+	} {
+		var buf bytes.Buffer
+		if err := s.render(scriptDoc, &buf); err != nil {
+			log.Printf("WARNING: failed to render %s for bundle: %v", s.name, err)
+			b.Missing(s.name, err.Error())
+			continue
+		}
+		if err := b.Add(s.name, buf.Bytes()); err != nil {
+			fail(err)
+			return
+		}
+	}
+
+	if err := b.Close(); err != nil {
+		fail(err)
+	}
+}
+
+// bundleConfig menyusun konfigurasi lengkap analysis untuk config.json di dalam bundle
+func bundleConfig(project model.Project, analysis model.Analysis, upload model.Upload, dataPath string, derived []map[string]string, lang string) map[string]interface{} {
+	methods := make([]map[string]interface{}, 0, len(analysis.Results))
+	for i, r := range analysis.Results {
+		raw := stats.NormalizeOutput(r.RawOutput)
+		id := r.MethodID
+		if id == "" {
+			id = stats.String(raw, stats.KeyMethod)
+		}
+		m := map[string]interface{}{
+			"number":    i + 1,
+			"method":    r.Method,
+			"method_id": id,
+			"variables": stats.Strings(raw, stats.KeyVariables),
+		}
+		if alpha, ok := stats.Float(raw[stats.KeyAlpha]); ok {
+			m["alpha"] = alpha
+		}
+		settings := map[string]interface{}{}
+		for _, key := range bundleSettingKeys {
+			if v, ok := raw[key]; ok {
+				settings[key] = v
+			}
+		}
+		if len(settings) > 0 {
+			m["settings"] = settings
+		}
+		if opts := analysis.MethodOptions[id]; len(opts) > 0 {
+			m["options"] = opts
+		}
+		if r.Error != "" {
+			m["error"] = r.Error
+		}
+		methods = append(methods, m)
+	}
+
+	cfg := map[string]interface{}{
+		"analysis_id":      analysis.ID.Hex(),
+		"project_id":       project.ID.Hex(),
+		"iteration":        analysis.Iteration,
+		"language":         lang,
+		"selected_methods": analysis.SelectedMethods,
+		"method_options":   analysis.MethodOptions,
+		"methods":          methods,
+		"variables":        project.Variables,
+		"engine": map[string]interface{}{
+			"name":          "research-data-analysis stats engine",
+			"version":       stats.EngineVersion,
+			"app_version":   config.GetConfig().App.Version,
+			"deterministic": true,
+		},
+		"random_seeds": map[string]interface{}{
+			"python_mediation_bootstrap": report.BootstrapSeed,
+		},
+		"notes": []string{
+			"The stats engine uses no random numbers; rerunning the methods above on data/ reproduces results.json exactly.",
+			"random_seeds only applies to the bootstrap interval in analysis.py.",
+		},
+		"created_at":   analysis.CreatedAt,
+		"completed_at": analysis.CompletedAt,
+	}
+	if !analysis.ParentID.IsZero() {
+		cfg["parent_id"] = analysis.ParentID.Hex()
+	}
+	if upload.FileName != "" {
+		cfg["dataset"] = map[string]interface{}{
+			"path":         dataPath,
+			"file_name":    upload.FileName,
+			"file_type":    upload.FileType,
+			"file_size":    upload.FileSize,
+			"uploaded_at":  upload.UploadedAt,
+			"data_summary": upload.DataSummary,
+		}
+	}
+	cfg["derived_datasets"] = derived
+	return cfg
+}
+
+// bundleSettingKeys adalah opsi metode di RawOutput yang menentukan hasil dan dicatat di config.json
+var bundleSettingKeys = []string{
+	"test_value", "groups", "equal_variances_assumed", "dependent", "predictors",
+	"independent", "moderator", "centered", "mediator", "n_items",
 }
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/storage"
+	"github.com/research-data-analysis/model"
+)
+
+// ServeStoredFile handler untuk mengunduh file storage local melalui signed URL
+func ServeStoredFile(w http.ResponseWriter, r *http.Request, name string) {
+	status, err := storage.ServeLocal(w, r, name)
+	if err != nil {
+		at.WriteJSON(w, status, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+	}
+}
+
+// figureURLTTL adalah masa berlaku signed URL gambar chart pada response analysis
+const figureURLTTL = time.Hour
+
+// signFigures mengganti URL tersimpan gambar chart dengan signed URL agar frontend bisa memuatnya.
+// URL yang gagal ditandatangani dibiarkan apa adanya; slice asli tidak diubah.
+func signFigures(ctx context.Context, figures []model.Figure) []model.Figure {
+	signed := make([]model.Figure, len(figures))
+	for i, f := range figures {
+		f.StorageURL = signedFileURL(ctx, f.StorageURL, figureURLTTL)
+		f.SVGURL = signedFileURL(ctx, f.SVGURL, figureURLTTL)
+		signed[i] = f
+	}
+	return signed
+}
+
+// signedFileURL membuat signed URL dari URL hasil storage.UploadFile
+func signedFileURL(ctx context.Context, fileURL string, ttl time.Duration) string {
+	if fileURL == "" {
+		return ""
+	}
+	signed, err := storage.GetSignedURL(ctx, storage.ObjectName(fileURL), ttl)
+	if err != nil {
+		log.Printf("WARNING: failed to sign file URL %s: %v", fileURL, err)
+		return fileURL
+	}
+	return signed
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/report"
+	"github.com/research-data-analysis/helper/storage"
+	"github.com/research-data-analysis/model"
+)
+
+func TestMain(m *testing.M) {
+	// Storage local di direktori sementara agar test tidak menulis ke ./data/storage
+	dir, err := os.MkdirTemp("", "controller-storage-")
+	if err != nil {
+		panic(err)
+	}
+	cfg := config.GetConfig().Storage
+	cfg.Backend = "local"
+	cfg.LocalPath = dir
+	cfg.PublicURL = "http://localhost:8080/files"
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// serveFileURL meminta URL /files ke ServeStoredFile seperti yang dilakukan router
+func serveFileURL(t *testing.T, fileURL string) *httptest.ResponseRecorder {
+	t.Helper()
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q): %v", fileURL, err)
+	}
+	req := httptest.NewRequest(http.MethodGet, u.RequestURI(), nil)
+	rec := httptest.NewRecorder()
+	ServeStoredFile(rec, req, strings.TrimPrefix(u.Path, "/files/"))
+	return rec
+}
+
+func TestSignFiguresServesFigure(t *testing.T) {
+	ctx := context.Background()
+	png := []byte("\x89PNG\r\n\x1a\nfigure")
+	pngURL, err := storage.UploadFile(ctx, "figures/test/descriptive_score.png", bytes.NewReader(png), "image/png")
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	svgURL, err := storage.UploadFile(ctx, "figures/test/descriptive_score.svg", strings.NewReader("<svg/>"), "image/svg+xml")
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+
+	stored := []model.Figure{{ID: "descriptive_score", StorageURL: pngURL, SVGURL: svgURL}}
+	signed := signFigures(ctx, stored)
+	if stored[0].StorageURL != pngURL {
+		t.Errorf("signFigures modified the stored figure URL: %s", stored[0].StorageURL)
+	}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantBody   string
+	}{
+		{"signed png", signed[0].StorageURL, http.StatusOK, string(png)},
+		{"signed svg", signed[0].SVGURL, http.StatusOK, "<svg/>"},
+		{"unsigned png", pngURL, http.StatusForbidden, ""},
+		{"tampered signature", strings.Replace(signed[0].StorageURL, "signature=", "signature=0", 1), http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveFileURL(t, tt.url)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSignFiguresKeepsEmptyURL(t *testing.T) {
+	signed := signFigures(context.Background(), []model.Figure{{ID: "chart"}})
+	if signed[0].StorageURL != "" || signed[0].SVGURL != "" {
+		t.Errorf("signFigures = %+v, want empty URLs", signed[0])
+	}
+}
+
+func TestMarkdownExportLinksSignedFigure(t *testing.T) {
+	ctx := context.Background()
+	pngURL, err := storage.UploadFile(ctx, "figures/test/markdown.png", strings.NewReader("png"), "image/png")
+	if err != nil {
+		t.Fatalf("UploadFile: %v", err)
+	}
+	doc := report.Document{Lang: "en", Figures: []report.Figure{
+		{ID: "markdown", Title: "Scores", StorageURL: pngURL},
+		{ID: "missing", Title: "Not stored"},
+	}}
+	linkFigures(ctx, &doc)
+
+	var buf bytes.Buffer
+	if err := report.RenderMarkdown(doc, &buf); err != nil {
+		t.Fatalf("RenderMarkdown: %v", err)
+	}
+	md := buf.String()
+	start := strings.Index(md, "![Scores](")
+	if start < 0 {
+		t.Fatalf("figure link not found in markdown:\n%s", md)
+	}
+	link := md[start+len("![Scores]("):]
+	link = link[:strings.Index(link, ")")]
+	if strings.Contains(md, "Not stored") {
+		t.Errorf("figure without a stored file is linked:\n%s", md)
+	}
+
+	if rec := serveFileURL(t, link); rec.Code != http.StatusOK || rec.Body.String() != "png" {
+		t.Errorf("GET %s = %d %q, want 200 \"png\"", link, rec.Code, rec.Body.String())
+	}
+}
@@ -3,9 +3,14 @@ package controller
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/research-data-analysis/config"
-	"github.com/research-data-analysis/helper/watoken"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/keystore"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -21,11 +26,18 @@ func getMongoDB() *mongo.Database {
 
 // getUserIDFromToken extracts user ID from PASETO token
 func getUserIDFromToken(r *http.Request) (primitive.ObjectID, error) {
+	userID, _, err := getTokenClaims(r)
+	return userID, err
+}
+
+// getTokenClaims memverifikasi token lalu mengembalikan user ID dan token ID (jti);
+// token ber-jti ditolak jika sesinya sudah dicabut
+func getTokenClaims(r *http.Request) (primitive.ObjectID, string, error) {
 	// Get token from Authorization header
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		fmt.Printf("No authorization header found\n")
-		return primitive.NilObjectID, fmt.Errorf("no authorization header")
+		return primitive.NilObjectID, "", fmt.Errorf("no authorization header")
 	}
 
 	// Remove "Bearer " prefix if present
@@ -34,26 +46,77 @@ func getUserIDFromToken(r *http.Request) (primitive.ObjectID, error) {
 		tokenString = authHeader[7:]
 	}
 
+	// API key sudah diverifikasi AuthenticateAPIKey sebelum routing; tidak punya sesi (jti kosong)
+	if strings.HasPrefix(tokenString, apiKeyPrefix) {
+		key, ok := apiKeyFromRequest(r)
+		if !ok || key.KeyHash != hashSecretToken(tokenString) {
+			return primitive.NilObjectID, "", fmt.Errorf("invalid API key")
+		}
+		return key.UserID, "", nil
+	}
+
 	// Debug log for development
 	fmt.Printf("Token string length: %d\n", len(tokenString))
 
-	// Decode token using public key
-	publicKey := config.GetConfig().Auth.PublicKey
-	fmt.Printf("Public key length: %d\n", len(publicKey))
+	// Decode token dengan key ring (kunci dipilih dari key ID di footer)
+	ring, err := keystore.Ring()
+	if err != nil {
+		fmt.Printf("Key ring error: %v\n", err)
+		return primitive.NilObjectID, "", fmt.Errorf("invalid token: %v", err)
+	}
 
-	payload, err := watoken.Decode(publicKey, tokenString)
+	payload, err := ring.Decode(tokenString)
 	if err != nil {
 		fmt.Printf("Token decode error: %v\n", err)
-		return primitive.NilObjectID, fmt.Errorf("invalid token: %v", err)
+		return primitive.NilObjectID, "", fmt.Errorf("invalid token: %v", err)
 	}
 
 	// Convert user ID from string to ObjectID
 	userID, err := primitive.ObjectIDFromHex(payload.Id)
 	if err != nil {
 		fmt.Printf("Invalid user ID in token: %v\n", err)
-		return primitive.NilObjectID, fmt.Errorf("invalid user ID in token: %v", err)
+		return primitive.NilObjectID, "", fmt.Errorf("invalid user ID in token: %v", err)
+	}
+
+	// Cek revokasi sesi; token lama tanpa jti ditolak karena tidak bisa dicabut lewat logout atau reset password
+	if payload.Jti == "" {
+		fmt.Printf("Token without session ID rejected\n")
+		return primitive.NilObjectID, "", fmt.Errorf("invalid token: missing session ID, please log in again")
+	}
+	if err := checkTokenSession(payload.Jti, userID); err != nil {
+		fmt.Printf("Token session check failed: %v\n", err)
+		return primitive.NilObjectID, "", fmt.Errorf("invalid token: %v", err)
 	}
 
 	fmt.Printf("Successfully extracted user ID: %s\n", userID.Hex())
-	return userID, nil
+	return userID, payload.Jti, nil
+}
+
+// requestLanguage menentukan bahasa output: override di body request, query "lang",
+// preferensi bahasa pengguna, lalu bahasa bawaan institusinya, dengan default bahasa Indonesia
+func requestLanguage(r *http.Request, mongoDB *mongo.Database, userID primitive.ObjectID, override string) string {
+	if lang := i18n.Normalize(override); lang != "" {
+		return lang
+	}
+	if lang := i18n.Normalize(r.URL.Query().Get("lang")); lang != "" {
+		return lang
+	}
+	if mongoDB != nil && !userID.IsZero() {
+		if user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID}); err == nil {
+			if user.Language == "" {
+				institution, _ := userInstitution(mongoDB, user)
+				return i18n.Resolve(institution.Defaults.Language)
+			}
+			return i18n.Resolve(user.Language)
+		}
+	}
+	return i18n.Default
+}
+
+// reportLanguage menentukan bahasa laporan sebuah analysis: query "lang", bahasa analysis, lalu preferensi pengguna
+func reportLanguage(r *http.Request, mongoDB *mongo.Database, userID primitive.ObjectID, analysis model.Analysis) string {
+	if lang := i18n.Normalize(r.URL.Query().Get("lang")); lang != "" {
+		return lang
+	}
+	return requestLanguage(r, mongoDB, userID, analysis.Language)
 }
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Peran pengguna dalam institusi
+const (
+	institutionAdmin  = "admin"
+	institutionMember = "member"
+)
+
+// Jenis kuota institusi
+const (
+	quotaProjects = "projects"
+	quotaStorage  = "storage"
+	quotaAnalyses = "analyses"
+)
+
+// GetMyInstitution handler untuk institusi pengguna beserta kuota, pengaturan bawaan, dan pemakaiannya
+func GetMyInstitution(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+	institution, ok := userInstitution(mongoDB, user)
+	if !ok {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "You are not a member of an institution",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Institution retrieved successfully",
+		Data: map[string]interface{}{
+			"institution": institution,
+			"role":        user.InstitutionRole,
+			"usage":       memberUsage(mongoDB, userID),
+		},
+	})
+}
+
+// isPlatformAdmin mengecek apakah email terverifikasi pengguna terdaftar di ADMIN_EMAILS
+func isPlatformAdmin(user model.User) bool {
+	return user.EmailVerified && containsString(config.GetConfig().App.AdminEmails, normalizeEmail(user.Email))
+}
+
+// userInstitution memuat institusi tempat user menjadi anggota
+func userInstitution(mongoDB *mongo.Database, user model.User) (model.Institution, bool) {
+	if user.InstitutionID.IsZero() {
+		return model.Institution{}, false
+	}
+	institution, err := atdb.GetOneDoc[model.Institution](mongoDB, "institutions", bson.M{"_id": user.InstitutionID})
+	if err != nil {
+		return model.Institution{}, false
+	}
+	return institution, true
+}
+
+// institutionOf memuat institusi pengguna dengan userID, mis. pemilik project
+func institutionOf(mongoDB *mongo.Database, userID primitive.ObjectID) (model.Institution, bool) {
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		return model.Institution{}, false
+	}
+	return userInstitution(mongoDB, user)
+}
+
+// memberUsage menghitung pemakaian userID atas project miliknya: jumlah project, ukuran upload, dan analysis bulan ini
+func memberUsage(mongoDB *mongo.Database, userID primitive.ObjectID) model.InstitutionUsage {
+	projects, _ := atdb.GetAllDoc[model.Project](mongoDB, "projects", bson.M{"user_id": userID})
+	usage := model.InstitutionUsage{Projects: len(projects)}
+	if len(projects) == 0 {
+		return usage
+	}
+	ids := make([]primitive.ObjectID, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+
+	uploads, _ := atdb.GetAllDoc[model.Upload](mongoDB, "uploads", bson.M{"project_id": bson.M{"$in": ids}})
+	for _, u := range uploads {
+		usage.StorageBytes += u.FileSize
+	}
+	analyses, _ := atdb.CountDoc(mongoDB, "analyses", bson.M{
+		"project_id": bson.M{"$in": ids},
+		"created_at": bson.M{"$gte": monthStart(time.Now())},
+	})
+	usage.AnalysesThisMonth = int(analyses)
+	return usage
+}
+
+// monthStart mengembalikan awal bulan kalender (UTC) dari t
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// checkQuota memastikan pemilik project (ownerID) belum melampaui kuota institusinya untuk jenis kuota kind.
+// size adalah tambahan byte untuk kuota storage. Pengguna tanpa institusi tidak dibatasi.
+func checkQuota(mongoDB *mongo.Database, ownerID primitive.ObjectID, kind string, size int64) error {
+	institution, ok := institutionOf(mongoDB, ownerID)
+	if !ok {
+		return nil
+	}
+	quota := institution.Quota
+	switch {
+	case kind == quotaProjects && quota.MaxProjects > 0:
+		if memberUsage(mongoDB, ownerID).Projects >= quota.MaxProjects {
+			return fmt.Errorf("Institution quota exceeded: at most %d projects per member", quota.MaxProjects)
+		}
+	case kind == quotaStorage && quota.MaxStorageBytes > 0:
+		if memberUsage(mongoDB, ownerID).StorageBytes+size > quota.MaxStorageBytes {
+			return fmt.Errorf("Institution quota exceeded: at most %.1f MB of uploaded data per member", float64(quota.MaxStorageBytes)/(1<<20))
+		}
+	case kind == quotaAnalyses && quota.MaxAnalysesPerMonth > 0:
+		if memberUsage(mongoDB, ownerID).AnalysesThisMonth >= quota.MaxAnalysesPerMonth {
+			return fmt.Errorf("Institution quota exceeded: at most %d analyses per month per member", quota.MaxAnalysesPerMonth)
+		}
+	}
+	return nil
+}
+
+// institutionDefaults mengembalikan pengaturan bawaan institusi userID, kosong jika bukan anggota institusi
+func institutionDefaults(mongoDB *mongo.Database, userID primitive.ObjectID) model.InstitutionDefaults {
+	if mongoDB == nil {
+		return model.InstitutionDefaults{}
+	}
+	institution, _ := institutionOf(mongoDB, userID)
+	return institution.Defaults
+}
+
+// joinInstitutionByDomain menambahkan pengguna tanpa institusi ke institusi yang mendaftarkan domain email-nya.
+// Dipanggil setelah email terverifikasi agar domain tidak bisa diklaim dengan email palsu.
+func joinInstitutionByDomain(mongoDB *mongo.Database, userID primitive.ObjectID, email string) {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return
+	}
+	domain := strings.ToLower(email[i+1:])
+	institution, err := atdb.GetOneDoc[model.Institution](mongoDB, "institutions", bson.M{"domains": domain})
+	if err != nil {
+		return
+	}
+	_, err = atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": userID, "institution_id": nil}, bson.M{
+		"institution_id":   institution.ID,
+		"institution_role": institutionMember,
+		"institution":      institution.Name,
+		"updated_at":       time.Now(),
+	})
+	if err != nil {
+		log.Printf("WARNING: Failed to add user %s to institution %s: %v", userID.Hex(), institution.ID.Hex(), err)
+	}
+}
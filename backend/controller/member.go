@@ -0,0 +1,552 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/mail"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetProjectMembers handler untuk daftar owner, kolaborator, dan undangan yang belum diterima
+func GetProjectMembers(w http.ResponseWriter, r *http.Request, projectIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer)
+	if !ok {
+		return
+	}
+
+	members, err := atdb.GetAllDocWithSort[model.ProjectMember](mongoDB, "project_members", bson.M{"project_id": projectID}, bson.D{{Key: "created_at", Value: 1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve members",
+		})
+		return
+	}
+
+	// Lengkapi dengan nama pengguna untuk tampilan
+	list := make([]map[string]interface{}, 0, len(members)+1)
+	if owner, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": project.UserID}); err == nil {
+		list = append(list, map[string]interface{}{
+			"user_id":   owner.ID,
+			"email":     owner.Email,
+			"full_name": owner.FullName,
+			"role":      roleOwner,
+			"status":    memberActive,
+			"creator":   true,
+		})
+	}
+	for _, m := range members {
+		entry := map[string]interface{}{
+			"_id":        m.ID,
+			"email":      m.Email,
+			"role":       m.Role,
+			"status":     m.Status,
+			"invited_by": m.InvitedBy,
+			"created_at": m.CreatedAt,
+		}
+		if !m.UserID.IsZero() {
+			entry["user_id"] = m.UserID
+			entry["accepted_at"] = m.AcceptedAt
+			if u, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": m.UserID}); err == nil {
+				entry["full_name"] = u.FullName
+			}
+		}
+		list = append(list, entry)
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Members retrieved successfully",
+		Data: map[string]interface{}{
+			"project_id": projectID,
+			"role":       project.Role,
+			"members":    list,
+		},
+	})
+}
+
+// InviteMember handler untuk mengundang kolaborator lewat email (khusus owner)
+func InviteMember(w http.ResponseWriter, r *http.Request, projectIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+
+	var req model.MemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	email := normalizeEmail(req.Email)
+	if _, err := netmail.ParseAddress(email); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid email address",
+		})
+		return
+	}
+	if _, valid := roleRank[req.Role]; !valid {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Role must be owner, editor, reviewer, or viewer",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleOwner)
+	if !ok {
+		return
+	}
+
+	if creator, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": project.UserID}); err == nil && normalizeEmail(creator.Email) == email {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "User is already the project owner",
+		})
+		return
+	}
+	if n, _ := atdb.CountDoc(mongoDB, "project_members", bson.M{"project_id": projectID, "email": email}); n > 0 {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "User is already a member or has been invited",
+		})
+		return
+	}
+
+	member := model.ProjectMember{
+		ProjectID: projectID,
+		Email:     email,
+		Role:      req.Role,
+		Status:    memberPending,
+		InvitedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	memberID, err := atdb.InsertOneDoc(mongoDB, "project_members", member)
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to invite member",
+		})
+		return
+	}
+	member.ID = memberID
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditMemberInvite, Resource: "member", ResourceID: memberID, Details: fmt.Sprintf("%s as %s on project %s", member.Email, member.Role, projectIDStr)})
+
+	if err := sendInvitationEmail(r.Context(), mongoDB, project, member, userID, req.Language); err != nil {
+		log.Printf("WARNING: Failed to send invitation email for project %s: %v", projectIDStr, err)
+	}
+
+	at.WriteJSON(w, http.StatusCreated, model.Response{
+		Status:  "success",
+		Message: "Invitation sent",
+		Data:    member,
+	})
+}
+
+// UpdateMember handler untuk mengubah peran kolaborator (khusus owner)
+func UpdateMember(w http.ResponseWriter, r *http.Request, projectIDStr, memberIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+	memberID, err := primitive.ObjectIDFromHex(memberIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid member ID",
+		})
+		return
+	}
+
+	var req model.MemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	if _, valid := roleRank[req.Role]; !valid {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Role must be owner, editor, reviewer, or viewer",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	if _, ok := requireProject(w, r, mongoDB, projectID, userID, roleOwner); !ok {
+		return
+	}
+
+	result, err := atdb.UpdateOneDoc(mongoDB, "project_members", bson.M{"_id": memberID, "project_id": projectID}, bson.M{"role": req.Role})
+	if err != nil || result.MatchedCount == 0 {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Member not found",
+		})
+		return
+	}
+
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditMemberUpdate, Resource: "member", ResourceID: memberID, Details: fmt.Sprintf("role %s on project %s", req.Role, projectIDStr)})
+
+	member, _ := atdb.GetOneDoc[model.ProjectMember](mongoDB, "project_members", bson.M{"_id": memberID})
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Member updated successfully",
+		Data:    member,
+	})
+}
+
+// RemoveMember handler untuk mengeluarkan kolaborator atau membatalkan undangan;
+// owner dapat mengeluarkan siapa saja, anggota lain hanya dirinya sendiri (keluar dari project)
+func RemoveMember(w http.ResponseWriter, r *http.Request, projectIDStr, memberIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+	memberID, err := primitive.ObjectIDFromHex(memberIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid member ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	project, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer)
+	if !ok {
+		return
+	}
+
+	member, err := atdb.GetOneDoc[model.ProjectMember](mongoDB, "project_members", bson.M{"_id": memberID, "project_id": projectID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Member not found",
+		})
+		return
+	}
+	if project.Role != roleOwner && member.UserID != userID {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Your role on this project does not allow this action",
+		})
+		return
+	}
+
+	if _, err := atdb.DeleteOneDoc(mongoDB, "project_members", bson.M{"_id": memberID}); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to remove member",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditMemberRemove, Resource: "member", ResourceID: memberID, Details: fmt.Sprintf("%s from project %s", member.Email, projectIDStr)})
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Member removed successfully",
+		Data: map[string]interface{}{
+			"member_id":  memberID,
+			"removed_at": time.Now(),
+		},
+	})
+}
+
+// GetInvitations handler untuk daftar undangan project yang belum diterima untuk email pengguna
+func GetInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+
+	invitations, err := atdb.GetAllDocWithSort[model.ProjectMember](mongoDB, "project_members", bson.M{
+		"email":  normalizeEmail(user.Email),
+		"status": memberPending,
+	}, bson.D{{Key: "created_at", Value: -1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve invitations",
+		})
+		return
+	}
+
+	list := make([]map[string]interface{}, 0, len(invitations))
+	for _, inv := range invitations {
+		entry := map[string]interface{}{
+			"_id":        inv.ID,
+			"project_id": inv.ProjectID,
+			"role":       inv.Role,
+			"created_at": inv.CreatedAt,
+		}
+		if project, err := atdb.GetOneDoc[model.Project](mongoDB, "projects", bson.M{"_id": inv.ProjectID}); err == nil {
+			entry["project_title"] = project.Title
+		}
+		if inviter, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": inv.InvitedBy}); err == nil {
+			entry["invited_by"] = inviter.FullName
+		}
+		list = append(list, entry)
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Invitations retrieved successfully",
+		Data: map[string]interface{}{
+			"email_verified": user.EmailVerified,
+			"invitations":    list,
+		},
+	})
+}
+
+// RespondInvitation handler untuk menerima atau menolak undangan project.
+// Menerima undangan mensyaratkan email terverifikasi agar undangan tidak bisa diklaim oleh pendaftar palsu.
+func RespondInvitation(w http.ResponseWriter, r *http.Request, invitationIDStr string, accept bool) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	invitationID, err := primitive.ObjectIDFromHex(invitationIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid invitation ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+
+	filter := bson.M{"_id": invitationID, "email": normalizeEmail(user.Email), "status": memberPending}
+
+	if !accept {
+		result, err := atdb.DeleteOneDoc(mongoDB, "project_members", filter)
+		if err != nil || result.DeletedCount == 0 {
+			at.WriteJSON(w, http.StatusNotFound, model.Response{
+				Status:  "error",
+				Message: "Invitation not found",
+			})
+			return
+		}
+		recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditMemberDecline, Resource: "member", ResourceID: invitationID})
+		at.WriteJSON(w, http.StatusOK, model.Response{
+			Status:  "success",
+			Message: "Invitation declined",
+		})
+		return
+	}
+
+	if !user.EmailVerified {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Please verify your email before accepting invitations",
+		})
+		return
+	}
+
+	now := time.Now()
+	result, err := atdb.UpdateOneDoc(mongoDB, "project_members", filter, bson.M{
+		"user_id":     userID,
+		"status":      memberActive,
+		"accepted_at": now,
+	})
+	if err != nil || result.MatchedCount == 0 {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Invitation not found",
+		})
+		return
+	}
+
+	member, _ := atdb.GetOneDoc[model.ProjectMember](mongoDB, "project_members", bson.M{"_id": invitationID})
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditMemberAccept, Resource: "member", ResourceID: invitationID, Details: fmt.Sprintf("%s on project %s", member.Role, member.ProjectID.Hex())})
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Invitation accepted",
+		Data:    member,
+	})
+}
+
+// sendInvitationEmail memberi tahu penerima undangan; tautan mengarah ke halaman undangan di frontend
+func sendInvitationEmail(ctx context.Context, mongoDB *mongo.Database, project model.Project, member model.ProjectMember, inviterID primitive.ObjectID, lang string) error {
+	inviter, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": inviterID})
+	if err != nil {
+		return err
+	}
+	var inviteeLang string
+	if invitee, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"email": member.Email}); err == nil {
+		inviteeLang = invitee.Language
+	}
+	lang = i18n.Resolve(lang, inviteeLang, inviter.Language)
+
+	inviterName := inviter.FullName
+	if inviterName == "" {
+		inviterName = inviter.Email
+	}
+	msg, err := mail.Render(mail.KindInvite, lang, member.Email, mail.TemplateData{
+		Link:   config.GetConfig().Mail.AppURL + "/#invitations",
+		Params: []interface{}{inviterName, project.Title, i18n.T(lang, "role."+member.Role)},
+	})
+	if err != nil {
+		return err
+	}
+	return mail.Send(ctx, msg)
+}
+
+// normalizeEmail menyamakan format email untuk pencocokan undangan
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/mail"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Tipe notifikasi
+const (
+	notifyReviewSubmitted        = "review_submitted"
+	notifyReviewApproved         = "review_approved"
+	notifyReviewChangesRequested = "review_changes_requested"
+	notifyComment                = "comment"
+	notifyCommentReply           = "comment_reply"
+)
+
+// GetNotifications handler untuk daftar notifikasi pengguna, terbaru lebih dulu; ?unread=true untuk yang belum dibaca
+func GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	filter := bson.M{"user_id": userID}
+	if r.URL.Query().Get("unread") == "true" {
+		filter["read_at"] = nil
+	}
+	notifications, err := atdb.GetAllDocWithSort[model.Notification](mongoDB, "notifications", filter, bson.D{{Key: "created_at", Value: -1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve notifications",
+		})
+		return
+	}
+	unread, _ := atdb.CountDoc(mongoDB, "notifications", bson.M{"user_id": userID, "read_at": nil})
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Notifications retrieved successfully",
+		Data: map[string]interface{}{
+			"notifications": notifications,
+			"unread_count":  unread,
+		},
+	})
+}
+
+// MarkNotificationsRead handler untuk menandai satu notifikasi (notificationIDStr) atau semuanya (string kosong) sebagai dibaca
+func MarkNotificationsRead(w http.ResponseWriter, r *http.Request, notificationIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	filter := bson.M{"user_id": userID, "read_at": nil}
+	if notificationIDStr != "" {
+		notificationID, err := primitive.ObjectIDFromHex(notificationIDStr)
+		if err != nil {
+			at.WriteJSON(w, http.StatusBadRequest, model.Response{
+				Status:  "error",
+				Message: "Invalid notification ID",
+			})
+			return
+		}
+		filter["_id"] = notificationID
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	result, err := atdb.UpdateManyDoc(mongoDB, "notifications", filter, bson.M{"read_at": time.Now()})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to update notifications",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Notifications marked as read",
+		Data: map[string]interface{}{
+			"updated": result.ModifiedCount,
+		},
+	})
+}
+
+// notify membuat notifikasi n untuk setiap penerima selain pelaku, dengan pesan dalam bahasa penerima.
+// Notifikasi review juga dikirim lewat email; kegagalan hanya dicatat.
+func notify(ctx context.Context, mongoDB *mongo.Database, recipients []primitive.ObjectID, n model.Notification, project model.Project, note string) {
+	actorName := "-"
+	if actor, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": n.ActorID}); err == nil {
+		actorName = actor.FullName
+		if actorName == "" {
+			actorName = actor.Email
+		}
+	}
+
+	seen := map[primitive.ObjectID]bool{n.ActorID: true}
+	for _, recipientID := range recipients {
+		if recipientID.IsZero() || seen[recipientID] {
+			continue
+		}
+		seen[recipientID] = true
+
+		user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": recipientID})
+		if err != nil {
+			continue
+		}
+		lang := i18n.Resolve(user.Language)
+
+		noteText := ""
+		if note != "" {
+			noteText = i18n.T(lang, "notify.note", note)
+		}
+		entry := n
+		entry.UserID = recipientID
+		entry.Message = i18n.T(lang, "notify."+n.Type, actorName, project.Title)
+		if noteText != "" {
+			entry.Message += ". " + noteText
+		}
+		entry.CreatedAt = time.Now()
+		if _, err := atdb.InsertOneDoc(mongoDB, "notifications", entry); err != nil {
+			log.Printf("WARNING: Failed to save notification for user %s: %v", recipientID.Hex(), err)
+			continue
+		}
+
+		if !strings.HasPrefix(n.Type, "review_") {
+			continue
+		}
+		msg, err := mail.Render(n.Type, lang, user.Email, mail.TemplateData{
+			Name:   user.FullName,
+			Link:   config.GetConfig().Mail.AppURL + "/#project?id=" + project.ID.Hex(),
+			Params: []interface{}{actorName, project.Title, noteText},
+		})
+		if err == nil {
+			err = mail.Send(ctx, msg)
+		}
+		if err != nil {
+			log.Printf("WARNING: Failed to send %s email to user %s: %v", n.Type, recipientID.Hex(), err)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Status review analysis
+const (
+	reviewDraft            = "draft"
+	reviewSubmitted        = "submitted"
+	reviewChangesRequested = "changes_requested"
+	reviewApproved         = "approved"
+)
+
+// reviewAction adalah transisi status review: status asal yang diizinkan, status tujuan,
+// dan apakah transisi hanya boleh dilakukan reviewer (selain itu minimal editor)
+type reviewAction struct {
+	from     []string
+	to       string
+	reviewer bool
+	notify   string
+}
+
+var reviewActions = map[string]reviewAction{
+	"submit":          {from: []string{reviewDraft, reviewChangesRequested}, to: reviewSubmitted, notify: notifyReviewSubmitted},
+	"withdraw":        {from: []string{reviewSubmitted}, to: reviewDraft},
+	"request_changes": {from: []string{reviewSubmitted}, to: reviewChangesRequested, reviewer: true, notify: notifyReviewChangesRequested},
+	"approve":         {from: []string{reviewSubmitted}, to: reviewApproved, reviewer: true, notify: notifyReviewApproved},
+}
+
+// ReviewAnalysis handler untuk alur review: draft → submitted → changes_requested/approved.
+// Editor mengajukan atau menarik pengajuan; reviewer menyetujui atau meminta perbaikan.
+func ReviewAnalysis(w http.ResponseWriter, r *http.Request, analysisIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	analysisID, err := primitive.ObjectIDFromHex(analysisIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid analysis ID",
+		})
+		return
+	}
+
+	var req model.ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	action, valid := reviewActions[req.Action]
+	if !valid {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Action must be submit, withdraw, request_changes, or approve",
+		})
+		return
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	if req.Action == "request_changes" && req.Note == "" {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "A note is required when requesting changes",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	analysis, err := atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": analysisID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Analysis not found",
+		})
+		return
+	}
+	project, ok := requireProject(w, r, mongoDB, analysis.ProjectID, userID, roleViewer)
+	if !ok {
+		return
+	}
+
+	// Keputusan review khusus reviewer; owner pun tidak dapat menyetujui analisisnya sendiri
+	allowed := roleRank[project.Role] >= roleRank[roleEditor]
+	if action.reviewer {
+		allowed = project.Role == roleReviewer
+	}
+	if !allowed {
+		at.WriteJSON(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Your role on this project does not allow this action",
+		})
+		return
+	}
+
+	if req.Action == "submit" && analysis.Status != "completed" {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Analysis not yet completed",
+		})
+		return
+	}
+
+	current := analysis.ReviewStatus
+	if current == "" {
+		current = reviewDraft
+	}
+	if !containsString(action.from, current) {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: fmt.Sprintf("Cannot %s an analysis with review status %s", strings.ReplaceAll(req.Action, "_", " "), current),
+		})
+		return
+	}
+
+	// Filter status saat ini mencegah dua keputusan bersamaan saling menimpa
+	filter := bson.M{"_id": analysisID, "review_status": current}
+	if current == reviewDraft {
+		filter["review_status"] = bson.M{"$in": bson.A{reviewDraft, nil}}
+	}
+	submitter := lastSubmitter(analysis.ReviewHistory)
+	history := append(analysis.ReviewHistory, model.ReviewEvent{
+		From:      current,
+		To:        action.to,
+		UserID:    userID,
+		Note:      req.Note,
+		CreatedAt: time.Now(),
+	})
+	result, err := atdb.UpdateOneDoc(mongoDB, "analyses", filter, bson.M{
+		"review_status":  action.to,
+		"review_history": history,
+	})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to update review status",
+		})
+		return
+	}
+	if result.MatchedCount == 0 {
+		at.WriteJSON(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "Review status was changed by someone else, please reload",
+		})
+		return
+	}
+
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditAnalysisReview, Resource: "analysis", ResourceID: analysisID, Details: current + " -> " + action.to})
+
+	if action.notify != "" {
+		recipients := []primitive.ObjectID{submitter}
+		if action.notify == notifyReviewSubmitted {
+			recipients = projectReviewers(mongoDB, project.ID)
+		}
+		notify(r.Context(), mongoDB, recipients, model.Notification{
+			Type:       action.notify,
+			ActorID:    userID,
+			ProjectID:  project.ID,
+			AnalysisID: analysisID,
+		}, project, req.Note)
+	}
+
+	updated, _ := atdb.GetOneDoc[model.Analysis](mongoDB, "analyses", bson.M{"_id": analysisID})
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Review status updated",
+		Data:    updated,
+	})
+}
+
+// lastSubmitter mengembalikan pengguna yang terakhir mengajukan analysis untuk ditinjau
+func lastSubmitter(history []model.ReviewEvent) primitive.ObjectID {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].To == reviewSubmitted {
+			return history[i].UserID
+		}
+	}
+	return primitive.NilObjectID
+}
+
+// projectReviewers mengembalikan anggota aktif project dengan peran reviewer
+func projectReviewers(mongoDB *mongo.Database, projectID primitive.ObjectID) []primitive.ObjectID {
+	members, _ := atdb.GetAllDoc[model.ProjectMember](mongoDB, "project_members", bson.M{
+		"project_id": projectID,
+		"role":       roleReviewer,
+		"status":     memberActive,
+	})
+	ids := make([]primitive.ObjectID, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.UserID)
+	}
+	return ids
+}
+
+// containsString mengecek apakah value ada di list
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
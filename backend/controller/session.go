@@ -0,0 +1,420 @@
+package controller
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/keystore"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// RefreshToken handler untuk menukar refresh token dengan pasangan token baru (refresh token dirotasi)
+func RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "refresh_token is required",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	session, hash, err := sessionFromRefreshToken(mongoDB, req.RefreshToken)
+	if err != nil {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid refresh token",
+		})
+		return
+	}
+	switch checkRefreshToken(session, hash, time.Now()) {
+	case refreshExpired:
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Refresh token expired or revoked",
+		})
+		return
+	case refreshReused:
+		log.Printf("WARNING: Refresh token reuse detected for session %s, revoking it", session.ID.Hex())
+		revokeSession(mongoDB, session.ID)
+		fallthrough
+	case refreshInvalid:
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid refresh token",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": session.UserID})
+	if err != nil {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+
+	// Sesi yang dibuat sebelum institusi mewajibkan 2FA tidak diperpanjang sampai pengguna login ulang dan mendaftar
+	if !user.TwoFactorEnabled && twoFactorRequired(mongoDB, user) {
+		revokeSession(mongoDB, session.ID)
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Your institution requires two-factor authentication, please log in again",
+		})
+		return
+	}
+
+	secret, err := newSecretToken()
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate token",
+		})
+		return
+	}
+	now := time.Now()
+	expiresAt := now.Add(config.GetConfig().Auth.RefreshTokenExpiration)
+
+	// Filter menyertakan hash lama agar dua refresh bersamaan tidak sama-sama berhasil
+	result, err := atdb.UpdateOneDoc(mongoDB, "sessions", bson.M{"_id": session.ID, "refresh_hash": hash, "revoked_at": nil}, bson.M{
+		"refresh_hash":  hashSecretToken(secret),
+		"previous_hash": hash,
+		"last_used_at":  now,
+		"expires_at":    expiresAt,
+		"user_agent":    r.UserAgent(),
+		"ip_address":    at.GetClientIP(r),
+	})
+	if err != nil || result.MatchedCount == 0 {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid refresh token",
+		})
+		return
+	}
+
+	tokens, err := tokenPair(user, session.ID, secret, expiresAt)
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate token",
+		})
+		return
+	}
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Token refreshed successfully",
+		Data:    tokens,
+	})
+}
+
+// Logout handler untuk mencabut sesi saat ini, lewat refresh token di body atau access token di header
+func Logout(w http.ResponseWriter, r *http.Request) {
+	var req model.RefreshRequest
+	json.NewDecoder(r.Body).Decode(&req) // body opsional
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	var sessionID, userID primitive.ObjectID
+	if req.RefreshToken != "" {
+		session, hash, err := sessionFromRefreshToken(mongoDB, req.RefreshToken)
+		if err != nil || !hashEqual(hash, session.RefreshHash) {
+			Response(w, http.StatusUnauthorized, model.Response{
+				Status:  "error",
+				Message: "Invalid refresh token",
+			})
+			return
+		}
+		sessionID, userID = session.ID, session.UserID
+	} else {
+		var jti string
+		var err error
+		userID, jti, err = getTokenClaims(r)
+		if err == nil {
+			sessionID, err = sessionIDFromJTI(jti)
+		}
+		if err != nil {
+			Response(w, http.StatusUnauthorized, model.Response{
+				Status:  "error",
+				Message: "Unauthorized",
+			})
+			return
+		}
+	}
+
+	if err := revokeSession(mongoDB, sessionID); err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to log out",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditLogout, Resource: "session", ResourceID: sessionID})
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Logged out successfully",
+	})
+}
+
+// LogoutAll handler untuk mencabut semua sesi aktif pengguna
+func LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	result, err := atdb.UpdateManyDoc(mongoDB, "sessions", bson.M{"user_id": userID, "revoked_at": nil}, bson.M{"revoked_at": time.Now()})
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to log out sessions",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: userID, Action: auditLogoutAll, Resource: "session", Details: fmt.Sprintf("%d sessions revoked", result.ModifiedCount)})
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "All sessions logged out successfully",
+		Data: map[string]interface{}{
+			"revoked_sessions": result.ModifiedCount,
+		},
+	})
+}
+
+// GetSessions handler untuk melihat sesi login yang masih aktif
+func GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, jti, err := getTokenClaims(r)
+	if err != nil || userID.IsZero() {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	sessions, err := atdb.GetAllDocWithSort[model.Session](mongoDB, "sessions", bson.M{
+		"user_id":    userID,
+		"revoked_at": nil,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}, bson.D{{Key: "last_used_at", Value: -1}})
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to retrieve sessions",
+		})
+		return
+	}
+
+	current, _ := sessionIDFromJTI(jti)
+	Response(w, http.StatusOK, model.Response{
+		Status: "success",
+		Data: map[string]interface{}{
+			"sessions":        sessions,
+			"current_session": current,
+		},
+	})
+}
+
+// issueSession membuat sesi baru untuk pengguna lalu mengembalikan pasangan token
+func issueSession(mongoDB *mongo.Database, r *http.Request, user model.User) (model.TokenPair, error) {
+	secret, err := newSecretToken()
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+
+	now := time.Now()
+	session := model.Session{
+		UserID:      user.ID,
+		RefreshHash: hashSecretToken(secret),
+		UserAgent:   r.UserAgent(),
+		IPAddress:   at.GetClientIP(r),
+		CreatedAt:   now,
+		LastUsedAt:  now,
+		ExpiresAt:   now.Add(config.GetConfig().Auth.RefreshTokenExpiration),
+	}
+	sessionID, err := atdb.InsertOneDoc(mongoDB, "sessions", session)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	return tokenPair(user, sessionID, secret, session.ExpiresAt)
+}
+
+// tokenPair menandatangani access token untuk sesi; jti berbentuk "<sessionID>.<acak>"
+func tokenPair(user model.User, sessionID primitive.ObjectID, secret string, refreshExpiresAt time.Time) (model.TokenPair, error) {
+	auth := config.GetConfig().Auth
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return model.TokenPair{}, err
+	}
+	jti := sessionID.Hex() + "." + hex.EncodeToString(nonce)
+
+	ring, err := keystore.Ring()
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	access, err := ring.Encode(user.ID.Hex(), user.FullName, jti, auth.TokenExpiration)
+	if err != nil {
+		return model.TokenPair{}, err
+	}
+	return model.TokenPair{
+		AccessToken:      access,
+		RefreshToken:     sessionID.Hex() + "." + secret,
+		ExpiresIn:        int64(auth.TokenExpiration.Seconds()),
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// checkTokenSession memastikan sesi pemilik access token belum dicabut atau kedaluwarsa
+func checkTokenSession(jti string, userID primitive.ObjectID) error {
+	sessionID, err := sessionIDFromJTI(jti)
+	if err != nil {
+		return err
+	}
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		return errors.New("session check unavailable")
+	}
+	session, err := atdb.GetOneDoc[model.Session](mongoDB, "sessions", bson.M{"_id": sessionID, "user_id": userID})
+	if err != nil {
+		return errors.New("session not found")
+	}
+	if session.RevokedAt != nil {
+		return errors.New("session revoked")
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return errors.New("session expired")
+	}
+	return nil
+}
+
+// Hasil pengecekan refresh token terhadap sesinya
+type refreshState int
+
+const (
+	refreshValid refreshState = iota
+	refreshExpired
+	refreshReused
+	refreshInvalid
+)
+
+// checkRefreshToken membandingkan hash refresh token dengan sesi. Hash yang cocok dengan
+// previous_hash berarti token lama dipakai lagi setelah dirotasi: kemungkinan dicuri,
+// sehingga pemanggil harus mencabut seluruh sesi.
+func checkRefreshToken(session model.Session, hash string, now time.Time) refreshState {
+	if session.RevokedAt != nil || now.After(session.ExpiresAt) {
+		return refreshExpired
+	}
+	if hashEqual(hash, session.RefreshHash) {
+		return refreshValid
+	}
+	if session.PreviousHash != "" && hashEqual(hash, session.PreviousHash) {
+		return refreshReused
+	}
+	return refreshInvalid
+}
+
+// sessionFromRefreshToken mengambil sesi dari refresh token "<sessionID>.<secret>" beserta hash secret-nya
+func sessionFromRefreshToken(mongoDB *mongo.Database, token string) (model.Session, string, error) {
+	id, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return model.Session{}, "", errors.New("malformed refresh token")
+	}
+	sessionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return model.Session{}, "", errors.New("malformed refresh token")
+	}
+	session, err := atdb.GetOneDoc[model.Session](mongoDB, "sessions", bson.M{"_id": sessionID})
+	if err != nil {
+		return model.Session{}, "", err
+	}
+	return session, hashSecretToken(secret), nil
+}
+
+func sessionIDFromJTI(jti string) (primitive.ObjectID, error) {
+	id, _, _ := strings.Cut(jti, ".")
+	sessionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, errors.New("invalid token ID")
+	}
+	return sessionID, nil
+}
+
+func revokeSession(mongoDB *mongo.Database, sessionID primitive.ObjectID) error {
+	_, err := atdb.UpdateOneDoc(mongoDB, "sessions", bson.M{"_id": sessionID}, bson.M{"revoked_at": time.Now()})
+	return err
+}
+
+func newSecretToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecretToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
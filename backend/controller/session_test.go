@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/research-data-analysis/model"
+)
+
+func TestCheckRefreshToken(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	current := hashSecretToken("current-secret")
+	previous := hashSecretToken("previous-secret")
+	revokedAt := now.Add(-time.Minute)
+
+	rotated := model.Session{RefreshHash: current, PreviousHash: previous, ExpiresAt: now.Add(time.Hour)}
+	fresh := model.Session{RefreshHash: current, ExpiresAt: now.Add(time.Hour)}
+	expired := rotated
+	expired.ExpiresAt = now.Add(-time.Second)
+	revoked := rotated
+	revoked.RevokedAt = &revokedAt
+
+	tests := []struct {
+		name    string
+		session model.Session
+		hash    string
+		want    refreshState
+	}{
+		{"current token", rotated, current, refreshValid},
+		{"current token on fresh session", fresh, current, refreshValid},
+		{"previous token reused after rotation", rotated, previous, refreshReused},
+		{"unknown token", rotated, hashSecretToken("other-secret"), refreshInvalid},
+		{"empty hash on fresh session", fresh, "", refreshInvalid},
+		{"expired session", expired, current, refreshExpired},
+		{"expired session with previous token", expired, previous, refreshExpired},
+		{"revoked session", revoked, current, refreshExpired},
+		{"revoked session with previous token", revoked, previous, refreshExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkRefreshToken(tt.session, tt.hash, now); got != tt.want {
+				t.Errorf("checkRefreshToken = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
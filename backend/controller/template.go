@@ -0,0 +1,329 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/helper/at"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/report"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GetReportTemplates handler untuk mengambil template laporan milik proyek
+func GetReportTemplates(w http.ResponseWriter, r *http.Request, projectIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	if _, ok := requireProject(w, r, mongoDB, projectID, userID, roleViewer); !ok {
+		return
+	}
+
+	templates, err := atdb.GetAllDocWithSort[model.ReportTemplate](mongoDB, "report_templates", bson.M{"project_id": projectID}, bson.D{{Key: "created_at", Value: 1}})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to fetch report templates",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status: "success",
+		Data: map[string]interface{}{
+			"templates": templates,
+			"sections":  report.Sections,
+		},
+	})
+}
+
+// CreateReportTemplate handler untuk menyimpan template laporan baru pada proyek
+func CreateReportTemplate(w http.ResponseWriter, r *http.Request, projectIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid project ID",
+		})
+		return
+	}
+
+	var req model.ReportTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	sections, lang, err := validateReportTemplate(req)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	if _, ok := requireProject(w, r, mongoDB, projectID, userID, roleEditor); !ok {
+		return
+	}
+
+	now := time.Now()
+	template := model.ReportTemplate{
+		ProjectID: projectID,
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Sections:  sections,
+		Language:  lang,
+		IsDefault: req.IsDefault,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	templateID, err := atdb.InsertOneDoc(mongoDB, "report_templates", template)
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to create report template",
+		})
+		return
+	}
+	template.ID = templateID
+	if template.IsDefault {
+		clearDefaultTemplates(mongoDB, projectID, templateID)
+	}
+
+	at.WriteJSON(w, http.StatusCreated, model.Response{
+		Status:  "success",
+		Message: "Report template created successfully",
+		Data:    template,
+	})
+}
+
+// UpdateReportTemplate handler untuk mengubah template laporan
+func UpdateReportTemplate(w http.ResponseWriter, r *http.Request, templateIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(templateIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid template ID",
+		})
+		return
+	}
+
+	var req model.ReportTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return
+	}
+	sections, lang, err := validateReportTemplate(req)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	template, ok := editableReportTemplate(w, r, mongoDB, templateID, userID)
+	if !ok {
+		return
+	}
+
+	_, err = atdb.UpdateOneDoc(mongoDB, "report_templates", bson.M{"_id": templateID}, bson.M{
+		"name":       strings.TrimSpace(req.Name),
+		"sections":   sections,
+		"language":   lang,
+		"is_default": req.IsDefault,
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to update report template",
+		})
+		return
+	}
+	if req.IsDefault {
+		clearDefaultTemplates(mongoDB, template.ProjectID, templateID)
+	}
+
+	updated, _ := atdb.GetOneDoc[model.ReportTemplate](mongoDB, "report_templates", bson.M{"_id": templateID})
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Report template updated successfully",
+		Data:    updated,
+	})
+}
+
+// DeleteReportTemplate handler untuk menghapus template laporan
+func DeleteReportTemplate(w http.ResponseWriter, r *http.Request, templateIDStr string) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		at.WriteJSON(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(templateIDStr)
+	if err != nil {
+		at.WriteJSON(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid template ID",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	if _, ok := editableReportTemplate(w, r, mongoDB, templateID, userID); !ok {
+		return
+	}
+
+	if _, err := atdb.DeleteOneDoc(mongoDB, "report_templates", bson.M{"_id": templateID}); err != nil {
+		at.WriteJSON(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to delete report template",
+		})
+		return
+	}
+
+	at.WriteJSON(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Report template deleted successfully",
+		Data: map[string]interface{}{
+			"deleted_template_id": templateIDStr,
+		},
+	})
+}
+
+// validateReportTemplate memvalidasi nama, bagian, dan bahasa template; bagian disimpan dalam bentuk kanonik
+func validateReportTemplate(req model.ReportTemplateRequest) ([]string, string, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, "", errors.New("Template name is required")
+	}
+	sel, err := report.ParseSections(req.Sections)
+	if err != nil {
+		return nil, "", err
+	}
+	if sel.Full() {
+		return nil, "", errors.New("Template must select at least one section")
+	}
+	lang := ""
+	if req.Language != "" {
+		if lang = i18n.Normalize(req.Language); lang == "" {
+			return nil, "", errors.New("Unsupported language. Supported: " + strings.Join(i18n.Supported(), ", "))
+		}
+	}
+	return sel.Names(), lang, nil
+}
+
+// editableReportTemplate mengambil template dan memastikan pengguna minimal editor di proyeknya; respons error sudah ditulis jika gagal
+func editableReportTemplate(w http.ResponseWriter, r *http.Request, mongoDB *mongo.Database, templateID, userID primitive.ObjectID) (model.ReportTemplate, bool) {
+	template, err := atdb.GetOneDoc[model.ReportTemplate](mongoDB, "report_templates", bson.M{"_id": templateID})
+	if err != nil {
+		at.WriteJSON(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "Report template not found",
+		})
+		return template, false
+	}
+	if _, ok := requireProject(w, r, mongoDB, template.ProjectID, userID, roleEditor); !ok {
+		return template, false
+	}
+	return template, true
+}
+
+// clearDefaultTemplates memastikan hanya satu template default per proyek
+func clearDefaultTemplates(mongoDB *mongo.Database, projectID, keepID primitive.ObjectID) {
+	others, err := atdb.GetAllDoc[model.ReportTemplate](mongoDB, "report_templates", bson.M{
+		"project_id": projectID,
+		"is_default": true,
+		"_id":        bson.M{"$ne": keepID},
+	})
+	if err != nil {
+		return
+	}
+	for _, t := range others {
+		atdb.UpdateOneDoc(mongoDB, "report_templates", bson.M{"_id": t.ID}, bson.M{"is_default": false})
+	}
+}
@@ -0,0 +1,645 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/keystore"
+	"github.com/research-data-analysis/helper/password"
+	"github.com/research-data-analysis/helper/totp"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Tujuan token pra-autentikasi yang diterbitkan Login sebelum sesi dibuat
+const (
+	tokenTwoFactorLogin = "two_factor_login"
+	tokenTwoFactorSetup = "two_factor_setup"
+)
+
+// Batas token pra-autentikasi dan jumlah recovery code
+const (
+	twoFactorTokenTTL     = 10 * time.Minute
+	maxTwoFactorAttempts  = 5
+	recoveryCodeCount     = 10
+	recoveryCodeByteCount = 7 // 56 bit, dienkode menjadi 12 karakter base32 ("XXXXXX-XXXXXX")
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var errPreAuthToken = errors.New("Invalid or expired token, please log in again")
+
+// GetTwoFactorStatus handler untuk status two-factor authentication pengguna
+func GetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := getUserIDFromToken(r)
+	if err != nil || userID.IsZero() {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		Response(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return
+	}
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Two-factor status retrieved successfully",
+		Data: map[string]interface{}{
+			"enabled":                  user.TwoFactorEnabled,
+			"required":                 twoFactorRequired(mongoDB, user),
+			"recovery_codes_remaining": len(user.RecoveryCodes),
+		},
+	})
+}
+
+// SetupTwoFactor handler untuk memulai pendaftaran TOTP: membuat secret baru dan provisioning URI untuk QR code.
+// Bisa dipanggil dengan sesi login atau dengan mfa_token setup dari Login jika institusi mewajibkan 2FA.
+func SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req model.TwoFactorRequest
+	json.NewDecoder(r.Body).Decode(&req)
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, _, ok := twoFactorSetupUser(w, r, mongoDB, req)
+	if !ok {
+		return
+	}
+	if user.TwoFactorEnabled {
+		Response(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "Two-factor authentication is already enabled",
+		})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate two-factor secret",
+		})
+		return
+	}
+	sealed, err := keystore.Seal(secret)
+	if err != nil {
+		log.Printf("WARNING: Failed to encrypt two-factor secret for user %s: %v", user.ID.Hex(), err)
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate two-factor secret",
+		})
+		return
+	}
+	if _, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID}, bson.M{"two_factor_pending": sealed, "two_factor_setup_attempts": 0, "updated_at": time.Now()}); err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to save two-factor secret",
+		})
+		return
+	}
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Scan the QR code with your authenticator app, then confirm with a code",
+		Data: map[string]interface{}{
+			"secret":      secret,
+			"otpauth_uri": totp.URI(config.GetConfig().App.Name, user.Email, secret),
+		},
+	})
+}
+
+// EnableTwoFactor handler untuk mengonfirmasi pendaftaran TOTP dengan code pertama. Recovery code dikembalikan
+// sekali pada respons ini. Jika dipanggil dengan mfa_token setup, sesi login langsung dibuat.
+func EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req model.TwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "code is required",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	user, token, ok := twoFactorSetupUser(w, r, mongoDB, req)
+	if !ok {
+		return
+	}
+	if user.TwoFactorEnabled || user.TwoFactorPending == "" {
+		Response(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "Start two-factor setup first",
+		})
+		return
+	}
+
+	if token != nil {
+		if err := claimPreAuthAttempt(mongoDB, *token); err != nil {
+			Response(w, http.StatusUnauthorized, model.Response{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	if !claimSetupAttempt(mongoDB, user) {
+		Response(w, http.StatusTooManyRequests, model.Response{
+			Status:  "error",
+			Message: "Too many invalid codes; start two-factor setup again",
+		})
+		return
+	}
+
+	secret, err := keystore.Open(user.TwoFactorPending)
+	if err != nil {
+		log.Printf("WARNING: Failed to decrypt pending two-factor secret for user %s: %v", user.ID.Hex(), err)
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to verify code",
+		})
+		return
+	}
+	step, valid := totp.Validate(secret, req.Code, time.Now(), 0)
+	if !valid {
+		recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorFailed, Resource: "user", ResourceID: user.ID, Details: "setup"})
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid two-factor code",
+		})
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate recovery codes",
+		})
+		return
+	}
+	if token != nil {
+		if _, err := consumeUserToken(mongoDB, req.MFAToken, tokenTwoFactorSetup); err != nil {
+			Response(w, http.StatusUnauthorized, model.Response{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	// Filter pending mencegah dua konfirmasi bersamaan dengan secret berbeda
+	result, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID, "two_factor_pending": user.TwoFactorPending}, bson.M{
+		"two_factor_enabled":        true,
+		"two_factor_secret":         user.TwoFactorPending,
+		"two_factor_pending":        "",
+		"two_factor_setup_attempts": 0,
+		"two_factor_last_step":      step,
+		"recovery_codes":            hashes,
+		"updated_at":                time.Now(),
+	})
+	if err != nil || result.MatchedCount == 0 {
+		Response(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "Start two-factor setup first",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorEnable, Resource: "user", ResourceID: user.ID})
+
+	data := map[string]interface{}{"recovery_codes": codes}
+	if token != nil {
+		user.TwoFactorEnabled = true
+		if !completeLogin(w, r, mongoDB, user, data) {
+			return
+		}
+	}
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Two-factor authentication enabled; store the recovery codes somewhere safe",
+		Data:    data,
+	})
+}
+
+// VerifyTwoFactor handler untuk langkah kedua login: menukar mfa_token dan code TOTP (atau recovery code) dengan sesi
+func VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req model.TwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.MFAToken == "" || (req.Code == "" && req.RecoveryCode == "") {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "mfa_token and code or recovery_code are required",
+		})
+		return
+	}
+
+	mongoDB := getMongoDB()
+	if mongoDB == nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Database connection failed",
+		})
+		return
+	}
+
+	token, err := preAuthToken(mongoDB, req.MFAToken, tokenTwoFactorLogin)
+	if err != nil {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": token.UserID})
+	if err != nil || !user.TwoFactorEnabled {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid or expired token",
+		})
+		return
+	}
+
+	if err := claimPreAuthAttempt(mongoDB, token); err != nil {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	usedRecovery, valid := verifyTwoFactor(mongoDB, user, req.Code, req.RecoveryCode)
+	if !valid {
+		recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorFailed, Resource: "user", ResourceID: user.ID, Details: "login"})
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid two-factor code",
+		})
+		return
+	}
+	if _, err := consumeUserToken(mongoDB, req.MFAToken, tokenTwoFactorLogin); err != nil {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return
+	}
+	if usedRecovery {
+		recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorRecovery, Resource: "user", ResourceID: user.ID, Details: fmt.Sprintf("%d recovery codes left", len(user.RecoveryCodes)-1)})
+	}
+
+	data := map[string]interface{}{}
+	if !completeLogin(w, r, mongoDB, user, data) {
+		return
+	}
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "User logged in successfully",
+		Data:    data,
+	})
+}
+
+// DisableTwoFactor handler untuk menonaktifkan 2FA; memerlukan password dan code TOTP atau recovery code.
+// Ditolak jika institusi pengguna mewajibkan 2FA.
+func DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	mongoDB, user, req, ok := twoFactorManageRequest(w, r)
+	if !ok {
+		return
+	}
+	if twoFactorRequired(mongoDB, user) {
+		Response(w, http.StatusForbidden, model.Response{
+			Status:  "error",
+			Message: "Your institution requires two-factor authentication",
+		})
+		return
+	}
+	if !checkPassword(user, req.Password) {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid password",
+		})
+		return
+	}
+	if _, valid := verifyTwoFactor(mongoDB, user, req.Code, req.RecoveryCode); !valid {
+		recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorFailed, Resource: "user", ResourceID: user.ID, Details: "disable"})
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid two-factor code",
+		})
+		return
+	}
+
+	_, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID}, bson.M{
+		"two_factor_enabled":   false,
+		"two_factor_secret":    "",
+		"two_factor_pending":   "",
+		"two_factor_last_step": 0,
+		"recovery_codes":       []string{},
+		"updated_at":           time.Now(),
+	})
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to disable two-factor authentication",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorDisable, Resource: "user", ResourceID: user.ID})
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes handler untuk mengganti semua recovery code; code lama tidak berlaku lagi
+func RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	mongoDB, user, req, ok := twoFactorManageRequest(w, r)
+	if !ok {
+		return
+	}
+	if _, valid := verifyTwoFactor(mongoDB, user, req.Code, ""); !valid {
+		recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorFailed, Resource: "user", ResourceID: user.ID, Details: "recovery codes"})
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Invalid two-factor code",
+		})
+		return
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate recovery codes",
+		})
+		return
+	}
+	if _, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID}, bson.M{"recovery_codes": hashes, "updated_at": time.Now()}); err != nil {
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to save recovery codes",
+		})
+		return
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditTwoFactorRecoveryReset, Resource: "user", ResourceID: user.ID})
+
+	Response(w, http.StatusOK, model.Response{
+		Status:  "success",
+		Message: "Recovery codes regenerated; store them somewhere safe",
+		Data: map[string]interface{}{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// twoFactorStep menentukan langkah 2FA setelah password benar: verifikasi code, pendaftaran wajib, atau tidak ada ("")
+func twoFactorStep(mongoDB *mongo.Database, user model.User) string {
+	switch {
+	case user.TwoFactorEnabled:
+		return tokenTwoFactorLogin
+	case twoFactorRequired(mongoDB, user):
+		return tokenTwoFactorSetup
+	default:
+		return ""
+	}
+}
+
+// twoFactorRequired mengecek apakah institusi pengguna mewajibkan 2FA
+func twoFactorRequired(mongoDB *mongo.Database, user model.User) bool {
+	institution, ok := userInstitution(mongoDB, user)
+	return ok && institution.Require2FA
+}
+
+// completeLogin membuat sesi untuk user dan menambahkan token ke data respons; respons error sudah ditulis jika gagal
+func completeLogin(w http.ResponseWriter, r *http.Request, mongoDB *mongo.Database, user model.User, data map[string]interface{}) bool {
+	tokens, err := issueSession(mongoDB, r, user)
+	if err != nil {
+		log.Printf("WARNING: Failed to issue session for user %s: %v", user.ID.Hex(), err)
+		Response(w, http.StatusInternalServerError, model.Response{
+			Status:  "error",
+			Message: "Failed to generate token",
+		})
+		return false
+	}
+	recordAudit(r, mongoDB, model.AuditLog{UserID: user.ID, Action: auditLogin, Resource: "user", ResourceID: user.ID, Details: "two-factor"})
+
+	data["token"] = tokens.AccessToken
+	data["refresh_token"] = tokens.RefreshToken
+	data["expires_in"] = tokens.ExpiresIn
+	data["refresh_expires_at"] = tokens.RefreshExpiresAt
+	data["user"] = user
+	return true
+}
+
+// twoFactorSetupUser memuat pengguna untuk pendaftaran TOTP, lewat mfa_token setup atau sesi login.
+// Token dikembalikan (belum dipakai) jika request memakai mfa_token.
+func twoFactorSetupUser(w http.ResponseWriter, r *http.Request, mongoDB *mongo.Database, req model.TwoFactorRequest) (model.User, *model.UserToken, bool) {
+	var token *model.UserToken
+	var userID primitive.ObjectID
+	var err error
+	if req.MFAToken != "" {
+		t, err := preAuthToken(mongoDB, req.MFAToken, tokenTwoFactorSetup)
+		if err != nil {
+			Response(w, http.StatusUnauthorized, model.Response{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return model.User{}, nil, false
+		}
+		token, userID = &t, t.UserID
+	} else {
+		userID, err = getUserIDFromToken(r)
+	}
+	if err != nil || userID.IsZero() {
+		Response(w, http.StatusUnauthorized, model.Response{
+			Status:  "error",
+			Message: "Unauthorized",
+		})
+		return model.User{}, nil, false
+	}
+
+	user, err := atdb.GetOneDoc[model.User](mongoDB, "users", bson.M{"_id": userID})
+	if err != nil {
+		Response(w, http.StatusNotFound, model.Response{
+			Status:  "error",
+			Message: "User not found",
+		})
+		return model.User{}, nil, false
+	}
+	return user, token, true
+}
+
+// twoFactorManageRequest membaca body dan memuat pengguna login yang sudah mengaktifkan 2FA
+func twoFactorManageRequest(w http.ResponseWriter, r *http.Request) (*mongo.Database, model.User, model.TwoFactorRequest, bool) {
+	var req model.TwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Response(w, http.StatusBadRequest, model.Response{
+			Status:  "error",
+			Message: "Invalid request body",
+		})
+		return nil, model.User{}, req, false
+	}
+
+	mongoDB, user, ok := requireCurrentUser(w, r)
+	if !ok {
+		return nil, model.User{}, req, false
+	}
+	if !user.TwoFactorEnabled {
+		Response(w, http.StatusConflict, model.Response{
+			Status:  "error",
+			Message: "Two-factor authentication is not enabled",
+		})
+		return nil, model.User{}, req, false
+	}
+	return mongoDB, user, req, true
+}
+
+// preAuthToken memvalidasi token pra-autentikasi tanpa memakainya, agar code yang salah masih bisa diulang
+// sampai maxTwoFactorAttempts
+func preAuthToken(mongoDB *mongo.Database, raw, purpose string) (model.UserToken, error) {
+	token, err := atdb.GetOneDoc[model.UserToken](mongoDB, "user_tokens", bson.M{"token_hash": hashSecretToken(raw), "purpose": purpose})
+	if err != nil || token.UsedAt != nil || time.Now().After(token.ExpiresAt) || token.Attempts >= maxTwoFactorAttempts {
+		return token, errPreAuthToken
+	}
+	return token, nil
+}
+
+// claimPreAuthAttempt mencatat satu percobaan code secara atomik sebelum code dicek, sehingga request paralel
+// tidak bisa melewati maxTwoFactorAttempts. Token dibatalkan begitu batas tercapai.
+func claimPreAuthAttempt(mongoDB *mongo.Database, token model.UserToken) error {
+	result, err := atdb.IncOneDoc(mongoDB, "user_tokens", bson.M{
+		"_id":      token.ID,
+		"used_at":  nil,
+		"attempts": bson.M{"$not": bson.M{"$gte": maxTwoFactorAttempts}},
+	}, bson.M{"attempts": 1})
+	if err != nil || result.MatchedCount == 0 {
+		return errPreAuthToken
+	}
+	if _, err := atdb.UpdateOneDoc(mongoDB, "user_tokens", bson.M{"_id": token.ID, "used_at": nil, "attempts": bson.M{"$gte": maxTwoFactorAttempts}}, bson.M{"used_at": time.Now()}); err != nil {
+		log.Printf("WARNING: Failed to invalidate two-factor token for user %s: %v", token.UserID.Hex(), err)
+	}
+	return nil
+}
+
+// claimSetupAttempt mencatat satu percobaan konfirmasi untuk secret yang sedang didaftarkan, juga untuk pendaftaran
+// lewat sesi login. Setelah maxTwoFactorAttempts secret dibuang dan pendaftaran harus dimulai ulang.
+func claimSetupAttempt(mongoDB *mongo.Database, user model.User) bool {
+	result, err := atdb.IncOneDoc(mongoDB, "users", bson.M{
+		"_id":                       user.ID,
+		"two_factor_pending":        user.TwoFactorPending,
+		"two_factor_setup_attempts": bson.M{"$not": bson.M{"$gte": maxTwoFactorAttempts}},
+	}, bson.M{"two_factor_setup_attempts": 1})
+	if err != nil || result.MatchedCount == 0 {
+		atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID, "two_factor_pending": user.TwoFactorPending}, bson.M{"two_factor_pending": ""})
+		return false
+	}
+	return true
+}
+
+// verifyTwoFactor mengecek code TOTP atau recovery code milik user. Step TOTP terakhir disimpan agar code yang sama
+// tidak bisa dipakai ulang, dan recovery code yang cocok dihapus. usedRecovery bernilai true jika recovery code dipakai.
+func verifyTwoFactor(mongoDB *mongo.Database, user model.User, code, recoveryCode string) (usedRecovery bool, ok bool) {
+	if recoveryCode != "" {
+		hash := hashSecretToken(normalizeRecoveryCode(recoveryCode))
+		remaining := []string{}
+		for _, h := range user.RecoveryCodes {
+			if !hashEqual(h, hash) {
+				remaining = append(remaining, h)
+			}
+		}
+		if len(remaining) == len(user.RecoveryCodes) {
+			return true, false
+		}
+		result, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID, "recovery_codes": hash}, bson.M{"recovery_codes": remaining})
+		return true, err == nil && result.MatchedCount > 0
+	}
+
+	secret, err := keystore.Open(user.TwoFactorSecret)
+	if err != nil {
+		log.Printf("WARNING: Failed to decrypt two-factor secret for user %s: %v", user.ID.Hex(), err)
+		return false, false
+	}
+	step, valid := totp.Validate(secret, code, time.Now(), user.TwoFactorLastStep)
+	if !valid {
+		return false, false
+	}
+	// Filter step mencegah code yang sama dipakai dua kali secara bersamaan
+	result, err := atdb.UpdateOneDoc(mongoDB, "users", bson.M{"_id": user.ID, "two_factor_last_step": bson.M{"$not": bson.M{"$gte": step}}}, bson.M{"two_factor_last_step": step})
+	return false, err == nil && result.MatchedCount > 0
+}
+
+// newRecoveryCodes membuat recovery code sekali pakai beserta hash-nya untuk disimpan
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		b := make([]byte, recoveryCodeByteCount)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := recoveryEncoding.EncodeToString(b)[:12]
+		codes = append(codes, raw[:6]+"-"+raw[6:])
+		hashes = append(hashes, hashSecretToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode menghapus pemisah dan spasi agar format input bebas
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(code)))
+}
+
+// checkPassword memverifikasi password pengguna, termasuk record lama yang masih plaintext
+func checkPassword(user model.User, plain string) bool {
+	if plain == "" {
+		return false
+	}
+	if !password.IsHashed(user.Password) {
+		return password.VerifyLegacy(plain, user.Password)
+	}
+	valid, err := password.Verify(plain, user.Password)
+	if err != nil {
+		log.Printf("WARNING: Failed to verify password for user %s: %v", user.ID.Hex(), err)
+	}
+	return valid
+}
@@ -6,7 +6,11 @@ require (
 	aidanwoods.dev/go-paseto v1.5.1
 	cloud.google.com/go/storage v1.42.0
 	github.com/GoogleCloudPlatform/functions-framework-go v1.8.1
+	github.com/go-pdf/fpdf v0.9.0
 	go.mongodb.org/mongo-driver v1.16.0
+	golang.org/x/crypto v0.25.0
+	golang.org/x/image v0.18.0
+	google.golang.org/api v0.190.0
 )
 
 require (
@@ -44,14 +48,12 @@ require (
 	go.uber.org/atomic v1.4.0 // indirect
 	go.uber.org/multierr v1.1.0 // indirect
 	go.uber.org/zap v1.10.0 // indirect
-	golang.org/x/crypto v0.25.0 // indirect
 	golang.org/x/net v0.27.0 // indirect
 	golang.org/x/oauth2 v0.21.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
 	golang.org/x/sys v0.22.0 // indirect
 	golang.org/x/text v0.16.0 // indirect
 	golang.org/x/time v0.5.0 // indirect
-	google.golang.org/api v0.190.0 // indirect
 	google.golang.org/genproto v0.0.0-20240730163845-b1a4ccb954bf // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20240725223205-93522f1f2a9f // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240730163845-b1a4ccb954bf // indirect
@@ -67,6 +67,30 @@ func GetAllDocWithSort[T any](db *mongo.Database, collection string, filter bson
 	return results, cursor.Err()
 }
 
+// GetPageDoc mengambil satu halaman dokumen dengan sorting: lewati skip dokumen lalu ambil maksimal limit dokumen
+func GetPageDoc[T any](db *mongo.Database, collection string, filter bson.M, sort bson.D, skip, limit int64) ([]T, error) {
+	results := []T{}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(sort).SetSkip(skip).SetLimit(limit)
+	cursor, err := db.Collection(collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var elem T
+		if err := cursor.Decode(&elem); err != nil {
+			continue
+		}
+		results = append(results, elem)
+	}
+
+	return results, cursor.Err()
+}
+
 // InsertOneDoc menyisipkan satu dokumen ke collection
 func InsertOneDoc(db *mongo.Database, collection string, doc interface{}) (primitive.ObjectID, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -88,6 +112,22 @@ func UpdateOneDoc(db *mongo.Database, collection string, filter bson.M, update b
 	return db.Collection(collection).UpdateOne(ctx, filter, bson.M{"$set": update})
 }
 
+// IncOneDoc menambah field angka pada satu dokumen secara atomik ($inc)
+func IncOneDoc(db *mongo.Database, collection string, filter bson.M, inc bson.M) (*mongo.UpdateResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return db.Collection(collection).UpdateOne(ctx, filter, bson.M{"$inc": inc})
+}
+
+// UpdateManyDoc mengupdate semua dokumen yang cocok dengan filter
+func UpdateManyDoc(db *mongo.Database, collection string, filter bson.M, update bson.M) (*mongo.UpdateResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return db.Collection(collection).UpdateMany(ctx, filter, bson.M{"$set": update})
+}
+
 // ReplaceOneDoc mengganti satu dokumen
 func ReplaceOneDoc(db *mongo.Database, collection string, filter bson.M, replacement interface{}) (*mongo.UpdateResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -104,6 +144,14 @@ func DeleteOneDoc(db *mongo.Database, collection string, filter bson.M) (*mongo.
 	return db.Collection(collection).DeleteOne(ctx, filter)
 }
 
+// DeleteManyDoc menghapus semua dokumen yang cocok dengan filter
+func DeleteManyDoc(db *mongo.Database, collection string, filter bson.M) (*mongo.DeleteResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return db.Collection(collection).DeleteMany(ctx, filter)
+}
+
 // CountDoc menghitung dokumen dalam collection
 func CountDoc(db *mongo.Database, collection string, filter bson.M) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
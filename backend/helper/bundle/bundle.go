@@ -0,0 +1,161 @@
+package bundle
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestName adalah nama file manifest di dalam bundle
+const ManifestName = "manifest.json"
+
+// ChecksumName adalah daftar checksum dengan format `sha256sum -c`
+const ChecksumName = "SHA256SUMS"
+
+// Entry mencatat satu file di dalam bundle beserta checksum-nya
+type Entry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest menjelaskan isi bundle agar dapat diverifikasi setelah diunduh
+type Manifest struct {
+	AnalysisID  string    `json:"analysis_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Algorithm   string    `json:"algorithm"`
+	Files       []Entry   `json:"files"`
+	Missing     []string  `json:"missing,omitempty"`
+}
+
+// Writer menulis bundle zip secara streaming sambil menghitung checksum tiap file
+type Writer struct {
+	zw       *zip.Writer
+	manifest Manifest
+	seen     map[string]bool
+}
+
+// NewWriter membuat Writer yang menulis zip langsung ke w
+func NewWriter(w io.Writer, analysisID string, generatedAt time.Time) *Writer {
+	return &Writer{
+		zw: zip.NewWriter(w),
+		manifest: Manifest{
+			AnalysisID:  analysisID,
+			GeneratedAt: generatedAt.UTC(),
+			Algorithm:   "SHA-256",
+		},
+		seen: map[string]bool{},
+	}
+}
+
+// Add menulis satu file ke bundle; nama yang sama diberi akhiran agar tidak saling menimpa
+func (b *Writer) Add(name string, data []byte) error {
+	name = b.uniqueName(cleanPath(name))
+	fw, err := b.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: b.manifest.GeneratedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	b.manifest.Files = append(b.manifest.Files, Entry{
+		Path:   name,
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	})
+	return nil
+}
+
+// AddJSON menulis nilai sebagai JSON ber-indentasi
+func (b *Writer) AddJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return b.Add(name, append(data, '\n'))
+}
+
+// Missing mencatat file yang seharusnya ada tetapi tidak dapat disertakan
+func (b *Writer) Missing(name, reason string) {
+	b.manifest.Missing = append(b.manifest.Missing, fmt.Sprintf("%s: %s", cleanPath(name), reason))
+}
+
+// Close menulis manifest dan SHA256SUMS lalu menutup zip
+func (b *Writer) Close() error {
+	files := append([]Entry(nil), b.manifest.Files...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	var sums strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&sums, "%s  %s\n", f.SHA256, f.Path)
+	}
+	if err := b.Add(ChecksumName, []byte(sums.String())); err != nil {
+		return err
+	}
+
+	// Manifest tidak memuat checksum dirinya sendiri
+	sort.Slice(b.manifest.Files, func(i, j int) bool { return b.manifest.Files[i].Path < b.manifest.Files[j].Path })
+	data, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	fw, err := b.zw.CreateHeader(&zip.FileHeader{
+		Name:     ManifestName,
+		Method:   zip.Deflate,
+		Modified: b.manifest.GeneratedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return b.zw.Close()
+}
+
+func (b *Writer) uniqueName(name string) string {
+	if !b.seen[name] {
+		b.seen[name] = true
+		return name
+	}
+	ext := ""
+	base := name
+	if i := strings.LastIndex(name, "."); i > strings.LastIndex(name, "/") {
+		base, ext = name[:i], name[i:]
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s_%d%s", base, n, ext)
+		if !b.seen[candidate] {
+			b.seen[candidate] = true
+			return candidate
+		}
+	}
+}
+
+// cleanPath menormalkan path di dalam zip dan mencegah path keluar dari root bundle
+func cleanPath(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	parts := strings.Split(name, "/")
+	kept := parts[:0]
+	for _, p := range parts {
+		if p == "" || p == "." || p == ".." {
+			continue
+		}
+		kept = append(kept, p)
+	}
+	if len(kept) == 0 {
+		return "file"
+	}
+	return strings.Join(kept, "/")
+}
@@ -0,0 +1,303 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Anchor menentukan perataan horizontal teks terhadap titik acuan
+type Anchor int
+
+const (
+	AnchorStart Anchor = iota
+	AnchorMiddle
+	AnchorEnd
+)
+
+// Canvas adalah permukaan gambar vektor sederhana; satu chart digambar sekali lalu dirender ke SVG atau PNG
+type Canvas interface {
+	Line(x1, y1, x2, y2 float64, c color.NRGBA, width float64, dashed bool)
+	Rect(x, y, w, h float64, fill, stroke color.NRGBA)
+	Circle(cx, cy, r float64, fill, stroke color.NRGBA)
+	Polygon(points [][2]float64, fill color.NRGBA)
+	// Text menulis teks dengan y sebagai baseline; vertical memutar teks 90° berlawanan arah jarum jam
+	Text(x, y float64, s string, size float64, anchor Anchor, vertical, bold bool, c color.NRGBA)
+}
+
+// svgCanvas menulis elemen SVG secara langsung
+type svgCanvas struct {
+	b strings.Builder
+}
+
+func newSVGCanvas(w, h float64) *svgCanvas {
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="0 0 %g %g" font-family="Helvetica, Arial, sans-serif">`, w, h, w, h)
+	fmt.Fprintf(&c.b, `<rect width="%g" height="%g" fill="#ffffff"/>`, w, h)
+	return c
+}
+
+func (c *svgCanvas) bytes() []byte {
+	return []byte(c.b.String() + "</svg>\n")
+}
+
+// svgPaint menulis atribut warna; alpha nol berarti tidak digambar
+func svgPaint(attr string, col color.NRGBA) string {
+	if col.A == 0 {
+		return fmt.Sprintf(` %s="none"`, attr)
+	}
+	s := fmt.Sprintf(` %s="#%02x%02x%02x"`, attr, col.R, col.G, col.B)
+	if col.A < 255 {
+		s += fmt.Sprintf(` %s-opacity="%.2f"`, attr, float64(col.A)/255)
+	}
+	return s
+}
+
+func (c *svgCanvas) Line(x1, y1, x2, y2 float64, col color.NRGBA, width float64, dashed bool) {
+	dash := ""
+	if dashed {
+		dash = ` stroke-dasharray="6 4"`
+	}
+	fmt.Fprintf(&c.b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"%s stroke-width="%g"%s/>`, x1, y1, x2, y2, svgPaint("stroke", col), width, dash)
+}
+
+func (c *svgCanvas) Rect(x, y, w, h float64, fill, stroke color.NRGBA) {
+	fmt.Fprintf(&c.b, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f"%s%s/>`, x, y, w, h, svgPaint("fill", fill), svgPaint("stroke", stroke))
+}
+
+func (c *svgCanvas) Circle(cx, cy, r float64, fill, stroke color.NRGBA) {
+	fmt.Fprintf(&c.b, `<circle cx="%.2f" cy="%.2f" r="%.2f"%s%s/>`, cx, cy, r, svgPaint("fill", fill), svgPaint("stroke", stroke))
+}
+
+func (c *svgCanvas) Polygon(points [][2]float64, fill color.NRGBA) {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = fmt.Sprintf("%.2f,%.2f", p[0], p[1])
+	}
+	fmt.Fprintf(&c.b, `<polygon points="%s"%s/>`, strings.Join(parts, " "), svgPaint("fill", fill))
+}
+
+func (c *svgCanvas) Text(x, y float64, s string, size float64, anchor Anchor, vertical, bold bool, col color.NRGBA) {
+	attrs := fmt.Sprintf(` x="%.2f" y="%.2f" font-size="%g"%s`, x, y, size, svgPaint("fill", col))
+	switch anchor {
+	case AnchorMiddle:
+		attrs += ` text-anchor="middle"`
+	case AnchorEnd:
+		attrs += ` text-anchor="end"`
+	}
+	if bold {
+		attrs += ` font-weight="bold"`
+	}
+	if vertical {
+		attrs += fmt.Sprintf(` transform="rotate(-90 %.2f %.2f)"`, x, y)
+	}
+	var escaped bytes.Buffer
+	xml.EscapeText(&escaped, []byte(s))
+	fmt.Fprintf(&c.b, `<text%s>%s</text>`, attrs, escaped.String())
+}
+
+// pngScale adalah faktor supersampling PNG agar garis dan teks tetap tajam saat dicetak
+const pngScale = 2
+
+// pngCanvas merasterisasi bentuk dengan x/image/vector dan teks dengan font Go
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func newPNGCanvas(w, h float64) *pngCanvas {
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(w*pngScale)), int(math.Ceil(h*pngScale))))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	return &pngCanvas{img: img}
+}
+
+func (c *pngCanvas) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *pngCanvas) fill(points [][2]float64, col color.NRGBA) {
+	if col.A == 0 || len(points) < 3 {
+		return
+	}
+	// Rasterizer hanya seluas kotak pembatas bentuk; rasterizer seukuran gambar terlalu lambat untuk ratusan titik
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, minY = math.Min(minX, p[0]*pngScale), math.Min(minY, p[1]*pngScale)
+		maxX, maxY = math.Max(maxX, p[0]*pngScale), math.Max(maxY, p[1]*pngScale)
+	}
+	box := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX))+1, int(math.Ceil(maxY))+1)
+	if box.Intersect(c.img.Bounds()).Empty() {
+		return
+	}
+	r := vector.NewRasterizer(box.Dx(), box.Dy())
+	r.MoveTo(float32(points[0][0]*pngScale-float64(box.Min.X)), float32(points[0][1]*pngScale-float64(box.Min.Y)))
+	for _, p := range points[1:] {
+		r.LineTo(float32(p[0]*pngScale-float64(box.Min.X)), float32(p[1]*pngScale-float64(box.Min.Y)))
+	}
+	r.ClosePath()
+	r.Draw(c.img, box, image.NewUniform(col), image.Point{})
+}
+
+func (c *pngCanvas) segment(x1, y1, x2, y2 float64, col color.NRGBA, width float64) {
+	dx, dy := x2-x1, y2-y1
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return
+	}
+	nx, ny := -dy/length*width/2, dx/length*width/2
+	c.fill([][2]float64{{x1 + nx, y1 + ny}, {x2 + nx, y2 + ny}, {x2 - nx, y2 - ny}, {x1 - nx, y1 - ny}}, col)
+}
+
+func (c *pngCanvas) Line(x1, y1, x2, y2 float64, col color.NRGBA, width float64, dashed bool) {
+	if !dashed {
+		c.segment(x1, y1, x2, y2, col, width)
+		return
+	}
+	length := math.Hypot(x2-x1, y2-y1)
+	for start := 0.0; start < length; start += 10 {
+		end := math.Min(start+6, length)
+		c.segment(x1+(x2-x1)*start/length, y1+(y2-y1)*start/length, x1+(x2-x1)*end/length, y1+(y2-y1)*end/length, col, width)
+	}
+}
+
+func (c *pngCanvas) Rect(x, y, w, h float64, fill, stroke color.NRGBA) {
+	c.fill([][2]float64{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, fill)
+	if stroke.A > 0 {
+		c.Line(x, y, x+w, y, stroke, 1, false)
+		c.Line(x+w, y, x+w, y+h, stroke, 1, false)
+		c.Line(x+w, y+h, x, y+h, stroke, 1, false)
+		c.Line(x, y+h, x, y, stroke, 1, false)
+	}
+}
+
+func circlePoints(cx, cy, r float64) [][2]float64 {
+	const segments = 32
+	points := make([][2]float64, segments)
+	for i := range points {
+		a := 2 * math.Pi * float64(i) / segments
+		points[i] = [2]float64{cx + r*math.Cos(a), cy + r*math.Sin(a)}
+	}
+	return points
+}
+
+func (c *pngCanvas) Circle(cx, cy, r float64, fill, stroke color.NRGBA) {
+	points := circlePoints(cx, cy, r)
+	c.fill(points, fill)
+	if stroke.A > 0 {
+		for i := range points {
+			next := points[(i+1)%len(points)]
+			c.segment(points[i][0], points[i][1], next[0], next[1], stroke, 1)
+		}
+	}
+}
+
+func (c *pngCanvas) Polygon(points [][2]float64, fill color.NRGBA) {
+	c.fill(points, fill)
+}
+
+var (
+	fontOnce  sync.Once
+	fontErr   error
+	fontFaces = map[bool]*opentype.Font{}
+	faceMu    sync.Mutex
+	faceCache = map[[2]float64]font.Face{}
+)
+
+// face mengembalikan font Go (regular/bold) berukuran tertentu; face di-cache karena parsing TTF mahal
+func face(size float64, bold bool) (font.Face, error) {
+	fontOnce.Do(func() {
+		for isBold, ttf := range map[bool][]byte{false: goregular.TTF, true: gobold.TTF} {
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				fontErr = err
+				return
+			}
+			fontFaces[isBold] = f
+		}
+	})
+	if fontErr != nil {
+		return nil, fontErr
+	}
+	key := [2]float64{size, 0}
+	if bold {
+		key[1] = 1
+	}
+	faceMu.Lock()
+	defer faceMu.Unlock()
+	if f, ok := faceCache[key]; ok {
+		return f, nil
+	}
+	f, err := opentype.NewFace(fontFaces[bold], &opentype.FaceOptions{Size: size * pngScale, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	faceCache[key] = f
+	return f, nil
+}
+
+func (c *pngCanvas) Text(x, y float64, s string, size float64, anchor Anchor, vertical, bold bool, col color.NRGBA) {
+	f, err := face(size, bold)
+	if err != nil || s == "" {
+		return
+	}
+	faceMu.Lock()
+	defer faceMu.Unlock()
+	advance := font.MeasureString(f, s).Ceil()
+	shift := 0
+	switch anchor {
+	case AnchorMiddle:
+		shift = advance / 2
+	case AnchorEnd:
+		shift = advance
+	}
+	px, py := int(math.Round(x*pngScale)), int(math.Round(y*pngScale))
+	src := image.NewUniform(col)
+	if !vertical {
+		d := font.Drawer{Dst: c.img, Src: src, Face: f, Dot: fixed.P(px-shift, py)}
+		d.DrawString(s)
+		return
+	}
+
+	// Teks vertikal digambar mendatar pada gambar sementara lalu diputar 90° berlawanan arah jarum jam
+	metrics := f.Metrics()
+	ascent, height := metrics.Ascent.Ceil(), (metrics.Ascent + metrics.Descent).Ceil()
+	tmp := image.NewRGBA(image.Rect(0, 0, advance, height))
+	d := font.Drawer{Dst: tmp, Src: src, Face: f, Dot: fixed.P(0, ascent)}
+	d.DrawString(s)
+	rotated := image.NewRGBA(image.Rect(0, 0, height, advance))
+	for ty := 0; ty < height; ty++ {
+		for tx := 0; tx < advance; tx++ {
+			rotated.Set(ty, advance-1-tx, tmp.At(tx, ty))
+		}
+	}
+	origin := image.Pt(px-ascent, py-advance+shift)
+	draw.Draw(c.img, rotated.Bounds().Add(origin), rotated, image.Point{}, draw.Over)
+}
+
+// textWidth memperkirakan lebar teks dalam piksel untuk tata letak (label sumbu, legenda)
+func textWidth(s string, size float64) float64 {
+	f, err := face(size, false)
+	if err != nil {
+		return float64(len([]rune(s))) * size * 0.55
+	}
+	faceMu.Lock()
+	defer faceMu.Unlock()
+	return float64(font.MeasureString(f, s).Ceil()) / pngScale
+}
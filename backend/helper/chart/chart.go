@@ -0,0 +1,597 @@
+package chart
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/research-data-analysis/helper/stats"
+)
+
+// Jenis chart yang disimpan di model.Figure.Type
+const (
+	TypeHistogram = "histogram"
+	TypeBoxPlot   = "boxplot"
+	TypeQQ        = "qq"
+	TypeScatter   = "scatter"
+	TypeResiduals = "residuals"
+	TypeBar       = "bar"
+	TypeHeatmap   = "heatmap"
+	TypePath      = "path"
+)
+
+const (
+	defaultWidth  = 640
+	defaultHeight = 420
+	fontSize      = 12
+)
+
+var (
+	colorAxis   = color.NRGBA{0x33, 0x33, 0x33, 0xff}
+	colorGrid   = color.NRGBA{0xdd, 0xdd, 0xdd, 0xff}
+	colorText   = color.NRGBA{0x22, 0x22, 0x22, 0xff}
+	colorMuted  = color.NRGBA{0x66, 0x66, 0x66, 0xff}
+	colorFill   = color.NRGBA{0x4c, 0x78, 0xa8, 0xff}
+	colorPoint  = color.NRGBA{0x4c, 0x78, 0xa8, 0xb4}
+	colorAccent = color.NRGBA{0xe4, 0x57, 0x56, 0xff}
+	colorWhite  = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	colorNone   = color.NRGBA{}
+	palette     = []color.NRGBA{
+		{0x4c, 0x78, 0xa8, 0xff}, {0xf5, 0x85, 0x18, 0xff}, {0x54, 0xa2, 0x4b, 0xff}, {0xe4, 0x57, 0x56, 0xff},
+		{0x72, 0xb7, 0xb2, 0xff}, {0xee, 0xca, 0x3b, 0xff}, {0xb2, 0x79, 0xa2, 0xff}, {0x9d, 0x75, 0x5d, 0xff},
+	}
+)
+
+// Chart adalah gambar hasil analisis yang dapat dirender ke SVG maupun PNG dari satu fungsi gambar
+type Chart struct {
+	ID     string
+	Title  string
+	Type   string
+	Width  float64
+	Height float64
+	draw   func(c Canvas, w, h float64)
+}
+
+func newChart(id, title, kind string, draw func(c Canvas, w, h float64)) Chart {
+	return Chart{ID: id, Title: title, Type: kind, Width: defaultWidth, Height: defaultHeight, draw: draw}
+}
+
+// SVG merender chart sebagai dokumen SVG untuk tampilan web
+func (ch Chart) SVG() []byte {
+	c := newSVGCanvas(ch.Width, ch.Height)
+	ch.draw(c, ch.Width, ch.Height)
+	return c.bytes()
+}
+
+// PNG merender chart sebagai gambar PNG (resolusi 2x) untuk laporan PDF/DOCX/LaTeX
+func (ch Chart) PNG() ([]byte, error) {
+	c := newPNGCanvas(ch.Width, ch.Height)
+	ch.draw(c, ch.Width, ch.Height)
+	return c.encode()
+}
+
+// frame adalah area plot dengan skala linear untuk sumbu x dan y
+type frame struct {
+	c                        Canvas
+	left, top, right, bottom float64
+	xmin, xmax, ymin, ymax   float64
+}
+
+func newFrame(c Canvas, w, h float64, xmin, xmax, ymin, ymax float64) *frame {
+	if xmax <= xmin {
+		xmin, xmax = xmin-0.5, xmin+0.5
+	}
+	if ymax <= ymin {
+		ymin, ymax = ymin-0.5, ymin+0.5
+	}
+	return &frame{c: c, left: 72, top: 20, right: w - 24, bottom: h - 56, xmin: xmin, xmax: xmax, ymin: ymin, ymax: ymax}
+}
+
+func (f *frame) x(v float64) float64 {
+	return f.left + (v-f.xmin)/(f.xmax-f.xmin)*(f.right-f.left)
+}
+
+func (f *frame) y(v float64) float64 {
+	return f.bottom - (v-f.ymin)/(f.ymax-f.ymin)*(f.bottom-f.top)
+}
+
+// axes menggambar grid, tick, dan label sumbu; xTicks nil berarti sumbu x kategorik yang diberi label sendiri
+func (f *frame) axes(xLabel, yLabel string, xTicks, yTicks []float64) {
+	for _, t := range yTicks {
+		y := f.y(t)
+		f.c.Line(f.left, y, f.right, y, colorGrid, 1, false)
+		f.c.Text(f.left-6, y+4, formatTick(t, yTicks), fontSize-1, AnchorEnd, false, false, colorMuted)
+	}
+	for _, t := range xTicks {
+		x := f.x(t)
+		f.c.Line(x, f.bottom, x, f.bottom+4, colorAxis, 1, false)
+		f.c.Text(x, f.bottom+17, formatTick(t, xTicks), fontSize-1, AnchorMiddle, false, false, colorMuted)
+	}
+	f.c.Line(f.left, f.bottom, f.right, f.bottom, colorAxis, 1, false)
+	f.c.Line(f.left, f.top, f.left, f.bottom, colorAxis, 1, false)
+	if xLabel != "" {
+		f.c.Text((f.left+f.right)/2, f.bottom+42, truncate(xLabel, 70), fontSize, AnchorMiddle, false, false, colorText)
+	}
+	if yLabel != "" {
+		f.c.Text(18, (f.top+f.bottom)/2, truncate(yLabel, 45), fontSize, AnchorMiddle, true, false, colorText)
+	}
+}
+
+// categoryLabels menulis label kategori di bawah sumbu x untuk posisi tengah setiap kategori
+func (f *frame) categoryLabels(labels []string) {
+	width := (f.right - f.left) / float64(len(labels))
+	limit := int(width / 6.5)
+	if limit < 4 {
+		limit = 4
+	}
+	for i, l := range labels {
+		f.c.Text(f.x(float64(i)+0.5), f.bottom+17, truncate(l, limit), fontSize-1, AnchorMiddle, false, false, colorMuted)
+	}
+}
+
+// niceTicks menghasilkan tick "bulat" (1, 2, 5 × 10^k) yang mencakup rentang data
+func niceTicks(lo, hi float64, count int) []float64 {
+	if math.IsNaN(lo) || math.IsNaN(hi) || math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return nil
+	}
+	if hi <= lo {
+		lo, hi = lo-1, hi+1
+	}
+	raw := (hi - lo) / float64(count)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if m*mag >= raw {
+			step = m * mag
+			break
+		}
+	}
+	var ticks []float64
+	for t := math.Ceil(lo/step-1e-9) * step; t <= hi+step*1e-9; t += step {
+		ticks = append(ticks, math.Round(t/step)*step)
+	}
+	return ticks
+}
+
+// niceRange memperluas rentang ke tick terdekat agar sumbu berakhir pada angka bulat
+func niceRange(lo, hi float64) (float64, float64, []float64) {
+	ticks := niceTicks(lo, hi, 5)
+	if len(ticks) == 0 {
+		return lo, hi, nil
+	}
+	step := 1.0
+	if len(ticks) > 1 {
+		step = ticks[1] - ticks[0]
+	}
+	if ticks[0] > lo {
+		ticks = append([]float64{ticks[0] - step}, ticks...)
+	}
+	if ticks[len(ticks)-1] < hi {
+		ticks = append(ticks, ticks[len(ticks)-1]+step)
+	}
+	return ticks[0], ticks[len(ticks)-1], ticks
+}
+
+func formatTick(v float64, ticks []float64) string {
+	decimals := 0
+	if len(ticks) > 1 {
+		step := math.Abs(ticks[1] - ticks[0])
+		if step > 0 && step < 1 {
+			decimals = int(math.Ceil(-math.Log10(step) - 1e-9))
+			if step*math.Pow(10, float64(decimals)) != math.Round(step*math.Pow(10, float64(decimals))) {
+				decimals++
+			}
+		}
+	}
+	if math.Abs(v) < 1e-12 {
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e9 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func truncate(s string, limit int) string {
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	return string(r[:limit-1]) + "…"
+}
+
+func extent(values ...[]float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		for _, x := range v {
+			lo, hi = math.Min(lo, x), math.Max(hi, x)
+		}
+	}
+	return lo, hi
+}
+
+// Histogram menggambar distribusi frekuensi (aturan Sturges) dengan kurva normal pembanding dan garis acuan opsional
+func Histogram(id, title string, values []float64, xLabel, yLabel string, ref *float64) Chart {
+	return newChart(id, title, TypeHistogram, func(c Canvas, w, h float64) {
+		lo, hi := extent(values)
+		bins := int(math.Ceil(math.Log2(float64(len(values))))) + 1
+		if bins > 30 {
+			bins = 30
+		}
+		if bins < 1 || hi == lo {
+			bins = 1
+		}
+		width := (hi - lo) / float64(bins)
+		if width == 0 {
+			width = 1
+		}
+		counts := make([]float64, bins)
+		for _, v := range values {
+			i := int((v - lo) / width)
+			if i >= bins {
+				i = bins - 1
+			}
+			counts[i]++
+		}
+		mean, sd := stats.Mean(values), stats.StdDev(values)
+		_, maxCount := extent(counts)
+		peak := 0.0
+		if sd > 0 {
+			peak = float64(len(values)) * width / (sd * math.Sqrt(2*math.Pi))
+		}
+		xLo, xHi := lo, lo+width*float64(bins)
+		if ref != nil {
+			xLo, xHi = math.Min(xLo, *ref), math.Max(xHi, *ref)
+		}
+		xmin, xmax, xTicks := niceRange(xLo, xHi)
+		ymin, ymax, yTicks := niceRange(0, math.Max(maxCount, peak))
+		f := newFrame(c, w, h, xmin, xmax, ymin, ymax)
+		f.axes(xLabel, yLabel, xTicks, yTicks)
+		for i, n := range counts {
+			x0, x1 := f.x(lo+float64(i)*width), f.x(lo+float64(i+1)*width)
+			c.Rect(x0, f.y(n), x1-x0, f.y(0)-f.y(n), colorFill, colorWhite)
+		}
+		if sd > 0 {
+			prev := [2]float64{}
+			for i := 0; i <= 120; i++ {
+				v := xmin + (xmax-xmin)*float64(i)/120
+				z := (v - mean) / sd
+				p := [2]float64{f.x(v), f.y(peak * math.Exp(-z*z/2))}
+				if i > 0 {
+					c.Line(prev[0], prev[1], p[0], p[1], colorAccent, 1.5, false)
+				}
+				prev = p
+			}
+		}
+		if ref != nil {
+			c.Line(f.x(*ref), f.top, f.x(*ref), f.bottom, colorText, 1.5, true)
+		}
+	})
+}
+
+// boxStats menghitung ringkasan lima angka dengan whisker 1,5 × IQR dan pencilan
+type boxStats struct {
+	q1, median, q3, low, high float64
+	outliers                  []float64
+}
+
+func summarizeBox(values []float64) boxStats {
+	b := boxStats{q1: stats.Quantile(values, 0.25), median: stats.Median(values), q3: stats.Quantile(values, 0.75)}
+	iqr := b.q3 - b.q1
+	lower, upper := b.q1-1.5*iqr, b.q3+1.5*iqr
+	b.low, b.high = b.q3, b.q1
+	for _, v := range values {
+		if v < lower || v > upper {
+			b.outliers = append(b.outliers, v)
+			continue
+		}
+		b.low, b.high = math.Min(b.low, v), math.Max(b.high, v)
+	}
+	return b
+}
+
+// BoxPlot menggambar diagram kotak untuk setiap kelompok dengan rata-rata sebagai titik
+func BoxPlot(id, title string, labels []string, groups [][]float64, xLabel, yLabel string) Chart {
+	return newChart(id, title, TypeBoxPlot, func(c Canvas, w, h float64) {
+		lo, hi := extent(groups...)
+		ymin, ymax, yTicks := niceRange(lo, hi)
+		f := newFrame(c, w, h, 0, float64(len(groups)), ymin, ymax)
+		f.axes(xLabel, yLabel, nil, yTicks)
+		f.categoryLabels(labels)
+		slot := (f.right - f.left) / float64(len(groups))
+		boxWidth := math.Min(slot*0.5, 80)
+		for i, g := range groups {
+			if len(g) == 0 {
+				continue
+			}
+			b := summarizeBox(g)
+			cx := f.x(float64(i) + 0.5)
+			col := palette[i%len(palette)]
+			fill := col
+			fill.A = 0x66
+			c.Line(cx, f.y(b.low), cx, f.y(b.q1), colorAxis, 1, false)
+			c.Line(cx, f.y(b.q3), cx, f.y(b.high), colorAxis, 1, false)
+			c.Line(cx-boxWidth/4, f.y(b.low), cx+boxWidth/4, f.y(b.low), colorAxis, 1, false)
+			c.Line(cx-boxWidth/4, f.y(b.high), cx+boxWidth/4, f.y(b.high), colorAxis, 1, false)
+			c.Rect(cx-boxWidth/2, f.y(b.q3), boxWidth, f.y(b.q1)-f.y(b.q3), fill, col)
+			c.Line(cx-boxWidth/2, f.y(b.median), cx+boxWidth/2, f.y(b.median), colorAxis, 2, false)
+			c.Circle(cx, f.y(stats.Mean(g)), 3, colorWhite, colorAxis)
+			for _, o := range b.outliers {
+				c.Circle(cx, f.y(o), 2.5, colorNone, colorAccent)
+			}
+		}
+	})
+}
+
+// QQPlot membandingkan kuantil sampel dengan kuantil normal teoretis (posisi plot Blom)
+func QQPlot(id, title string, values []float64, xLabel, yLabel string) Chart {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	n := float64(len(sorted))
+	theoretical := make([]float64, len(sorted))
+	for i := range sorted {
+		theoretical[i] = stats.NormalQuantile((float64(i+1) - 0.375) / (n + 0.25))
+	}
+	return newChart(id, title, TypeQQ, func(c Canvas, w, h float64) {
+		mean, sd := stats.Mean(sorted), stats.StdDev(sorted)
+		xlo, xhi := extent(theoretical)
+		ylo, yhi := extent(sorted)
+		xmin, xmax, xTicks := niceRange(xlo, xhi)
+		ymin, ymax, yTicks := niceRange(math.Min(ylo, mean+sd*xlo), math.Max(yhi, mean+sd*xhi))
+		f := newFrame(c, w, h, xmin, xmax, ymin, ymax)
+		f.axes(xLabel, yLabel, xTicks, yTicks)
+		c.Line(f.x(xmin), f.y(mean+sd*xmin), f.x(xmax), f.y(mean+sd*xmax), colorAccent, 1.5, false)
+		for i, v := range sorted {
+			c.Circle(f.x(theoretical[i]), f.y(v), 3, colorPoint, colorNone)
+		}
+	})
+}
+
+// Scatter menggambar diagram pencar; fit menambahkan garis regresi OLS beserta persamaan dan R²
+func Scatter(id, title string, x, y []float64, xLabel, yLabel string, fit bool) Chart {
+	return newChart(id, title, TypeScatter, func(c Canvas, w, h float64) {
+		xlo, xhi := extent(x)
+		ylo, yhi := extent(y)
+		xmin, xmax, xTicks := niceRange(xlo, xhi)
+		ymin, ymax, yTicks := niceRange(ylo, yhi)
+		f := newFrame(c, w, h, xmin, xmax, ymin, ymax)
+		f.axes(xLabel, yLabel, xTicks, yTicks)
+		for i := range x {
+			c.Circle(f.x(x[i]), f.y(y[i]), 3, colorPoint, colorNone)
+		}
+		if !fit || len(x) < 3 {
+			return
+		}
+		res, err := stats.OLS(y, [][]float64{x}, []string{xLabel}, stats.DefaultAlpha)
+		if err != nil {
+			return
+		}
+		b0, b1 := res.Coefficients[0], res.Coefficients[1]
+		c.Line(f.x(xlo), f.y(b0+b1*xlo), f.x(xhi), f.y(b0+b1*xhi), colorAccent, 2, false)
+		sign := "+"
+		if b1 < 0 {
+			sign = "−"
+		}
+		label := "y = " + strconv.FormatFloat(b0, 'f', 2, 64) + " " + sign + " " + strconv.FormatFloat(math.Abs(b1), 'f', 2, 64) + "x,  R² = " + strconv.FormatFloat(res.R2, 'f', 3, 64)
+		c.Rect(f.left+8, f.top+4, textWidth(label, fontSize-1)+12, 20, color.NRGBA{0xff, 0xff, 0xff, 0xdc}, colorGrid)
+		c.Text(f.left+14, f.top+18, label, fontSize-1, AnchorStart, false, false, colorText)
+	})
+}
+
+// Residuals menggambar residual terhadap nilai prediksi dengan garis nol untuk memeriksa linearitas dan homoskedastisitas
+func Residuals(id, title string, fitted, residuals []float64, xLabel, yLabel string) Chart {
+	return newChart(id, title, TypeResiduals, func(c Canvas, w, h float64) {
+		xlo, xhi := extent(fitted)
+		ylo, yhi := extent(residuals)
+		bound := math.Max(math.Abs(ylo), math.Abs(yhi))
+		xmin, xmax, xTicks := niceRange(xlo, xhi)
+		ymin, ymax, yTicks := niceRange(-bound, bound)
+		f := newFrame(c, w, h, xmin, xmax, ymin, ymax)
+		f.axes(xLabel, yLabel, xTicks, yTicks)
+		c.Line(f.left, f.y(0), f.right, f.y(0), colorAccent, 1.5, true)
+		for i := range fitted {
+			c.Circle(f.x(fitted[i]), f.y(residuals[i]), 3, colorPoint, colorNone)
+		}
+	})
+}
+
+// BarOptions mengatur diagram batang: beberapa seri menghasilkan batang berkelompok dengan legenda
+type BarOptions struct {
+	Series    []string
+	XLabel    string
+	YLabel    string
+	Reference *float64 // garis acuan horizontal, mis. batas r item-total 0,30
+	Labels    bool     // tulis nilai di atas batang
+}
+
+// Bar menggambar diagram batang untuk kategori dengan satu atau beberapa seri nilai
+func Bar(id, title string, categories []string, values [][]float64, opts BarOptions) Chart {
+	return newChart(id, title, TypeBar, func(c Canvas, w, h float64) {
+		lo, hi := extent(values...)
+		lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+		if opts.Reference != nil {
+			lo, hi = math.Min(lo, *opts.Reference), math.Max(hi, *opts.Reference)
+		}
+		ymin, ymax, yTicks := niceRange(lo, hi)
+		f := newFrame(c, w, h, 0, float64(len(categories)), ymin, ymax)
+		legend := len(values) > 1
+		if legend {
+			f.right -= 120
+		}
+		f.axes(opts.XLabel, opts.YLabel, nil, yTicks)
+		f.categoryLabels(categories)
+		slot := (f.right - f.left) / float64(len(categories))
+		group := slot * 0.75
+		barWidth := group / float64(len(values))
+		for s, series := range values {
+			col := palette[s%len(palette)]
+			for i, v := range series {
+				if i >= len(categories) {
+					break
+				}
+				x := f.left + slot*float64(i) + (slot-group)/2 + barWidth*float64(s)
+				top, base := f.y(math.Max(v, 0)), f.y(math.Min(v, 0))
+				c.Rect(x, top, barWidth, base-top, col, colorNone)
+				if opts.Labels && barWidth >= 18 {
+					c.Text(x+barWidth/2, top-4, formatValue(v), fontSize-2, AnchorMiddle, false, false, colorMuted)
+				}
+			}
+		}
+		if ymin < 0 {
+			c.Line(f.left, f.y(0), f.right, f.y(0), colorAxis, 1, false)
+		}
+		if opts.Reference != nil {
+			c.Line(f.left, f.y(*opts.Reference), f.right, f.y(*opts.Reference), colorAccent, 1.5, true)
+		}
+		if legend {
+			for s, name := range opts.Series {
+				y := f.top + 8 + float64(s)*20
+				c.Rect(f.right+16, y-9, 12, 12, palette[s%len(palette)], colorNone)
+				c.Text(f.right+34, y+1, truncate(name, 14), fontSize-1, AnchorStart, false, false, colorText)
+			}
+		}
+	})
+}
+
+// heatColor memetakan korelasi -1..1 ke skala biru-putih-merah
+func heatColor(r float64) color.NRGBA {
+	r = math.Max(-1, math.Min(1, r))
+	blend := func(a, b uint8, t float64) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*t) }
+	if r >= 0 {
+		return color.NRGBA{blend(0xff, 0xc0, r), blend(0xff, 0x39, r), blend(0xff, 0x2b, r), 0xff}
+	}
+	return color.NRGBA{blend(0xff, 0x21, -r), blend(0xff, 0x66, -r), blend(0xff, 0xac, -r), 0xff}
+}
+
+// Heatmap menggambar matriks korelasi; nilai NaN (tidak dapat dihitung) dibiarkan abu-abu
+func Heatmap(id, title string, names []string, matrix [][]float64) Chart {
+	return newChart(id, title, TypeHeatmap, func(c Canvas, w, h float64) {
+		labelWidth := 0.0
+		for _, n := range names {
+			labelWidth = math.Max(labelWidth, textWidth(truncate(n, 20), fontSize-1))
+		}
+		left := labelWidth + 16
+		size := math.Min(w-left-90, h-60) / float64(len(names))
+		top := 16.0
+		for i, row := range matrix {
+			c.Text(left-6, top+size*(float64(i)+0.5)+4, truncate(names[i], 20), fontSize-1, AnchorEnd, false, false, colorText)
+			for j, r := range row {
+				x, y := left+size*float64(j), top+size*float64(i)
+				if math.IsNaN(r) {
+					c.Rect(x, y, size, size, colorGrid, colorWhite)
+					continue
+				}
+				c.Rect(x, y, size, size, heatColor(r), colorWhite)
+				if size >= 28 {
+					text := colorText
+					if math.Abs(r) > 0.6 {
+						text = colorWhite
+					}
+					c.Text(x+size/2, y+size/2+4, strings.Replace(strconv.FormatFloat(r, 'f', 2, 64), "-", "−", 1), fontSize-1, AnchorMiddle, false, false, text)
+				}
+			}
+		}
+		bottom := top + size*float64(len(names))
+		for j, n := range names {
+			c.Text(left+size*(float64(j)+0.5), bottom+16, truncate(n, int(math.Max(size/6.5, 4))), fontSize-1, AnchorMiddle, false, false, colorText)
+		}
+
+		// Legenda skala warna
+		lx, steps := left+size*float64(len(names))+24, 40
+		height := bottom - top
+		for s := 0; s < steps; s++ {
+			r := 1 - 2*float64(s)/float64(steps-1)
+			c.Rect(lx, top+height*float64(s)/float64(steps), 14, height/float64(steps)+0.5, heatColor(r), colorNone)
+		}
+		for _, t := range []float64{1, 0.5, 0, -0.5, -1} {
+			c.Text(lx+20, top+height*(1-t)/2+4, formatTick(t, []float64{0, 0.5}), fontSize-2, AnchorStart, false, false, colorMuted)
+		}
+	})
+}
+
+// PathEdge adalah satu jalur (panah) pada diagram jalur
+type PathEdge struct {
+	From, To string
+	Label    string
+	Dashed   bool
+}
+
+// PathNode adalah variabel pada diagram jalur dengan posisi relatif 0..1
+type PathNode struct {
+	Name string
+	X, Y float64
+}
+
+// PathDiagram menggambar model jalur (mediasi/SEM) dengan kotak variabel dan panah berlabel koefisien
+func PathDiagram(id, title string, nodes []PathNode, edges []PathEdge) Chart {
+	return newChart(id, title, TypePath, func(c Canvas, w, h float64) {
+		boxW, boxH := 150.0, 44.0
+		pos := map[string][2]float64{}
+		for _, n := range nodes {
+			x := 40 + boxW/2 + n.X*(w-80-boxW)
+			y := 30 + boxH/2 + n.Y*(h-60-boxH)
+			pos[n.Name] = [2]float64{x, y}
+			c.Rect(x-boxW/2, y-boxH/2, boxW, boxH, color.NRGBA{0xf2, 0xf5, 0xfa, 0xff}, colorAxis)
+			c.Text(x, y+5, truncate(n.Name, 20), fontSize+1, AnchorMiddle, false, true, colorText)
+		}
+		for _, e := range edges {
+			from, okFrom := pos[e.From]
+			to, okTo := pos[e.To]
+			if !okFrom || !okTo {
+				continue
+			}
+			x1, y1 := boxEdge(from, to, boxW, boxH)
+			x2, y2 := boxEdge(to, from, boxW, boxH)
+			c.Line(x1, y1, x2, y2, colorAxis, 1.5, e.Dashed)
+			arrowHead(c, x1, y1, x2, y2)
+			// Label diletakkan di sisi atas garis dan digeser menjauh agar tidak menimpa garis miring
+			mx, my := (x1+x2)/2, (y1+y2)/2
+			dx, dy := x2-x1, y2-y1
+			length := math.Hypot(dx, dy)
+			ox, oy := -dy/length*10, dx/length*10
+			if oy > 0 {
+				ox, oy = -ox, -oy
+			}
+			anchor := AnchorMiddle
+			switch {
+			case ox < -3:
+				anchor = AnchorEnd
+			case ox > 3:
+				anchor = AnchorStart
+			}
+			c.Text(mx+ox, my+oy+4, e.Label, fontSize, anchor, false, false, colorText)
+		}
+	})
+}
+
+// boxEdge mencari titik potong garis antar pusat kotak dengan tepi kotak asal
+func boxEdge(from, to [2]float64, w, h float64) (float64, float64) {
+	dx, dy := to[0]-from[0], to[1]-from[1]
+	if dx == 0 && dy == 0 {
+		return from[0], from[1]
+	}
+	scale := math.Inf(1)
+	if dx != 0 {
+		scale = math.Min(scale, (w/2)/math.Abs(dx))
+	}
+	if dy != 0 {
+		scale = math.Min(scale, (h/2)/math.Abs(dy))
+	}
+	return from[0] + dx*scale, from[1] + dy*scale
+}
+
+func arrowHead(c Canvas, x1, y1, x2, y2 float64) {
+	angle := math.Atan2(y2-y1, x2-x1)
+	const size, spread = 10.0, 0.4
+	c.Polygon([][2]float64{
+		{x2, y2},
+		{x2 - size*math.Cos(angle-spread), y2 - size*math.Sin(angle-spread)},
+		{x2 - size*math.Cos(angle+spread), y2 - size*math.Sin(angle+spread)},
+	}, colorAxis)
+}
@@ -0,0 +1,383 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/model"
+)
+
+// maxChartsPerKind membatasi jumlah chart sejenis per metode (mis. histogram descriptive untuk banyak variabel)
+const maxChartsPerKind = 8
+
+// ForResult menyusun chart yang relevan untuk satu hasil metode dari data mentah dan RawOutput.
+// Hasil yang gagal atau data yang tidak cukup menghasilkan daftar kosong, bukan error.
+func ForResult(data stats.Data, result model.MethodResult, lang string) []Chart {
+	if result.Error != "" || result.RawOutput == nil {
+		return nil
+	}
+	raw := stats.NormalizeOutput(result.RawOutput)
+	vars := stats.Strings(raw, stats.KeyVariables)
+	b := builder{data: data, lang: lang, method: result.MethodID, raw: raw}
+
+	switch result.MethodID {
+	case stats.MethodDescriptive:
+		numeric, categorical := tableColumns(raw, "descriptives"), tableColumns(raw, "frequencies")
+		for i, v := range limit(numeric) {
+			b.histogram(i+1, v, nil)
+		}
+		for i, v := range limit(categorical) {
+			b.frequencies(i+1, v)
+		}
+	case stats.MethodNormality:
+		for i, v := range limit(vars) {
+			b.qq(i+1, v)
+		}
+	case stats.MethodOneSampleTTest:
+		if len(vars) > 0 {
+			mu, _ := stats.Number(raw, "test_value")
+			b.histogram(1, vars[0], &mu)
+		}
+	case stats.MethodIndependentTTest, stats.MethodMannWhitney, stats.MethodOneWayANOVA, stats.MethodKruskalWallis:
+		if len(vars) >= 2 {
+			b.groupBox(vars[0], vars[1], stats.Strings(raw, "groups"))
+		}
+	case stats.MethodPairedTTest, stats.MethodWilcoxon:
+		if len(vars) >= 2 {
+			b.pairBox(vars[0], vars[1])
+		}
+	case stats.MethodChiSquare:
+		if len(vars) >= 2 {
+			b.crosstab(vars[0], vars[1])
+		}
+	case stats.MethodPearson, stats.MethodSpearman:
+		if len(vars) == 2 {
+			b.scatter(vars[1], vars[0])
+		} else if len(vars) > 2 {
+			b.heatmap(vars)
+		}
+	case stats.MethodLinearRegression:
+		dependent, predictors := stats.String(raw, "dependent"), stats.Strings(raw, "predictors")
+		if dependent != "" && len(predictors) > 0 {
+			if len(predictors) == 1 {
+				b.scatter(predictors[0], dependent)
+			}
+			b.residuals(dependent, predictors)
+		}
+	case stats.MethodModeration:
+		dependent, independent := stats.String(raw, "dependent"), stats.String(raw, "independent")
+		if moderator := stats.String(raw, "moderator"); dependent != "" && independent != "" && moderator != "" {
+			b.moderationPath(dependent, independent, moderator)
+		}
+	case stats.MethodMediation:
+		b.mediationPath()
+	case stats.MethodReliability:
+		b.itemTotal()
+	}
+	return b.charts
+}
+
+type builder struct {
+	data   stats.Data
+	lang   string
+	method string
+	raw    map[string]interface{}
+	charts []Chart
+}
+
+func (b *builder) id(kind string, n int) string {
+	return fmt.Sprintf("%s_%s_%d", b.method, kind, n)
+}
+
+func (b *builder) add(c Chart) {
+	b.charts = append(b.charts, c)
+}
+
+func (b *builder) histogram(n int, column string, ref *float64) {
+	values, err := b.data.CompleteCases(column)
+	if err != nil || len(values[0]) < 2 {
+		return
+	}
+	b.add(Histogram(b.id(TypeHistogram, n), i18n.T(b.lang, "chart.histogram", column), values[0], column, i18n.T(b.lang, "chart.frequency_axis"), ref))
+}
+
+func (b *builder) qq(n int, column string) {
+	values, err := b.data.CompleteCases(column)
+	if err != nil || len(values[0]) < 3 {
+		return
+	}
+	b.add(QQPlot(b.id(TypeQQ, n), i18n.T(b.lang, "chart.qq", column), values[0], i18n.T(b.lang, "chart.theoretical"), i18n.T(b.lang, "chart.sample", column)))
+}
+
+// frequencies menggambar distribusi frekuensi dari tabel frequencies hasil descriptive
+func (b *builder) frequencies(n int, column string) {
+	t, ok := stats.FindTable(b.raw, "frequencies")
+	if !ok {
+		return
+	}
+	var categories []string
+	var counts []float64
+	for _, row := range t.Rows {
+		if len(row) < 3 || row[0] != column {
+			continue
+		}
+		count, _ := stats.Float(row[2])
+		categories = append(categories, fmt.Sprint(row[1]))
+		counts = append(counts, count)
+	}
+	if len(categories) == 0 || len(categories) > 30 {
+		return
+	}
+	b.add(Bar(b.id(TypeBar, n), i18n.T(b.lang, "chart.frequency", column), categories, [][]float64{counts},
+		BarOptions{XLabel: column, YLabel: i18n.T(b.lang, "chart.frequency_axis"), Labels: true}))
+}
+
+// groupBox menggambar diagram kotak variabel dependen per kelompok dengan urutan label seperti engine
+func (b *builder) groupBox(dependent, group string, order []string) {
+	labels, groups, err := b.data.Groups(dependent, group)
+	if err != nil || len(groups) == 0 {
+		return
+	}
+	if len(order) > 0 {
+		index := map[string]int{}
+		for i, l := range labels {
+			index[l] = i
+		}
+		var orderedLabels []string
+		var ordered [][]float64
+		for _, l := range order {
+			if i, ok := index[l]; ok {
+				orderedLabels = append(orderedLabels, l)
+				ordered = append(ordered, groups[i])
+			}
+		}
+		if len(ordered) > 0 {
+			labels, groups = orderedLabels, ordered
+		}
+	}
+	if len(groups) > 20 {
+		return
+	}
+	b.add(BoxPlot(b.id(TypeBoxPlot, 1), i18n.T(b.lang, "chart.boxplot", dependent, group), labels, groups, group, dependent))
+}
+
+func (b *builder) pairBox(first, second string) {
+	values, err := b.data.CompleteCases(first, second)
+	if err != nil || len(values[0]) == 0 {
+		return
+	}
+	b.add(BoxPlot(b.id(TypeBoxPlot, 1), i18n.T(b.lang, "chart.boxplot_pair", first, second), []string{first, second}, values, "", i18n.T(b.lang, "chart.value")))
+}
+
+// crosstab menggambar batang berkelompok dari tabel crosstab hasil chi-square
+func (b *builder) crosstab(rowVar, colVar string) {
+	t, ok := stats.FindTable(b.raw, "crosstab")
+	if !ok || len(t.Columns) < 2 || len(t.Rows) == 0 {
+		return
+	}
+	categories := make([]string, len(t.Rows))
+	series := make([][]float64, len(t.Columns)-1)
+	for i, row := range t.Rows {
+		categories[i] = fmt.Sprint(row[0])
+		for j := range series {
+			v, _ := stats.Float(cellAt(row, j+1))
+			series[j] = append(series[j], v)
+		}
+	}
+	if len(categories) > 20 || len(series) > len(palette) {
+		return
+	}
+	b.add(Bar(b.id(TypeBar, 1), i18n.T(b.lang, "chart.crosstab", rowVar, colVar), categories, series,
+		BarOptions{Series: t.Columns[1:], XLabel: rowVar, YLabel: i18n.T(b.lang, "chart.frequency_axis"), Labels: len(series) <= 3}))
+}
+
+func (b *builder) scatter(x, y string) {
+	values, err := b.data.CompleteCases(x, y)
+	if err != nil || len(values[0]) < 3 {
+		return
+	}
+	b.add(Scatter(b.id(TypeScatter, 1), i18n.T(b.lang, "chart.scatter", y, x), values[0], values[1], x, y, true))
+}
+
+func (b *builder) heatmap(vars []string) {
+	t, ok := stats.FindTable(b.raw, "correlation_matrix")
+	if !ok || len(vars) > 15 {
+		return
+	}
+	matrix := make([][]float64, len(t.Rows))
+	for i, row := range t.Rows {
+		matrix[i] = make([]float64, len(vars))
+		for j := range vars {
+			v, ok := stats.Float(cellAt(row, j+1))
+			if !ok {
+				v = math.NaN()
+			}
+			matrix[i][j] = v
+		}
+	}
+	if len(matrix) != len(vars) {
+		return
+	}
+	kind := "Pearson"
+	if b.method == stats.MethodSpearman {
+		kind = "Spearman"
+	}
+	b.add(Heatmap(b.id(TypeHeatmap, 1), i18n.T(b.lang, "chart.heatmap", kind), vars, matrix))
+}
+
+// residuals menghitung ulang model OLS pada kasus lengkap untuk memperoleh nilai prediksi dan residual
+func (b *builder) residuals(dependent string, predictors []string) {
+	values, err := b.data.CompleteCases(append([]string{dependent}, predictors...)...)
+	if err != nil {
+		return
+	}
+	res, err := stats.OLS(values[0], values[1:], predictors, stats.DefaultAlpha)
+	if err != nil {
+		return
+	}
+	fitted := make([]float64, len(res.Residuals))
+	for i, e := range res.Residuals {
+		fitted[i] = values[0][i] - e
+	}
+	b.add(Residuals(b.id(TypeResiduals, 1), i18n.T(b.lang, "chart.residuals", dependent), fitted, res.Residuals, i18n.T(b.lang, "chart.fitted"), i18n.T(b.lang, "chart.residual")))
+	if len(res.Residuals) >= 3 {
+		b.add(QQPlot(b.id(TypeQQ, 1), i18n.T(b.lang, "chart.qq_residuals", dependent), res.Residuals, i18n.T(b.lang, "chart.theoretical"), i18n.T(b.lang, "chart.residual")))
+	}
+}
+
+// mediationPath menggambar model X → M → Y dengan koefisien dari tabel paths
+func (b *builder) mediationPath() {
+	t, ok := stats.FindTable(b.raw, "paths")
+	if !ok {
+		return
+	}
+	x, m, y := stats.String(b.raw, "independent"), stats.String(b.raw, "mediator"), stats.String(b.raw, "dependent")
+	if x == "" || m == "" || y == "" {
+		return
+	}
+	coef := map[string]string{}
+	for _, row := range t.Rows {
+		path, _ := cellAt(row, 0).(string)
+		estimate, ok := stats.Float(cellAt(row, 2))
+		if !ok {
+			continue
+		}
+		p, _ := stats.Float(cellAt(row, 5))
+		coef[path] = fmt.Sprintf("%s = %.2f%s", path, estimate, stars(p))
+	}
+	direct := coef["c'"]
+	if total := coef["c"]; total != "" {
+		direct += " (" + total + ")"
+	}
+	b.add(PathDiagram(b.id(TypePath, 1), i18n.T(b.lang, "chart.path_mediation"),
+		[]PathNode{{Name: x, X: 0, Y: 1}, {Name: m, X: 0.5, Y: 0}, {Name: y, X: 1, Y: 1}},
+		[]PathEdge{{From: x, To: m, Label: coef["a"]}, {From: m, To: y, Label: coef["b"]}, {From: x, To: y, Label: direct}}))
+}
+
+// moderationPath menggambar diagram konseptual moderasi dengan koefisien prediktor dari tabel coefficients
+func (b *builder) moderationPath(dependent, independent, moderator string) {
+	t, ok := stats.FindTable(b.raw, "coefficients")
+	if !ok {
+		return
+	}
+	term, estimate, pCol := columnIndex(t, "Term"), columnIndex(t, "B"), columnIndex(t, "p")
+	coef := map[string]string{}
+	for _, row := range t.Rows {
+		name, _ := cellAt(row, term).(string)
+		v, ok := stats.Float(cellAt(row, estimate))
+		if !ok {
+			continue
+		}
+		p, _ := stats.Float(cellAt(row, pCol))
+		coef[name] = fmt.Sprintf("B = %.2f%s", v, stars(p))
+	}
+	interaction := independent + " x " + moderator
+	b.add(PathDiagram(b.id(TypePath, 1), i18n.T(b.lang, "chart.path_moderation"),
+		[]PathNode{{Name: independent, X: 0, Y: 1}, {Name: moderator, X: 0.5, Y: 0}, {Name: interaction, X: 0, Y: 0}, {Name: dependent, X: 1, Y: 1}},
+		[]PathEdge{
+			{From: independent, To: dependent, Label: coef[independent]},
+			{From: moderator, To: dependent, Label: coef[moderator]},
+			{From: interaction, To: dependent, Label: coef[interaction], Dashed: true},
+		}))
+}
+
+// itemTotal menggambar korelasi item-total terkoreksi dengan batas 0,30
+func (b *builder) itemTotal() {
+	t, ok := stats.FindTable(b.raw, "item_total")
+	if !ok {
+		return
+	}
+	col := columnIndex(t, "Corrected item-total r")
+	var items []string
+	var values []float64
+	for _, row := range t.Rows {
+		v, ok := stats.Float(cellAt(row, col))
+		if !ok {
+			continue
+		}
+		items = append(items, fmt.Sprint(cellAt(row, 0)))
+		values = append(values, v)
+	}
+	if len(items) == 0 || len(items) > 40 {
+		return
+	}
+	threshold := 0.3
+	b.add(Bar(b.id(TypeBar, 1), i18n.T(b.lang, "chart.item_total"), items, [][]float64{values},
+		BarOptions{XLabel: i18n.T(b.lang, "chart.item"), YLabel: i18n.T(b.lang, "chart.item_total_axis"), Reference: &threshold, Labels: len(items) <= 12}))
+}
+
+// tableColumns mengambil nama variabel unik dari kolom pertama tabel
+func tableColumns(raw map[string]interface{}, name string) []string {
+	t, ok := stats.FindTable(raw, name)
+	if !ok {
+		return nil
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, row := range t.Rows {
+		if s, ok := cellAt(row, 0).(string); ok && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func limit(names []string) []string {
+	if len(names) > maxChartsPerKind {
+		return names[:maxChartsPerKind]
+	}
+	return names
+}
+
+func columnIndex(t stats.Table, name string) int {
+	for i, c := range t.Columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func cellAt(row []interface{}, i int) interface{} {
+	if i < 0 || i >= len(row) {
+		return nil
+	}
+	return row[i]
+}
+
+func stars(p float64) string {
+	switch {
+	case p <= 0:
+		return ""
+	case p < .001:
+		return "***"
+	case p < .01:
+		return "**"
+	case p < .05:
+		return "*"
+	}
+	return ""
+}
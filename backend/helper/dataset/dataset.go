@@ -0,0 +1,320 @@
+package dataset
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dataset menyimpan data tabular hasil parsing file upload
+type Dataset struct {
+	FileName string     `json:"file_name"`
+	Columns  []string   `json:"columns"`
+	Rows     [][]string `json:"rows"`
+}
+
+// ErrUnsupportedFormat dikembalikan jika format file tidak dapat dibaca
+var ErrUnsupportedFormat = errors.New("unsupported data file format, use CSV, TSV or XLSX")
+
+// Parse membaca file upload (CSV, TSV, TXT, atau XLSX) menjadi Dataset
+func Parse(fileName string, data []byte) (*Dataset, error) {
+	var (
+		ds  *Dataset
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx":
+		ds, err = parseXLSX(data)
+	case ".csv", ".tsv", ".txt", "":
+		ds, err = parseDelimited(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ds.FileName = fileName
+	if len(ds.Columns) == 0 {
+		return nil, errors.New("data file has no header row")
+	}
+	return ds, nil
+}
+
+// parseDelimited membaca teks berpemisah dengan mendeteksi delimiter (koma, titik koma, tab, pipe)
+func parseDelimited(data []byte) (*Dataset, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		return nil, ErrUnsupportedFormat
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+
+	ds := &Dataset{Columns: normalizeHeader(header)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read row %d: %v", len(ds.Rows)+2, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		ds.Rows = append(ds.Rows, fitRecord(record, len(ds.Columns)))
+	}
+	return ds, nil
+}
+
+// detectDelimiter memilih delimiter yang paling sering muncul pada baris header
+func detectDelimiter(data []byte) rune {
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+	}
+
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{',', ';', '\t', '|'} {
+		if count := bytes.Count(line, []byte(string(candidate))); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+// normalizeHeader merapikan nama kolom dan memberi nama untuk kolom kosong/duplikat
+func normalizeHeader(header []string) []string {
+	seen := make(map[string]int)
+	columns := make([]string, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = fmt.Sprintf("col%d", i+1)
+		}
+		if n := seen[strings.ToLower(name)]; n > 0 {
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		}
+		seen[strings.ToLower(name)]++
+		columns[i] = name
+	}
+	return columns
+}
+
+// fitRecord menyamakan panjang record dengan jumlah kolom
+func fitRecord(record []string, width int) []string {
+	row := make([]string, width)
+	for i := 0; i < width && i < len(record); i++ {
+		row[i] = strings.TrimSpace(record[i])
+	}
+	return row
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// NumRows mengembalikan jumlah baris data
+func (d *Dataset) NumRows() int {
+	return len(d.Rows)
+}
+
+// ColumnIndex mencari indeks kolom (tidak peka huruf besar/kecil), -1 jika tidak ada
+func (d *Dataset) ColumnIndex(name string) int {
+	name = strings.TrimSpace(name)
+	for i, column := range d.Columns {
+		if column == name {
+			return i
+		}
+	}
+	for i, column := range d.Columns {
+		if strings.EqualFold(column, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// HasColumn mengecek apakah kolom ada di dataset
+func (d *Dataset) HasColumn(name string) bool {
+	return d.ColumnIndex(name) >= 0
+}
+
+// Values mengambil seluruh nilai mentah satu kolom
+func (d *Dataset) Values(name string) ([]string, error) {
+	idx := d.ColumnIndex(name)
+	if idx < 0 {
+		return nil, fmt.Errorf("column %q not found", name)
+	}
+	values := make([]string, len(d.Rows))
+	for i, row := range d.Rows {
+		values[i] = row[idx]
+	}
+	return values, nil
+}
+
+// Numeric mengambil nilai numerik satu kolom, melewati nilai kosong
+func (d *Dataset) Numeric(name string) ([]float64, error) {
+	columns, err := d.CompleteCases(name)
+	if err != nil {
+		return nil, err
+	}
+	return columns[0], nil
+}
+
+// CompleteCases mengambil beberapa kolom numerik dengan listwise deletion
+// (hanya baris yang lengkap pada semua kolom yang dipakai)
+func (d *Dataset) CompleteCases(names ...string) ([][]float64, error) {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		indexes[i] = d.ColumnIndex(name)
+		if indexes[i] < 0 {
+			return nil, fmt.Errorf("column %q not found", name)
+		}
+	}
+
+	columns := make([][]float64, len(names))
+	for _, row := range d.Rows {
+		values := make([]float64, len(indexes))
+		complete := true
+		for i, idx := range indexes {
+			if IsMissing(row[idx]) {
+				complete = false
+				break
+			}
+			v, ok := ParseNumber(row[idx])
+			if !ok {
+				return nil, fmt.Errorf("column %q contains non-numeric value %q", names[i], row[idx])
+			}
+			values[i] = v
+		}
+		if !complete {
+			continue
+		}
+		for i, v := range values {
+			columns[i] = append(columns[i], v)
+		}
+	}
+	return columns, nil
+}
+
+// Groups mengelompokkan nilai numerik kolom value berdasarkan kolom group.
+// Urutan kelompok mengikuti kemunculan pertama di data.
+func (d *Dataset) Groups(valueColumn, groupColumn string) ([]string, [][]float64, error) {
+	vi, gi := d.ColumnIndex(valueColumn), d.ColumnIndex(groupColumn)
+	if vi < 0 {
+		return nil, nil, fmt.Errorf("column %q not found", valueColumn)
+	}
+	if gi < 0 {
+		return nil, nil, fmt.Errorf("column %q not found", groupColumn)
+	}
+
+	var labels []string
+	index := make(map[string]int)
+	var groups [][]float64
+	for _, row := range d.Rows {
+		if IsMissing(row[vi]) || IsMissing(row[gi]) {
+			continue
+		}
+		v, ok := ParseNumber(row[vi])
+		if !ok {
+			return nil, nil, fmt.Errorf("column %q contains non-numeric value %q", valueColumn, row[vi])
+		}
+		label := row[gi]
+		k, exists := index[label]
+		if !exists {
+			k = len(labels)
+			index[label] = k
+			labels = append(labels, label)
+			groups = append(groups, nil)
+		}
+		groups[k] = append(groups[k], v)
+	}
+	return labels, groups, nil
+}
+
+// IsMissing mengecek apakah nilai dianggap data hilang
+func IsMissing(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "", "na", "n/a", "nan", "null", "none", ".", "-", "?":
+		return true
+	}
+	return false
+}
+
+// ParseNumber mem-parse angka, termasuk format desimal koma (mis. "3,5" atau "1.234,5")
+func ParseNumber(v string) (float64, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if f, err := strconv.ParseFloat(v, 64); err == nil {
+		return f, !math.IsNaN(f) && !math.IsInf(f, 0)
+	}
+
+	if strings.Contains(v, ",") {
+		normalized := v
+		if strings.Contains(v, ".") {
+			if strings.LastIndex(v, ",") < strings.LastIndex(v, ".") {
+				// Format 1,234.5
+				normalized = strings.ReplaceAll(v, ",", "")
+			} else {
+				// Format 1.234,5
+				normalized = strings.ReplaceAll(strings.ReplaceAll(v, ".", ""), ",", ".")
+			}
+		} else {
+			normalized = strings.ReplaceAll(v, ",", ".")
+		}
+		if f, err := strconv.ParseFloat(normalized, 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// ColumnNames mengembalikan nama kolom dataset
+func (d *Dataset) ColumnNames() []string {
+	return d.Columns
+}
+
+// CategoryPairs mengambil pasangan nilai kategorik dua kolom, melewati baris yang kosong pada salah satunya
+func (d *Dataset) CategoryPairs(a, b string) ([]string, []string, error) {
+	ai, bi := d.ColumnIndex(a), d.ColumnIndex(b)
+	if ai < 0 {
+		return nil, nil, fmt.Errorf("column %q not found", a)
+	}
+	if bi < 0 {
+		return nil, nil, fmt.Errorf("column %q not found", b)
+	}
+
+	var x, y []string
+	for _, row := range d.Rows {
+		if IsMissing(row[ai]) || IsMissing(row[bi]) {
+			continue
+		}
+		x = append(x, strings.TrimSpace(row[ai]))
+		y = append(y, strings.TrimSpace(row[bi]))
+	}
+	return x, y, nil
+}
@@ -0,0 +1,175 @@
+package dataset
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/model"
+)
+
+// Tipe kolom hasil inferensi
+const (
+	TypeNumeric     = "numeric"
+	TypeCategorical = "categorical"
+	TypeBoolean     = "boolean"
+	TypeDate        = "date"
+	TypeText        = "text"
+)
+
+// maxFrequencyLevels membatasi jumlah level yang frekuensinya disimpan di ringkasan
+const maxFrequencyLevels = 50
+
+var dateLayouts = []string{"2006-01-02", "02/01/2006", "2006/01/02", "02-01-2006", "2006-01-02 15:04:05", time.RFC3339}
+
+// Summarize membuat ringkasan data: tipe kolom, skala pengukuran, jumlah level, data hilang, dan statistik
+func Summarize(d *Dataset) model.DataSummary {
+	summary := model.DataSummary{
+		Rows:         d.NumRows(),
+		Columns:      len(d.Columns),
+		ColumnNames:  append([]string(nil), d.Columns...),
+		ColumnTypes:  make(map[string]string),
+		Scales:       make(map[string]string),
+		Levels:       make(map[string]int),
+		Frequencies:  make(map[string]map[string]int),
+		MissingCount: make(map[string]int),
+		Statistics:   make(map[string]interface{}),
+	}
+
+	for i, column := range d.Columns {
+		var present []string
+		for _, row := range d.Rows {
+			if IsMissing(row[i]) {
+				summary.MissingCount[column]++
+				continue
+			}
+			present = append(present, row[i])
+		}
+
+		frequencies := countLevels(present)
+		columnType := inferType(present, len(frequencies))
+		summary.ColumnTypes[column] = columnType
+		summary.Levels[column] = len(frequencies)
+
+		var numbers []float64
+		if columnType == TypeNumeric {
+			for _, v := range present {
+				f, _ := ParseNumber(v)
+				numbers = append(numbers, f)
+			}
+		}
+		summary.Scales[column] = inferScale(columnType, numbers, len(frequencies))
+		if len(frequencies) <= maxFrequencyLevels {
+			summary.Frequencies[column] = frequencies
+		}
+
+		switch {
+		case columnType == TypeNumeric && summary.Scales[column] == stats.ScaleInterval:
+			summary.Statistics[column] = stats.Describe(numbers)
+		case columnType == TypeNumeric:
+			entry := stats.Describe(numbers)
+			entry["levels"] = len(frequencies)
+			summary.Statistics[column] = entry
+		default:
+			summary.Statistics[column] = map[string]interface{}{
+				"n":      len(present),
+				"levels": len(frequencies),
+			}
+		}
+	}
+
+	return summary
+}
+
+// countLevels menghitung frekuensi setiap nilai unik
+func countLevels(values []string) map[string]int {
+	frequencies := make(map[string]int)
+	for _, v := range values {
+		frequencies[v]++
+	}
+	return frequencies
+}
+
+// inferType menentukan tipe kolom dari nilai yang tidak hilang
+func inferType(values []string, distinct int) string {
+	if len(values) == 0 {
+		return TypeText
+	}
+
+	numeric, boolean, date := true, true, true
+	for _, v := range values {
+		if numeric {
+			if _, ok := ParseNumber(v); !ok {
+				numeric = false
+			}
+		}
+		if boolean && !isBooleanLiteral(v) {
+			boolean = false
+		}
+		if date && !isDate(v) {
+			date = false
+		}
+		if !numeric && !boolean && !date {
+			break
+		}
+	}
+
+	switch {
+	case numeric:
+		return TypeNumeric
+	case boolean:
+		return TypeBoolean
+	case date:
+		return TypeDate
+	case distinct <= 20 || float64(distinct) <= 0.5*float64(len(values)):
+		return TypeCategorical
+	}
+	return TypeText
+}
+
+// inferScale menebak skala pengukuran (nominal, ordinal, interval) dari tipe dan sebaran nilai.
+// Skala rasio tidak dapat dibedakan dari interval tanpa pengetahuan substantif, sehingga
+// pengguna dapat menimpanya lewat Project.Variables.Scales.
+func inferScale(columnType string, numbers []float64, distinct int) string {
+	switch columnType {
+	case TypeNumeric:
+		if distinct <= 2 {
+			return stats.ScaleNominal
+		}
+		lo, hi := stats.MinMax(numbers)
+		if distinct <= 10 && lo >= 0 && hi <= 10 && allIntegers(numbers) {
+			return stats.ScaleOrdinal
+		}
+		return stats.ScaleInterval
+	case TypeDate:
+		return stats.ScaleInterval
+	}
+	return stats.ScaleNominal
+}
+
+func allIntegers(x []float64) bool {
+	for _, v := range x {
+		if v != math.Trunc(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func isBooleanLiteral(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "true", "false", "yes", "no", "ya", "tidak", "y", "n":
+		return true
+	}
+	return false
+}
+
+func isDate(v string) bool {
+	for _, layout := range dateLayouts {
+		if _, err := time.Parse(layout, v); err == nil {
+			return true
+		}
+	}
+	return false
+}
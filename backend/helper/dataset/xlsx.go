@@ -0,0 +1,196 @@
+package dataset
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// xlsxSharedStrings untuk xl/sharedStrings.xml
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+// xlsxWorksheet untuk xl/worksheets/sheetN.xml
+type xlsxWorksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline struct {
+				Text string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// xlsxWorkbook dan xlsxRelationships dipakai untuk menemukan sheet pertama
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// parseXLSX membaca sheet pertama workbook XLSX; baris pertama dianggap header
+func parseXLSX(data []byte) (*Dataset, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+	strs := make([]string, len(shared.Items))
+	for i, item := range shared.Items {
+		if len(item.Runs) == 0 {
+			strs[i] = item.Text
+			continue
+		}
+		var b strings.Builder
+		for _, run := range item.Runs {
+			b.WriteString(run.Text)
+		}
+		strs[i] = b.String()
+	}
+
+	sheetFile := firstSheet(files)
+	if sheetFile == nil {
+		return nil, fmt.Errorf("workbook has no worksheet")
+	}
+	var sheet xlsxWorksheet
+	if err := decodeZipXML(sheetFile, &sheet); err != nil {
+		return nil, err
+	}
+
+	var grid [][]string
+	for _, row := range sheet.Rows {
+		var values []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				col = columnIndexFromRef(cell.Ref)
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				if n, err := strconv.Atoi(cell.Value); err == nil && n >= 0 && n < len(strs) {
+					values[col] = strs[n]
+				}
+			case "inlineStr":
+				values[col] = cell.Inline.Text
+			case "b":
+				values[col] = map[string]string{"1": "TRUE", "0": "FALSE"}[cell.Value]
+			default:
+				values[col] = cell.Value
+			}
+		}
+		grid = append(grid, values)
+	}
+
+	if len(grid) == 0 {
+		return nil, fmt.Errorf("worksheet is empty")
+	}
+	ds := &Dataset{Columns: normalizeHeader(grid[0])}
+	for _, record := range grid[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		ds.Rows = append(ds.Rows, fitRecord(record, len(ds.Columns)))
+	}
+	return ds, nil
+}
+
+// firstSheet mencari file worksheet pertama sesuai urutan di workbook
+func firstSheet(files map[string]*zip.File) *zip.File {
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	if wb, ok := files["xl/workbook.xml"]; ok && decodeZipXML(wb, &workbook) == nil && len(workbook.Sheets) > 0 {
+		if rf, ok := files["xl/_rels/workbook.xml.rels"]; ok && decodeZipXML(rf, &rels) == nil {
+			for _, rel := range rels.Relationships {
+				if rel.ID != workbook.Sheets[0].RID {
+					continue
+				}
+				target := strings.TrimPrefix(rel.Target, "/")
+				if !strings.HasPrefix(target, "xl/") {
+					target = path.Join("xl", target)
+				}
+				if f, ok := files[target]; ok {
+					return f
+				}
+			}
+		}
+	}
+
+	// Fallback: worksheet dengan nama terkecil
+	var names []string
+	for name := range files {
+		if strings.HasPrefix(name, "xl/worksheets/") && strings.HasSuffix(name, ".xml") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	return files[names[0]]
+}
+
+func decodeZipXML(f *zip.File, target interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("invalid %s: %v", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndexFromRef mengubah referensi sel (mis. "AB12") menjadi indeks kolom berbasis 0
+func columnIndexFromRef(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bahasa yang didukung untuk output AI dan laporan
+const (
+	Indonesian = "id"
+	English    = "en"
+	Default    = Indonesian
+)
+
+// Supported mengembalikan daftar kode bahasa yang didukung
+func Supported() []string {
+	return []string{Indonesian, English}
+}
+
+// Normalize mengubah kode/nama bahasa (mis. "en-US", "english", "Bahasa Indonesia") menjadi kode yang didukung.
+// Mengembalikan string kosong jika bahasa tidak dikenali.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_,;"); i > 0 {
+		lang = lang[:i]
+	}
+	switch lang {
+	case "id", "ind", "indonesian", "indonesia", "bahasa":
+		return Indonesian
+	case "en", "eng", "english", "inggris":
+		return English
+	}
+	if strings.HasPrefix(lang, "bahasa") {
+		return Indonesian
+	}
+	return ""
+}
+
+// IsSupported mengecek apakah bahasa dikenali
+func IsSupported(lang string) bool {
+	return Normalize(lang) != ""
+}
+
+// Resolve memilih bahasa pertama yang valid dari kandidat (urut prioritas), atau Default
+func Resolve(candidates ...string) string {
+	for _, candidate := range candidates {
+		if lang := Normalize(candidate); lang != "" {
+			return lang
+		}
+	}
+	return Default
+}
+
+// T menerjemahkan key ke bahasa lang; jika args diberikan, pesan diformat dengan fmt.Sprintf.
+// Key yang tidak ada pada bahasa tersebut memakai bahasa Default, lalu key itu sendiri.
+func T(lang, key string, args ...interface{}) string {
+	msg, ok := messages[Resolve(lang)][key]
+	if !ok {
+		if msg, ok = messages[Default][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Has mengecek apakah key tersedia di katalog
+func Has(key string) bool {
+	_, ok := messages[Default][key]
+	return ok
+}
@@ -0,0 +1,467 @@
+package i18n
+
+// messages adalah katalog pesan per bahasa
+var messages = map[string]map[string]string{
+	Indonesian: {
+		// Laporan
+		"report.title":          "Laporan Analisis Penelitian",
+		"report.project":        "Judul",
+		"report.description":    "Deskripsi",
+		"report.research_type":  "Jenis Penelitian",
+		"report.hypothesis":     "Hipotesis",
+		"report.variables":      "Variabel Penelitian",
+		"report.independent":    "Independen",
+		"report.dependent":      "Dependen",
+		"report.control":        "Kontrol",
+		"report.moderating":     "Moderasi",
+		"report.mediating":      "Mediasi",
+		"report.results":        "Hasil Analisis",
+		"report.method":         "Metode",
+		"report.interpretation": "Interpretasi",
+		"report.effect_size":    "Effect Size",
+		"report.conclusion":     "Kesimpulan",
+		"report.summary":        "Ringkasan",
+		"report.generated_at":   "Dibuat pada",
+		"report.iteration":      "Iterasi ke",
+		"report.failed":         "Gagal",
+		"report.metadata":       "Informasi Proyek",
+		"report.role":           "Peran",
+		"report.variable":       "Variabel",
+		"report.scale":          "Skala",
+		"report.notes":          "Catatan",
+		"report.figures":        "Gambar",
+		"report.table":          "Tabel %d",
+		"report.figure":         "Gambar %d",
+		"report.page":           "Halaman %d dari %s",
+		"report.analysis_id":    "ID Analisis",
+		"report.yes":            "Ya",
+		"report.no":             "Tidak",
+		"report.chapter":        "BAB IV HASIL DAN PEMBAHASAN",
+		"report.note":           "Catatan.",
+		"report.descriptives":   "Statistik Deskriptif Data",
+		"report.data_file":      "File Data",
+		"report.status":         "Status",
+		"report.statistic":      "Statistik",
+		"report.significant":    "Signifikan",
+		"report.apa":            "Pelaporan APA",
+
+		// Bagian laporan
+		"report.methodology":                  "Metodologi",
+		"report.analysis_methods":             "Metode Analisis yang Digunakan",
+		"report.assumption":                   "Asumsi",
+		"report.assumptions":                  "Uji Asumsi",
+		"report.assumption_met":               "Terpenuhi",
+		"report.assumption_normality":         "Normalitas (Shapiro-Wilk)",
+		"report.assumption_homogeneity":       "Homogenitas varians (Levene)",
+		"report.assumption_multicollinearity": "Tidak ada multikolinearitas (VIF < 10)",
+		"report.assumption_expected":          "Frekuensi harapan ≥ 5 (maks. 20% sel)",
+		"report.limitations":                  "Keterbatasan",
+		"report.appendix":                     "Lampiran",
+		"report.engine_version":               "Versi Engine Statistik",
+		"report.method_data":                  "Data yang dianalisis berasal dari file %s dengan %d observasi dan %d variabel.",
+		"report.method_engine":                "Seluruh perhitungan dilakukan oleh engine statistik aplikasi versi %s secara deterministik.",
+		"report.method_alpha":                 "Tingkat signifikansi yang digunakan adalah α = %s.",
+		"report.method_ai":                    "Interpretasi naratif disusun oleh model AI dan diverifikasi otomatis terhadap output statistik sebelum ditampilkan.",
+		"report.limit_failed":                 "%s tidak dapat dihitung: %s",
+		"report.limit_assumption":             "%s: %s tidak terpenuhi untuk %s (%s); tafsirkan hasil dengan hati-hati.",
+		"report.limit_small_sample":           "Ukuran sampel terkecil yang dianalisis hanya %d observasi sehingga daya uji terbatas.",
+		"report.limit_missing":                "Terdapat %d nilai hilang pada %d variabel; setiap metode hanya memakai kasus lengkap.",
+		"report.limit_causal":                 "Korelasi dan regresi menunjukkan hubungan antarvariabel, bukan bukti sebab-akibat.",
+		"report.limit_unverified":             "Interpretasi AI untuk %s tidak lolos verifikasi otomatis: %s",
+		"report.limit_ai":                     "Interpretasi naratif dihasilkan AI dan perlu ditinjau oleh peneliti sebelum dipublikasikan.",
+
+		// Pelaporan APA
+		"apa.regression":     "Hasil Analisis Regresi Linear untuk Memprediksi %s",
+		"apa.anova":          "Hasil Analisis Varians (ANOVA) untuk %s",
+		"apa.correlation":    "Korelasi antar Variabel",
+		"apa.predictor":      "Prediktor",
+		"apa.source":         "Sumber",
+		"apa.variable":       "Variabel",
+		"apa.constant":       "Konstanta",
+		"apa.between":        "Antar kelompok",
+		"apa.within":         "Dalam kelompok",
+		"apa.total":          "Total",
+		"apa.regression_row": "Regresi",
+		"apa.residual":       "Residual",
+		"apa.note_model":     "R² = %s, R² terkoreksi = %s. N = %d. CI = confidence interval.",
+		"apa.note_stars":     "* p < .05. ** p < .01. *** p < .001.",
+		"apa.note_n":         "N = %d.",
+		"apa.interaction":    "interaksi",
+		"apa.indirect":       "efek tidak langsung",
+		"apa.items":          "%d item",
+
+		// Judul dan sumbu chart
+		"chart.histogram":       "Histogram %s",
+		"chart.frequency_axis":  "Frekuensi",
+		"chart.qq":              "Plot Q-Q Normal %s",
+		"chart.qq_residuals":    "Plot Q-Q Normal Residual (%s)",
+		"chart.theoretical":     "Kuantil normal teoretis",
+		"chart.sample":          "Kuantil sampel %s",
+		"chart.frequency":       "Distribusi Frekuensi %s",
+		"chart.boxplot":         "Diagram Kotak %s menurut %s",
+		"chart.boxplot_pair":    "Diagram Kotak %s dan %s",
+		"chart.value":           "Nilai",
+		"chart.crosstab":        "Frekuensi %s menurut %s",
+		"chart.scatter":         "Diagram Pencar %s terhadap %s",
+		"chart.heatmap":         "Matriks Korelasi %s",
+		"chart.residuals":       "Residual terhadap Nilai Prediksi (%s)",
+		"chart.fitted":          "Nilai prediksi",
+		"chart.residual":        "Residual",
+		"chart.path_mediation":  "Diagram Jalur Model Mediasi",
+		"chart.path_moderation": "Diagram Konseptual Model Moderasi",
+		"chart.item_total":      "Korelasi Item-Total Terkoreksi",
+		"chart.item":            "Item",
+		"chart.item_total_axis": "r item-total terkoreksi",
+
+		// Ringkasan dan fallback interpretasi
+		"result.header":            "Analisis selesai untuk proyek: %s\nFile: %s\n",
+		"result.failed":            "gagal (%s)",
+		"result.see_tables":        "%s selesai dihitung; lihat tabel hasil untuk rinciannya.",
+		"result.significant":       "; hasil signifikan pada alpha = %.2f.",
+		"result.not_significant":   "; hasil tidak signifikan pada alpha = %.2f.",
+		"result.ai_unavailable":    "Interpretasi AI tidak tersedia (%s); teks disusun dari output komputasi",
+		"refine.rerun_instruction": "Jelaskan perubahan hasil setelah metode dijalankan ulang: %s",
+		"recommend.summary":        "Metode yang direkomendasikan:",
+		"recommend.summary_rules":  "Rekomendasi AI tidak dapat divalidasi; metode berikut dipilih dari profil data berbasis aturan:",
+
+		// Konteks penelitian untuk prompt
+		"context.title":         "Judul penelitian: %s",
+		"context.description":   "Deskripsi: %s",
+		"context.research_type": "Jenis penelitian: %s",
+		"context.hypothesis":    "Hipotesis: %s",
+		"context.independent":   "Variabel independen: %s",
+		"context.dependent":     "Variabel dependen: %s",
+		"context.upload":        "Data upload: %s",
+
+		// Profil data (DescribeDataContext)
+		"data.sample":      "Ukuran sampel: %d baris, %d kolom",
+		"data.variables":   "Variabel:",
+		"data.variable":    "- %s: tipe %s, skala %s, %d nilai unik, %d data hilang",
+		"data.role":        ", peran %s",
+		"data.smallest":    ", kelompok terkecil %d",
+		"data.missing_var": "Catatan: variabel %q tidak ditemukan di data",
+		"data.rules":       "Rekomendasi berbasis aturan (skala pengukuran, jumlah kelompok, ukuran sampel):",
+		"role.dependent":   "dependen",
+		"role.independent": "independen",
+		"role.control":     "kontrol",
+		"role.moderating":  "moderasi",
+		"role.mediating":   "mediasi",
+
+		// Alasan rekomendasi berbasis aturan
+		"rec.descriptive":         "Langkah awal untuk menggambarkan %d observasi dan %d variabel sebelum uji inferensial.",
+		"rec.multiple_regression": "%s berskala %s dengan %d prediktor kuantitatif (%s); regresi berganda menguji pengaruh simultan dan parsial.",
+		"rec.small_regression":    " Perhatikan ukuran sampel (n = %d) relatif kecil untuk jumlah prediktor ini.",
+		"rec.moderation":          "%s dideklarasikan sebagai variabel moderasi; regresi dengan suku interaksi menguji apakah %s memperkuat/memperlemah pengaruh %s terhadap %s.",
+		"rec.mediation":           "%s dideklarasikan sebagai variabel mediasi; uji Sobel menguji efek tidak langsung %s → %s → %s.",
+		"rec.normality":           "Memeriksa asumsi normalitas untuk %s.",
+		"rec.independent_t":       "%s berskala %s dan %s memiliki 2 kelompok; uji t membandingkan rata-rata kedua kelompok.",
+		"rec.mann_whitney_small":  "Kelompok terkecil pada %s hanya %d observasi; Mann-Whitney menjadi alternatif bila normalitas tidak terpenuhi.",
+		"rec.anova":               "%s berskala %s dan %s memiliki %d kelompok; ANOVA satu arah membandingkan rata-rata antar kelompok.",
+		"rec.kruskal_small":       "Kelompok terkecil pada %s hanya %d observasi; Kruskal-Wallis menjadi alternatif bila asumsi ANOVA tidak terpenuhi.",
+		"rec.pearson":             "%s dan %s sama-sama berskala %s; korelasi Pearson mengukur kekuatan hubungan linear.",
+		"rec.regression":          "Regresi linear mengestimasi pengaruh %s terhadap %s.",
+		"rec.spearman_small":      "Ukuran sampel kecil (n = %d); korelasi Spearman lebih tahan terhadap pelanggaran normalitas.",
+		"rec.spearman_ordinal_iv": "%s berskala ordinal dengan %d level; korelasi Spearman sesuai untuk hubungan monoton dengan %s.",
+		"rec.mann_whitney":        "%s berskala ordinal dan %s memiliki 2 kelompok independen; Mann-Whitney membandingkan distribusi peringkat.",
+		"rec.kruskal":             "%s berskala ordinal dan %s memiliki %d kelompok; Kruskal-Wallis membandingkan distribusi peringkat antar kelompok.",
+		"rec.spearman_ordinal_dv": "%s berskala ordinal; korelasi Spearman mengukur hubungan monoton dengan %s.",
+		"rec.chi_square":          "%s (%d kategori) dan %s (%d kategori) berskala kategorik; chi-square menguji independensi keduanya.",
+		"rec.dichotomous_dv":      "%s dikotomis dan %s berskala %s; uji t membandingkan %s antar kedua kategori %s (regresi logistik belum didukung engine).",
+		"rec.pearson_matrix":      "Peran variabel belum dideklarasikan; matriks korelasi Pearson antar %d variabel interval/rasio membantu eksplorasi hubungan.",
+		"rec.spearman_explore":    "Terdapat variabel ordinal; korelasi Spearman sesuai untuk eksplorasi hubungan monoton.",
+		"rec.paired_t":            "Kolom %s dan %s merupakan pengukuran berulang pada subjek yang sama; uji t berpasangan membandingkan rata-rata sebelum dan sesudah.",
+		"rec.wilcoxon_small":      "Jumlah pasangan kecil (n = %d); Wilcoxon menjadi alternatif bila selisih tidak normal.",
+		"rec.wilcoxon_ordinal":    "Kolom %s dan %s merupakan pengukuran berulang berskala ordinal; Wilcoxon signed-rank sesuai.",
+		"rec.reliability":         "Terdeteksi %d item ordinal berawalan %q (skala Likert); Cronbach's alpha menguji konsistensi internal instrumen.",
+
+		// Asumsi metode
+		"assumptions.descriptive":          "Tidak ada asumsi distribusi",
+		"assumptions.normality_test":       "Data numerik dengan 3 sampai 5000 observasi",
+		"assumptions.one_sample_t_test":    "Data interval/rasio berdistribusi normal",
+		"assumptions.independent_t_test":   "Variabel dependen interval/rasio berdistribusi normal pada tiap kelompok, observasi independen",
+		"assumptions.paired_t_test":        "Selisih pasangan berdistribusi normal",
+		"assumptions.one_way_anova":        "Normalitas tiap kelompok, homogenitas varians, observasi independen",
+		"assumptions.mann_whitney":         "Variabel dependen minimal ordinal, dua kelompok independen",
+		"assumptions.wilcoxon_signed_rank": "Data berpasangan minimal ordinal",
+		"assumptions.kruskal_wallis":       "Variabel dependen minimal ordinal, tiga kelompok independen atau lebih",
+		"assumptions.chi_square":           "Dua variabel nominal, frekuensi harapan minimal 5 pada sebagian besar sel",
+		"assumptions.pearson_correlation":  "Hubungan linear, kedua variabel interval/rasio dan berdistribusi normal",
+		"assumptions.spearman_correlation": "Hubungan monoton, variabel minimal ordinal",
+		"assumptions.linear_regression":    "Linearitas, normalitas residual, homoskedastisitas, tidak ada multikolinearitas",
+		"assumptions.moderation":           "Asumsi regresi linear terpenuhi, variabel moderator diukur",
+		"assumptions.mediation":            "Asumsi regresi linear terpenuhi, urutan kausal X → M → Y",
+		"assumptions.reliability":          "Item mengukur konstruk yang sama, minimal dua item",
+
+		// Email
+		"mail.greeting":        "Halo %s,",
+		"mail.verify.subject":  "Verifikasi email akun Research Data Analysis",
+		"mail.verify.intro":    "Terima kasih telah mendaftar. Konfirmasikan bahwa alamat email ini milik Anda dengan membuka tautan berikut.",
+		"mail.verify.action":   "Verifikasi Email",
+		"mail.reset.subject":   "Reset password akun Research Data Analysis",
+		"mail.reset.intro":     "Kami menerima permintaan untuk mengatur ulang password akun Anda. Buka tautan berikut untuk membuat password baru.",
+		"mail.reset.action":    "Atur Ulang Password",
+		"mail.invite.subject":  "Undangan kolaborasi proyek \"%[2]s\"",
+		"mail.invite.intro":    "%[1]s mengundang Anda untuk bergabung dengan proyek \"%[2]s\" sebagai %[3]s. Masuk atau daftar dengan alamat email ini untuk menerima undangan.",
+		"mail.invite.action":   "Lihat Undangan",
+		"mail.expires_hours":   "Tautan ini berlaku selama %d jam dan hanya dapat digunakan satu kali.",
+		"mail.expires_minutes": "Tautan ini berlaku selama %d menit dan hanya dapat digunakan satu kali.",
+		"mail.link_hint":       "Jika tombol tidak berfungsi, salin tautan berikut ke browser Anda:",
+		"mail.ignore":          "Jika Anda tidak meminta email ini, abaikan saja. Akun Anda tetap aman.",
+		"mail.signature":       "Salam,\nTim Research Data Analysis",
+
+		"mail.review_submitted.subject":         "Analisis menunggu tinjauan: %[2]s",
+		"mail.review_submitted.intro":           "%[1]s mengajukan analisis pada proyek \"%[2]s\" untuk Anda tinjau.",
+		"mail.review_submitted.action":          "Tinjau Analisis",
+		"mail.review_approved.subject":          "Analisis disetujui: %[2]s",
+		"mail.review_approved.intro":            "%[1]s menyetujui analisis Anda pada proyek \"%[2]s\". %[3]s",
+		"mail.review_approved.action":           "Lihat Analisis",
+		"mail.review_changes_requested.subject": "Perbaikan analisis diminta: %[2]s",
+		"mail.review_changes_requested.intro":   "%[1]s meminta perbaikan pada analisis Anda di proyek \"%[2]s\". %[3]s",
+		"mail.review_changes_requested.action":  "Lihat Catatan",
+
+		// Notifikasi dalam aplikasi
+		"notify.review_submitted":         "%[1]s mengajukan analisis pada proyek \"%[2]s\" untuk ditinjau",
+		"notify.review_approved":          "%[1]s menyetujui analisis Anda pada proyek \"%[2]s\"",
+		"notify.review_changes_requested": "%[1]s meminta perbaikan analisis Anda pada proyek \"%[2]s\"",
+		"notify.comment":                  "%[1]s mengomentari proyek \"%[2]s\"",
+		"notify.comment_reply":            "%[1]s membalas komentar Anda pada proyek \"%[2]s\"",
+		"notify.note":                     "Catatan: %s",
+
+		// Peran kolaborator proyek
+		"role.owner":    "pemilik",
+		"role.editor":   "editor",
+		"role.reviewer": "pembimbing",
+		"role.viewer":   "pembaca",
+	},
+	English: {
+		// Report
+		"report.title":          "Research Analysis Report",
+		"report.project":        "Title",
+		"report.description":    "Description",
+		"report.research_type":  "Research Type",
+		"report.hypothesis":     "Hypothesis",
+		"report.variables":      "Research Variables",
+		"report.independent":    "Independent",
+		"report.dependent":      "Dependent",
+		"report.control":        "Control",
+		"report.moderating":     "Moderating",
+		"report.mediating":      "Mediating",
+		"report.results":        "Analysis Results",
+		"report.method":         "Method",
+		"report.interpretation": "Interpretation",
+		"report.effect_size":    "Effect Size",
+		"report.conclusion":     "Conclusion",
+		"report.summary":        "Summary",
+		"report.generated_at":   "Generated at",
+		"report.iteration":      "Iteration",
+		"report.failed":         "Failed",
+		"report.metadata":       "Project Information",
+		"report.role":           "Role",
+		"report.variable":       "Variable",
+		"report.scale":          "Scale",
+		"report.notes":          "Notes",
+		"report.figures":        "Figures",
+		"report.table":          "Table %d",
+		"report.figure":         "Figure %d",
+		"report.page":           "Page %d of %s",
+		"report.analysis_id":    "Analysis ID",
+		"report.yes":            "Yes",
+		"report.no":             "No",
+		"report.chapter":        "CHAPTER IV RESULTS AND DISCUSSION",
+		"report.note":           "Note.",
+		"report.descriptives":   "Descriptive Statistics of the Data",
+		"report.data_file":      "Data File",
+		"report.status":         "Status",
+		"report.statistic":      "Statistic",
+		"report.significant":    "Significant",
+		"report.apa":            "APA Reporting",
+
+		// Report sections
+		"report.methodology":                  "Methodology",
+		"report.analysis_methods":             "Analysis Methods Used",
+		"report.assumption":                   "Assumption",
+		"report.assumptions":                  "Assumption Tests",
+		"report.assumption_met":               "Met",
+		"report.assumption_normality":         "Normality (Shapiro-Wilk)",
+		"report.assumption_homogeneity":       "Homogeneity of variances (Levene)",
+		"report.assumption_multicollinearity": "No multicollinearity (VIF < 10)",
+		"report.assumption_expected":          "Expected counts ≥ 5 (max. 20% of cells)",
+		"report.limitations":                  "Limitations",
+		"report.appendix":                     "Appendix",
+		"report.engine_version":               "Statistics Engine Version",
+		"report.method_data":                  "The analysed data come from %s with %d observations and %d variables.",
+		"report.method_engine":                "All computations were performed deterministically by the application's statistics engine version %s.",
+		"report.method_alpha":                 "The significance level used is α = %s.",
+		"report.method_ai":                    "Narrative interpretations were written by an AI model and automatically verified against the statistical output before being shown.",
+		"report.limit_failed":                 "%s could not be computed: %s",
+		"report.limit_assumption":             "%s: %s is not met for %s (%s); interpret the results with caution.",
+		"report.limit_small_sample":           "The smallest analysed sample has only %d observations, which limits statistical power.",
+		"report.limit_missing":                "There are %d missing values across %d variables; each method uses complete cases only.",
+		"report.limit_causal":                 "Correlation and regression show associations between variables, not evidence of causation.",
+		"report.limit_unverified":             "The AI interpretation of %s did not pass automatic verification: %s",
+		"report.limit_ai":                     "Narrative interpretations are AI-generated and should be reviewed by the researcher before publication.",
+
+		// APA reporting
+		"apa.regression":     "Linear Regression Analysis Predicting %s",
+		"apa.anova":          "Analysis of Variance (ANOVA) for %s",
+		"apa.correlation":    "Correlations Between Variables",
+		"apa.predictor":      "Predictor",
+		"apa.source":         "Source",
+		"apa.variable":       "Variable",
+		"apa.constant":       "Constant",
+		"apa.between":        "Between groups",
+		"apa.within":         "Within groups",
+		"apa.total":          "Total",
+		"apa.regression_row": "Regression",
+		"apa.residual":       "Residual",
+		"apa.note_model":     "R² = %s, adjusted R² = %s. N = %d. CI = confidence interval.",
+		"apa.note_stars":     "* p < .05. ** p < .01. *** p < .001.",
+		"apa.note_n":         "N = %d.",
+		"apa.interaction":    "interaction",
+		"apa.indirect":       "indirect effect",
+		"apa.items":          "%d items",
+
+		// Chart titles and axes
+		"chart.histogram":       "Histogram of %s",
+		"chart.frequency_axis":  "Frequency",
+		"chart.qq":              "Normal Q-Q Plot of %s",
+		"chart.qq_residuals":    "Normal Q-Q Plot of Residuals (%s)",
+		"chart.theoretical":     "Theoretical normal quantiles",
+		"chart.sample":          "Sample quantiles of %s",
+		"chart.frequency":       "Frequency Distribution of %s",
+		"chart.boxplot":         "Box Plot of %s by %s",
+		"chart.boxplot_pair":    "Box Plot of %s and %s",
+		"chart.value":           "Value",
+		"chart.crosstab":        "Frequencies of %s by %s",
+		"chart.scatter":         "Scatter Plot of %s against %s",
+		"chart.heatmap":         "%s Correlation Matrix",
+		"chart.residuals":       "Residuals vs Fitted Values (%s)",
+		"chart.fitted":          "Fitted values",
+		"chart.residual":        "Residuals",
+		"chart.path_mediation":  "Path Diagram of the Mediation Model",
+		"chart.path_moderation": "Conceptual Diagram of the Moderation Model",
+		"chart.item_total":      "Corrected Item-Total Correlations",
+		"chart.item":            "Item",
+		"chart.item_total_axis": "Corrected item-total r",
+
+		// Summary and fallback interpretation
+		"result.header":            "Analysis completed for project: %s\nFile: %s\n",
+		"result.failed":            "failed (%s)",
+		"result.see_tables":        "%s has been computed; see the result tables for details.",
+		"result.significant":       "; the result is significant at alpha = %.2f.",
+		"result.not_significant":   "; the result is not significant at alpha = %.2f.",
+		"result.ai_unavailable":    "AI interpretation unavailable (%s); text generated from computed output",
+		"refine.rerun_instruction": "Explain how the results changed after re-running: %s",
+		"recommend.summary":        "Recommended methods:",
+		"recommend.summary_rules":  "AI recommendations could not be validated; these methods were chosen by rules from the data profile:",
+
+		// Research context for prompts
+		"context.title":         "Research title: %s",
+		"context.description":   "Description: %s",
+		"context.research_type": "Research type: %s",
+		"context.hypothesis":    "Hypothesis: %s",
+		"context.independent":   "Independent variables: %s",
+		"context.dependent":     "Dependent variables: %s",
+		"context.upload":        "Uploaded data: %s",
+
+		// Data profile (DescribeDataContext)
+		"data.sample":      "Sample size: %d rows, %d columns",
+		"data.variables":   "Variables:",
+		"data.variable":    "- %s: type %s, scale %s, %d unique values, %d missing",
+		"data.role":        ", role %s",
+		"data.smallest":    ", smallest group %d",
+		"data.missing_var": "Note: variable %q was not found in the data",
+		"data.rules":       "Rule-based recommendations (measurement scale, number of groups, sample size):",
+		"role.dependent":   "dependent",
+		"role.independent": "independent",
+		"role.control":     "control",
+		"role.moderating":  "moderating",
+		"role.mediating":   "mediating",
+
+		// Rule-based recommendation reasons
+		"rec.descriptive":         "First step to describe %d observations and %d variables before inferential testing.",
+		"rec.multiple_regression": "%s is %s-scaled with %d quantitative predictors (%s); multiple regression tests their joint and partial effects.",
+		"rec.small_regression":    " Note that the sample size (n = %d) is small for this number of predictors.",
+		"rec.moderation":          "%s is declared as a moderating variable; regression with an interaction term tests whether %s strengthens/weakens the effect of %s on %s.",
+		"rec.mediation":           "%s is declared as a mediating variable; the Sobel test examines the indirect effect %s → %s → %s.",
+		"rec.normality":           "Checks the normality assumption for %s.",
+		"rec.independent_t":       "%s is %s-scaled and %s has 2 groups; the t-test compares the means of both groups.",
+		"rec.mann_whitney_small":  "The smallest group of %s has only %d observations; Mann-Whitney is an alternative if normality does not hold.",
+		"rec.anova":               "%s is %s-scaled and %s has %d groups; one-way ANOVA compares the group means.",
+		"rec.kruskal_small":       "The smallest group of %s has only %d observations; Kruskal-Wallis is an alternative if the ANOVA assumptions do not hold.",
+		"rec.pearson":             "%s and %s are both %s-scaled; Pearson correlation measures the strength of their linear relationship.",
+		"rec.regression":          "Linear regression estimates the effect of %s on %s.",
+		"rec.spearman_small":      "Small sample size (n = %d); Spearman correlation is more robust to non-normality.",
+		"rec.spearman_ordinal_iv": "%s is ordinal with %d levels; Spearman correlation suits its monotonic relationship with %s.",
+		"rec.mann_whitney":        "%s is ordinal and %s has 2 independent groups; Mann-Whitney compares their rank distributions.",
+		"rec.kruskal":             "%s is ordinal and %s has %d groups; Kruskal-Wallis compares the rank distributions across groups.",
+		"rec.spearman_ordinal_dv": "%s is ordinal; Spearman correlation measures its monotonic relationship with %s.",
+		"rec.chi_square":          "%s (%d categories) and %s (%d categories) are categorical; chi-square tests their independence.",
+		"rec.dichotomous_dv":      "%s is dichotomous and %s is %s-scaled; the t-test compares %s across both categories of %s (logistic regression is not yet supported by the engine).",
+		"rec.pearson_matrix":      "Variable roles are not declared; a Pearson correlation matrix of %d interval/ratio variables helps explore relationships.",
+		"rec.spearman_explore":    "Ordinal variables are present; Spearman correlation suits exploring monotonic relationships.",
+		"rec.paired_t":            "Columns %s and %s are repeated measurements on the same subjects; the paired t-test compares the means before and after.",
+		"rec.wilcoxon_small":      "Few pairs (n = %d); Wilcoxon is an alternative if the differences are not normal.",
+		"rec.wilcoxon_ordinal":    "Columns %s and %s are ordinal repeated measurements; the Wilcoxon signed-rank test is appropriate.",
+		"rec.reliability":         "Detected %d ordinal items prefixed %q (Likert scale); Cronbach's alpha tests the internal consistency of the instrument.",
+
+		// Method assumptions
+		"assumptions.descriptive":          "No distributional assumptions",
+		"assumptions.normality_test":       "Numeric data with 3 to 5000 observations",
+		"assumptions.one_sample_t_test":    "Normally distributed interval/ratio data",
+		"assumptions.independent_t_test":   "Interval/ratio dependent variable normally distributed in each group, independent observations",
+		"assumptions.paired_t_test":        "Normally distributed paired differences",
+		"assumptions.one_way_anova":        "Normality in each group, homogeneity of variances, independent observations",
+		"assumptions.mann_whitney":         "At least ordinal dependent variable, two independent groups",
+		"assumptions.wilcoxon_signed_rank": "At least ordinal paired data",
+		"assumptions.kruskal_wallis":       "At least ordinal dependent variable, three or more independent groups",
+		"assumptions.chi_square":           "Two nominal variables, expected frequency of at least 5 in most cells",
+		"assumptions.pearson_correlation":  "Linear relationship, both variables interval/ratio and normally distributed",
+		"assumptions.spearman_correlation": "Monotonic relationship, at least ordinal variables",
+		"assumptions.linear_regression":    "Linearity, normal residuals, homoscedasticity, no multicollinearity",
+		"assumptions.moderation":           "Linear regression assumptions hold, moderator is measured",
+		"assumptions.mediation":            "Linear regression assumptions hold, causal order X → M → Y",
+		"assumptions.reliability":          "Items measure the same construct, at least two items",
+
+		// Email
+		"mail.greeting":        "Hello %s,",
+		"mail.verify.subject":  "Verify your Research Data Analysis email",
+		"mail.verify.intro":    "Thank you for signing up. Please confirm that this email address belongs to you by opening the link below.",
+		"mail.verify.action":   "Verify Email",
+		"mail.reset.subject":   "Reset your Research Data Analysis password",
+		"mail.reset.intro":     "We received a request to reset the password for your account. Open the link below to choose a new password.",
+		"mail.reset.action":    "Reset Password",
+		"mail.invite.subject":  "Invitation to collaborate on \"%[2]s\"",
+		"mail.invite.intro":    "%[1]s invited you to join the project \"%[2]s\" as %[3]s. Log in or sign up with this email address to accept the invitation.",
+		"mail.invite.action":   "View Invitation",
+		"mail.expires_hours":   "This link is valid for %d hours and can only be used once.",
+		"mail.expires_minutes": "This link is valid for %d minutes and can only be used once.",
+		"mail.link_hint":       "If the button does not work, copy this link into your browser:",
+		"mail.ignore":          "If you did not request this email, you can safely ignore it. Your account remains secure.",
+		"mail.signature":       "Regards,\nThe Research Data Analysis Team",
+
+		"mail.review_submitted.subject":         "Analysis awaiting review: %[2]s",
+		"mail.review_submitted.intro":           "%[1]s submitted an analysis in the project \"%[2]s\" for your review.",
+		"mail.review_submitted.action":          "Review Analysis",
+		"mail.review_approved.subject":          "Analysis approved: %[2]s",
+		"mail.review_approved.intro":            "%[1]s approved your analysis in the project \"%[2]s\". %[3]s",
+		"mail.review_approved.action":           "View Analysis",
+		"mail.review_changes_requested.subject": "Changes requested: %[2]s",
+		"mail.review_changes_requested.intro":   "%[1]s requested changes to your analysis in the project \"%[2]s\". %[3]s",
+		"mail.review_changes_requested.action":  "View Notes",
+
+		// In-app notifications
+		"notify.review_submitted":         "%[1]s submitted an analysis in the project \"%[2]s\" for review",
+		"notify.review_approved":          "%[1]s approved your analysis in the project \"%[2]s\"",
+		"notify.review_changes_requested": "%[1]s requested changes to your analysis in the project \"%[2]s\"",
+		"notify.comment":                  "%[1]s commented on the project \"%[2]s\"",
+		"notify.comment_reply":            "%[1]s replied to your comment in the project \"%[2]s\"",
+		"notify.note":                     "Note: %s",
+
+		// Project collaborator roles
+		"role.owner":    "owner",
+		"role.editor":   "editor",
+		"role.reviewer": "reviewer",
+		"role.viewer":   "viewer",
+	},
+}
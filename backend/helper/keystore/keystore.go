@@ -0,0 +1,301 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/helper/atdb"
+	"github.com/research-data-analysis/helper/watoken"
+	"github.com/research-data-analysis/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Collection menyimpan kunci hasil rotasi
+const Collection = "signing_keys"
+
+// reloadInterval adalah jeda pemuatan ulang key ring; rotasi harus memberi jeda aktivasi lebih panjang dari ini
+const reloadInterval = time.Minute
+
+var (
+	mu       sync.Mutex
+	cached   *watoken.KeyRing
+	loadedAt time.Time
+)
+
+// Ring mengembalikan key ring yang dipakai untuk menandatangani dan memverifikasi token.
+// Hasilnya di-cache per instance dan dimuat ulang berkala agar kunci baru tersebar ke semua instance.
+func Ring() (*watoken.KeyRing, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if cached != nil && time.Since(loadedAt) < reloadInterval {
+		return cached, nil
+	}
+	ring, err := load()
+	if err != nil {
+		if cached != nil {
+			log.Printf("WARNING: Failed to reload signing keys, keeping previous key ring: %v", err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	cached, loadedAt = ring, time.Now()
+	return ring, nil
+}
+
+// Invalidate memaksa Ring memuat ulang kunci pada pemanggilan berikutnya
+func Invalidate() {
+	mu.Lock()
+	cached = nil
+	mu.Unlock()
+}
+
+// envKey adalah kunci dari PRIVATEKEY/PUBLICKEY, dipakai sebelum rotasi pertama
+func envKey() watoken.Key {
+	auth := config.GetConfig().Auth
+	return watoken.Key{ID: watoken.KeyID(auth.PublicKey), PrivateKey: auth.PrivateKey, PublicKey: auth.PublicKey}
+}
+
+// load membaca kunci dari database; tanpa database key ring hanya berisi kunci env
+func load() (*watoken.KeyRing, error) {
+	db, err := config.GetConfig().GetMongoDatabase()
+	if err != nil {
+		return watoken.NewKeyRing(envKey())
+	}
+	keys, err := List(db)
+	if err != nil {
+		log.Printf("WARNING: Failed to load signing keys, using PRIVATEKEY/PUBLICKEY only: %v", err)
+		return watoken.NewKeyRing(envKey())
+	}
+	return build(envKey(), keys, time.Now())
+}
+
+// build menyusun key ring: kunci penandatangan adalah kunci aktif terbaru yang belum dijadwalkan pensiun
+// atau belum melewati waktu pensiunnya; kunci pensiun dibuang
+func build(env watoken.Key, keys []model.SigningKey, now time.Time) (*watoken.KeyRing, error) {
+	var current *watoken.Key
+	var others []watoken.Key
+	envKnown := false
+
+	// keys terurut dari aktivasi terbaru
+	for _, k := range keys {
+		if k.KeyID == env.ID {
+			envKnown = true
+		}
+		if Status(k, now) == StatusRetired {
+			continue
+		}
+
+		key := watoken.Key{ID: k.KeyID, PublicKey: k.PublicKey}
+		switch {
+		case k.Source == "env" && k.KeyID == env.ID:
+			key.PrivateKey = env.PrivateKey
+		case k.PrivateKey != "":
+			private, err := decrypt(k.PrivateKey)
+			if err != nil {
+				log.Printf("WARNING: Cannot decrypt signing key %s, using it for verification only: %v", k.KeyID, err)
+			}
+			key.PrivateKey = private
+		}
+
+		if current == nil && key.PrivateKey != "" && !k.ActivatesAt.After(now) {
+			current = &key
+			continue
+		}
+		others = append(others, key)
+	}
+
+	if !envKnown {
+		if current == nil {
+			current = &env
+		} else {
+			others = append(others, env)
+		}
+	}
+	if current == nil {
+		return nil, errors.New("no active signing key")
+	}
+	return watoken.NewKeyRing(*current, others...)
+}
+
+// Status kunci
+const (
+	StatusPending  = "pending"  // diterima untuk verifikasi, belum dipakai menandatangani
+	StatusActive   = "active"   // dapat menandatangani
+	StatusRetiring = "retiring" // dijadwalkan pensiun, masih diterima
+	StatusRetired  = "retired"  // tidak diterima lagi
+)
+
+// Status menentukan status kunci pada waktu tertentu
+func Status(k model.SigningKey, now time.Time) string {
+	switch {
+	case k.RetireAt != nil && !now.Before(*k.RetireAt):
+		return StatusRetired
+	case k.RetireAt != nil:
+		return StatusRetiring
+	case k.ActivatesAt.After(now):
+		return StatusPending
+	default:
+		return StatusActive
+	}
+}
+
+// List mengembalikan semua kunci di database, aktivasi terbaru lebih dulu
+func List(db *mongo.Database) ([]model.SigningKey, error) {
+	return atdb.GetAllDocWithSort[model.SigningKey](db, Collection, bson.M{}, bson.D{{Key: "activates_at", Value: -1}})
+}
+
+// Rotate membuat pasangan kunci baru yang aktif setelah activateIn, lalu menjadwalkan
+// kunci penandatangan lama pensiun grace setelah kunci baru aktif
+func Rotate(db *mongo.Database, activateIn, grace time.Duration) (model.SigningKey, error) {
+	ring, err := load()
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+	keys, err := List(db)
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+
+	private, public := watoken.GenerateKey()
+	encrypted, err := encrypt(private)
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+	now := time.Now()
+	newKey := model.SigningKey{
+		KeyID:       watoken.KeyID(public),
+		PublicKey:   public,
+		PrivateKey:  encrypted,
+		Source:      "generated",
+		CreatedAt:   now,
+		ActivatesAt: now.Add(activateIn),
+	}
+	retireAt := newKey.ActivatesAt.Add(grace)
+
+	// Jadwalkan pensiun kunci penandatangan lama; kunci env dicatat dulu (tanpa private key)
+	oldID := ring.CurrentID()
+	recorded := false
+	for _, k := range keys {
+		if k.KeyID == oldID {
+			recorded = true
+			if _, err := atdb.UpdateOneDoc(db, Collection, bson.M{"_id": k.ID}, bson.M{"retire_at": retireAt}); err != nil {
+				return model.SigningKey{}, err
+			}
+		}
+	}
+	if !recorded {
+		env := envKey()
+		if _, err := atdb.InsertOneDoc(db, Collection, model.SigningKey{
+			KeyID:     env.ID,
+			PublicKey: env.PublicKey,
+			Source:    "env",
+			CreatedAt: now,
+			RetireAt:  &retireAt,
+		}); err != nil {
+			return model.SigningKey{}, err
+		}
+	}
+
+	id, err := atdb.InsertOneDoc(db, Collection, newKey)
+	if err != nil {
+		return model.SigningKey{}, err
+	}
+	newKey.ID = id
+
+	// Private key kunci yang sudah pensiun tidak diperlukan lagi
+	if _, err := atdb.UpdateManyDoc(db, Collection, bson.M{"retire_at": bson.M{"$lte": now}, "private_key": bson.M{"$ne": ""}}, bson.M{"private_key": ""}); err != nil {
+		log.Printf("WARNING: Failed to purge retired private keys: %v", err)
+	}
+
+	Invalidate()
+	return newKey, nil
+}
+
+// Retire menjadwalkan pensiun kunci pada waktu at; kunci penandatangan saat ini tidak bisa dipensiunkan sebelum rotasi
+func Retire(db *mongo.Database, kid string, at time.Time) error {
+	ring, err := load()
+	if err != nil {
+		return err
+	}
+	if kid == ring.CurrentID() {
+		return fmt.Errorf("key %s is the current signing key; rotate first", kid)
+	}
+	result, err := atdb.UpdateOneDoc(db, Collection, bson.M{"kid": kid}, bson.M{"retire_at": at})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("key %s not found", kid)
+	}
+	Invalidate()
+	return nil
+}
+
+// Seal mengenkripsi rahasia lain yang disimpan di database (mis. secret TOTP) dengan kunci yang sama
+// seperti private key hasil rotasi
+func Seal(plain string) (string, error) {
+	return encrypt(plain)
+}
+
+// Open membuka rahasia hasil Seal
+func Open(encoded string) (string, error) {
+	return decrypt(encoded)
+}
+
+// encrypt mengenkripsi private key dengan AES-256-GCM; kunci diturunkan dari KEY_ENCRYPTION_KEY
+func encrypt(plain string) (string, error) {
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(plain), nil)
+	return "v1:" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decrypt(encoded string) (string, error) {
+	raw, ok := strings.CutPrefix(encoded, "v1:")
+	if !ok {
+		return "", errors.New("unknown key encryption format")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := newGCM()
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func newGCM() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(config.GetConfig().Auth.KeyEncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
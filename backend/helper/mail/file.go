@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fileSender menulis setiap email sebagai file .eml untuk pengembangan lokal
+type fileSender struct {
+	dir  string
+	from string
+}
+
+func (f *fileSender) Send(ctx context.Context, msg Message) error {
+	data, err := build(f.from, msg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("os.MkdirAll: %v", err)
+	}
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_", " ", "_").Replace(msg.To)
+	path := filepath.Join(f.dir, fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405.000000000"), recipient))
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("os.WriteFile: %v", err)
+	}
+	log.Printf("Mail to %s written to %s", msg.To, path)
+	return nil
+}
+
+// logSender hanya mencatat penerima dan subjek ke log (default tanpa SMTP). Isi email tidak dicatat karena
+// memuat token verifikasi dan reset password; gunakan MAIL_DRIVER=file untuk membaca isinya saat pengembangan.
+type logSender struct {
+	from string
+}
+
+func (l *logSender) Send(ctx context.Context, msg Message) error {
+	log.Printf("Mail (not sent) from %s to %s: %s", l.from, msg.To, msg.Subject)
+	return nil
+}
@@ -0,0 +1,105 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/research-data-analysis/config"
+)
+
+// Message adalah satu email dengan versi teks dan HTML
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender mengirim email; implementasi dipilih dari MAIL_DRIVER
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+var (
+	defaultOnce   sync.Once
+	defaultSender Sender
+)
+
+// Default mengembalikan sender sesuai konfigurasi; instance dibuat sekali
+func Default() Sender {
+	defaultOnce.Do(func() {
+		defaultSender = New(config.GetConfig().Mail)
+	})
+	return defaultSender
+}
+
+// New membuat sender dari konfigurasi mail
+func New(cfg *config.MailConfig) Sender {
+	switch cfg.Driver {
+	case "smtp":
+		return &smtpSender{host: cfg.SMTPHost, port: cfg.SMTPPort, username: cfg.SMTPUsername, password: cfg.SMTPPassword, from: cfg.From}
+	case "file":
+		return &fileSender{dir: cfg.Dir, from: cfg.From}
+	default:
+		return &logSender{from: cfg.From}
+	}
+}
+
+// Send mengirim email dengan sender default
+func Send(ctx context.Context, msg Message) error {
+	return Default().Send(ctx, msg)
+}
+
+// build menyusun email MIME multipart/alternative (teks lalu HTML)
+func build(from string, msg Message) ([]byte, error) {
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return nil, errors.New("mail: header contains a line break")
+	}
+
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", body.Boundary())
+
+	parts := []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	}
+	for _, p := range parts {
+		if p.content == "" {
+			continue
+		}
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
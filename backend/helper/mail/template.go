@@ -0,0 +1,114 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/research-data-analysis/helper/i18n"
+)
+
+// Jenis template email
+const (
+	KindVerifyEmail   = "verify"
+	KindResetPassword = "reset"
+	KindInvite        = "invite"
+
+	KindReviewSubmitted        = "review_submitted"
+	KindReviewApproved         = "review_approved"
+	KindReviewChangesRequested = "review_changes_requested"
+)
+
+// TemplateData adalah isi yang disisipkan ke template
+type TemplateData struct {
+	Name      string
+	Link      string
+	ExpiresIn time.Duration // nol jika tautan tidak kedaluwarsa
+	// Params adalah argumen untuk subjek dan kalimat pembuka, mis. nama pengundang dan judul proyek
+	Params []interface{}
+}
+
+// view adalah teks yang sudah diterjemahkan untuk template
+type view struct {
+	Greeting  string
+	Intro     string
+	Action    string
+	Link      string
+	LinkHint  string
+	Expires   string
+	Ignore    string
+	Signature []string
+}
+
+var textTemplate = texttemplate.Must(texttemplate.New("text").Parse(`{{.Greeting}}
+
+{{.Intro}}
+
+{{.Action}}: {{.Link}}
+
+{{if .Expires}}{{.Expires}}
+{{end}}{{.Ignore}}
+
+{{range .Signature}}{{.}}
+{{end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Arial, Helvetica, sans-serif; color: #1f2937; line-height: 1.5;">
+<div style="max-width: 560px; margin: 0 auto; padding: 24px;">
+<p>{{.Greeting}}</p>
+<p>{{.Intro}}</p>
+<p style="margin: 28px 0;"><a href="{{.Link}}" style="background: #2563eb; color: #ffffff; padding: 12px 20px; border-radius: 6px; text-decoration: none;">{{.Action}}</a></p>
+<p style="font-size: 13px; color: #6b7280;">{{.LinkHint}}<br><a href="{{.Link}}">{{.Link}}</a></p>
+<p style="font-size: 13px; color: #6b7280;">{{if .Expires}}{{.Expires}}<br>{{end}}{{.Ignore}}</p>
+<p>{{range $i, $line := .Signature}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+</div>
+</body>
+</html>
+`))
+
+// Render menyusun email kind dalam bahasa lang untuk penerima to
+func Render(kind, lang, to string, data TemplateData) (Message, error) {
+	if !i18n.Has("mail." + kind + ".subject") {
+		return Message{}, fmt.Errorf("unknown mail template %q", kind)
+	}
+
+	name := data.Name
+	if name == "" {
+		name = to
+	}
+	var expires string
+	switch {
+	case data.ExpiresIn >= time.Hour:
+		expires = i18n.T(lang, "mail.expires_hours", int(data.ExpiresIn.Hours()))
+	case data.ExpiresIn > 0:
+		expires = i18n.T(lang, "mail.expires_minutes", int(data.ExpiresIn.Minutes()))
+	}
+	v := view{
+		Greeting:  i18n.T(lang, "mail.greeting", name),
+		Intro:     i18n.T(lang, "mail."+kind+".intro", data.Params...),
+		Action:    i18n.T(lang, "mail."+kind+".action"),
+		Link:      data.Link,
+		LinkHint:  i18n.T(lang, "mail.link_hint"),
+		Expires:   expires,
+		Ignore:    i18n.T(lang, "mail.ignore"),
+		Signature: strings.Split(i18n.T(lang, "mail.signature"), "\n"),
+	}
+
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, v); err != nil {
+		return Message{}, err
+	}
+	if err := htmlTemplate.Execute(&html, v); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		To:      to,
+		Subject: i18n.T(lang, "mail."+kind+".subject", data.Params...),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,147 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/research-data-analysis/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	saltLength = 16
+	keyLength  = 32
+	maxMemory  = 4 * 1024 * 1024 // KiB; batas wajar agar hash rusak tidak menghabiskan memori
+)
+
+// ErrInvalidHash dikembalikan jika hash tersimpan tidak bisa dibaca
+var ErrInvalidHash = errors.New("password: invalid hash format")
+
+// Params adalah parameter hashing; diambil dari config.AuthConfig
+type Params struct {
+	Algorithm   string // "argon2id" atau "bcrypt"
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	BcryptCost  int
+}
+
+// FromConfig membuat Params dari konfigurasi auth
+func FromConfig(cfg *config.AuthConfig) Params {
+	return Params{
+		Algorithm:   cfg.PasswordHash,
+		Memory:      cfg.Argon2Memory,
+		Iterations:  cfg.Argon2Iterations,
+		Parallelism: cfg.Argon2Parallelism,
+		BcryptCost:  cfg.BcryptCost,
+	}
+}
+
+// Hash menghasilkan hash password dalam format PHC (argon2id) atau modular crypt (bcrypt)
+func Hash(plain string, p Params) (string, error) {
+	if p.Algorithm == "bcrypt" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), p.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("bcrypt: %v", err)
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("rand.Read: %v", err)
+	}
+	key := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, keyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// IsHashed mengecek apakah nilai tersimpan sudah berupa hash (bukan password plaintext lama)
+func IsHashed(stored string) bool {
+	return strings.HasPrefix(stored, "$argon2id$") || isBcrypt(stored)
+}
+
+func isBcrypt(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// Verify membandingkan password dengan hash tersimpan secara constant-time
+func Verify(plain, stored string) (bool, error) {
+	if isBcrypt(stored) {
+		err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	p, salt, key, err := decodeArgon2(stored)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+// VerifyLegacy membandingkan password dengan nilai plaintext lama secara constant-time
+func VerifyLegacy(plain, stored string) bool {
+	return stored != "" && subtle.ConstantTimeCompare([]byte(plain), []byte(stored)) == 1
+}
+
+// NeedsRehash mengecek apakah hash dibuat dengan algoritma atau parameter yang berbeda dari konfigurasi sekarang
+func NeedsRehash(stored string, p Params) bool {
+	if isBcrypt(stored) {
+		if p.Algorithm != "bcrypt" {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(stored))
+		return err != nil || cost != p.BcryptCost
+	}
+	if p.Algorithm != "argon2id" {
+		return true
+	}
+	current, _, _, err := decodeArgon2(stored)
+	if err != nil {
+		return true
+	}
+	return current.Memory != p.Memory || current.Iterations != p.Iterations || current.Parallelism != p.Parallelism
+}
+
+// decodeArgon2 membaca format $argon2id$v=19$m=..,t=..,p=..$salt$key
+func decodeArgon2(stored string) (Params, []byte, []byte, error) {
+	var p Params
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	// argon2.IDKey panic jika parameter di bawah batas minimum
+	if p.Iterations < 1 || p.Parallelism < 1 || p.Memory < 8*uint32(p.Parallelism) || p.Memory > maxMemory {
+		return p, nil, nil, ErrInvalidHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, ErrInvalidHash
+	}
+	p.Algorithm = "argon2id"
+	return p, salt, key, nil
+}
@@ -0,0 +1,136 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+// Parameter kecil agar test cepat; nilai produksi diambil dari config
+var (
+	argonParams  = Params{Algorithm: "argon2id", Memory: 64, Iterations: 1, Parallelism: 1}
+	bcryptParams = Params{Algorithm: "bcrypt", BcryptCost: 4}
+)
+
+func TestHashVerifyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		params Params
+	}{
+		{"argon2id", argonParams},
+		{"bcrypt", bcryptParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := Hash("correct horse", tt.params)
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if !IsHashed(hash) {
+				t.Fatalf("IsHashed(%q) = false", hash)
+			}
+			if ok, err := Verify("correct horse", hash); err != nil || !ok {
+				t.Errorf("Verify(correct) = %v, %v; want true, nil", ok, err)
+			}
+			if ok, err := Verify("wrong horse", hash); err != nil || ok {
+				t.Errorf("Verify(wrong) = %v, %v; want false, nil", ok, err)
+			}
+			if NeedsRehash(hash, tt.params) {
+				t.Errorf("NeedsRehash with the same params = true")
+			}
+		})
+	}
+}
+
+func TestHashUsesRandomSalt(t *testing.T) {
+	a, _ := Hash("secret123", argonParams)
+	b, _ := Hash("secret123", argonParams)
+	if a == b {
+		t.Errorf("two argon2id hashes of the same password are identical: %s", a)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argonHash, err := Hash("secret123", argonParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := Hash("secret123", bcryptParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stronger := argonParams
+	stronger.Iterations = 2
+	costlier := bcryptParams
+	costlier.BcryptCost = 5
+
+	tests := []struct {
+		name   string
+		stored string
+		params Params
+		want   bool
+	}{
+		{"argon2id same params", argonHash, argonParams, false},
+		{"argon2id more iterations", argonHash, stronger, true},
+		{"argon2id to bcrypt", argonHash, bcryptParams, true},
+		{"bcrypt same cost", bcryptHash, bcryptParams, false},
+		{"bcrypt higher cost", bcryptHash, costlier, true},
+		{"bcrypt to argon2id", bcryptHash, argonParams, true},
+		{"malformed argon2id", "$argon2id$v=19$m=64$salt$key", argonParams, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsRehash(tt.stored, tt.params); got != tt.want {
+				t.Errorf("NeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeArgon2Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string
+	}{
+		{"empty", ""},
+		{"plaintext", "secret123"},
+		{"wrong algorithm", "$argon2i$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5"},
+		{"missing segment", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ"},
+		{"extra segment", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5$x"},
+		{"wrong version", "$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5"},
+		{"bad params", "$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5"},
+		{"zero iterations", "$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5a2V5"},
+		{"zero parallelism", "$argon2id$v=19$m=64,t=1,p=0$c2FsdHNhbHQ$a2V5a2V5"},
+		{"memory below minimum", "$argon2id$v=19$m=4,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5"},
+		{"memory too large", "$argon2id$v=19$m=4294967295,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5"},
+		{"bad salt encoding", "$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5a2V5"},
+		{"bad key encoding", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$!!!"},
+		{"empty key", "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := decodeArgon2(tt.stored); !errors.Is(err, ErrInvalidHash) {
+				t.Errorf("decodeArgon2 error = %v, want ErrInvalidHash", err)
+			}
+			if ok, err := Verify("secret123", tt.stored); ok || err == nil {
+				t.Errorf("Verify = %v, %v; want false with an error", ok, err)
+			}
+		})
+	}
+}
+
+func TestVerifyLegacy(t *testing.T) {
+	tests := []struct {
+		plain, stored string
+		want          bool
+	}{
+		{"secret123", "secret123", true},
+		{"secret124", "secret123", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := VerifyLegacy(tt.plain, tt.stored); got != tt.want {
+			t.Errorf("VerifyLegacy(%q, %q) = %v, want %v", tt.plain, tt.stored, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,583 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/model"
+)
+
+// APAResult adalah pelaporan APA 7 untuk satu MethodResult: teks in-text dan tabel siap salin
+type APAResult struct {
+	Method   string     `json:"method"`
+	MethodID string     `json:"method_id,omitempty"`
+	Text     string     `json:"text,omitempty"`
+	Tables   []APATable `json:"tables,omitempty"`
+}
+
+// APATable adalah tabel bergaya APA (judul, kolom, baris teks, dan catatan)
+type APATable struct {
+	Title   string     `json:"title"`
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+	Note    string     `json:"note,omitempty"`
+}
+
+// FormatAPAAll memformat seluruh hasil yang berhasil dihitung
+func FormatAPAAll(results []model.MethodResult, lang string) []APAResult {
+	out := []APAResult{}
+	for _, r := range results {
+		if r.Error != "" || len(r.RawOutput) == 0 {
+			continue
+		}
+		out = append(out, FormatAPA(r, lang))
+	}
+	return out
+}
+
+// FormatAPA mengubah MethodResult terstruktur menjadi teks dan tabel APA 7
+func FormatAPA(result model.MethodResult, lang string) APAResult {
+	raw := stats.NormalizeOutput(result.RawOutput)
+	methodID := result.MethodID
+	if methodID == "" {
+		methodID = stats.String(raw, stats.KeyMethod)
+	}
+	if m, ok := stats.LookupMethod(methodID); ok {
+		methodID = m.ID
+	} else if m, ok := stats.LookupMethod(result.Method); ok {
+		methodID = m.ID
+	}
+	a := APAResult{Method: result.Method, MethodID: methodID}
+	if raw == nil || stats.String(raw, stats.KeyError) != "" {
+		return a
+	}
+
+	switch methodID {
+	case stats.MethodDescriptive:
+		a.Text = apaDescriptive(raw)
+	case stats.MethodNormality:
+		a.Text = apaTests(raw, "W", false)
+	case stats.MethodOneSampleTTest, stats.MethodIndependentTTest, stats.MethodPairedTTest:
+		a.Text = apaTTest(raw)
+	case stats.MethodOneWayANOVA:
+		a.Text = apaJoin(
+			apaStat("F", apaDFs(raw, stats.KeyDF1, stats.KeyDF2), raw),
+			apaP(raw),
+			apaEffect("η²", raw, true),
+		)
+		dv := ""
+		if vars := stats.Strings(raw, stats.KeyVariables); len(vars) > 0 {
+			dv = vars[0]
+		}
+		a.Tables = append(a.Tables, apaANOVATable(raw, lang, dv, true))
+	case stats.MethodMannWhitney, stats.MethodWilcoxon:
+		a.Text = apaJoin(
+			apaStat(stats.String(raw, stats.KeyStatisticName), "", raw),
+			apaValue("z", raw, "z", false),
+			apaP(raw),
+			apaEffect("r", raw, true),
+		)
+	case stats.MethodKruskalWallis:
+		a.Text = apaJoin(apaStat("H", apaDFs(raw, stats.KeyDF), raw), apaP(raw), apaEffect("ε²", raw, true))
+	case stats.MethodChiSquare:
+		df := apaDFs(raw, stats.KeyDF)
+		if n, ok := stats.Number(raw, stats.KeyN); ok && df != "" {
+			df = fmt.Sprintf("%s, N = %s", df, apaDF(n))
+		}
+		a.Text = apaJoin(apaStat("χ²", df, raw), apaP(raw), apaEffect("V", raw, true))
+	case stats.MethodPearson, stats.MethodSpearman:
+		symbol := "r"
+		if methodID == stats.MethodSpearman {
+			symbol = "rs"
+		}
+		a.Text = apaTests(raw, symbol, true)
+		if t, ok := apaCorrelationTable(raw, lang); ok {
+			a.Tables = append(a.Tables, t)
+		}
+	case stats.MethodLinearRegression:
+		a.Text = apaRegression(raw)
+		if t, ok := apaCoefficientTable(raw, lang); ok {
+			a.Tables = append(a.Tables, t)
+		}
+		a.Tables = append(a.Tables, apaANOVATable(raw, lang, stats.String(raw, "dependent"), false))
+	case stats.MethodModeration:
+		b, _ := stats.Number(raw, "interaction_b")
+		a.Text = i18n.T(lang, "apa.interaction") + ": " + apaJoin(
+			"b = "+apaNum(b),
+			apaStat("t", apaDFs(raw, stats.KeyDF), raw),
+			apaP(raw),
+			apaValue("ΔR²", raw, "r_squared_change", true),
+		)
+		if t, ok := apaCoefficientTable(raw, lang); ok {
+			a.Tables = append(a.Tables, t)
+		}
+	case stats.MethodMediation:
+		a.Text = i18n.T(lang, "apa.indirect") + ": " + apaJoin(
+			apaValue("ab", raw, "indirect_effect", false),
+			apaCI(raw, "indirect_ci_lower", "indirect_ci_upper"),
+			apaStat("z", "", raw),
+			apaP(raw),
+		)
+	case stats.MethodReliability:
+		text := apaValue("Cronbach's α", raw, stats.KeyStatistic, true)
+		if k, ok := stats.Number(raw, "n_items"); ok && text != "" {
+			text += " (" + i18n.T(lang, "apa.items", int(k)) + ")"
+		}
+		a.Text = text
+	default:
+		a.Text = apaJoin(apaStat(stats.String(raw, stats.KeyStatisticName), apaDFs(raw, stats.KeyDF), raw), apaP(raw))
+	}
+	return a
+}
+
+// --- teks in-text ---
+
+func apaDescriptive(raw map[string]interface{}) string {
+	t, ok := stats.FindTable(raw, "descriptives")
+	if !ok {
+		return ""
+	}
+	mean, sd := columnIndex(t, "Mean"), columnIndex(t, "SD")
+	if mean < 0 || sd < 0 {
+		return ""
+	}
+	var parts []string
+	for _, row := range t.Rows {
+		m, okM := stats.Float(cellAt(row, mean))
+		s, okS := stats.Float(cellAt(row, sd))
+		if !okM || !okS {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%v (M = %s, SD = %s)", cellAt(row, 0), apaNum(m), apaNum(s)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func apaTTest(raw map[string]interface{}) string {
+	return apaJoin(
+		apaStat("t", apaDFs(raw, stats.KeyDF), raw),
+		apaP(raw),
+		apaEffect("d", raw, false),
+		apaCI(raw, "ci_lower", "ci_upper"),
+	)
+}
+
+func apaRegression(raw map[string]interface{}) string {
+	text := apaJoin(
+		apaValue("R²", raw, "r_squared", true),
+		apaStat("F", apaDFs(raw, stats.KeyDF1, stats.KeyDF2), raw),
+		apaP(raw),
+	)
+	t, ok := stats.FindTable(raw, "coefficients")
+	if !ok {
+		return text
+	}
+	beta, tv, p := columnIndex(t, "Beta"), columnIndex(t, "t"), columnIndex(t, "p")
+	df := apaDFs(raw, stats.KeyDF2)
+	var parts []string
+	for i, row := range t.Rows {
+		if i == 0 {
+			continue // konstanta
+		}
+		b, okB := stats.Float(cellAt(row, beta))
+		tt, okT := stats.Float(cellAt(row, tv))
+		pp, okP := stats.Float(cellAt(row, p))
+		if !okB || !okT || !okP {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%v: β = %s, t(%s) = %s, %s", cellAt(row, 0), apaBounded(b), df, apaNum(tt), apaPValue(pp)))
+	}
+	if len(parts) > 0 {
+		text += "; " + strings.Join(parts, "; ")
+	}
+	return text
+}
+
+// apaTests memformat daftar uji (normalitas atau korelasi berpasangan)
+func apaTests(raw map[string]interface{}, symbol string, bounded bool) string {
+	list, _ := stats.Normalize(raw[stats.KeyTests]).([]interface{})
+	var parts []string
+	for _, item := range list {
+		test, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		stat, okS := stats.Number(test, stats.KeyStatistic)
+		p, okP := stats.Number(test, stats.KeyPValue)
+		if !okS || !okP {
+			continue
+		}
+		value := apaNum(stat)
+		if bounded {
+			value = apaBounded(stat)
+		}
+		label := symbol
+		if df := apaDFs(test, stats.KeyDF); df != "" {
+			label += "(" + df + ")"
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s = %s, %s", strings.Join(stats.Strings(test, stats.KeyVariables), "–"), label, value, apaPValue(p)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// apaStat memformat statistik uji utama, mis. "t(48) = 2.31"
+func apaStat(symbol, df string, raw map[string]interface{}) string {
+	v, ok := stats.Number(raw, stats.KeyStatistic)
+	if !ok || symbol == "" {
+		return ""
+	}
+	if df != "" {
+		symbol += "(" + df + ")"
+	}
+	return symbol + " = " + apaNum(v)
+}
+
+func apaP(raw map[string]interface{}) string {
+	p, ok := stats.Number(raw, stats.KeyPValue)
+	if !ok {
+		return ""
+	}
+	return apaPValue(p)
+}
+
+// apaPValue memformat "p = .025" atau "p < .001"
+func apaPValue(p float64) string {
+	s := FormatP(p)
+	if strings.HasPrefix(s, "<") {
+		return "p " + s
+	}
+	return "p = " + s
+}
+
+func apaEffect(symbol string, raw map[string]interface{}, bounded bool) string {
+	return apaValue(symbol, raw, stats.KeyEffectSize, bounded)
+}
+
+func apaValue(symbol string, raw map[string]interface{}, key string, bounded bool) string {
+	v, ok := stats.Number(raw, key)
+	if !ok {
+		return ""
+	}
+	if bounded {
+		return symbol + " = " + apaBounded(v)
+	}
+	return symbol + " = " + apaNum(v)
+}
+
+// apaCI memformat interval kepercayaan sesuai alpha, mis. "95% CI [0.12, 0.54]"
+func apaCI(raw map[string]interface{}, lowerKey, upperKey string) string {
+	lo, okL := stats.Number(raw, lowerKey)
+	hi, okH := stats.Number(raw, upperKey)
+	if !okL || !okH {
+		return ""
+	}
+	alpha, ok := stats.Number(raw, stats.KeyAlpha)
+	if !ok {
+		alpha = stats.DefaultAlpha
+	}
+	return fmt.Sprintf("%s%% CI [%s, %s]", strconv.FormatFloat((1-alpha)*100, 'f', -1, 64), apaNum(lo), apaNum(hi))
+}
+
+func apaDFs(raw map[string]interface{}, keys ...string) string {
+	var parts []string
+	for _, key := range keys {
+		v, ok := stats.Number(raw, key)
+		if !ok {
+			return ""
+		}
+		parts = append(parts, apaDF(v))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func apaJoin(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+// --- format angka APA ---
+
+// apaNum memformat statistik dengan dua desimal
+func apaNum(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ""
+	}
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	if s == "-0.00" {
+		return "0.00"
+	}
+	return s
+}
+
+// apaBounded memformat statistik yang tidak dapat melebihi 1 (r, β, R², α) tanpa nol di depan
+func apaBounded(v float64) string {
+	s := apaNum(v)
+	if strings.HasPrefix(s, "-0.") {
+		return "-" + strings.TrimPrefix(s, "-0")
+	}
+	return strings.TrimPrefix(s, "0")
+}
+
+// apaDF memformat derajat bebas: bulat tanpa desimal, pecahan (Welch) dua desimal
+func apaDF(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return apaNum(v)
+}
+
+// --- tabel ---
+
+func columnIndex(t stats.Table, name string) int {
+	for i, c := range t.Columns {
+		if c == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func cellAt(row []interface{}, i int) interface{} {
+	if i < 0 || i >= len(row) {
+		return nil
+	}
+	return row[i]
+}
+
+func cellFloat(row []interface{}, i int, format func(float64) string) string {
+	if v, ok := stats.Float(cellAt(row, i)); ok {
+		return format(v)
+	}
+	return ""
+}
+
+// apaCoefficientTable menyusun tabel koefisien regresi: B, SE, β, t, p, dan CI
+func apaCoefficientTable(raw map[string]interface{}, lang string) (APATable, bool) {
+	t, ok := stats.FindTable(raw, "coefficients")
+	if !ok {
+		return APATable{}, false
+	}
+	idx := map[string]int{}
+	for _, c := range []string{"Term", "B", "SE", "Beta", "t", "p", "CI lower", "CI upper"} {
+		idx[c] = columnIndex(t, c)
+	}
+	alpha, ok := stats.Number(raw, stats.KeyAlpha)
+	if !ok {
+		alpha = stats.DefaultAlpha
+	}
+	out := APATable{
+		Title:   i18n.T(lang, "apa.regression", stats.String(raw, "dependent")),
+		Columns: []string{i18n.T(lang, "apa.predictor"), "B", "SE", "β", "t", "p", strconv.FormatFloat((1-alpha)*100, 'f', -1, 64) + "% CI"},
+	}
+	for i, row := range t.Rows {
+		term := fmt.Sprint(cellAt(row, idx["Term"]))
+		beta := cellFloat(row, idx["Beta"], apaBounded)
+		if i == 0 {
+			term, beta = i18n.T(lang, "apa.constant"), ""
+		}
+		ci := ""
+		if lo, hi := cellFloat(row, idx["CI lower"], apaNum), cellFloat(row, idx["CI upper"], apaNum); lo != "" && hi != "" {
+			ci = "[" + lo + ", " + hi + "]"
+		}
+		out.Rows = append(out.Rows, []string{
+			term,
+			cellFloat(row, idx["B"], apaNum),
+			cellFloat(row, idx["SE"], apaNum),
+			beta,
+			cellFloat(row, idx["t"], apaNum),
+			cellFloat(row, idx["p"], FormatP),
+			ci,
+		})
+	}
+	r2, _ := stats.Number(raw, "r_squared")
+	adj, okAdj := stats.Number(raw, "adj_r_squared")
+	n, _ := stats.Number(raw, stats.KeyN)
+	if okAdj {
+		out.Note = i18n.T(lang, "apa.note_model", apaBounded(r2), apaBounded(adj), int(n))
+	} else if n > 0 {
+		out.Note = i18n.T(lang, "apa.note_n", int(n))
+	}
+	return out, true
+}
+
+// apaANOVATable menyusun tabel ANOVA (one-way atau uji model regresi) dengan η² bila tersedia
+func apaANOVATable(raw map[string]interface{}, lang, subject string, oneWay bool) APATable {
+	out := APATable{
+		Title:   i18n.T(lang, "apa.anova", subject),
+		Columns: []string{i18n.T(lang, "apa.source"), "SS", "df", "MS", "F", "p"},
+	}
+	if oneWay {
+		out.Columns = append(out.Columns, "η²")
+	}
+	t, ok := stats.FindTable(raw, "anova")
+	if !ok {
+		return out
+	}
+	labels := map[string]string{
+		"Between Groups": i18n.T(lang, "apa.between"),
+		"Within Groups":  i18n.T(lang, "apa.within"),
+		"Regression":     i18n.T(lang, "apa.regression_row"),
+		"Residual":       i18n.T(lang, "apa.residual"),
+		"Total":          i18n.T(lang, "apa.total"),
+	}
+	ss, df, ms := columnIndex(t, "Sum of Squares"), columnIndex(t, "df"), columnIndex(t, "Mean Square")
+	f, p := columnIndex(t, "F"), columnIndex(t, "p")
+	eta, _ := stats.Number(raw, stats.KeyEffectSize)
+	for i, row := range t.Rows {
+		source := fmt.Sprint(cellAt(row, 0))
+		if label, ok := labels[source]; ok {
+			source = label
+		}
+		cells := []string{
+			source,
+			cellFloat(row, ss, apaNum),
+			cellFloat(row, df, apaDF),
+			cellFloat(row, ms, apaNum),
+			cellFloat(row, f, apaNum),
+			cellFloat(row, p, FormatP),
+		}
+		if oneWay {
+			e := ""
+			if i == 0 {
+				e = apaBounded(eta)
+			}
+			cells = append(cells, e)
+		}
+		out.Rows = append(out.Rows, cells)
+	}
+	if n, ok := stats.Number(raw, stats.KeyN); ok {
+		out.Note = i18n.T(lang, "apa.note_n", int(n))
+	}
+	return out
+}
+
+// apaCorrelationTable menyusun matriks korelasi segitiga bawah dengan tanda bintang signifikansi
+func apaCorrelationTable(raw map[string]interface{}, lang string) (APATable, bool) {
+	matrix, ok := stats.FindTable(raw, "correlation_matrix")
+	if !ok || len(matrix.Rows) < 2 {
+		return APATable{}, false
+	}
+	pMatrix, _ := stats.FindTable(raw, "p_values")
+	k := len(matrix.Rows)
+	out := APATable{
+		Title:   i18n.T(lang, "apa.correlation"),
+		Columns: []string{i18n.T(lang, "apa.variable")},
+		Note:    i18n.T(lang, "apa.note_stars"),
+	}
+	for j := 1; j <= k; j++ {
+		out.Columns = append(out.Columns, strconv.Itoa(j))
+	}
+	for i, row := range matrix.Rows {
+		cells := []string{fmt.Sprintf("%d. %v", i+1, cellAt(row, 0))}
+		for j := 0; j < k; j++ {
+			switch {
+			case j == i:
+				cells = append(cells, "—")
+			case j > i:
+				cells = append(cells, "")
+			default:
+				r, ok := stats.Float(cellAt(row, j+1))
+				if !ok {
+					cells = append(cells, "")
+					continue
+				}
+				stars := ""
+				if i < len(pMatrix.Rows) {
+					if p, ok := stats.Float(cellAt(pMatrix.Rows[i], j+1)); ok {
+						switch {
+						case p < .001:
+							stars = "***"
+						case p < .01:
+							stars = "**"
+						case p < .05:
+							stars = "*"
+						}
+					}
+				}
+				cells = append(cells, apaBounded(r)+stars)
+			}
+		}
+		out.Rows = append(out.Rows, cells)
+	}
+	return out, true
+}
+
+// RenderAPAText menulis pelaporan APA seluruh hasil sebagai teks polos (UTF-8) siap salin
+func RenderAPAText(doc Document, w io.Writer) error {
+	var b strings.Builder
+	b.WriteString(i18n.T(doc.Lang, "report.title") + "\n" + doc.Title + "\n")
+	tables := 0
+	for _, r := range doc.Results {
+		if r.Error != "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%d. %s\n", r.Number, r.Method)
+		if r.APA.Text != "" {
+			b.WriteString(r.APA.Text + "\n")
+		}
+		for _, t := range r.APA.Tables {
+			tables++
+			fmt.Fprintf(&b, "\n%s\n%s\n", i18n.T(doc.Lang, "report.table", tables), t.Title)
+			writeTextTable(&b, t.Columns, t.Rows)
+			if t.Note != "" {
+				b.WriteString(i18n.T(doc.Lang, "report.note") + " " + t.Note + "\n")
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeTextTable menulis tabel dengan kolom rata berdasarkan lebar karakter
+func writeTextTable(b *strings.Builder, columns []string, rows [][]string) {
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = utf8.RuneCountInString(c)
+	}
+	for _, row := range rows {
+		for i := range columns {
+			if i < len(row) && utf8.RuneCountInString(row[i]) > widths[i] {
+				widths[i] = utf8.RuneCountInString(row[i])
+			}
+		}
+	}
+	total := 0
+	for _, w := range widths {
+		total += w + 2
+	}
+	line := func(cells []string) {
+		var l strings.Builder
+		for i := range columns {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if i == 0 {
+				l.WriteString(cell + pad)
+			} else {
+				l.WriteString("  " + pad + cell)
+			}
+		}
+		b.WriteString(strings.TrimRight(l.String(), " ") + "\n")
+	}
+	rule := strings.Repeat("─", total) + "\n"
+	b.WriteString(rule)
+	line(columns)
+	b.WriteString(rule)
+	for _, row := range rows {
+		line(row)
+	}
+	b.WriteString(rule)
+}
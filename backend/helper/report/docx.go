@@ -0,0 +1,406 @@
+package report
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/research-data-analysis/helper/i18n"
+)
+
+// Lebar isi halaman A4 (margin 3 cm kiri, 2,5 cm kanan) dalam EMU untuk gambar
+const docxMaxImageWidth = 5760720
+
+// run adalah potongan teks dengan format karakter
+type run struct {
+	text   string
+	bold   bool
+	italic bool
+}
+
+// docxImage adalah gambar yang disertakan ke word/media
+type docxImage struct {
+	rel  string
+	name string
+	data []byte
+}
+
+// docxWriter menyusun word/document.xml beserta gambar yang dirujuk
+type docxWriter struct {
+	doc     Document
+	body    strings.Builder
+	images  []docxImage
+	tables  int
+	figures int
+}
+
+// RenderDOCX menulis Document sebagai dokumen Office Open XML (.docx) bergaya APA ke w
+func RenderDOCX(doc Document, w io.Writer) error {
+	dw := &docxWriter{doc: doc}
+	dw.build()
+
+	zw := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRootRels},
+		{"docProps/core.xml", dw.coreProps()},
+		{"word/styles.xml", docxStyles},
+		{"word/footer1.xml", docxFooter},
+		{"word/_rels/document.xml.rels", dw.documentRels()},
+		{"word/document.xml", dw.document()},
+	}
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+	for _, img := range dw.images {
+		fw, err := zw.Create("word/media/" + img.name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(img.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (dw *docxWriter) build() {
+	doc := dw.doc
+	lang := doc.Lang
+	if doc.Include(SectionCover) {
+		dw.paragraph("Heading1", "center", run{text: i18n.T(lang, "report.chapter")})
+	}
+
+	if len(doc.Meta) > 0 {
+		dw.paragraph("Heading2", "", run{text: i18n.T(lang, "report.metadata")})
+		for _, f := range doc.Meta {
+			dw.paragraph("", "", run{text: f.Label + ": ", bold: true}, run{text: f.Value})
+		}
+	}
+
+	if doc.Include(SectionMethodology) {
+		dw.paragraph("Heading2", "", run{text: i18n.T(lang, "report.methodology")})
+		dw.text(strings.Join(doc.MethodologyText(), " "))
+		if len(doc.Variables) > 0 {
+			dw.paragraph("Heading3", "", run{text: i18n.T(lang, "report.variables")})
+			dw.table(doc.VariableTable())
+		}
+		if t, ok := doc.MethodsTable(); ok {
+			dw.table(t)
+		}
+	}
+
+	if t, ok := doc.DescriptiveTable(); ok && doc.Include(SectionDescriptive) {
+		dw.paragraph("Heading2", "", run{text: t.Title})
+		dw.table(t)
+	}
+
+	if t, ok := doc.AssumptionTable(); ok && doc.Include(SectionAssumptions) {
+		dw.paragraph("Heading2", "", run{text: t.Title})
+		dw.table(t)
+	}
+
+	if len(doc.Results) > 0 {
+		dw.paragraph("Heading2", "", run{text: i18n.T(lang, "report.results")})
+	}
+	for _, r := range doc.Results {
+		dw.paragraph("Heading3", "", run{text: fmt.Sprintf("%d. %s", r.Number, r.Method)})
+		if r.Error != "" {
+			dw.paragraph("", "", run{text: i18n.T(lang, "report.failed") + ": ", bold: true}, run{text: r.Error})
+		}
+		for _, t := range r.Tables {
+			dw.table(t)
+		}
+		if len(r.Notes) > 0 {
+			dw.paragraph("TableNote", "", run{text: i18n.T(lang, "report.note"), italic: true}, run{text: " " + strings.Join(r.Notes, " ")})
+		}
+		dw.text(r.Interpretation)
+		if r.EffectSize != "" {
+			dw.paragraph("", "", run{text: i18n.T(lang, "report.effect_size") + ": ", bold: true}, run{text: r.EffectSize})
+		}
+		if r.Conclusion != "" {
+			dw.paragraph("", "", run{text: i18n.T(lang, "report.conclusion") + ": ", bold: true}, run{text: r.Conclusion})
+		}
+	}
+
+	dw.figuresSection()
+
+	if doc.Summary != "" {
+		dw.paragraph("Heading2", "", run{text: i18n.T(lang, "report.summary")})
+		dw.text(doc.Summary)
+	}
+
+	if items := doc.Limitations(); len(items) > 0 && doc.Include(SectionLimitations) {
+		dw.paragraph("Heading2", "", run{text: i18n.T(lang, "report.limitations")})
+		for _, item := range items {
+			dw.paragraph("BodyText", "", run{text: "• " + item})
+		}
+	}
+
+	if doc.Include(SectionAppendix) {
+		dw.paragraph("Heading2", "", run{text: i18n.T(lang, "report.appendix")})
+		for _, f := range doc.AppendixFields() {
+			dw.paragraph("", "", run{text: f.Label + ": ", bold: true}, run{text: f.Value})
+		}
+	}
+}
+
+// text menulis teks multi-paragraf (dipisah baris kosong atau baris baru)
+func (dw *docxWriter) text(s string) {
+	for _, part := range strings.Split(strings.TrimSpace(s), "\n") {
+		if part = strings.TrimSpace(part); part != "" {
+			dw.paragraph("BodyText", "", run{text: part})
+		}
+	}
+}
+
+func (dw *docxWriter) paragraph(style, align string, runs ...run) {
+	b := &dw.body
+	b.WriteString("<w:p>")
+	if style != "" || align != "" {
+		b.WriteString("<w:pPr>")
+		if style != "" {
+			fmt.Fprintf(b, `<w:pStyle w:val="%s"/>`, style)
+		}
+		if align != "" {
+			fmt.Fprintf(b, `<w:jc w:val="%s"/>`, align)
+		}
+		b.WriteString("</w:pPr>")
+	}
+	for _, r := range runs {
+		writeRun(b, r)
+	}
+	b.WriteString("</w:p>")
+}
+
+func writeRun(b *strings.Builder, r run) {
+	b.WriteString("<w:r>")
+	if r.bold || r.italic {
+		b.WriteString("<w:rPr>")
+		if r.bold {
+			b.WriteString("<w:b/>")
+		}
+		if r.italic {
+			b.WriteString("<w:i/>")
+		}
+		b.WriteString("</w:rPr>")
+	}
+	fmt.Fprintf(b, `<w:t xml:space="preserve">%s</w:t></w:r>`, xmlEscape(r.text))
+}
+
+// table menulis tabel APA: nomor tebal, judul miring, garis horizontal saja
+func (dw *docxWriter) table(t Table) {
+	if len(t.Columns) == 0 {
+		return
+	}
+	if t.Title != "" {
+		dw.tables++
+		dw.paragraph("Caption", "", run{text: i18n.T(dw.doc.Lang, "report.table", dw.tables), bold: true})
+		dw.paragraph("CaptionTitle", "", run{text: t.Title, italic: true})
+	}
+
+	b := &dw.body
+	b.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="APATable"/><w:tblW w:w="5000" w:type="pct"/>`)
+	b.WriteString(`<w:tblBorders><w:top w:val="single" w:sz="8" w:space="0" w:color="000000"/><w:bottom w:val="single" w:sz="8" w:space="0" w:color="000000"/></w:tblBorders>`)
+	b.WriteString(`<w:tblLook w:val="0020" w:firstRow="1" w:lastRow="0" w:firstColumn="0" w:lastColumn="0" w:noHBand="1" w:noVBand="1"/></w:tblPr><w:tblGrid>`)
+	for range t.Columns {
+		b.WriteString(`<w:gridCol/>`)
+	}
+	b.WriteString(`</w:tblGrid>`)
+
+	b.WriteString(`<w:tr><w:trPr><w:tblHeader/></w:trPr>`)
+	for i, c := range t.Columns {
+		align := "center"
+		if i == 0 {
+			align = "left"
+		}
+		dw.cell(run{text: c, italic: IsStatisticSymbol(c)}, align, true)
+	}
+	b.WriteString(`</w:tr>`)
+
+	for _, row := range t.Rows {
+		b.WriteString(`<w:tr>`)
+		for i := range t.Columns {
+			var cell Cell
+			if i < len(row) {
+				cell = row[i]
+			}
+			align := "left"
+			if cell.Numeric {
+				align = "right"
+			}
+			dw.cell(run{text: cell.Text}, align, false)
+		}
+		b.WriteString(`</w:tr>`)
+	}
+	b.WriteString(`</w:tbl>`)
+	dw.paragraph("", "")
+}
+
+func (dw *docxWriter) cell(r run, align string, header bool) {
+	b := &dw.body
+	b.WriteString(`<w:tc><w:tcPr>`)
+	if header {
+		b.WriteString(`<w:tcBorders><w:bottom w:val="single" w:sz="4" w:space="0" w:color="000000"/></w:tcBorders>`)
+	}
+	fmt.Fprintf(b, `</w:tcPr><w:p><w:pPr><w:pStyle w:val="TableText"/><w:jc w:val="%s"/></w:pPr>`, align)
+	writeRun(b, r)
+	b.WriteString(`</w:p></w:tc>`)
+}
+
+// figuresSection menyematkan gambar PNG/JPEG/GIF dengan nomor dan judul di atas gambar (APA)
+func (dw *docxWriter) figuresSection() {
+	started := false
+	for _, f := range dw.doc.Figures {
+		ext := docxImageExt(f.Data)
+		if ext == "" {
+			continue
+		}
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(f.Data))
+		if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+			continue
+		}
+		if !started {
+			dw.paragraph("Heading2", "", run{text: i18n.T(dw.doc.Lang, "report.figures")})
+			started = true
+		}
+		dw.figures++
+		img := docxImage{
+			rel:  fmt.Sprintf("rIdImage%d", dw.figures),
+			name: fmt.Sprintf("image%d.%s", dw.figures, ext),
+			data: f.Data,
+		}
+		dw.images = append(dw.images, img)
+
+		// 96 dpi: 1 piksel = 9525 EMU
+		cx, cy := int64(cfg.Width)*9525, int64(cfg.Height)*9525
+		if cx > docxMaxImageWidth {
+			cy = cy * docxMaxImageWidth / cx
+			cx = docxMaxImageWidth
+		}
+
+		dw.paragraph("Caption", "", run{text: i18n.T(dw.doc.Lang, "report.figure", dw.figures), bold: true})
+		dw.paragraph("CaptionTitle", "", run{text: f.Title, italic: true})
+		fmt.Fprintf(&dw.body, `<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:drawing>`+
+			`<wp:inline distT="0" distB="0" distL="0" distR="0"><wp:extent cx="%d" cy="%d"/><wp:docPr id="%d" name="%s"/>`+
+			`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+			`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:nvPicPr><pic:cNvPr id="%d" name="%s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+			`<pic:blipFill><a:blip r:embed="%s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+			`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr></pic:pic>`+
+			`</a:graphicData></a:graphic></wp:inline></w:drawing></w:r></w:p>`,
+			cx, cy, dw.figures, img.name, dw.figures, img.name, img.rel, cx, cy)
+	}
+}
+
+func docxImageExt(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return "png"
+	case "image/jpeg":
+		return "jpeg"
+	case "image/gif":
+		return "gif"
+	}
+	return ""
+}
+
+func (dw *docxWriter) document() string {
+	return xml.Header + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" ` +
+		`xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"><w:body>` +
+		dw.body.String() +
+		`<w:sectPr><w:footerReference w:type="default" r:id="rIdFooter"/>` +
+		`<w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1701" w:right="1417" w:bottom="1417" w:left="1701" w:header="708" w:footer="708" w:gutter="0"/>` +
+		`</w:sectPr></w:body></w:document>`
+}
+
+func (dw *docxWriter) documentRels() string {
+	var b strings.Builder
+	b.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	b.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	b.WriteString(`<Relationship Id="rIdFooter" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer" Target="footer1.xml"/>`)
+	for _, img := range dw.images {
+		fmt.Fprintf(&b, `<Relationship Id="%s" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/%s"/>`, img.rel, img.name)
+	}
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func (dw *docxWriter) coreProps() string {
+	return xml.Header + `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" ` +
+		`xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" ` +
+		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<dc:title>` + xmlEscape(dw.doc.Title) + `</dc:title><dc:language>` + dw.doc.Lang + `</dc:language>` +
+		`<dcterms:created xsi:type="dcterms:W3CDTF">` + dw.doc.GeneratedAt.UTC().Format("2006-01-02T15:04:05Z") + `</dcterms:created>` +
+		`</cp:coreProperties>`
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const docxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Default Extension="png" ContentType="image/png"/>` +
+	`<Default Extension="jpeg" ContentType="image/jpeg"/>` +
+	`<Default Extension="gif" ContentType="image/gif"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/footer1.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`</Types>`
+
+const docxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`</Relationships>`
+
+const docxFooter = xml.Header + `<w:ftr xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:fldChar w:fldCharType="begin"/></w:r>` +
+	`<w:r><w:instrText xml:space="preserve"> PAGE </w:instrText></w:r><w:r><w:fldChar w:fldCharType="separate"/></w:r>` +
+	`<w:r><w:t>1</w:t></w:r><w:r><w:fldChar w:fldCharType="end"/></w:r></w:p></w:ftr>`
+
+// docxStyles: Times New Roman 12 pt, spasi 1,5 untuk isi; tabel spasi tunggal 10 pt
+const docxStyles = xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Times New Roman" w:hAnsi="Times New Roman" w:cs="Times New Roman" w:eastAsia="Times New Roman"/>` +
+	`<w:sz w:val="24"/><w:szCs w:val="24"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="360" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="BodyText"><w:name w:val="Body Text"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:ind w:firstLine="720"/><w:jc w:val="both"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="240"/><w:jc w:val="center"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="28"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="200" w:after="120"/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:i/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Caption"><w:name w:val="caption"/><w:basedOn w:val="Normal"/><w:next w:val="CaptionTitle"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="0"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="CaptionTitle"><w:name w:val="Caption Title"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:after="120"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="TableText"><w:name w:val="Table Text"/><w:basedOn w:val="Normal"/>` +
+	`<w:pPr><w:spacing w:before="20" w:after="20" w:line="240" w:lineRule="auto"/></w:pPr><w:rPr><w:sz w:val="20"/><w:szCs w:val="20"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="TableNote"><w:name w:val="Table Note"/><w:basedOn w:val="Normal"/>` +
+	`<w:pPr><w:spacing w:after="240" w:line="240" w:lineRule="auto"/></w:pPr><w:rPr><w:sz w:val="20"/><w:szCs w:val="20"/></w:rPr></w:style>` +
+	`<w:style w:type="table" w:styleId="APATable"><w:name w:val="APA Table"/><w:tblPr><w:tblCellMar><w:left w:w="80" w:type="dxa"/><w:right w:w="80" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+	`</w:styles>`
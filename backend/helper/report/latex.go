@@ -0,0 +1,237 @@
+package report
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/research-data-analysis/helper/i18n"
+)
+
+// latexReplacer meng-escape karakter khusus LaTeX dan memetakan simbol statistik ke mode matematika
+var latexReplacer = strings.NewReplacer(
+	`\`, `\textbackslash{}`, "&", `\&`, "%", `\%`, "$", `\$`, "#", `\#`, "_", `\_`,
+	"{", `\{`, "}", `\}`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`,
+	"—", "---", "–", "--", "≤", `$\leq$`, "≥", `$\geq$`, "≠", `$\neq$`, "≈", `$\approx$`,
+	"−", "-", "→", `$\rightarrow$`, "←", `$\leftarrow$`, "×", `$\times$`,
+	"α", `$\alpha$`, "β", `$\beta$`, "η", `$\eta$`, "χ", `$\chi$`, "ρ", `$\rho$`, "μ", `$\mu$`,
+	"σ", `$\sigma$`, "ε", `$\varepsilon$`, "Δ", `$\Delta$`, "²", `$^2$`, "³", `$^3$`,
+)
+
+// latexMathJoin menggabungkan mode matematika yang bersebelahan, mis. "$\eta$$^2$" menjadi "$\eta^2$"
+var latexMathJoin = regexp.MustCompile(`([^\\])\$\$`)
+
+func latexEscape(s string) string {
+	return latexMathJoin.ReplaceAllString(latexReplacer.Replace(s), "$1")
+}
+
+// RenderLaTeX menulis proyek LaTeX (zip) siap unggah ke Overleaf: main.tex dengan tabel booktabs,
+// references.bib, dan gambar di folder figures/
+func RenderLaTeX(doc Document, w io.Writer) error {
+	var b strings.Builder
+	lang := doc.Lang
+	babel := "english"
+	if lang == i18n.Indonesian {
+		babel = "indonesian"
+	}
+
+	b.WriteString("\\documentclass[12pt,a4paper]{article}\n")
+	b.WriteString("\\usepackage[utf8]{inputenc}\n\\usepackage[T1]{fontenc}\n")
+	fmt.Fprintf(&b, "\\usepackage[%s]{babel}\n", babel)
+	b.WriteString("\\usepackage{booktabs}\n\\usepackage{graphicx}\n\\usepackage{float}\n\\usepackage[margin=2.5cm]{geometry}\n\\usepackage{hyperref}\n\n")
+	fmt.Fprintf(&b, "\\title{%s\\\\\\large %s}\n", latexEscape(i18n.T(lang, "report.title")), latexEscape(doc.Title))
+	fmt.Fprintf(&b, "\\date{%s}\n\n\\begin{document}\n", doc.GeneratedAt.Format("2006-01-02"))
+	if doc.Include(SectionCover) {
+		b.WriteString("\\maketitle\n\n")
+	}
+
+	if len(doc.Meta) > 0 {
+		fmt.Fprintf(&b, "\\section*{%s}\n\\begin{description}\n", latexEscape(i18n.T(lang, "report.metadata")))
+		for _, f := range doc.Meta {
+			fmt.Fprintf(&b, "  \\item[%s] %s\n", latexEscape(f.Label), latexEscape(f.Value))
+		}
+		b.WriteString("\\end{description}\n\n")
+	}
+
+	tables := 0
+	if doc.Include(SectionMethodology) {
+		fmt.Fprintf(&b, "\\section{%s}\n", latexEscape(i18n.T(lang, "report.methodology")))
+		latexParagraphs(&b, strings.Join(doc.MethodologyText(), " "))
+		if len(doc.Variables) > 0 {
+			fmt.Fprintf(&b, "\\subsection{%s}\n", latexEscape(i18n.T(lang, "report.variables")))
+			tables++
+			latexTable(&b, doc.VariableTable(), tables)
+		}
+		if t, ok := doc.MethodsTable(); ok {
+			tables++
+			latexTable(&b, t, tables)
+		}
+	}
+	if t, ok := doc.DescriptiveTable(); ok && doc.Include(SectionDescriptive) {
+		fmt.Fprintf(&b, "\\section{%s}\n", latexEscape(t.Title))
+		tables++
+		latexTable(&b, t, tables)
+	}
+	if t, ok := doc.AssumptionTable(); ok && doc.Include(SectionAssumptions) {
+		fmt.Fprintf(&b, "\\section{%s}\n", latexEscape(t.Title))
+		tables++
+		latexTable(&b, t, tables)
+	}
+
+	if len(doc.Results) > 0 {
+		fmt.Fprintf(&b, "\\section{%s}\n", latexEscape(i18n.T(lang, "report.results")))
+	}
+	for _, r := range doc.Results {
+		fmt.Fprintf(&b, "\\subsection{%s}\n", latexEscape(r.Method))
+		if r.Error != "" {
+			fmt.Fprintf(&b, "\\textbf{%s:} %s\n\n", latexEscape(i18n.T(lang, "report.failed")), latexEscape(r.Error))
+		}
+		for _, t := range r.Tables {
+			tables++
+			latexTable(&b, t, tables)
+		}
+		if len(r.Notes) > 0 {
+			fmt.Fprintf(&b, "{\\small\\textit{%s} %s}\n\n", latexEscape(i18n.T(lang, "report.note")), latexEscape(strings.Join(r.Notes, " ")))
+		}
+		latexParagraphs(&b, r.Interpretation)
+		if r.EffectSize != "" {
+			fmt.Fprintf(&b, "\\textbf{%s:} %s\n\n", latexEscape(i18n.T(lang, "report.effect_size")), latexEscape(r.EffectSize))
+		}
+		if r.Conclusion != "" {
+			fmt.Fprintf(&b, "\\textbf{%s:} %s\n\n", latexEscape(i18n.T(lang, "report.conclusion")), latexEscape(r.Conclusion))
+		}
+	}
+
+	type figureFile struct {
+		name string
+		data []byte
+	}
+	var files []figureFile
+	for _, f := range doc.Figures {
+		ext := docxImageExt(f.Data)
+		if ext != "png" && ext != "jpeg" {
+			continue
+		}
+		if len(files) == 0 {
+			fmt.Fprintf(&b, "\\section{%s}\n", latexEscape(i18n.T(lang, "report.figures")))
+		}
+		name := fmt.Sprintf("figure%d.%s", len(files)+1, ext)
+		files = append(files, figureFile{name: name, data: f.Data})
+		fmt.Fprintf(&b, "\\begin{figure}[H]\n  \\centering\n  \\caption{%s}\n  \\label{fig:%d}\n", latexEscape(f.Title), len(files))
+		fmt.Fprintf(&b, "  \\includegraphics[width=0.9\\textwidth]{figures/%s}\n\\end{figure}\n\n", name)
+	}
+
+	if doc.Summary != "" {
+		fmt.Fprintf(&b, "\\section{%s}\n", latexEscape(i18n.T(lang, "report.summary")))
+		latexParagraphs(&b, doc.Summary)
+	}
+
+	if items := doc.Limitations(); len(items) > 0 && doc.Include(SectionLimitations) {
+		fmt.Fprintf(&b, "\\section{%s}\n\\begin{itemize}\n", latexEscape(i18n.T(lang, "report.limitations")))
+		for _, item := range items {
+			fmt.Fprintf(&b, "  \\item %s\n", latexEscape(item))
+		}
+		b.WriteString("\\end{itemize}\n\n")
+	}
+
+	if doc.Include(SectionAppendix) {
+		fmt.Fprintf(&b, "\\appendix\n\\section{%s}\n\\begin{description}\n", latexEscape(i18n.T(lang, "report.appendix")))
+		for _, f := range doc.AppendixFields() {
+			fmt.Fprintf(&b, "  \\item[%s] %s\n", latexEscape(f.Label), latexEscape(f.Value))
+		}
+		b.WriteString("\\end{description}\n\n")
+	}
+
+	// Bibliografi dibiarkan sebagai komentar agar dokumen tetap terkompilasi selama references.bib masih kosong
+	b.WriteString("% Tambahkan entri ke references.bib, kutip dengan \\cite{key}, lalu aktifkan dua baris berikut\n")
+	b.WriteString("% \\bibliographystyle{apalike}\n% \\bibliography{references}\n\n\\end{document}\n")
+
+	zw := zip.NewWriter(w)
+	if err := writeZipFile(zw, "main.tex", []byte(b.String())); err != nil {
+		return err
+	}
+	if err := writeZipFile(zw, "references.bib", []byte(latexBib)); err != nil {
+		return err
+	}
+	for _, f := range files {
+		if err := writeZipFile(zw, "figures/"+f.name, f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// latexTable menulis tabel booktabs bernomor dengan keterangan di atas tabel (gaya APA)
+func latexTable(b *strings.Builder, t Table, number int) {
+	if len(t.Columns) == 0 {
+		return
+	}
+	spec := ""
+	for i := range t.Columns {
+		if t.NumericColumn(i) {
+			spec += "r"
+		} else {
+			spec += "l"
+		}
+	}
+	b.WriteString("\\begin{table}[H]\n  \\centering\n  \\small\n")
+	fmt.Fprintf(b, "  \\caption{%s}\n  \\label{tab:%d}\n", latexEscape(t.Title), number)
+	wide := len(t.Columns) > 7
+	if wide {
+		b.WriteString("  \\resizebox{\\textwidth}{!}{%\n")
+	}
+	fmt.Fprintf(b, "  \\begin{tabular}{%s}\n    \\toprule\n    ", spec)
+	header := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = latexEscape(c)
+		if IsStatisticSymbol(c) {
+			header[i] = "\\textit{" + header[i] + "}"
+		}
+	}
+	b.WriteString(strings.Join(header, " & ") + " \\\\\n    \\midrule\n")
+	for _, row := range t.Rows {
+		cells := make([]string, len(t.Columns))
+		for i := range t.Columns {
+			if i < len(row) {
+				cells[i] = latexEscape(row[i].Text)
+			}
+		}
+		b.WriteString("    " + strings.Join(cells, " & ") + " \\\\\n")
+	}
+	b.WriteString("    \\bottomrule\n  \\end{tabular}")
+	if wide {
+		b.WriteString("}")
+	}
+	b.WriteString("\n\\end{table}\n\n")
+}
+
+func latexParagraphs(b *strings.Builder, text string) {
+	for _, part := range strings.Split(strings.TrimSpace(text), "\n") {
+		if part = strings.TrimSpace(part); part != "" {
+			b.WriteString(latexEscape(part) + "\n\n")
+		}
+	}
+}
+
+func writeZipFile(zw *zip.Writer, name string, data []byte) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(data)
+	return err
+}
+
+// latexBib adalah berkas referensi awal berisi contoh entri yang dapat disesuaikan
+const latexBib = `% Referensi penelitian. Contoh entri:
+%
+% @book{field2018,
+%   author    = {Field, Andy},
+%   title     = {Discovering Statistics Using IBM SPSS Statistics},
+%   edition   = {5},
+%   publisher = {SAGE Publications},
+%   year      = {2018}
+% }
+`
@@ -0,0 +1,148 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/research-data-analysis/helper/i18n"
+)
+
+// markdownReplacer meng-escape karakter yang merusak sel tabel GitHub-flavored Markdown
+var markdownReplacer = strings.NewReplacer("|", `\|`, "\n", " ", "\r", "")
+
+// RenderMarkdown menulis Document sebagai GitHub-flavored Markdown; gambar ditautkan ke Link
+func RenderMarkdown(doc Document, w io.Writer) error {
+	var b strings.Builder
+	lang := doc.Lang
+
+	if doc.Include(SectionCover) {
+		fmt.Fprintf(&b, "# %s\n\n**%s**\n\n", i18n.T(lang, "report.title"), doc.Title)
+	}
+	if len(doc.Meta) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", i18n.T(lang, "report.metadata"))
+		for _, f := range doc.Meta {
+			fmt.Fprintf(&b, "- **%s:** %s\n", f.Label, strings.ReplaceAll(f.Value, "\n", " "))
+		}
+		b.WriteString("\n")
+	}
+
+	tables := 0
+	if doc.Include(SectionMethodology) {
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n", i18n.T(lang, "report.methodology"), strings.Join(doc.MethodologyText(), " "))
+		if len(doc.Variables) > 0 {
+			fmt.Fprintf(&b, "### %s\n\n", i18n.T(lang, "report.variables"))
+			tables++
+			markdownTable(&b, doc.VariableTable(), i18n.T(lang, "report.table", tables))
+		}
+		if t, ok := doc.MethodsTable(); ok {
+			tables++
+			markdownTable(&b, t, i18n.T(lang, "report.table", tables))
+		}
+	}
+	if t, ok := doc.DescriptiveTable(); ok && doc.Include(SectionDescriptive) {
+		fmt.Fprintf(&b, "## %s\n\n", t.Title)
+		tables++
+		markdownTable(&b, t, i18n.T(lang, "report.table", tables))
+	}
+	if t, ok := doc.AssumptionTable(); ok && doc.Include(SectionAssumptions) {
+		fmt.Fprintf(&b, "## %s\n\n", t.Title)
+		tables++
+		markdownTable(&b, t, i18n.T(lang, "report.table", tables))
+	}
+
+	if len(doc.Results) > 0 {
+		fmt.Fprintf(&b, "## %s\n\n", i18n.T(lang, "report.results"))
+	}
+	for _, r := range doc.Results {
+		fmt.Fprintf(&b, "### %d. %s\n\n", r.Number, r.Method)
+		if r.Error != "" {
+			fmt.Fprintf(&b, "> **%s:** %s\n\n", i18n.T(lang, "report.failed"), r.Error)
+		}
+		for _, t := range r.Tables {
+			tables++
+			markdownTable(&b, t, i18n.T(lang, "report.table", tables))
+		}
+		if len(r.Notes) > 0 {
+			fmt.Fprintf(&b, "*%s* %s\n\n", i18n.T(lang, "report.note"), strings.Join(r.Notes, " "))
+		}
+		if r.Interpretation != "" {
+			b.WriteString(r.Interpretation + "\n\n")
+		}
+		if r.EffectSize != "" {
+			fmt.Fprintf(&b, "**%s:** %s\n\n", i18n.T(lang, "report.effect_size"), r.EffectSize)
+		}
+		if r.Conclusion != "" {
+			fmt.Fprintf(&b, "**%s:** %s\n\n", i18n.T(lang, "report.conclusion"), r.Conclusion)
+		}
+	}
+
+	figures := 0
+	for _, f := range doc.Figures {
+		if f.Link == "" {
+			continue
+		}
+		if figures == 0 {
+			fmt.Fprintf(&b, "## %s\n\n", i18n.T(lang, "report.figures"))
+		}
+		figures++
+		fmt.Fprintf(&b, "**%s**\n\n*%s*\n\n![%s](%s)\n\n", i18n.T(lang, "report.figure", figures), f.Title, f.Title, f.Link)
+	}
+
+	if doc.Summary != "" {
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n", i18n.T(lang, "report.summary"), doc.Summary)
+	}
+
+	if items := doc.Limitations(); len(items) > 0 && doc.Include(SectionLimitations) {
+		fmt.Fprintf(&b, "## %s\n\n", i18n.T(lang, "report.limitations"))
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(item, "\n", " "))
+		}
+		b.WriteString("\n")
+	}
+
+	if doc.Include(SectionAppendix) {
+		fmt.Fprintf(&b, "## %s\n\n", i18n.T(lang, "report.appendix"))
+		for _, f := range doc.AppendixFields() {
+			fmt.Fprintf(&b, "- **%s:** %s\n", f.Label, strings.ReplaceAll(f.Value, "\n", " "))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownTable menulis tabel GFM dengan nomor tebal dan judul miring di atasnya
+func markdownTable(b *strings.Builder, t Table, label string) {
+	if len(t.Columns) == 0 {
+		return
+	}
+	if t.Title != "" {
+		fmt.Fprintf(b, "**%s**\n\n*%s*\n\n", label, t.Title)
+	}
+	header := make([]string, len(t.Columns))
+	align := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = markdownReplacer.Replace(c)
+		if IsStatisticSymbol(c) {
+			header[i] = "*" + header[i] + "*"
+		}
+		align[i] = ":---"
+		if t.NumericColumn(i) {
+			align[i] = "---:"
+		}
+	}
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("| " + strings.Join(align, " | ") + " |\n")
+	for _, row := range t.Rows {
+		cells := make([]string, len(t.Columns))
+		for i := range t.Columns {
+			if i < len(row) {
+				cells[i] = markdownReplacer.Replace(row[i].Text)
+			}
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,413 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/research-data-analysis/helper/i18n"
+)
+
+// Ukuran halaman A4 dalam milimeter
+const (
+	pdfMargin    = 20.0
+	pdfBodyWidth = 170.0
+	pdfLine      = 5.5
+)
+
+// pdfReplacer mengganti simbol yang tidak tersedia pada font inti PDF (cp1252)
+var pdfReplacer = strings.NewReplacer(
+	"≤", "<=", "≥", ">=", "≠", "!=", "≈", "~", "−", "-", "→", "->", "←", "<-",
+	"α", "alpha", "β", "beta", "η", "eta", "χ", "chi", "ρ", "rho", "μ", "mu", "σ", "sigma", "ε", "epsilon",
+	"³", "^3", "√", "sqrt",
+)
+
+// pdfWriter membungkus fpdf dengan penerjemah teks dan penomoran tabel/gambar
+type pdfWriter struct {
+	pdf     *fpdf.Fpdf
+	doc     Document
+	tr      func(string) string
+	tables  int
+	figures int
+}
+
+// RenderPDF menulis Document sebagai PDF (A4) ke w
+func RenderPDF(doc Document, w io.Writer) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetCreator("Research Data Analysis", true)
+	pdf.AliasNbPages("{nb}")
+
+	pw := &pdfWriter{pdf: pdf, doc: doc}
+	cp := pdf.UnicodeTranslatorFromDescriptor("")
+	pw.tr = func(s string) string { return cp(pdfReplacer.Replace(s)) }
+
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 10, pw.tr(i18n.T(doc.Lang, "report.page", pdf.PageNo(), "{nb}")), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	if doc.Include(SectionCover) {
+		pw.titlePage()
+	}
+	pdf.AddPage()
+	pw.metadata()
+	pw.methodology()
+	pw.descriptive()
+	pw.assumptions()
+	pw.results()
+	pw.figuresSection()
+	if doc.Summary != "" {
+		pw.heading(i18n.T(doc.Lang, "report.summary"))
+		pw.paragraph(doc.Summary)
+	}
+	pw.limitations()
+	pw.appendix()
+
+	return pdf.Output(w)
+}
+
+func (pw *pdfWriter) titlePage() {
+	pdf, lang := pw.pdf, pw.doc.Lang
+	pdf.AddPage()
+	pdf.SetY(80)
+	pdf.SetFont("Helvetica", "B", 22)
+	pdf.MultiCell(0, 10, pw.tr(i18n.T(lang, "report.title")), "", "C", false)
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "", 16)
+	pdf.MultiCell(0, 8, pw.tr(pw.doc.Title), "", "C", false)
+	pdf.Ln(20)
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.SetTextColor(90, 90, 90)
+	lines := []string{
+		fmt.Sprintf("%s: %s", i18n.T(lang, "report.analysis_id"), pw.doc.AnalysisID),
+		fmt.Sprintf("%s: %d", i18n.T(lang, "report.iteration"), pw.doc.Iteration),
+		fmt.Sprintf("%s: %s", i18n.T(lang, "report.generated_at"), pw.doc.GeneratedAt.Format("2006-01-02 15:04")),
+	}
+	for _, line := range lines {
+		pdf.CellFormat(0, 7, pw.tr(line), "", 1, "C", false, 0, "")
+	}
+	pdf.SetTextColor(0, 0, 0)
+}
+
+func (pw *pdfWriter) heading(text string) {
+	pdf := pw.pdf
+	if pdf.GetY() > 250 {
+		pdf.AddPage()
+	}
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.MultiCell(0, 8, pw.tr(text), "", "L", false)
+	pdf.Ln(2)
+}
+
+func (pw *pdfWriter) subheading(text string) {
+	pdf := pw.pdf
+	if pdf.GetY() > 255 {
+		pdf.AddPage()
+	}
+	pdf.Ln(3)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.MultiCell(0, 7, pw.tr(text), "", "L", false)
+	pdf.Ln(1)
+}
+
+func (pw *pdfWriter) paragraph(text string) {
+	pw.pdf.SetFont("Helvetica", "", 10.5)
+	for _, part := range strings.Split(strings.TrimSpace(text), "\n") {
+		if part = strings.TrimSpace(part); part == "" {
+			pw.pdf.Ln(2)
+			continue
+		}
+		pw.pdf.MultiCell(0, pdfLine, pw.tr(part), "", "J", false)
+	}
+	pw.pdf.Ln(1)
+}
+
+// labeled menulis label tebal diikuti teksnya
+func (pw *pdfWriter) labeled(label, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	pw.pdf.SetFont("Helvetica", "B", 10.5)
+	pw.pdf.MultiCell(0, pdfLine, pw.tr(label), "", "L", false)
+	pw.paragraph(text)
+}
+
+func (pw *pdfWriter) metadata() {
+	if len(pw.doc.Meta) == 0 {
+		return
+	}
+	pw.heading(i18n.T(pw.doc.Lang, "report.metadata"))
+	for _, f := range pw.doc.Meta {
+		pw.labeled(f.Label, f.Value)
+	}
+}
+
+func (pw *pdfWriter) methodology() {
+	doc := pw.doc
+	if !doc.Include(SectionMethodology) {
+		return
+	}
+	pw.heading(i18n.T(doc.Lang, "report.methodology"))
+	pw.paragraph(strings.Join(doc.MethodologyText(), " "))
+	if len(doc.Variables) > 0 {
+		pw.subheading(i18n.T(doc.Lang, "report.variables"))
+		pw.table(doc.VariableTable())
+	}
+	if t, ok := doc.MethodsTable(); ok {
+		pw.table(t)
+	}
+}
+
+func (pw *pdfWriter) descriptive() {
+	if !pw.doc.Include(SectionDescriptive) {
+		return
+	}
+	if t, ok := pw.doc.DescriptiveTable(); ok {
+		pw.heading(t.Title)
+		pw.table(t)
+	}
+}
+
+func (pw *pdfWriter) assumptions() {
+	if !pw.doc.Include(SectionAssumptions) {
+		return
+	}
+	if t, ok := pw.doc.AssumptionTable(); ok {
+		pw.heading(t.Title)
+		pw.table(t)
+	}
+}
+
+func (pw *pdfWriter) limitations() {
+	if !pw.doc.Include(SectionLimitations) {
+		return
+	}
+	items := pw.doc.Limitations()
+	if len(items) == 0 {
+		return
+	}
+	pw.heading(i18n.T(pw.doc.Lang, "report.limitations"))
+	for _, item := range items {
+		pw.paragraph("- " + item)
+	}
+}
+
+func (pw *pdfWriter) appendix() {
+	if !pw.doc.Include(SectionAppendix) {
+		return
+	}
+	pw.heading(i18n.T(pw.doc.Lang, "report.appendix"))
+	for _, f := range pw.doc.AppendixFields() {
+		pw.labeled(f.Label, f.Value)
+	}
+}
+
+func (pw *pdfWriter) results() {
+	lang := pw.doc.Lang
+	if len(pw.doc.Results) == 0 {
+		return
+	}
+	pw.heading(i18n.T(lang, "report.results"))
+	for _, r := range pw.doc.Results {
+		pw.subheading(fmt.Sprintf("%d. %s", r.Number, r.Method))
+		if r.Error != "" {
+			pw.pdf.SetTextColor(170, 30, 30)
+			pw.labeled(i18n.T(lang, "report.failed"), r.Error)
+			pw.pdf.SetTextColor(0, 0, 0)
+		}
+		for _, t := range r.Tables {
+			pw.table(t)
+		}
+		if len(r.Notes) > 0 {
+			pw.pdf.SetFont("Helvetica", "I", 9)
+			pw.pdf.MultiCell(0, 4.5, pw.tr(i18n.T(lang, "report.notes")+": "+strings.Join(r.Notes, " ")), "", "L", false)
+			pw.pdf.Ln(2)
+		}
+		pw.labeled(i18n.T(lang, "report.interpretation"), r.Interpretation)
+		pw.labeled(i18n.T(lang, "report.effect_size"), r.EffectSize)
+		pw.labeled(i18n.T(lang, "report.conclusion"), r.Conclusion)
+	}
+}
+
+// table menggambar tabel bernomor; header diulang saat tabel terpotong halaman
+func (pw *pdfWriter) table(t Table) {
+	pdf := pw.pdf
+	if len(t.Columns) == 0 {
+		return
+	}
+	if t.Title != "" {
+		pw.tables++
+		if pdf.GetY() > 245 {
+			pdf.AddPage()
+		}
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(0, 6, pw.tr(i18n.T(pw.doc.Lang, "report.table", pw.tables)), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.MultiCell(0, 5, pw.tr(t.Title), "", "L", false)
+		pdf.Ln(1)
+	}
+
+	size := 9.0
+	if len(t.Columns) > 7 {
+		size = 7
+	}
+	height := size * 0.6
+	widths := pw.columnWidths(t, size)
+
+	header := func() {
+		pdf.SetFont("Helvetica", "B", size)
+		pdf.SetFillColor(235, 235, 235)
+		for i, c := range t.Columns {
+			pdf.CellFormat(widths[i], height, pw.fit(c, widths[i]), "TB", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", size)
+	}
+	header()
+	_, pageHeight := pdf.GetPageSize()
+	for r, row := range t.Rows {
+		if pdf.GetY()+height > pageHeight-pdfMargin-5 {
+			pdf.AddPage()
+			header()
+		}
+		border := ""
+		if r == len(t.Rows)-1 {
+			border = "B"
+		}
+		for i := range t.Columns {
+			var cell Cell
+			if i < len(row) {
+				cell = row[i]
+			}
+			align := "L"
+			if cell.Numeric {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], height, pw.fit(cell.Text, widths[i]), border, 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(3)
+}
+
+// columnWidths membagi lebar halaman sebanding dengan isi terpanjang tiap kolom
+func (pw *pdfWriter) columnWidths(t Table, size float64) []float64 {
+	pdf := pw.pdf
+	pdf.SetFont("Helvetica", "B", size)
+	widths := make([]float64, len(t.Columns))
+	total := 0.0
+	for i, c := range t.Columns {
+		widths[i] = pdf.GetStringWidth(pw.tr(c)) + 3
+		for _, row := range t.Rows {
+			if i < len(row) {
+				if w := pdf.GetStringWidth(pw.tr(row[i].Text)) + 3; w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+		if widths[i] > 60 {
+			widths[i] = 60
+		}
+		total += widths[i]
+	}
+	for i := range widths {
+		widths[i] = widths[i] * pdfBodyWidth / total
+	}
+	return widths
+}
+
+// fit memotong teks agar muat di sel
+func (pw *pdfWriter) fit(text string, width float64) string {
+	s := pw.tr(text)
+	if pw.pdf.GetStringWidth(s) <= width-1 {
+		return s
+	}
+	runes := []rune(text)
+	for len(runes) > 1 {
+		runes = runes[:len(runes)-1]
+		s = pw.tr(string(runes) + "...")
+		if pw.pdf.GetStringWidth(s) <= width-1 {
+			return s
+		}
+	}
+	return s
+}
+
+// figuresSection menyematkan gambar yang berhasil diunduh beserta keterangannya
+func (pw *pdfWriter) figuresSection() {
+	pdf := pw.pdf
+	started := false
+	for _, f := range pw.doc.Figures {
+		imageType := pdfImageType(f)
+		if imageType == "" {
+			continue
+		}
+		if !started {
+			pw.heading(i18n.T(pw.doc.Lang, "report.figures"))
+			started = true
+		}
+		pw.figures++
+		name := fmt.Sprintf("figure-%d", pw.figures)
+		options := fpdf.ImageOptions{ImageType: imageType, ReadDpi: true}
+		info := pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(f.Data))
+		if info == nil || pdf.Err() {
+			return
+		}
+		w, h := info.Width(), info.Height()
+		if w > pdfBodyWidth {
+			h, w = h*pdfBodyWidth/w, pdfBodyWidth
+		}
+		if h > 180 {
+			w, h = w*180/h, 180
+		}
+		_, pageHeight := pdf.GetPageSize()
+		if pdf.GetY()+h+12 > pageHeight-pdfMargin {
+			pdf.AddPage()
+		}
+		x := pdfMargin + (pdfBodyWidth-w)/2
+		pdf.ImageOptions(name, x, pdf.GetY(), w, h, true, options, 0, "")
+		pdf.Ln(2)
+		pdf.SetFont("Helvetica", "B", 10)
+		caption := i18n.T(pw.doc.Lang, "report.figure", pw.figures)
+		pdf.CellFormat(0, 5, pw.tr(caption), "", 1, "C", false, 0, "")
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.MultiCell(0, 5, pw.tr(f.Title), "", "C", false)
+		pdf.Ln(4)
+	}
+}
+
+// pdfImageType menentukan tipe gambar yang dapat disematkan fpdf; kosong jika tidak didukung (mis. SVG)
+func pdfImageType(f Figure) string {
+	if len(f.Data) == 0 {
+		return ""
+	}
+	if _, _, err := image.DecodeConfig(bytes.NewReader(f.Data)); err != nil {
+		return ""
+	}
+	switch http.DetectContentType(f.Data) {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg":
+		return "JPG"
+	case "image/gif":
+		return "GIF"
+	}
+	return ""
+}
@@ -0,0 +1,336 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/model"
+)
+
+// Document adalah model laporan yang dipakai bersama oleh seluruh format export
+type Document struct {
+	Lang        string
+	Title       string
+	AnalysisID  string
+	Iteration   int
+	GeneratedAt time.Time
+	Meta        []Field
+	Variables   []VariableRow
+	Results     []Result
+	Figures     []Figure
+	Summary     string
+	// DataFile dan DataSummary diisi pemanggil dari upload analysis (opsional)
+	DataFile    string
+	DataSummary *model.DataSummary
+	// Sections adalah bagian yang dipilih lewat Apply; nilai nol berarti laporan lengkap
+	Sections Selection
+	scope    []Result
+}
+
+// Field adalah pasangan label dan nilai pada metadata proyek
+type Field struct {
+	Label string
+	Value string
+}
+
+// VariableRow adalah satu baris tabel variabel penelitian
+type VariableRow struct {
+	Role     string
+	Variable string
+	Scale    string
+}
+
+// Result adalah bagian laporan untuk satu MethodResult
+type Result struct {
+	Number         int
+	Method         string
+	MethodID       string
+	Error          string
+	Tables         []Table
+	Notes          []string
+	Interpretation string
+	EffectSize     string
+	Conclusion     string
+	APA            APAResult
+	Verification   *model.Verification
+	// Raw adalah RawOutput yang sudah dinormalkan
+	Raw map[string]interface{}
+}
+
+// Table adalah tabel hasil yang sudah siap dicetak
+type Table struct {
+	Name    string
+	Title   string
+	Columns []string
+	Rows    [][]Cell
+}
+
+// Cell adalah satu sel tabel; nilai numerik disimpan agar format seperti xlsx tetap bertipe angka
+type Cell struct {
+	Text    string
+	Value   float64
+	Numeric bool
+}
+
+// Figure adalah gambar hasil analysis; Data diisi pemanggil jika gambar berhasil diunduh.
+// Link adalah alamat gambar yang bisa dibuka pembaca export Markdown (signed URL atau path relatif), diisi pemanggil.
+type Figure struct {
+	ID          string
+	Title       string
+	Type        string
+	StorageURL  string
+	Link        string
+	Data        []byte
+	ContentType string
+}
+
+// Build menyusun Document dari proyek dan analysis dalam bahasa lang
+func Build(project model.Project, analysis model.Analysis, lang string) Document {
+	lang = i18n.Resolve(lang)
+	doc := Document{
+		Lang:       lang,
+		Title:      project.Title,
+		AnalysisID: analysis.ID.Hex(),
+		Iteration:  analysis.Iteration,
+		Summary:    strings.TrimSpace(analysis.Summary),
+	}
+	doc.GeneratedAt = time.Now()
+	if analysis.CompletedAt != nil {
+		doc.GeneratedAt = *analysis.CompletedAt
+	}
+
+	for _, f := range []Field{
+		{Label: i18n.T(lang, "report.project"), Value: project.Title},
+		{Label: i18n.T(lang, "report.description"), Value: project.Description},
+		{Label: i18n.T(lang, "report.research_type"), Value: project.ResearchType},
+		{Label: i18n.T(lang, "report.hypothesis"), Value: project.Hypothesis},
+		{Label: i18n.T(lang, "report.iteration"), Value: strconv.Itoa(analysis.Iteration)},
+		{Label: i18n.T(lang, "report.generated_at"), Value: doc.GeneratedAt.Format("2006-01-02 15:04")},
+	} {
+		if strings.TrimSpace(f.Value) != "" {
+			doc.Meta = append(doc.Meta, f)
+		}
+	}
+
+	roles := []struct {
+		key   string
+		names []string
+	}{
+		{"report.independent", project.Variables.Independent},
+		{"report.dependent", project.Variables.Dependent},
+		{"report.control", project.Variables.Control},
+		{"report.moderating", project.Variables.Moderating},
+		{"report.mediating", project.Variables.Mediating},
+	}
+	for _, role := range roles {
+		for _, name := range role.names {
+			doc.Variables = append(doc.Variables, VariableRow{
+				Role:     i18n.T(lang, role.key),
+				Variable: name,
+				Scale:    project.Variables.Scales[name],
+			})
+		}
+	}
+
+	for i, r := range analysis.Results {
+		doc.Results = append(doc.Results, buildResult(i+1, r, lang))
+	}
+
+	for _, f := range analysis.Figures {
+		doc.Figures = append(doc.Figures, Figure{ID: f.ID, Title: f.Title, Type: f.Type, StorageURL: f.StorageURL})
+	}
+	return doc
+}
+
+// AttachUpload menambahkan informasi file data upload ke Document
+func (d *Document) AttachUpload(upload model.Upload) {
+	if upload.FileName == "" {
+		return
+	}
+	d.DataFile = upload.FileName
+	summary := upload.DataSummary
+	d.DataSummary = &summary
+	d.Meta = append(d.Meta, Field{Label: i18n.T(d.Lang, "report.data_file"), Value: upload.FileName})
+}
+
+// VariableTable menyusun tabel variabel penelitian; kolom skala hanya muncul jika ada skala yang diisi
+func (d Document) VariableTable() Table {
+	t := Table{
+		Name:    "variables",
+		Title:   i18n.T(d.Lang, "report.variables"),
+		Columns: []string{i18n.T(d.Lang, "report.role"), i18n.T(d.Lang, "report.variable")},
+	}
+	withScale := false
+	for _, v := range d.Variables {
+		withScale = withScale || v.Scale != ""
+	}
+	if withScale {
+		t.Columns = append(t.Columns, i18n.T(d.Lang, "report.scale"))
+	}
+	for _, v := range d.Variables {
+		row := []Cell{{Text: v.Role}, {Text: v.Variable}}
+		if withScale {
+			row = append(row, Cell{Text: v.Scale})
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// DescriptiveTable menyusun statistik deskriptif seluruh kolom data upload dari DataSummary
+func (d Document) DescriptiveTable() (Table, bool) {
+	if d.DataSummary == nil || len(d.DataSummary.ColumnNames) == 0 {
+		return Table{}, false
+	}
+	keys := []string{"n", "mean", "std", "min", "q1", "median", "q3", "max", "skewness", "kurtosis"}
+	t := Table{
+		Name:    "data_descriptives",
+		Title:   i18n.T(d.Lang, "report.descriptives"),
+		Columns: []string{i18n.T(d.Lang, "report.variable"), "Type", i18n.T(d.Lang, "report.scale"), "Missing", "N", "Mean", "SD", "Min", "Q1", "Median", "Q3", "Max", "Skewness", "Kurtosis"},
+	}
+	for _, column := range d.DataSummary.ColumnNames {
+		row := []Cell{
+			{Text: column},
+			{Text: d.DataSummary.ColumnTypes[column]},
+			{Text: d.DataSummary.Scales[column]},
+			FormatCell(d.Lang, d.DataSummary.MissingCount[column], false),
+		}
+		entry, _ := stats.Normalize(d.DataSummary.Statistics[column]).(map[string]interface{})
+		for _, key := range keys {
+			row = append(row, FormatCell(d.Lang, entry[key], false))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, true
+}
+
+// NumericColumn mengecek apakah seluruh sel terisi pada kolom i bernilai numerik (untuk perataan kanan)
+func (t Table) NumericColumn(i int) bool {
+	numeric := false
+	for _, row := range t.Rows {
+		if i >= len(row) || row[i].Text == "" {
+			continue
+		}
+		if !row[i].Numeric {
+			return false
+		}
+		numeric = true
+	}
+	return numeric
+}
+
+// buildResult mengubah satu MethodResult menjadi bagian laporan
+func buildResult(number int, r model.MethodResult, lang string) Result {
+	raw := stats.NormalizeOutput(r.RawOutput)
+	res := Result{
+		Number:         number,
+		Method:         r.Method,
+		MethodID:       r.MethodID,
+		Error:          r.Error,
+		Notes:          stats.Strings(raw, stats.KeyNotes),
+		Interpretation: strings.TrimSpace(r.Interpretation),
+		EffectSize:     strings.TrimSpace(r.EffectSize),
+		Conclusion:     strings.TrimSpace(r.Conclusion),
+		Verification:   r.Verification,
+		Raw:            raw,
+	}
+	if res.MethodID == "" {
+		res.MethodID = stats.String(raw, stats.KeyMethod)
+	}
+	if r.Error == "" {
+		res.APA = FormatAPA(r, lang)
+	}
+	if res.Error == "" {
+		res.Error = stats.String(raw, stats.KeyError)
+	}
+	for _, t := range stats.Tables(raw) {
+		res.Tables = append(res.Tables, buildTable(t, lang))
+	}
+	return res
+}
+
+// buildTable memformat sel tabel RawOutput sesuai jenis kolomnya
+func buildTable(t stats.Table, lang string) Table {
+	out := Table{Name: t.Name, Title: t.Title, Columns: t.Columns}
+	allP := t.Name == "p_values"
+	for _, row := range t.Rows {
+		cells := make([]Cell, len(row))
+		for i, v := range row {
+			column := ""
+			if i < len(t.Columns) {
+				column = t.Columns[i]
+			}
+			cells[i] = FormatCell(lang, v, (allP && i > 0) || IsPColumn(column))
+		}
+		out.Rows = append(out.Rows, cells)
+	}
+	return out
+}
+
+// IsPColumn mengecek apakah kolom berisi p-value
+func IsPColumn(column string) bool {
+	c := strings.ToLower(strings.TrimSpace(column))
+	return c == "p" || strings.HasSuffix(c, " p") || c == "sig." || strings.HasPrefix(c, "p-value")
+}
+
+// FormatCell memformat satu nilai RawOutput menjadi sel laporan
+func FormatCell(lang string, v interface{}, pValue bool) Cell {
+	switch t := v.(type) {
+	case nil:
+		return Cell{}
+	case bool:
+		if t {
+			return Cell{Text: i18n.T(lang, "report.yes")}
+		}
+		return Cell{Text: i18n.T(lang, "report.no")}
+	case string:
+		return Cell{Text: t}
+	}
+	f, ok := stats.Float(v)
+	if !ok {
+		return Cell{Text: fmt.Sprint(v)}
+	}
+	if pValue {
+		return Cell{Text: FormatP(f), Value: f, Numeric: true}
+	}
+	return Cell{Text: FormatNumber(f), Value: f, Numeric: true}
+}
+
+// FormatNumber memformat angka: bilangan bulat tanpa desimal, selain itu tiga desimal
+func FormatNumber(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ""
+	}
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', 3, 64)
+}
+
+// FormatP memformat p-value gaya APA: tanpa nol di depan, "< .001" untuk nilai sangat kecil
+func FormatP(p float64) string {
+	if math.IsNaN(p) || math.IsInf(p, 0) {
+		return ""
+	}
+	if p < 0.001 {
+		return "< .001"
+	}
+	s := strconv.FormatFloat(p, 'f', 3, 64)
+	return strings.TrimPrefix(s, "0")
+}
+
+// statisticSymbols adalah header kolom berupa simbol statistik yang dicetak miring menurut APA
+var statisticSymbols = map[string]bool{
+	"N": true, "n": true, "M": true, "SD": true, "SE": true, "t": true, "df": true, "df1": true, "df2": true,
+	"p": true, "F": true, "r": true, "W": true, "z": true, "B": true, "U": true, "H": true, "R": true, "d": true,
+}
+
+// IsStatisticSymbol mengecek apakah header kolom adalah simbol statistik
+func IsStatisticSymbol(column string) bool {
+	return statisticSymbols[strings.TrimSpace(column)]
+}
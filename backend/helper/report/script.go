@@ -0,0 +1,790 @@
+package report
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/research-data-analysis/helper/stats"
+)
+
+// BootstrapSeed adalah seed bootstrap mediasi di skrip Python; engine sendiri deterministik
+const BootstrapSeed = 42
+
+// scriptStep adalah satu metode yang akan direproduksi di skrip R, Python, dan SPSS.
+// Variabel dan opsi diambil dari RawOutput sehingga sama persis dengan yang dipakai engine.
+type scriptStep struct {
+	Number int
+	Method string
+	ID     string
+	Vars   []string
+	Raw    map[string]interface{}
+	Alpha  float64
+	Skip   string
+}
+
+func scriptSteps(doc Document) []scriptStep {
+	steps := make([]scriptStep, 0, len(doc.Results))
+	for _, r := range doc.Results {
+		step := scriptStep{Number: r.Number, Method: r.Method, ID: r.MethodID, Raw: r.Raw, Alpha: stats.DefaultAlpha}
+		if r.Raw != nil {
+			step.Vars = stats.Strings(r.Raw, stats.KeyVariables)
+			if a, ok := stats.Number(r.Raw, stats.KeyAlpha); ok && a > 0 && a < 1 {
+				step.Alpha = a
+			}
+		}
+		switch {
+		case r.Error != "":
+			step.Skip = "analysis failed: " + r.Error
+		case len(step.Vars) == 0:
+			step.Skip = "no variables recorded for this method"
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// descriptiveColumns memisahkan variabel numerik dan kategorik sesuai tabel hasil descriptive
+func (s scriptStep) descriptiveColumns() (numeric, categorical []string) {
+	seen := map[string]bool{}
+	for _, t := range stats.Tables(s.Raw) {
+		for _, row := range t.Rows {
+			name, _ := cellAt(row, 0).(string)
+			if name == "" || seen[t.Name+name] {
+				continue
+			}
+			seen[t.Name+name] = true
+			switch t.Name {
+			case "descriptives":
+				numeric = append(numeric, name)
+			case "frequencies":
+				categorical = append(categorical, name)
+			}
+		}
+	}
+	if len(numeric) == 0 && len(categorical) == 0 {
+		numeric = s.Vars
+	}
+	return numeric, categorical
+}
+
+// groups mengembalikan label kelompok yang dibandingkan engine
+func (s scriptStep) groups() []string {
+	return stats.Strings(s.Raw, "groups")
+}
+
+func (s scriptStep) bool(key string, fallback bool) bool {
+	if v, ok := s.Raw[key].(bool); ok {
+		return v
+	}
+	return fallback
+}
+
+func (s scriptStep) number(key string, fallback float64) float64 {
+	if v, ok := stats.Number(s.Raw, key); ok {
+		return v
+	}
+	return fallback
+}
+
+func (s scriptStep) list(key string) []string {
+	if v := stats.Strings(s.Raw, key); len(v) > 0 {
+		return v
+	}
+	return nil
+}
+
+func scriptNum(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func scriptConfidence(alpha float64) string {
+	return scriptNum(1 - alpha)
+}
+
+// scriptDataFile mengembalikan nama file data asli atau placeholder jika upload tidak ditemukan
+func scriptDataFile(doc Document) string {
+	if doc.DataFile != "" {
+		return doc.DataFile
+	}
+	return "data.csv"
+}
+
+// scriptHeader menulis komentar pembuka; end dipakai SPSS yang mengakhiri setiap komentar dengan titik
+func scriptHeader(b *strings.Builder, comment, end string, doc Document, tools string) {
+	fmt.Fprintf(b, "%s %s%s\n", comment, scriptComment(doc.Title), end)
+	fmt.Fprintf(b, "%s Analysis ID: %s%s\n", comment, doc.AnalysisID, end)
+	fmt.Fprintf(b, "%s Generated: %s%s\n", comment, doc.GeneratedAt.Format("2006-01-02 15:04 MST"), end)
+	fmt.Fprintf(b, "%s Reproduces every method of this analysis with the same variables and options.\n", comment)
+	fmt.Fprintf(b, "%s Requires: %s\n\n", comment, tools)
+}
+
+// RenderRScript menulis skrip R (base, psych, lavaan) yang mereproduksi seluruh metode analisis
+func RenderRScript(doc Document, w io.Writer) error {
+	var b strings.Builder
+	scriptHeader(&b, "#", "", doc, `R >= 4.0 with install.packages(c("psych", "lavaan", "car", "readxl"))`)
+	b.WriteString("library(psych)\nlibrary(lavaan)\nlibrary(car)\n\n")
+
+	file := scriptDataFile(doc)
+	b.WriteString("# ---- Data ----\n")
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xlsx":
+		fmt.Fprintf(&b, "data <- as.data.frame(readxl::read_excel(%s, sheet = 1))\n", strconv.Quote(file))
+	case ".tsv", ".txt":
+		fmt.Fprintf(&b, "data <- read.delim(%s, check.names = FALSE, na.strings = c(\"\", \"NA\"))\n", strconv.Quote(file))
+	default:
+		b.WriteString("# Change sep to \";\" if the file uses semicolons\n")
+		fmt.Fprintf(&b, "data <- read.csv(%s, check.names = FALSE, na.strings = c(\"\", \"NA\"))\n", strconv.Quote(file))
+	}
+	b.WriteString("str(data)\n\n")
+
+	for _, s := range scriptSteps(doc) {
+		fmt.Fprintf(&b, "# ---- %d. %s ----\n", s.Number, s.Method)
+		if s.Skip != "" {
+			fmt.Fprintf(&b, "# Skipped: %s\n\n", scriptComment(s.Skip))
+			continue
+		}
+		if !writeRStep(&b, s) {
+			fmt.Fprintf(&b, "# Method %q has no R equivalent in this export\n", s.ID)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func rVector(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = strconv.Quote(n)
+	}
+	return "c(" + strings.Join(quoted, ", ") + ")"
+}
+
+// rName menulis nama kolom untuk formula R dengan backtick agar aman untuk spasi
+func rName(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "\\`") + "`"
+}
+
+func rCol(frame, name string) string {
+	return frame + "[[" + strconv.Quote(name) + "]]"
+}
+
+func writeRStep(b *strings.Builder, s scriptStep) bool {
+	v := s.Vars
+	conf := scriptConfidence(s.Alpha)
+	switch s.ID {
+	case stats.MethodDescriptive:
+		numeric, categorical := s.descriptiveColumns()
+		if len(numeric) > 0 {
+			fmt.Fprintf(b, "print(psych::describe(data[%s], quant = c(.25, .75)))\n", rVector(numeric))
+		}
+		if len(categorical) > 0 {
+			fmt.Fprintf(b, "for (v in %s) {\n  counts <- table(data[[v]])\n  print(cbind(Frequency = counts, Percent = 100 * prop.table(counts)))\n}\n", rVector(categorical))
+		}
+	case stats.MethodNormality:
+		fmt.Fprintf(b, "for (v in %s) print(shapiro.test(data[[v]]))\n", rVector(v))
+	case stats.MethodOneSampleTTest:
+		mu := scriptNum(s.number("test_value", 0))
+		fmt.Fprintf(b, "x <- na.omit(%s)\n", rCol("data", v[0]))
+		fmt.Fprintf(b, "print(t.test(x, mu = %s, conf.level = %s))\n", mu, conf)
+		fmt.Fprintf(b, "cat(\"Cohen's d =\", (mean(x) - %s) / sd(x), \"\\n\")\n", mu)
+	case stats.MethodIndependentTTest, stats.MethodMannWhitney:
+		if len(v) < 2 {
+			return false
+		}
+		rTwoGroups(b, s)
+		formula := rName(v[0]) + " ~ " + rName(v[1])
+		if s.ID == stats.MethodIndependentTTest {
+			fmt.Fprintf(b, "print(car::leveneTest(%s, data = d, center = mean))\n", formula)
+			fmt.Fprintf(b, "print(t.test(%s, data = d, var.equal = %s, conf.level = %s))\n", formula, rBool(s.bool("equal_variances_assumed", true)), conf)
+			fmt.Fprintf(b, "print(psych::cohen.d(d[c(%s, %s)], %s))\n", strconv.Quote(v[0]), strconv.Quote(v[1]), strconv.Quote(v[1]))
+		} else {
+			fmt.Fprintf(b, "print(wilcox.test(%s, data = d, exact = FALSE, correct = FALSE))\n", formula)
+		}
+	case stats.MethodPairedTTest, stats.MethodWilcoxon:
+		if len(v) < 2 {
+			return false
+		}
+		fmt.Fprintf(b, "d <- na.omit(data[%s])\n", rVector(v[:2]))
+		if s.ID == stats.MethodPairedTTest {
+			fmt.Fprintf(b, "print(t.test(%s, %s, paired = TRUE, conf.level = %s))\n", rCol("d", v[0]), rCol("d", v[1]), conf)
+			fmt.Fprintf(b, "diffs <- %s - %s\ncat(\"Cohen's d =\", mean(diffs) / sd(diffs), \"\\n\")\n", rCol("d", v[0]), rCol("d", v[1]))
+		} else {
+			fmt.Fprintf(b, "print(wilcox.test(%s, %s, paired = TRUE, exact = FALSE, correct = FALSE))\n", rCol("d", v[0]), rCol("d", v[1]))
+		}
+	case stats.MethodOneWayANOVA, stats.MethodKruskalWallis:
+		if len(v) < 2 {
+			return false
+		}
+		fmt.Fprintf(b, "d <- na.omit(data[%s])\n%s <- factor(%s)\n", rVector(v[:2]), rCol("d", v[1]), rCol("d", v[1]))
+		formula := rName(v[0]) + " ~ " + rName(v[1])
+		if s.ID == stats.MethodOneWayANOVA {
+			fmt.Fprintf(b, "print(car::leveneTest(%s, data = d, center = mean))\n", formula)
+			fmt.Fprintf(b, "fit <- aov(%s, data = d)\nprint(summary(fit))\n", formula)
+			b.WriteString("ss <- summary(fit)[[1]][[\"Sum Sq\"]]\ncat(\"Eta squared =\", ss[1] / sum(ss), \"\\n\")\n")
+			fmt.Fprintf(b, "print(pairwise.t.test(%s, %s, p.adjust.method = \"bonferroni\"))\n", rCol("d", v[0]), rCol("d", v[1]))
+		} else {
+			fmt.Fprintf(b, "print(kruskal.test(%s, data = d))\n", formula)
+		}
+	case stats.MethodChiSquare:
+		if len(v) < 2 {
+			return false
+		}
+		fmt.Fprintf(b, "tab <- table(%s, %s)\nprint(tab)\n", rCol("data", v[0]), rCol("data", v[1]))
+		b.WriteString("test <- chisq.test(tab, correct = FALSE)\nprint(test)\nprint(round(test$expected, 2))\n")
+		b.WriteString("cat(\"Cramer's V =\", sqrt(test$statistic / (sum(tab) * (min(dim(tab)) - 1))), \"\\n\")\n")
+	case stats.MethodPearson, stats.MethodSpearman:
+		method := "pearson"
+		if s.ID == stats.MethodSpearman {
+			method = "spearman"
+		}
+		fmt.Fprintf(b, "print(psych::corr.test(data[%s], method = %q, use = \"pairwise\", adjust = \"none\", alpha = %s), short = FALSE)\n", rVector(v), method, scriptNum(s.Alpha))
+	case stats.MethodLinearRegression:
+		dv, predictors := v[0], v[1:]
+		if d := stats.String(s.Raw, "dependent"); d != "" {
+			dv = d
+		}
+		if p := s.list("predictors"); len(p) > 0 {
+			predictors = p
+		}
+		terms := make([]string, len(predictors))
+		for i, p := range predictors {
+			terms[i] = rName(p)
+		}
+		fmt.Fprintf(b, "fit <- lm(%s ~ %s, data = data)\nprint(summary(fit))\n", rName(dv), strings.Join(terms, " + "))
+		fmt.Fprintf(b, "print(confint(fit, level = %s))\n", conf)
+		if len(predictors) > 1 {
+			b.WriteString("print(car::vif(fit))\n")
+		}
+	case stats.MethodModeration:
+		if len(v) < 3 {
+			return false
+		}
+		dv, x, m := v[0], v[1], v[2]
+		fmt.Fprintf(b, "d <- na.omit(data[%s])\n", rVector(v[:3]))
+		if s.bool("centered", true) {
+			for _, name := range []string{x, m} {
+				fmt.Fprintf(b, "%s <- %s - mean(%s)\n", rCol("d", name), rCol("d", name), rCol("d", name))
+			}
+		}
+		fmt.Fprintf(b, "reduced <- lm(%s ~ %s + %s, data = d)\n", rName(dv), rName(x), rName(m))
+		fmt.Fprintf(b, "fit <- lm(%s ~ %s * %s, data = d)\nprint(summary(fit))\n", rName(dv), rName(x), rName(m))
+		fmt.Fprintf(b, "print(confint(fit, level = %s))\n", conf)
+		b.WriteString("print(anova(reduced, fit))\ncat(\"R-squared change =\", summary(fit)$r.squared - summary(reduced)$r.squared, \"\\n\")\n")
+	case stats.MethodMediation:
+		if len(v) < 3 {
+			return false
+		}
+		b.WriteString("# Columns are renamed to Y, X, M so the lavaan syntax stays valid for any column name\n")
+		fmt.Fprintf(b, "d <- setNames(na.omit(data[%s]), c(\"Y\", \"X\", \"M\"))\n", rVector(v[:3]))
+		b.WriteString("model <- '\n  M ~ a * X\n  Y ~ cp * X + b * M\n  indirect := a * b\n  total := cp + a * b\n'\n")
+		b.WriteString("fit <- sem(model, data = d)\n")
+		b.WriteString("# Delta-method standard errors correspond to the Sobel test reported by the application\n")
+		fmt.Fprintf(b, "print(parameterEstimates(fit, level = %s))\n", conf)
+	case stats.MethodReliability:
+		fmt.Fprintf(b, "print(psych::alpha(na.omit(data[%s])))\n", rVector(v))
+	default:
+		return false
+	}
+	return true
+}
+
+// rTwoGroups membatasi data pada dua kelompok yang dibandingkan engine
+func rTwoGroups(b *strings.Builder, s scriptStep) {
+	dv, group := s.Vars[0], s.Vars[1]
+	fmt.Fprintf(b, "d <- na.omit(data[%s])\n", rVector([]string{dv, group}))
+	if labels := s.groups(); len(labels) == 2 {
+		fmt.Fprintf(b, "d <- d[as.character(%s) %%in%% %s, ]\n", rCol("d", group), rVector(labels))
+		fmt.Fprintf(b, "%s <- factor(%s, levels = %s)\n", rCol("d", group), rCol("d", group), rVector(labels))
+		return
+	}
+	fmt.Fprintf(b, "%s <- factor(%s)\n", rCol("d", group), rCol("d", group))
+}
+
+func rBool(v bool) string {
+	if v {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// RenderPythonScript menulis skrip Python (pandas, statsmodels, pingouin) yang mereproduksi seluruh metode analisis
+func RenderPythonScript(doc Document, w io.Writer) error {
+	var b strings.Builder
+	scriptHeader(&b, "#", "", doc, "Python >= 3.9 with pip install pandas openpyxl statsmodels pingouin")
+	b.WriteString("import pandas as pd\nimport pingouin as pg\nimport statsmodels.formula.api as smf\n")
+	b.WriteString("from statsmodels.stats.outliers_influence import variance_inflation_factor\n\n")
+	b.WriteString("pd.set_option(\"display.width\", 160)\npd.set_option(\"display.max_columns\", 20)\n\n")
+
+	file := scriptDataFile(doc)
+	b.WriteString("# ---- Data ----\n")
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xlsx":
+		fmt.Fprintf(&b, "data = pd.read_excel(%s, sheet_name=0)\n", strconv.Quote(file))
+	default:
+		b.WriteString("# sep=None detects comma, semicolon, tab or pipe delimiters like the application does\n")
+		fmt.Fprintf(&b, "data = pd.read_csv(%s, sep=None, engine=\"python\")\n", strconv.Quote(file))
+	}
+	b.WriteString("print(data.dtypes)\n\n")
+
+	for _, s := range scriptSteps(doc) {
+		fmt.Fprintf(&b, "# ---- %d. %s ----\n", s.Number, s.Method)
+		if s.Skip != "" {
+			fmt.Fprintf(&b, "# Skipped: %s\n\n", scriptComment(s.Skip))
+			continue
+		}
+		if !writePythonStep(&b, s) {
+			fmt.Fprintf(&b, "# Method %q has no Python equivalent in this export\n", s.ID)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func pyList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = strconv.Quote(n)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// pyFormula menyusun formula patsy dengan Q("...") lalu mengutipnya sebagai string Python
+func pyFormula(dv string, terms []string, op string) string {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = "Q(" + strconv.Quote(t) + ")"
+	}
+	return strconv.Quote("Q(" + strconv.Quote(dv) + ") ~ " + strings.Join(quoted, op))
+}
+
+func pyBool(v bool) string {
+	if v {
+		return "True"
+	}
+	return "False"
+}
+
+func writePythonStep(b *strings.Builder, s scriptStep) bool {
+	v := s.Vars
+	conf := scriptConfidence(s.Alpha)
+	switch s.ID {
+	case stats.MethodDescriptive:
+		numeric, categorical := s.descriptiveColumns()
+		if len(numeric) > 0 {
+			fmt.Fprintf(b, "cols = %s\n", pyList(numeric))
+			b.WriteString("desc = data[cols].describe().T\ndesc[\"skewness\"] = data[cols].skew()\ndesc[\"kurtosis\"] = data[cols].kurt()\nprint(desc)\n")
+		}
+		if len(categorical) > 0 {
+			fmt.Fprintf(b, "for v in %s:\n", pyList(categorical))
+			b.WriteString("    counts = data[v].value_counts().sort_index()\n")
+			b.WriteString("    print(pd.DataFrame({\"Frequency\": counts, \"Percent\": 100 * counts / counts.sum()}))\n")
+		}
+	case stats.MethodNormality:
+		fmt.Fprintf(b, "for v in %s:\n    print(v, pg.normality(data[v].dropna(), method=\"shapiro\", alpha=%s), sep=\"\\n\")\n", pyList(v), scriptNum(s.Alpha))
+	case stats.MethodOneSampleTTest:
+		fmt.Fprintf(b, "print(pg.ttest(data[%s].dropna(), %s, confidence=%s))\n", strconv.Quote(v[0]), scriptNum(s.number("test_value", 0)), conf)
+	case stats.MethodIndependentTTest, stats.MethodMannWhitney:
+		if len(v) < 2 {
+			return false
+		}
+		pyTwoGroups(b, s)
+		if s.ID == stats.MethodIndependentTTest {
+			fmt.Fprintf(b, "print(pg.homoscedasticity(d, dv=%s, group=%s, method=\"levene\", center=\"mean\"))\n", strconv.Quote(v[0]), strconv.Quote(v[1]))
+			fmt.Fprintf(b, "print(pg.ttest(x1, x2, correction=%s, confidence=%s))\n", pyBool(!s.bool("equal_variances_assumed", true)), conf)
+		} else {
+			b.WriteString("print(pg.mwu(x1, x2, method=\"asymptotic\"))\n")
+		}
+	case stats.MethodPairedTTest, stats.MethodWilcoxon:
+		if len(v) < 2 {
+			return false
+		}
+		fmt.Fprintf(b, "d = data[%s].dropna()\n", pyList(v[:2]))
+		if s.ID == stats.MethodPairedTTest {
+			fmt.Fprintf(b, "print(pg.ttest(d[%s], d[%s], paired=True, confidence=%s))\n", strconv.Quote(v[0]), strconv.Quote(v[1]), conf)
+		} else {
+			fmt.Fprintf(b, "print(pg.wilcoxon(d[%s], d[%s], method=\"approx\"))\n", strconv.Quote(v[0]), strconv.Quote(v[1]))
+		}
+	case stats.MethodOneWayANOVA, stats.MethodKruskalWallis:
+		if len(v) < 2 {
+			return false
+		}
+		dv, group := strconv.Quote(v[0]), strconv.Quote(v[1])
+		fmt.Fprintf(b, "d = data[%s].dropna()\n", pyList(v[:2]))
+		if s.ID == stats.MethodOneWayANOVA {
+			fmt.Fprintf(b, "print(pg.homoscedasticity(d, dv=%s, group=%s, method=\"levene\", center=\"mean\"))\n", dv, group)
+			fmt.Fprintf(b, "print(pg.anova(data=d, dv=%s, between=%s, detailed=True, effsize=\"n2\"))\n", dv, group)
+			fmt.Fprintf(b, "print(pg.pairwise_tests(data=d, dv=%s, between=%s, padjust=\"bonf\"))\n", dv, group)
+		} else {
+			fmt.Fprintf(b, "print(pg.kruskal(data=d, dv=%s, between=%s))\n", dv, group)
+		}
+	case stats.MethodChiSquare:
+		if len(v) < 2 {
+			return false
+		}
+		fmt.Fprintf(b, "expected, observed, tests = pg.chi2_independence(data, x=%s, y=%s, correction=False)\n", strconv.Quote(v[0]), strconv.Quote(v[1]))
+		b.WriteString("print(observed)\nprint(expected.round(2))\nprint(tests[tests[\"test\"] == \"pearson\"])\n")
+	case stats.MethodPearson, stats.MethodSpearman:
+		method := "pearson"
+		if s.ID == stats.MethodSpearman {
+			method = "spearman"
+		}
+		fmt.Fprintf(b, "print(data[%s].corr(method=%q))\n", pyList(v), method)
+		fmt.Fprintf(b, "print(pg.pairwise_corr(data, columns=%s, method=%q))\n", pyList(v), method)
+	case stats.MethodLinearRegression:
+		dv, predictors := v[0], v[1:]
+		if d := stats.String(s.Raw, "dependent"); d != "" {
+			dv = d
+		}
+		if p := s.list("predictors"); len(p) > 0 {
+			predictors = p
+		}
+		fmt.Fprintf(b, "d = data[%s].dropna()\n", pyList(append([]string{dv}, predictors...)))
+		fmt.Fprintf(b, "fit = smf.ols(%s, data=d).fit()\nprint(fit.summary(alpha=%s))\n", pyFormula(dv, predictors, " + "), scriptNum(s.Alpha))
+		fmt.Fprintf(b, "print(pg.linear_regression(d[%s], d[%s], alpha=%s))\n", pyList(predictors), strconv.Quote(dv), scriptNum(s.Alpha))
+		if len(predictors) > 1 {
+			b.WriteString("exog = fit.model.exog\nprint({name: variance_inflation_factor(exog, i) for i, name in enumerate(fit.model.exog_names) if i > 0})\n")
+		}
+	case stats.MethodModeration:
+		if len(v) < 3 {
+			return false
+		}
+		dv, x, m := v[0], v[1], v[2]
+		fmt.Fprintf(b, "d = data[%s].dropna()\n", pyList(v[:3]))
+		if s.bool("centered", true) {
+			for _, name := range []string{x, m} {
+				fmt.Fprintf(b, "d[%s] = d[%s] - d[%s].mean()\n", strconv.Quote(name), strconv.Quote(name), strconv.Quote(name))
+			}
+		}
+		fmt.Fprintf(b, "reduced = smf.ols(%s, data=d).fit()\n", pyFormula(dv, []string{x, m}, " + "))
+		fmt.Fprintf(b, "fit = smf.ols(%s, data=d).fit()\nprint(fit.summary(alpha=%s))\n", pyFormula(dv, []string{x, m}, " * "), scriptNum(s.Alpha))
+		b.WriteString("print(\"R-squared change =\", fit.rsquared - reduced.rsquared)\nprint(\"F change (F, p, df) =\", fit.compare_f_test(reduced))\n")
+	case stats.MethodMediation:
+		if len(v) < 3 {
+			return false
+		}
+		fmt.Fprintf(b, "d = data[%s].dropna()\n", pyList(v[:3]))
+		b.WriteString("# Percentile bootstrap CI; the application reports the Sobel (normal theory) interval\n")
+		fmt.Fprintf(b, "print(pg.mediation_analysis(data=d, x=%s, m=%s, y=%s, alpha=%s, seed=%d))\n",
+			strconv.Quote(v[1]), strconv.Quote(v[2]), strconv.Quote(v[0]), scriptNum(s.Alpha), BootstrapSeed)
+	case stats.MethodReliability:
+		fmt.Fprintf(b, "print(pg.cronbach_alpha(data=data[%s].dropna(), ci=%s))\n", pyList(v), conf)
+	default:
+		return false
+	}
+	return true
+}
+
+func pyTwoGroups(b *strings.Builder, s scriptStep) {
+	dv, group := strconv.Quote(s.Vars[0]), strconv.Quote(s.Vars[1])
+	fmt.Fprintf(b, "d = data[[%s, %s]].dropna()\n", dv, group)
+	labels := s.groups()
+	if len(labels) != 2 {
+		fmt.Fprintf(b, "labels = sorted(d[%s].astype(str).unique())[:2]\n", group)
+	} else {
+		fmt.Fprintf(b, "labels = %s\n", pyList(labels))
+	}
+	fmt.Fprintf(b, "d = d[d[%s].astype(str).isin(labels)]\n", group)
+	fmt.Fprintf(b, "x1 = d.loc[d[%s].astype(str) == labels[0], %s]\n", group, dv)
+	fmt.Fprintf(b, "x2 = d.loc[d[%s].astype(str) == labels[1], %s]\n", group, dv)
+}
+
+// RenderSPSSSyntax menulis syntax SPSS yang mereproduksi seluruh metode analisis
+func RenderSPSSSyntax(doc Document, w io.Writer) error {
+	var b strings.Builder
+	scriptHeader(&b, "*", ".", doc, "IBM SPSS Statistics 27 or later (effect sizes in T-TEST).")
+	b.WriteString("* Column names are converted to valid SPSS names; VARIABLE LABELS keep the originals.\n\n")
+
+	file := scriptDataFile(doc)
+	b.WriteString("* ---- Data ----.\n")
+	writeSPSSLoad(&b, doc, file)
+
+	for _, s := range scriptSteps(doc) {
+		fmt.Fprintf(&b, "* ---- %d. %s ----.\n", s.Number, scriptComment(s.Method))
+		if s.Skip != "" {
+			fmt.Fprintf(&b, "* Skipped: %s.\n\n", scriptComment(s.Skip))
+			continue
+		}
+		if !writeSPSSStep(&b, s) {
+			fmt.Fprintf(&b, "* Method %s has no SPSS equivalent in this export.\n", s.ID)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("EXECUTE.\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var spssReserved = map[string]bool{
+	"ALL": true, "AND": true, "BY": true, "EQ": true, "GE": true, "GT": true, "LE": true,
+	"LT": true, "NE": true, "NOT": true, "OR": true, "TO": true, "WITH": true,
+}
+
+// spssName mengubah nama kolom menjadi nama variabel SPSS yang valid (huruf pertama, tanpa spasi, maks. 64 karakter)
+func spssName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	out := strings.TrimRight(b.String(), "._")
+	if out == "" {
+		out = "var"
+	}
+	if r := []rune(out)[0]; !unicode.IsLetter(r) {
+		out = "v" + out
+	}
+	if spssReserved[strings.ToUpper(out)] {
+		out += "_"
+	}
+	if runes := []rune(out); len(runes) > 64 {
+		out = string(runes[:64])
+	}
+	return out
+}
+
+func spssNames(names []string) string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = spssName(n)
+	}
+	return strings.Join(out, " ")
+}
+
+func spssString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func writeSPSSLoad(b *strings.Builder, doc Document, file string) {
+	var columns []string
+	if doc.DataSummary != nil {
+		columns = doc.DataSummary.ColumnNames
+	}
+	ext := strings.ToLower(filepath.Ext(file))
+	if ext == ".xlsx" {
+		fmt.Fprintf(b, "GET DATA\n  /TYPE=XLSX\n  /FILE=%s\n  /SHEET=INDEX 1\n  /CELLRANGE=FULL\n  /READNAMES=ON.\n", spssString(file))
+		if len(columns) > 0 {
+			b.WriteString("* Rename the imported variables if SPSS converted the names differently.\n")
+		}
+	} else {
+		delimiter := ","
+		if ext == ".tsv" || ext == ".txt" {
+			delimiter = `\t`
+		}
+		fmt.Fprintf(b, "* Change DELIMITERS to \";\" if the file uses semicolons.\n")
+		fmt.Fprintf(b, "GET DATA\n  /TYPE=TXT\n  /FILE=%s\n  /ENCODING='UTF8'\n  /ARRANGEMENT=DELIMITED\n  /FIRSTCASE=2\n  /DELIMITERS=\"%s\"\n  /QUALIFIER='\"'\n  /VARIABLES=\n", spssString(file), delimiter)
+		if len(columns) == 0 {
+			b.WriteString("* List every column here, e.g. score F8.2 group A50.\n")
+		}
+		for _, c := range columns {
+			format := "A255"
+			if doc.DataSummary.ColumnTypes[c] == "numeric" {
+				format = "F12.4"
+			}
+			fmt.Fprintf(b, "  %s %s\n", spssName(c), format)
+		}
+		b.WriteString(".\n")
+	}
+	b.WriteString("DATASET NAME analysis WINDOW=FRONT.\n")
+	var labels []string
+	for _, c := range columns {
+		if spssName(c) != c {
+			labels = append(labels, fmt.Sprintf("  %s %s", spssName(c), spssString(c)))
+		}
+	}
+	if len(labels) > 0 {
+		b.WriteString("VARIABLE LABELS\n" + strings.Join(labels, "\n  /") + ".\n")
+	}
+	b.WriteString("\n")
+}
+
+// spssGroups menyiapkan variabel pengelompok numerik; label non-numerik dikodekan ulang menjadi 1..k
+func spssGroups(b *strings.Builder, s scriptStep, group string, labels []string) (string, []string) {
+	name := spssName(group)
+	numeric := true
+	for _, l := range labels {
+		if _, err := strconv.ParseFloat(l, 64); err != nil {
+			numeric = false
+		}
+	}
+	if numeric {
+		return name, labels
+	}
+	coded := fmt.Sprintf("grp%d", s.Number)
+	codes := make([]string, len(labels))
+	var rules []string
+	for i, l := range labels {
+		codes[i] = strconv.Itoa(i + 1)
+		rules = append(rules, fmt.Sprintf("(%s=%d)", spssString(l), i+1))
+	}
+	fmt.Fprintf(b, "RECODE %s %s INTO %s.\n", name, strings.Join(rules, " "), coded)
+	fmt.Fprintf(b, "VALUE LABELS %s", coded)
+	for i, l := range labels {
+		fmt.Fprintf(b, " %d %s", i+1, spssString(l))
+	}
+	b.WriteString(".\n")
+	return coded, codes
+}
+
+func writeSPSSStep(b *strings.Builder, s scriptStep) bool {
+	v := s.Vars
+	ci := scriptNum(100 * (1 - s.Alpha))
+	switch s.ID {
+	case stats.MethodDescriptive:
+		numeric, categorical := s.descriptiveColumns()
+		if len(numeric) > 0 {
+			fmt.Fprintf(b, "DESCRIPTIVES VARIABLES=%s\n  /STATISTICS=MEAN STDDEV MIN MAX SKEWNESS KURTOSIS.\n", spssNames(numeric))
+			fmt.Fprintf(b, "FREQUENCIES VARIABLES=%s\n  /FORMAT=NOTABLE\n  /NTILES=4.\n", spssNames(numeric))
+		}
+		if len(categorical) > 0 {
+			fmt.Fprintf(b, "FREQUENCIES VARIABLES=%s\n  /ORDER=ANALYSIS.\n", spssNames(categorical))
+		}
+	case stats.MethodNormality:
+		fmt.Fprintf(b, "EXAMINE VARIABLES=%s\n  /PLOT NPPLOT\n  /STATISTICS NONE\n  /MISSING PAIRWISE.\n", spssNames(v))
+	case stats.MethodOneSampleTTest:
+		fmt.Fprintf(b, "T-TEST\n  /TESTVAL=%s\n  /VARIABLES=%s\n  /ES DISPLAY(TRUE)\n  /CRITERIA=CI(%s).\n", scriptNum(s.number("test_value", 0)), spssName(v[0]), scriptNum(1-s.Alpha))
+	case stats.MethodIndependentTTest, stats.MethodMannWhitney:
+		if len(v) < 2 {
+			return false
+		}
+		labels := s.groups()
+		if len(labels) != 2 {
+			return false
+		}
+		group, codes := spssGroups(b, s, v[1], labels)
+		if s.ID == stats.MethodIndependentTTest {
+			fmt.Fprintf(b, "T-TEST GROUPS=%s(%s %s)\n  /VARIABLES=%s\n  /ES DISPLAY(TRUE)\n  /CRITERIA=CI(%s).\n", group, codes[0], codes[1], spssName(v[0]), scriptNum(1-s.Alpha))
+		} else {
+			fmt.Fprintf(b, "NPAR TESTS\n  /M-W=%s BY %s(%s %s).\n", spssName(v[0]), group, codes[0], codes[1])
+		}
+	case stats.MethodPairedTTest, stats.MethodWilcoxon:
+		if len(v) < 2 {
+			return false
+		}
+		if s.ID == stats.MethodPairedTTest {
+			fmt.Fprintf(b, "T-TEST PAIRS=%s WITH %s (PAIRED)\n  /ES DISPLAY(TRUE)\n  /CRITERIA=CI(%s).\n", spssName(v[0]), spssName(v[1]), scriptNum(1-s.Alpha))
+		} else {
+			fmt.Fprintf(b, "NPAR TESTS\n  /WILCOXON=%s WITH %s (PAIRED).\n", spssName(v[0]), spssName(v[1]))
+		}
+	case stats.MethodOneWayANOVA, stats.MethodKruskalWallis:
+		if len(v) < 2 {
+			return false
+		}
+		labels := s.groups()
+		group, codes := spssName(v[1]), labels
+		if len(labels) > 0 {
+			group, codes = spssGroups(b, s, v[1], labels)
+		}
+		if s.ID == stats.MethodOneWayANOVA {
+			fmt.Fprintf(b, "ONEWAY %s BY %s\n  /STATISTICS DESCRIPTIVES HOMOGENEITY\n  /POSTHOC=BONFERRONI ALPHA(%s).\n", spssName(v[0]), group, scriptNum(s.Alpha))
+		} else {
+			low, high := spssRange(codes)
+			fmt.Fprintf(b, "NPAR TESTS\n  /K-W=%s BY %s(%s %s).\n", spssName(v[0]), group, low, high)
+		}
+	case stats.MethodChiSquare:
+		if len(v) < 2 {
+			return false
+		}
+		fmt.Fprintf(b, "CROSSTABS\n  /TABLES=%s BY %s\n  /STATISTICS=CHISQ PHI\n  /CELLS=COUNT EXPECTED.\n", spssName(v[0]), spssName(v[1]))
+	case stats.MethodPearson:
+		fmt.Fprintf(b, "CORRELATIONS\n  /VARIABLES=%s\n  /PRINT=TWOTAIL NOSIG\n  /MISSING=PAIRWISE.\n", spssNames(v))
+	case stats.MethodSpearman:
+		fmt.Fprintf(b, "NONPAR CORR\n  /VARIABLES=%s\n  /PRINT=SPEARMAN TWOTAIL NOSIG\n  /MISSING=PAIRWISE.\n", spssNames(v))
+	case stats.MethodLinearRegression:
+		dv, predictors := v[0], v[1:]
+		if d := stats.String(s.Raw, "dependent"); d != "" {
+			dv = d
+		}
+		if p := s.list("predictors"); len(p) > 0 {
+			predictors = p
+		}
+		fmt.Fprintf(b, "REGRESSION\n  /MISSING LISTWISE\n  /STATISTICS COEFF OUTS CI(%s) R ANOVA COLLIN TOL\n  /DEPENDENT %s\n  /METHOD=ENTER %s.\n", ci, spssName(dv), spssNames(predictors))
+	case stats.MethodModeration:
+		if len(v) < 3 {
+			return false
+		}
+		dv, x, m := spssName(v[0]), spssName(v[1]), spssName(v[2])
+		n := s.Number
+		fmt.Fprintf(b, "COMPUTE complete%d = NMISS(%s, %s, %s) = 0.\nFILTER BY complete%d.\n", n, dv, x, m, n)
+		xc, mc := fmt.Sprintf("x%d_c", n), fmt.Sprintf("w%d_c", n)
+		if s.bool("centered", true) {
+			fmt.Fprintf(b, "AGGREGATE\n  /OUTFILE=* MODE=ADDVARIABLES OVERWRITE=YES\n  /x%d_mean=MEAN(%s)\n  /w%d_mean=MEAN(%s).\n", n, x, n, m)
+			fmt.Fprintf(b, "COMPUTE %s = %s - x%d_mean.\nCOMPUTE %s = %s - w%d_mean.\n", xc, x, n, mc, m, n)
+		} else {
+			fmt.Fprintf(b, "COMPUTE %s = %s.\nCOMPUTE %s = %s.\n", xc, x, mc, m)
+		}
+		fmt.Fprintf(b, "COMPUTE xw%d = %s * %s.\n", n, xc, mc)
+		fmt.Fprintf(b, "REGRESSION\n  /MISSING LISTWISE\n  /STATISTICS COEFF OUTS CI(%s) R ANOVA CHANGE\n  /DEPENDENT %s\n  /METHOD=ENTER %s %s\n  /METHOD=ENTER xw%d.\n", ci, dv, xc, mc, n)
+		b.WriteString("FILTER OFF.\n")
+	case stats.MethodMediation:
+		if len(v) < 3 {
+			return false
+		}
+		dv, x, m := spssName(v[0]), spssName(v[1]), spssName(v[2])
+		b.WriteString("* Baron-Kenny paths; Sobel z = a*b / SQRT(b**2*SEa**2 + a**2*SEb**2), or use the PROCESS macro (model 4).\n")
+		fmt.Fprintf(b, "COMPUTE complete%d = NMISS(%s, %s, %s) = 0.\nFILTER BY complete%d.\n", s.Number, dv, x, m, s.Number)
+		fmt.Fprintf(b, "* Path a.\nREGRESSION\n  /STATISTICS COEFF OUTS CI(%s) R ANOVA\n  /DEPENDENT %s\n  /METHOD=ENTER %s.\n", ci, m, x)
+		fmt.Fprintf(b, "* Paths b and c'.\nREGRESSION\n  /STATISTICS COEFF OUTS CI(%s) R ANOVA\n  /DEPENDENT %s\n  /METHOD=ENTER %s %s.\n", ci, dv, x, m)
+		fmt.Fprintf(b, "* Path c (total effect).\nREGRESSION\n  /STATISTICS COEFF OUTS CI(%s) R ANOVA\n  /DEPENDENT %s\n  /METHOD=ENTER %s.\n", ci, dv, x)
+		b.WriteString("FILTER OFF.\n")
+	case stats.MethodReliability:
+		fmt.Fprintf(b, "RELIABILITY\n  /VARIABLES=%s\n  /SCALE('ALL VARIABLES') ALL\n  /MODEL=ALPHA\n  /STATISTICS=DESCRIPTIVE\n  /SUMMARY=TOTAL.\n", spssNames(v))
+	default:
+		return false
+	}
+	return true
+}
+
+// spssRange mengembalikan kode terkecil dan terbesar untuk sintaks BY var(min max)
+func spssRange(codes []string) (string, string) {
+	low, high := 0.0, 0.0
+	for i, c := range codes {
+		f, err := strconv.ParseFloat(c, 64)
+		if err != nil {
+			continue
+		}
+		if i == 0 || f < low {
+			low = f
+		}
+		if i == 0 || f > high {
+			high = f
+		}
+	}
+	return scriptNum(low), scriptNum(high)
+}
+
+// scriptComment meratakan teks menjadi satu baris agar aman dipakai sebagai komentar skrip
+func scriptComment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// RenderScripts menulis zip berisi analysis.R, analysis.py, dan analysis.sps
+func RenderScripts(doc Document, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, f := range []struct {
+		name   string
+		render func(Document, io.Writer) error
+	}{
+		{"analysis.R", RenderRScript},
+		{"analysis.py", RenderPythonScript},
+		{"analysis.sps", RenderSPSSSyntax},
+	} {
+		var b strings.Builder
+		if err := f.render(doc, &b); err != nil {
+			return err
+		}
+		if err := writeZipFile(zw, f.name, []byte(b.String())); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
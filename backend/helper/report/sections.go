@@ -0,0 +1,479 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/stats"
+)
+
+// Bagian laporan yang dapat dipilih per export, dalam urutan tampil
+const (
+	SectionCover          = "cover"
+	SectionMethodology    = "methodology"
+	SectionDescriptive    = "descriptive"
+	SectionAssumptions    = "assumptions"
+	SectionMethods        = "methods"
+	SectionFigures        = "figures"
+	SectionInterpretation = "interpretation"
+	SectionSummary        = "summary"
+	SectionLimitations    = "limitations"
+	SectionAppendix       = "appendix"
+)
+
+// methodSectionPrefix memilih satu metode saja: "method:<method_id>" atau "method:<nomor>"
+const methodSectionPrefix = "method:"
+
+// Sections adalah seluruh bagian laporan dalam urutan tampil
+var Sections = []string{
+	SectionCover, SectionMethodology, SectionDescriptive, SectionAssumptions, SectionMethods,
+	SectionFigures, SectionInterpretation, SectionSummary, SectionLimitations, SectionAppendix,
+}
+
+// sectionAliases menerima nama bagian yang umum dipakai di frontend
+var sectionAliases = map[string]string{
+	"title":             SectionCover,
+	"descriptives":      SectionDescriptive,
+	"descriptive_stats": SectionDescriptive,
+	"assumption_tests":  SectionAssumptions,
+	"results":           SectionMethods,
+	"charts":            SectionFigures,
+	"ai_interpretation": SectionInterpretation,
+	"limitation":        SectionLimitations,
+}
+
+// Selection adalah pilihan bagian laporan untuk satu export; nilai nol berarti laporan lengkap
+type Selection struct {
+	sections map[string]bool
+	methods  map[string]bool // method_id atau nomor urut; kosong berarti semua metode
+}
+
+// ParseSections membaca daftar nama bagian; daftar kosong menghasilkan laporan lengkap
+func ParseSections(names []string) (Selection, error) {
+	sel := Selection{}
+	for _, raw := range names {
+		for _, name := range strings.Split(raw, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if sel.sections == nil {
+				sel.sections = map[string]bool{}
+				sel.methods = map[string]bool{}
+			}
+			if strings.HasPrefix(name, methodSectionPrefix) {
+				key := strings.TrimSpace(strings.TrimPrefix(name, methodSectionPrefix))
+				if key == "" {
+					return Selection{}, fmt.Errorf("section %q does not name a method", name)
+				}
+				if m, ok := stats.LookupMethod(key); ok {
+					key = m.ID
+				}
+				sel.methods[key] = true
+				continue
+			}
+			if alias, ok := sectionAliases[name]; ok {
+				name = alias
+			}
+			if !isSection(name) {
+				return Selection{}, fmt.Errorf("unknown report section %q; supported: %s, method:<id>", name, strings.Join(Sections, ", "))
+			}
+			sel.sections[name] = true
+		}
+	}
+	return sel, nil
+}
+
+func isSection(name string) bool {
+	for _, s := range Sections {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Full mengecek apakah seluruh bagian laporan dipilih
+func (s Selection) Full() bool {
+	return s.sections == nil
+}
+
+// Has mengecek apakah bagian dipilih; SectionMethods juga terpenuhi oleh pilihan "method:<id>"
+func (s Selection) Has(section string) bool {
+	if s.sections == nil {
+		return true
+	}
+	if section == SectionMethods {
+		return s.sections[SectionMethods] || len(s.methods) > 0
+	}
+	return s.sections[section]
+}
+
+// HasMethod mengecek apakah hasil metode ke-number (dengan ID methodID) dipilih
+func (s Selection) HasMethod(number int, methodID string) bool {
+	if s.sections == nil || s.sections[SectionMethods] {
+		return true
+	}
+	return s.methods[methodID] || s.methods[strconv.Itoa(number)]
+}
+
+// Names mengembalikan pilihan dalam bentuk kanonik (urutan tampil) untuk disimpan sebagai template
+func (s Selection) Names() []string {
+	if s.sections == nil {
+		return append([]string(nil), Sections...)
+	}
+	names := []string{}
+	for _, section := range Sections {
+		if s.sections[section] {
+			names = append(names, section)
+		}
+	}
+	methods := make([]string, 0, len(s.methods))
+	for m := range s.methods {
+		methods = append(methods, methodSectionPrefix+m)
+	}
+	sort.Strings(methods)
+	return append(names, methods...)
+}
+
+// Apply membatasi Document pada bagian yang dipilih sehingga setiap renderer cukup
+// merender bagian yang berisi. Hasil tanpa tabel metode tetap dipertahankan jika
+// hanya interpretasi AI yang dipilih.
+func (d *Document) Apply(sel Selection) {
+	d.Sections = sel
+	if sel.Full() {
+		return
+	}
+
+	scoped := make([]Result, 0, len(d.Results))
+	for _, r := range d.Results {
+		if sel.HasMethod(r.Number, r.MethodID) {
+			scoped = append(scoped, r)
+		}
+	}
+	if len(sel.methods) == 0 {
+		// Tanpa pilihan metode tertentu, bagian metodologi/asumsi/keterbatasan mencakup seluruh hasil
+		d.scope = d.Results
+	} else {
+		d.scope = scoped
+	}
+
+	results := scoped
+	if !sel.Has(SectionMethods) {
+		results = nil
+		if sel.Has(SectionInterpretation) {
+			for _, r := range d.Results {
+				r.Tables, r.Notes = nil, nil
+				results = append(results, r)
+			}
+		}
+	}
+	if !sel.Has(SectionInterpretation) {
+		for i := range results {
+			results[i].Interpretation, results[i].EffectSize, results[i].Conclusion = "", "", ""
+		}
+	}
+	d.Results = results
+
+	if !sel.Has(SectionFigures) {
+		d.Figures = nil
+	}
+	if !sel.Has(SectionSummary) {
+		d.Summary = ""
+	}
+	if !sel.Has(SectionCover) {
+		d.Meta = nil
+	}
+}
+
+// Include mengecek apakah bagian dipilih untuk Document ini
+func (d Document) Include(section string) bool {
+	return d.Sections.Has(section)
+}
+
+// scoped mengembalikan hasil yang menjadi cakupan bagian metodologi, asumsi, dan keterbatasan
+func (d Document) scoped() []Result {
+	if d.scope != nil {
+		return d.scope
+	}
+	return d.Results
+}
+
+// MethodologyText menyusun paragraf metodologi: data, engine, tingkat signifikansi, dan peran AI
+func (d Document) MethodologyText() []string {
+	lang := d.Lang
+	var lines []string
+	if d.DataSummary != nil {
+		lines = append(lines, i18n.T(lang, "report.method_data", d.DataFile, d.DataSummary.Rows, d.DataSummary.Columns))
+	}
+	lines = append(lines, i18n.T(lang, "report.method_engine", stats.EngineVersion))
+
+	alphas := map[string]bool{}
+	var list []string
+	for _, r := range d.scoped() {
+		if a, ok := stats.Number(r.Raw, stats.KeyAlpha); ok {
+			text := strconv.FormatFloat(a, 'f', -1, 64)
+			if !alphas[text] {
+				alphas[text] = true
+				list = append(list, text)
+			}
+		}
+	}
+	if len(list) > 0 {
+		lines = append(lines, i18n.T(lang, "report.method_alpha", strings.Join(list, ", ")))
+	}
+	if d.Include(SectionInterpretation) {
+		lines = append(lines, i18n.T(lang, "report.method_ai"))
+	}
+	return lines
+}
+
+// MethodsTable menyusun tabel metode yang dijalankan beserta asumsinya
+func (d Document) MethodsTable() (Table, bool) {
+	t := Table{
+		Name:    "analysis_methods",
+		Title:   i18n.T(d.Lang, "report.analysis_methods"),
+		Columns: []string{"No", i18n.T(d.Lang, "report.method"), i18n.T(d.Lang, "report.variable"), i18n.T(d.Lang, "report.assumption")},
+	}
+	for _, r := range d.scoped() {
+		assumptions := ""
+		if m, ok := stats.LookupMethod(r.MethodID); ok {
+			assumptions = m.AssumptionsIn(d.Lang)
+		} else if m, ok := stats.LookupMethod(r.Method); ok {
+			assumptions = m.AssumptionsIn(d.Lang)
+		}
+		t.Rows = append(t.Rows, []Cell{
+			{Text: strconv.Itoa(r.Number), Value: float64(r.Number), Numeric: true},
+			{Text: r.Method},
+			{Text: strings.Join(stats.Strings(r.Raw, stats.KeyVariables), ", ")},
+			{Text: assumptions},
+		})
+	}
+	return t, len(t.Rows) > 0
+}
+
+// assumptionCheck adalah satu pemeriksaan asumsi yang diambil dari output engine
+type assumptionCheck struct {
+	method    string
+	key       string // kunci i18n nama asumsi
+	variables string
+	statistic string
+	p         float64
+	hasP      bool
+	met       bool
+}
+
+// assumptionChecks mengumpulkan uji asumsi dari RawOutput: normalitas, homogenitas varians,
+// multikolinearitas (VIF), dan frekuensi harapan chi-square
+func (d Document) assumptionChecks() []assumptionCheck {
+	var checks []assumptionCheck
+	for _, r := range d.scoped() {
+		if r.Error != "" || r.Raw == nil {
+			continue
+		}
+		raw := r.Raw
+		alpha, ok := stats.Number(raw, stats.KeyAlpha)
+		if !ok {
+			alpha = stats.DefaultAlpha
+		}
+		vars := strings.Join(stats.Strings(raw, stats.KeyVariables), ", ")
+
+		if stats.String(raw, stats.KeyMethod) == stats.MethodNormality || r.MethodID == stats.MethodNormality {
+			tests, _ := raw[stats.KeyTests].([]interface{})
+			for _, item := range tests {
+				test, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				w, _ := stats.Number(test, stats.KeyStatistic)
+				p, hasP := stats.Number(test, stats.KeyPValue)
+				checks = append(checks, assumptionCheck{
+					method:    r.Method,
+					key:       "report.assumption_normality",
+					variables: strings.Join(stats.Strings(test, stats.KeyVariables), ", "),
+					statistic: "W = " + FormatNumber(w),
+					p:         p,
+					hasP:      hasP,
+					met:       hasP && p > alpha,
+				})
+			}
+		}
+
+		if f, ok := stats.Number(raw, "levene_f"); ok {
+			p, hasP := stats.Number(raw, "levene_p")
+			checks = append(checks, assumptionCheck{
+				method:    r.Method,
+				key:       "report.assumption_homogeneity",
+				variables: vars,
+				statistic: "F = " + FormatNumber(f),
+				p:         p,
+				hasP:      hasP,
+				met:       hasP && p > alpha,
+			})
+		}
+
+		if t, ok := stats.FindTable(raw, "coefficients"); ok {
+			if i := columnIndex(t, "VIF"); i >= 0 {
+				maxVIF := math.NaN()
+				for _, row := range t.Rows {
+					if v, ok := stats.Float(cellAt(row, i)); ok && (math.IsNaN(maxVIF) || v > maxVIF) {
+						maxVIF = v
+					}
+				}
+				if !math.IsNaN(maxVIF) {
+					checks = append(checks, assumptionCheck{
+						method:    r.Method,
+						key:       "report.assumption_multicollinearity",
+						variables: vars,
+						statistic: "max VIF = " + FormatNumber(maxVIF),
+						met:       maxVIF < 10,
+					})
+				}
+			}
+		}
+
+		if t, ok := stats.FindTable(raw, "expected"); ok {
+			cells, low := 0, 0
+			for _, row := range t.Rows {
+				for i := 1; i < len(row); i++ {
+					if v, ok := stats.Float(row[i]); ok {
+						cells++
+						if v < 5 {
+							low++
+						}
+					}
+				}
+			}
+			if cells > 0 {
+				share := float64(low) / float64(cells)
+				checks = append(checks, assumptionCheck{
+					method:    r.Method,
+					key:       "report.assumption_expected",
+					variables: vars,
+					statistic: fmt.Sprintf("%d/%d < 5 (%.0f%%)", low, cells, share*100),
+					met:       share <= 0.2,
+				})
+			}
+		}
+	}
+	return checks
+}
+
+// AssumptionTable menyusun ringkasan uji asumsi seluruh metode
+func (d Document) AssumptionTable() (Table, bool) {
+	lang := d.Lang
+	t := Table{
+		Name:  "assumption_tests",
+		Title: i18n.T(lang, "report.assumptions"),
+		Columns: []string{
+			i18n.T(lang, "report.method"), i18n.T(lang, "report.assumption"), i18n.T(lang, "report.variable"),
+			i18n.T(lang, "report.statistic"), "p", i18n.T(lang, "report.assumption_met"),
+		},
+	}
+	for _, c := range d.assumptionChecks() {
+		p := Cell{}
+		if c.hasP {
+			p = FormatCell(lang, c.p, true)
+		}
+		t.Rows = append(t.Rows, []Cell{
+			{Text: c.method}, {Text: i18n.T(lang, c.key)}, {Text: c.variables}, {Text: c.statistic}, p,
+			FormatCell(lang, c.met, false),
+		})
+	}
+	return t, len(t.Rows) > 0
+}
+
+// smallSample adalah batas ukuran sampel yang dianggap kecil untuk uji parametrik
+const smallSample = 30
+
+// Limitations menyusun keterbatasan analisis dari hasil yang gagal, asumsi yang tidak terpenuhi,
+// kualitas data, dan verifikasi interpretasi AI
+func (d Document) Limitations() []string {
+	lang := d.Lang
+	var items []string
+	for _, r := range d.scoped() {
+		if r.Error != "" {
+			items = append(items, i18n.T(lang, "report.limit_failed", r.Method, r.Error))
+		}
+	}
+	for _, c := range d.assumptionChecks() {
+		if c.met {
+			continue
+		}
+		detail := c.statistic
+		if c.hasP && c.p < 0.001 {
+			detail += ", p < .001"
+		} else if c.hasP {
+			detail += ", p = " + FormatP(c.p)
+		}
+		items = append(items, i18n.T(lang, "report.limit_assumption", c.method, i18n.T(lang, c.key), c.variables, detail))
+	}
+
+	minN := 0
+	for _, r := range d.scoped() {
+		if n, ok := stats.Number(r.Raw, stats.KeyN); ok && n > 0 && (minN == 0 || int(n) < minN) {
+			minN = int(n)
+		}
+	}
+	if minN > 0 && minN < smallSample {
+		items = append(items, i18n.T(lang, "report.limit_small_sample", minN))
+	}
+
+	if d.DataSummary != nil {
+		missing, columns := 0, 0
+		for _, n := range d.DataSummary.MissingCount {
+			if n > 0 {
+				missing += n
+				columns++
+			}
+		}
+		if missing > 0 {
+			items = append(items, i18n.T(lang, "report.limit_missing", missing, columns))
+		}
+	}
+
+	causal := false
+	for _, r := range d.scoped() {
+		switch r.MethodID {
+		case stats.MethodPearson, stats.MethodSpearman, stats.MethodLinearRegression, stats.MethodModeration, stats.MethodMediation:
+			causal = true
+		}
+	}
+	if causal {
+		items = append(items, i18n.T(lang, "report.limit_causal"))
+	}
+
+	if d.Include(SectionInterpretation) {
+		for _, r := range d.scoped() {
+			if r.Verification != nil && !r.Verification.Verified && !r.Verification.Fallback {
+				items = append(items, i18n.T(lang, "report.limit_unverified", r.Method, strings.Join(r.Verification.Issues, "; ")))
+			}
+		}
+		items = append(items, i18n.T(lang, "report.limit_ai"))
+	}
+	return items
+}
+
+// AppendixFields menyusun lampiran: informasi teknis analysis dan pelaporan APA setiap metode
+func (d Document) AppendixFields() []Field {
+	lang := d.Lang
+	fields := []Field{
+		{Label: i18n.T(lang, "report.analysis_id"), Value: d.AnalysisID},
+		{Label: i18n.T(lang, "report.iteration"), Value: strconv.Itoa(d.Iteration)},
+		{Label: i18n.T(lang, "report.generated_at"), Value: d.GeneratedAt.Format("2006-01-02 15:04")},
+		{Label: i18n.T(lang, "report.engine_version"), Value: stats.EngineVersion},
+	}
+	if d.DataFile != "" {
+		fields = append(fields, Field{Label: i18n.T(lang, "report.data_file"), Value: d.DataFile})
+	}
+	for _, r := range d.scoped() {
+		if r.APA.Text != "" {
+			fields = append(fields, Field{Label: fmt.Sprintf("%s — %d. %s", i18n.T(lang, "report.apa"), r.Number, r.Method), Value: r.APA.Text})
+		}
+	}
+	return fields
+}
@@ -0,0 +1,387 @@
+package report
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/helper/stats"
+)
+
+// Indeks gaya sel pada xl/styles.xml
+const (
+	xlsxStyleNormal = 0
+	xlsxStyleBold   = 1
+	xlsxStyleTitle  = 2
+)
+
+// xlsxCell adalah satu sel worksheet; Numeric menentukan apakah ditulis sebagai angka
+type xlsxCell struct {
+	Text    string
+	Value   float64
+	Numeric bool
+	Style   int
+}
+
+// xlsxSheet adalah satu worksheet beserta barisnya
+type xlsxSheet struct {
+	Name string
+	Rows [][]xlsxCell
+}
+
+func (s *xlsxSheet) row(cells ...xlsxCell) {
+	s.Rows = append(s.Rows, cells)
+}
+
+func (s *xlsxSheet) blank() {
+	s.Rows = append(s.Rows, nil)
+}
+
+func textCell(text string, style int) xlsxCell {
+	return xlsxCell{Text: text, Style: style}
+}
+
+func numberCell(v float64) xlsxCell {
+	return xlsxCell{Value: v, Numeric: true}
+}
+
+// valueCell mengubah nilai RawOutput menjadi sel bertipe
+func valueCell(v interface{}) xlsxCell {
+	if f, ok := stats.Float(v); ok {
+		return numberCell(f)
+	}
+	switch t := v.(type) {
+	case nil:
+		return xlsxCell{}
+	case string:
+		return textCell(t, xlsxStyleNormal)
+	}
+	return textCell(fmt.Sprint(v), xlsxStyleNormal)
+}
+
+// table menulis tabel laporan: judul tebal, header tebal, dan sel numerik dengan presisi penuh
+func (s *xlsxSheet) table(t Table) {
+	if t.Title != "" {
+		s.row(textCell(t.Title, xlsxStyleBold))
+	}
+	header := make([]xlsxCell, len(t.Columns))
+	for i, c := range t.Columns {
+		header[i] = textCell(c, xlsxStyleBold)
+	}
+	s.row(header...)
+	for _, r := range t.Rows {
+		cells := make([]xlsxCell, len(r))
+		for i, c := range r {
+			if c.Numeric {
+				cells[i] = numberCell(c.Value)
+			} else {
+				cells[i] = textCell(c.Text, xlsxStyleNormal)
+			}
+		}
+		s.row(cells...)
+	}
+	s.blank()
+}
+
+// RenderXLSX menulis Document sebagai workbook Excel: ringkasan, satu sheet per hasil metode,
+// statistik deskriptif data upload, serta sheet metodologi, uji asumsi, keterbatasan, dan lampiran
+// sesuai bagian yang dipilih
+func RenderXLSX(doc Document, w io.Writer) error {
+	lang := doc.Lang
+	var sheets []*xlsxSheet
+	used := map[string]bool{}
+
+	summary := &xlsxSheet{Name: sheetName(i18n.T(lang, "report.summary"), used)}
+	sheets = append(sheets, summary)
+	summary.row(textCell(i18n.T(lang, "report.title"), xlsxStyleTitle))
+	for _, f := range doc.Meta {
+		summary.row(textCell(f.Label, xlsxStyleBold), textCell(f.Value, xlsxStyleNormal))
+	}
+	if len(doc.Meta) == 0 {
+		summary.row(textCell(doc.Title, xlsxStyleNormal))
+	}
+	summary.blank()
+	summary.row(
+		textCell("No", xlsxStyleBold),
+		textCell(i18n.T(lang, "report.method"), xlsxStyleBold),
+		textCell(i18n.T(lang, "report.status"), xlsxStyleBold),
+		textCell(i18n.T(lang, "report.statistic"), xlsxStyleBold),
+		textCell("Value", xlsxStyleBold),
+		textCell("p", xlsxStyleBold),
+		textCell(i18n.T(lang, "report.significant"), xlsxStyleBold),
+		textCell(i18n.T(lang, "report.effect_size"), xlsxStyleBold),
+		textCell("Value", xlsxStyleBold),
+		textCell(i18n.T(lang, "report.apa"), xlsxStyleBold),
+		textCell(i18n.T(lang, "report.conclusion"), xlsxStyleBold),
+	)
+	for _, r := range doc.Results {
+		status := "OK"
+		if r.Error != "" {
+			status = i18n.T(lang, "report.failed") + ": " + r.Error
+		}
+		significant := xlsxCell{}
+		if b, ok := r.Raw[stats.KeySignificant].(bool); ok {
+			significant = FormatCell(lang, b, false).xlsx()
+		}
+		summary.row(
+			numberCell(float64(r.Number)),
+			textCell(r.Method, xlsxStyleNormal),
+			textCell(status, xlsxStyleNormal),
+			valueCell(r.Raw[stats.KeyStatisticName]),
+			valueCell(r.Raw[stats.KeyStatistic]),
+			valueCell(r.Raw[stats.KeyPValue]),
+			significant,
+			valueCell(r.Raw[stats.KeyEffectSizeName]),
+			valueCell(r.Raw[stats.KeyEffectSize]),
+			textCell(r.APA.Text, xlsxStyleNormal),
+			textCell(r.Conclusion, xlsxStyleNormal),
+		)
+	}
+	if doc.Summary != "" {
+		summary.blank()
+		summary.row(textCell(i18n.T(lang, "report.summary"), xlsxStyleBold))
+		for _, line := range strings.Split(doc.Summary, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				summary.row(textCell(line, xlsxStyleNormal))
+			}
+		}
+	}
+
+	for _, r := range doc.Results {
+		sheet := &xlsxSheet{Name: sheetName(fmt.Sprintf("%d %s", r.Number, r.Method), used)}
+		sheets = append(sheets, sheet)
+		sheet.row(textCell(fmt.Sprintf("%d. %s", r.Number, r.Method), xlsxStyleTitle))
+		if r.Error != "" {
+			sheet.row(textCell(i18n.T(lang, "report.failed"), xlsxStyleBold), textCell(r.Error, xlsxStyleNormal))
+		}
+		sheet.blank()
+		for _, t := range r.Tables {
+			sheet.table(t)
+		}
+		for _, note := range r.Notes {
+			sheet.row(textCell(i18n.T(lang, "report.notes"), xlsxStyleBold), textCell(note, xlsxStyleNormal))
+		}
+		for _, f := range []Field{
+			{Label: i18n.T(lang, "report.apa"), Value: r.APA.Text},
+			{Label: i18n.T(lang, "report.interpretation"), Value: r.Interpretation},
+			{Label: i18n.T(lang, "report.effect_size"), Value: r.EffectSize},
+			{Label: i18n.T(lang, "report.conclusion"), Value: r.Conclusion},
+		} {
+			if f.Value != "" {
+				sheet.row(textCell(f.Label, xlsxStyleBold), textCell(f.Value, xlsxStyleNormal))
+			}
+		}
+	}
+
+	if t, ok := doc.DescriptiveTable(); ok && doc.Include(SectionDescriptive) {
+		sheet := &xlsxSheet{Name: sheetName(i18n.T(lang, "report.descriptives"), used)}
+		sheets = append(sheets, sheet)
+		if doc.DataFile != "" {
+			sheet.row(textCell(i18n.T(lang, "report.data_file"), xlsxStyleBold), textCell(doc.DataFile, xlsxStyleNormal))
+			sheet.blank()
+		}
+		sheet.table(t)
+	}
+
+	if doc.Include(SectionMethodology) {
+		sheet := &xlsxSheet{Name: sheetName(i18n.T(lang, "report.methodology"), used)}
+		sheets = append(sheets, sheet)
+		sheet.row(textCell(i18n.T(lang, "report.methodology"), xlsxStyleTitle))
+		for _, line := range doc.MethodologyText() {
+			sheet.row(textCell(line, xlsxStyleNormal))
+		}
+		sheet.blank()
+		if len(doc.Variables) > 0 {
+			sheet.table(doc.VariableTable())
+		}
+		if t, ok := doc.MethodsTable(); ok {
+			sheet.table(t)
+		}
+	}
+
+	if t, ok := doc.AssumptionTable(); ok && doc.Include(SectionAssumptions) {
+		sheet := &xlsxSheet{Name: sheetName(t.Title, used)}
+		sheets = append(sheets, sheet)
+		sheet.table(t)
+	}
+
+	if items := doc.Limitations(); len(items) > 0 && doc.Include(SectionLimitations) {
+		sheet := &xlsxSheet{Name: sheetName(i18n.T(lang, "report.limitations"), used)}
+		sheets = append(sheets, sheet)
+		sheet.row(textCell(i18n.T(lang, "report.limitations"), xlsxStyleTitle))
+		for _, item := range items {
+			sheet.row(textCell(item, xlsxStyleNormal))
+		}
+	}
+
+	if doc.Include(SectionAppendix) {
+		sheet := &xlsxSheet{Name: sheetName(i18n.T(lang, "report.appendix"), used)}
+		sheets = append(sheets, sheet)
+		sheet.row(textCell(i18n.T(lang, "report.appendix"), xlsxStyleTitle))
+		for _, f := range doc.AppendixFields() {
+			sheet.row(textCell(f.Label, xlsxStyleBold), textCell(f.Value, xlsxStyleNormal))
+		}
+	}
+
+	return writeWorkbook(w, sheets)
+}
+
+func (c Cell) xlsx() xlsxCell {
+	if c.Numeric {
+		return numberCell(c.Value)
+	}
+	return textCell(c.Text, xlsxStyleNormal)
+}
+
+// sheetName membuat nama sheet yang valid (maks. 31 karakter, tanpa []:*?/\) dan unik
+func sheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = "Sheet"
+	}
+	base := truncateRunes(name, 31)
+	candidate := base
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(base, 31-len(suffix)) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// columnLetter mengubah indeks kolom (0-based) menjadi huruf kolom Excel (A, B, ..., AA)
+func columnLetter(i int) string {
+	letters := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		letters = string(rune('A'+(i-1)%26)) + letters
+	}
+	return letters
+}
+
+func writeWorkbook(w io.Writer, sheets []*xlsxSheet) error {
+	zw := zip.NewWriter(w)
+
+	var types, workbook, rels strings.Builder
+	types.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	rels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	for i, s := range sheets {
+		fmt.Fprintf(&types, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(s.Name), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	types.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	rels.WriteString(`</Relationships>`)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", types.String()},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", rels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, s := range sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheetXML(s)})
+	}
+	for _, f := range files {
+		if err := writeZipFile(zw, f.name, []byte(f.content)); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func worksheetXML(s *xlsxSheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	// Lebar kolom mengikuti isi terpanjang (dibatasi agar teks interpretasi tidak terlalu lebar)
+	var widths []int
+	for _, row := range s.Rows {
+		for i, c := range row {
+			n := utf8.RuneCountInString(c.Text)
+			if c.Numeric {
+				n = 12
+			}
+			for len(widths) <= i {
+				widths = append(widths, 8)
+			}
+			if n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	if len(widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, width := range widths {
+			if width > 60 {
+				width = 60
+			}
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width+2)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for r, row := range s.Rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for i, c := range row {
+			ref := columnLetter(i) + strconv.Itoa(r+1)
+			switch {
+			case c.Numeric:
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, c.Style, strconv.FormatFloat(c.Value, 'g', -1, 64))
+			case c.Text != "":
+				fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, c.Style, xmlEscape(c.Text))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles: indeks 0 normal, 1 tebal, 2 judul (tebal 14 pt)
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="3"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="14"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
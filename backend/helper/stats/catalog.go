@@ -0,0 +1,243 @@
+package stats
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/research-data-analysis/helper/i18n"
+)
+
+// Kategori metode analisis yang dikenal engine
+const (
+	CategoryDescriptive   = "descriptive"
+	CategoryAssumption    = "assumption"
+	CategoryInferential   = "inferential"
+	CategoryNonparametric = "nonparametric"
+	CategoryCorrelation   = "correlation"
+	CategoryRegression    = "regression"
+	CategoryReliability   = "reliability"
+)
+
+// ID metode analisis yang dapat dijalankan engine
+const (
+	MethodDescriptive      = "descriptive"
+	MethodNormality        = "normality_test"
+	MethodOneSampleTTest   = "one_sample_t_test"
+	MethodIndependentTTest = "independent_t_test"
+	MethodPairedTTest      = "paired_t_test"
+	MethodOneWayANOVA      = "one_way_anova"
+	MethodMannWhitney      = "mann_whitney"
+	MethodWilcoxon         = "wilcoxon_signed_rank"
+	MethodKruskalWallis    = "kruskal_wallis"
+	MethodChiSquare        = "chi_square"
+	MethodPearson          = "pearson_correlation"
+	MethodSpearman         = "spearman_correlation"
+	MethodLinearRegression = "linear_regression"
+	MethodModeration       = "moderation"
+	MethodMediation        = "mediation"
+	MethodReliability      = "reliability"
+)
+
+// Method menjelaskan satu metode analisis yang didukung engine
+type Method struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Category    string   `json:"category"`
+	Assumptions string   `json:"assumptions"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+// catalog adalah daftar metode yang benar-benar dapat dieksekusi
+var catalog = []Method{
+	{
+		ID:          MethodDescriptive,
+		Name:        "Descriptive Statistics",
+		Category:    CategoryDescriptive,
+		Assumptions: "Tidak ada asumsi distribusi",
+		Aliases:     []string{"statistik deskriptif", "analisis deskriptif", "descriptive analysis", "deskriptif"},
+	},
+	{
+		ID:          MethodNormality,
+		Name:        "Shapiro-Wilk Normality Test",
+		Category:    CategoryAssumption,
+		Assumptions: "Data numerik dengan 3 sampai 5000 observasi",
+		Aliases:     []string{"shapiro wilk", "uji normalitas", "normality test", "shapiro-wilk test"},
+	},
+	{
+		ID:          MethodOneSampleTTest,
+		Name:        "One-Sample t-Test",
+		Category:    CategoryInferential,
+		Assumptions: "Data interval/rasio berdistribusi normal",
+		Aliases:     []string{"one sample t test", "uji t satu sampel"},
+	},
+	{
+		ID:          MethodIndependentTTest,
+		Name:        "Independent Samples t-Test",
+		Category:    CategoryInferential,
+		Assumptions: "Variabel dependen interval/rasio berdistribusi normal pada tiap kelompok, observasi independen",
+		Aliases:     []string{"independent t test", "uji t independen", "t-test", "t test", "student t test", "welch t test", "uji t dua sampel independen"},
+	},
+	{
+		ID:          MethodPairedTTest,
+		Name:        "Paired Samples t-Test",
+		Category:    CategoryInferential,
+		Assumptions: "Selisih pasangan berdistribusi normal",
+		Aliases:     []string{"paired t test", "dependent t test", "uji t berpasangan", "paired sample t test"},
+	},
+	{
+		ID:          MethodOneWayANOVA,
+		Name:        "One-Way ANOVA",
+		Category:    CategoryInferential,
+		Assumptions: "Normalitas tiap kelompok, homogenitas varians, observasi independen",
+		Aliases:     []string{"anova", "anova satu arah", "one way analysis of variance", "analysis of variance"},
+	},
+	{
+		ID:          MethodMannWhitney,
+		Name:        "Mann-Whitney U Test",
+		Category:    CategoryNonparametric,
+		Assumptions: "Variabel dependen minimal ordinal, dua kelompok independen",
+		Aliases:     []string{"mann whitney", "uji mann whitney", "wilcoxon rank sum", "mann-whitney u"},
+	},
+	{
+		ID:          MethodWilcoxon,
+		Name:        "Wilcoxon Signed-Rank Test",
+		Category:    CategoryNonparametric,
+		Assumptions: "Data berpasangan minimal ordinal",
+		Aliases:     []string{"wilcoxon", "uji wilcoxon", "wilcoxon signed rank"},
+	},
+	{
+		ID:          MethodKruskalWallis,
+		Name:        "Kruskal-Wallis H Test",
+		Category:    CategoryNonparametric,
+		Assumptions: "Variabel dependen minimal ordinal, tiga kelompok independen atau lebih",
+		Aliases:     []string{"kruskal wallis", "uji kruskal wallis", "kruskal-wallis"},
+	},
+	{
+		ID:          MethodChiSquare,
+		Name:        "Chi-Square Test of Independence",
+		Category:    CategoryNonparametric,
+		Assumptions: "Dua variabel nominal, frekuensi harapan minimal 5 pada sebagian besar sel",
+		Aliases:     []string{"chi square", "chi-square", "uji chi kuadrat", "chi kuadrat", "pearson chi square"},
+	},
+	{
+		ID:          MethodPearson,
+		Name:        "Pearson Correlation",
+		Category:    CategoryCorrelation,
+		Assumptions: "Hubungan linear, kedua variabel interval/rasio dan berdistribusi normal",
+		Aliases:     []string{"pearson", "korelasi pearson", "pearson product moment", "korelasi product moment", "correlation"},
+	},
+	{
+		ID:          MethodSpearman,
+		Name:        "Spearman Rank Correlation",
+		Category:    CategoryCorrelation,
+		Assumptions: "Hubungan monoton, variabel minimal ordinal",
+		Aliases:     []string{"spearman", "korelasi spearman", "spearman rho", "rank correlation"},
+	},
+	{
+		ID:          MethodLinearRegression,
+		Name:        "Linear Regression",
+		Category:    CategoryRegression,
+		Assumptions: "Linearitas, normalitas residual, homoskedastisitas, tidak ada multikolinearitas",
+		Aliases:     []string{"regresi linear", "regresi linier", "multiple regression", "regresi linear berganda", "simple linear regression", "regresi sederhana", "ols regression", "multiple linear regression"},
+	},
+	{
+		ID:          MethodModeration,
+		Name:        "Moderated Regression Analysis",
+		Category:    CategoryRegression,
+		Assumptions: "Asumsi regresi linear terpenuhi, variabel moderator diukur",
+		Aliases:     []string{"moderation analysis", "analisis moderasi", "mra", "moderated regression", "regresi moderasi"},
+	},
+	{
+		ID:          MethodMediation,
+		Name:        "Mediation Analysis (Sobel Test)",
+		Category:    CategoryRegression,
+		Assumptions: "Asumsi regresi linear terpenuhi, urutan kausal X → M → Y",
+		Aliases:     []string{"mediation", "analisis mediasi", "sobel test", "uji sobel", "path analysis", "analisis jalur"},
+	},
+	{
+		ID:          MethodReliability,
+		Name:        "Cronbach's Alpha Reliability",
+		Category:    CategoryReliability,
+		Assumptions: "Item mengukur konstruk yang sama, minimal dua item",
+		Aliases:     []string{"cronbach alpha", "uji reliabilitas", "reliability analysis", "cronbachs alpha", "reliabilitas"},
+	},
+}
+
+// methodIndex memetakan nama ternormalisasi ke metode
+var methodIndex = buildMethodIndex()
+
+func buildMethodIndex() map[string]Method {
+	index := make(map[string]Method)
+	for _, m := range catalog {
+		index[normalizeMethodName(m.ID)] = m
+		index[normalizeMethodName(m.Name)] = m
+		for _, alias := range m.Aliases {
+			index[normalizeMethodName(alias)] = m
+		}
+	}
+	return index
+}
+
+// normalizeMethodName menyeragamkan penulisan nama metode untuk pencocokan
+func normalizeMethodName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Methods mengembalikan semua metode yang didukung engine
+func Methods() []Method {
+	methods := make([]Method, len(catalog))
+	copy(methods, catalog)
+	return methods
+}
+
+// MethodNames mengembalikan nama tampilan metode yang didukung, terurut
+func MethodNames() []string {
+	names := make([]string, 0, len(catalog))
+	for _, m := range catalog {
+		names = append(names, m.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupMethod mencari metode berdasarkan ID, nama, atau alias
+func LookupMethod(name string) (Method, bool) {
+	key := normalizeMethodName(name)
+	if key == "" {
+		return Method{}, false
+	}
+	if m, ok := methodIndex[key]; ok {
+		return m, true
+	}
+
+	// Nama dari AI sering menambahkan keterangan, mis. "Independent Samples t-Test (Welch)"
+	if i := strings.IndexAny(name, "(["); i > 0 {
+		return LookupMethod(name[:i])
+	}
+	return Method{}, false
+}
+
+// IsValidCategory mengecek apakah kategori dikenal engine
+func IsValidCategory(category string) bool {
+	switch category {
+	case CategoryDescriptive, CategoryAssumption, CategoryInferential, CategoryNonparametric,
+		CategoryCorrelation, CategoryRegression, CategoryReliability:
+		return true
+	}
+	return false
+}
+
+// AssumptionsIn mengembalikan asumsi metode dalam bahasa lang
+func (m Method) AssumptionsIn(lang string) string {
+	if key := "assumptions." + m.ID; i18n.Has(key) {
+		return i18n.T(lang, key)
+	}
+	return m.Assumptions
+}
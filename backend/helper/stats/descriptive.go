@@ -0,0 +1,148 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Sum menjumlahkan seluruh nilai
+func Sum(x []float64) float64 {
+	total := 0.0
+	for _, v := range x {
+		total += v
+	}
+	return total
+}
+
+// Mean menghitung rata-rata aritmetika
+func Mean(x []float64) float64 {
+	if len(x) == 0 {
+		return math.NaN()
+	}
+	return Sum(x) / float64(len(x))
+}
+
+// Variance menghitung varians sampel (pembagi n-1)
+func Variance(x []float64) float64 {
+	if len(x) < 2 {
+		return math.NaN()
+	}
+	m := Mean(x)
+	ss := 0.0
+	for _, v := range x {
+		ss += (v - m) * (v - m)
+	}
+	return ss / float64(len(x)-1)
+}
+
+// StdDev menghitung simpangan baku sampel
+func StdDev(x []float64) float64 {
+	return math.Sqrt(Variance(x))
+}
+
+// Sorted mengembalikan salinan data yang sudah terurut
+func Sorted(x []float64) []float64 {
+	s := make([]float64, len(x))
+	copy(s, x)
+	sort.Float64s(s)
+	return s
+}
+
+// Quantile menghitung kuantil dengan interpolasi linear (tipe 7, sama dengan default R)
+func Quantile(x []float64, p float64) float64 {
+	if len(x) == 0 {
+		return math.NaN()
+	}
+	s := Sorted(x)
+	h := float64(len(s)-1) * p
+	lo := math.Floor(h)
+	hi := math.Ceil(h)
+	return s[int(lo)] + (h-lo)*(s[int(hi)]-s[int(lo)])
+}
+
+// Median menghitung nilai tengah
+func Median(x []float64) float64 {
+	return Quantile(x, 0.5)
+}
+
+// MinMax mengembalikan nilai minimum dan maksimum
+func MinMax(x []float64) (float64, float64) {
+	if len(x) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	lo, hi := x[0], x[0]
+	for _, v := range x[1:] {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	return lo, hi
+}
+
+// Skewness menghitung kemencengan sampel (adjusted Fisher-Pearson, sama dengan SPSS)
+func Skewness(x []float64) float64 {
+	n := float64(len(x))
+	if n < 3 {
+		return math.NaN()
+	}
+	m, s := Mean(x), StdDev(x)
+	if s == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range x {
+		total += math.Pow((v-m)/s, 3)
+	}
+	return n / ((n - 1) * (n - 2)) * total
+}
+
+// Kurtosis menghitung excess kurtosis sampel (sama dengan SPSS)
+func Kurtosis(x []float64) float64 {
+	n := float64(len(x))
+	if n < 4 {
+		return math.NaN()
+	}
+	m, s := Mean(x), StdDev(x)
+	if s == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range x {
+		total += math.Pow((v-m)/s, 4)
+	}
+	return n*(n+1)/((n-1)*(n-2)*(n-3))*total - 3*(n-1)*(n-1)/((n-2)*(n-3))
+}
+
+// Describe menghitung ringkasan statistik deskriptif satu variabel numerik.
+// Nilai yang tidak terdefinisi (mis. skewness untuk n < 3) tidak dimasukkan.
+func Describe(x []float64) map[string]interface{} {
+	lo, hi := MinMax(x)
+	result := map[string]interface{}{"n": len(x)}
+	putFinite(result, "mean", Mean(x))
+	putFinite(result, "std", StdDev(x))
+	putFinite(result, "min", lo)
+	putFinite(result, "q1", Quantile(x, 0.25))
+	putFinite(result, "median", Median(x))
+	putFinite(result, "q3", Quantile(x, 0.75))
+	putFinite(result, "max", hi)
+	putFinite(result, "skewness", Skewness(x))
+	putFinite(result, "kurtosis", Kurtosis(x))
+	return result
+}
+
+// putFinite menyimpan nilai yang sudah dibulatkan hanya jika nilainya terdefinisi,
+// karena NaN dan Inf tidak dapat di-encode ke JSON
+func putFinite(m map[string]interface{}, key string, v float64) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+	m[key] = Round(v, 4)
+}
+
+// Round membulatkan ke sejumlah digit desimal
+func Round(v float64, digits int) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	p := math.Pow(10, float64(digits))
+	return math.Round(v*p) / p
+}
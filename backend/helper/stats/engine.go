@@ -0,0 +1,341 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/research-data-analysis/model"
+)
+
+// DefaultAlpha adalah tingkat signifikansi default
+const DefaultAlpha = 0.05
+
+// EngineVersion adalah versi engine statistik; dinaikkan setiap kali perubahan dapat menggeser hasil numerik
+const EngineVersion = "1.0.0"
+
+// Data adalah sumber data tabular yang dibutuhkan engine (diimplementasikan oleh dataset.Dataset)
+type Data interface {
+	ColumnNames() []string
+	CompleteCases(names ...string) ([][]float64, error)
+	Groups(valueColumn, groupColumn string) ([]string, [][]float64, error)
+	CategoryPairs(a, b string) ([]string, []string, error)
+}
+
+// Request menjelaskan satu metode yang akan dijalankan engine beserta konteks variabelnya
+type Request struct {
+	Method    string
+	Variables model.Variables
+	Summary   model.DataSummary
+	Options   map[string]interface{}
+}
+
+// runner menjalankan satu metode dan mengisi output
+type runner func(data Data, req Request, out *output) error
+
+var runners = map[string]runner{
+	MethodDescriptive:      runDescriptive,
+	MethodNormality:        runNormality,
+	MethodOneSampleTTest:   runOneSampleTTest,
+	MethodIndependentTTest: runIndependentTTest,
+	MethodPairedTTest:      runPairedTTest,
+	MethodOneWayANOVA:      runANOVA,
+	MethodMannWhitney:      runMannWhitney,
+	MethodWilcoxon:         runWilcoxon,
+	MethodKruskalWallis:    runKruskalWallis,
+	MethodChiSquare:        runChiSquare,
+	MethodPearson:          runCorrelation,
+	MethodSpearman:         runCorrelation,
+	MethodLinearRegression: runRegression,
+	MethodModeration:       runModeration,
+	MethodMediation:        runMediation,
+	MethodReliability:      runReliability,
+}
+
+// CanExecute mengecek apakah engine memiliki implementasi untuk metode
+func CanExecute(methodID string) bool {
+	_, ok := runners[methodID]
+	return ok
+}
+
+// output membangun RawOutput secara terstruktur
+type output struct {
+	raw    map[string]interface{}
+	tables []*Table
+	notes  []string
+}
+
+func (o *output) set(key string, value interface{}) {
+	if f, ok := value.(float64); ok {
+		o.raw[key] = num(f)
+		return
+	}
+	o.raw[key] = value
+}
+
+func (o *output) table(name, title string, columns ...string) *Table {
+	t := newTable(name, title, columns...)
+	o.tables = append(o.tables, t)
+	return t
+}
+
+func (o *output) note(format string, args ...interface{}) {
+	o.notes = append(o.notes, fmt.Sprintf(format, args...))
+}
+
+// test mengisi statistik uji utama beserta keputusan signifikansi
+func (o *output) test(statName string, statistic, p, alpha float64) {
+	o.set(KeyStatisticName, statName)
+	o.set(KeyStatistic, statistic)
+	o.set(KeyPValue, p)
+	if !math.IsNaN(p) {
+		o.set(KeySignificant, p < alpha)
+	}
+}
+
+// effect mengisi ukuran efek beserta label besarannya
+func (o *output) effect(name string, value float64) {
+	o.set(KeyEffectSizeName, name)
+	o.set(KeyEffectSize, value)
+	if label := EffectSizeLabel(name, value); label != "" {
+		o.set(KeyEffectSizeLabel, label)
+	}
+}
+
+// Run menjalankan metode pada data dan mengembalikan RawOutput terstruktur
+func Run(data Data, req Request) (map[string]interface{}, error) {
+	method, ok := LookupMethod(req.Method)
+	if !ok {
+		return nil, fmt.Errorf("method %q is not supported by the analysis engine", req.Method)
+	}
+	run, ok := runners[method.ID]
+	if !ok {
+		return nil, fmt.Errorf("method %q has no engine implementation", method.Name)
+	}
+
+	alpha := optFloat(req.Options, "alpha", DefaultAlpha)
+	if alpha <= 0 || alpha >= 1 {
+		return nil, fmt.Errorf("alpha must be between 0 and 1, got %v", alpha)
+	}
+	if req.Options == nil {
+		req.Options = map[string]interface{}{}
+	}
+	req.Options["alpha"] = alpha
+	req.Method = method.ID
+
+	out := &output{raw: map[string]interface{}{
+		KeyMethod:     method.ID,
+		KeyMethodName: method.Name,
+		KeyAlpha:      alpha,
+	}}
+	if err := run(data, req, out); err != nil {
+		return nil, err
+	}
+
+	tables := make([]interface{}, len(out.tables))
+	for i, t := range out.tables {
+		tables[i] = t.toMap()
+	}
+	out.raw[KeyTables] = tables
+	if len(out.notes) > 0 {
+		out.raw[KeyNotes] = out.notes
+	}
+	return out.raw, nil
+}
+
+// EffectSizeLabel memberi label besaran efek berdasarkan konvensi Cohen
+func EffectSizeLabel(name string, value float64) string {
+	v := math.Abs(value)
+	var small, medium, large float64
+	switch name {
+	case "cohens_d":
+		small, medium, large = 0.2, 0.5, 0.8
+	case "r", "rho", "cramers_v":
+		small, medium, large = 0.1, 0.3, 0.5
+	case "eta_squared", "epsilon_squared":
+		small, medium, large = 0.01, 0.06, 0.14
+	case "r_squared", "r_squared_change":
+		small, medium, large = 0.02, 0.13, 0.26
+	case "cronbach_alpha":
+		switch {
+		case v >= 0.9:
+			return "excellent"
+		case v >= 0.8:
+			return "good"
+		case v >= 0.7:
+			return "acceptable"
+		case v >= 0.6:
+			return "questionable"
+		}
+		return "poor"
+	default:
+		return ""
+	}
+	switch {
+	case v >= large:
+		return "large"
+	case v >= medium:
+		return "medium"
+	case v >= small:
+		return "small"
+	}
+	return "negligible"
+}
+
+// --- resolusi variabel ---
+
+func (req Request) alpha() float64 {
+	return optFloat(req.Options, "alpha", DefaultAlpha)
+}
+
+func (req Request) profile(name string) VariableProfile {
+	return ProfileVariable(name, "", req.Variables, req.Summary)
+}
+
+// column mencocokkan nama variabel dengan kolom data
+func (req Request) column(name string) (string, error) {
+	if column := matchColumn(name, req.Summary.ColumnNames); column != "" {
+		return column, nil
+	}
+	return "", fmt.Errorf("variable %q not found in the data", name)
+}
+
+// dependent menentukan variabel dependen: opsi "dependent" atau variabel dependen pertama proyek
+func (req Request) dependent() (string, error) {
+	if name := optString(req.Options, "dependent"); name != "" {
+		return req.column(name)
+	}
+	for _, name := range req.Variables.Dependent {
+		if column, err := req.column(name); err == nil {
+			return column, nil
+		}
+	}
+	return "", errors.New("no dependent variable: set project dependent variables or the \"dependent\" option")
+}
+
+// grouping menentukan variabel pengelompok: opsi "group" atau variabel independen/kontrol pertama yang memenuhi jumlah level
+func (req Request) grouping(accept func(levels int) bool) (string, error) {
+	if name := optString(req.Options, "group"); name != "" {
+		return req.column(name)
+	}
+	candidates := append(append([]string{}, req.Variables.Independent...), req.Variables.Control...)
+	for _, name := range candidates {
+		p := req.profile(name)
+		if p.Available && (p.Scale == ScaleNominal || p.Scale == ScaleOrdinal) && accept(p.Levels) {
+			return p.Name, nil
+		}
+	}
+	return "", errors.New("no suitable grouping variable: set the \"group\" option or declare a categorical independent variable")
+}
+
+// variableList mengambil daftar variabel dari opsi "variables" atau fallback
+func (req Request) variableList(fallback []string) ([]string, error) {
+	names := optStrings(req.Options, "variables")
+	if len(names) == 0 {
+		names = fallback
+	}
+	var columns []string
+	for _, name := range names {
+		column, err := req.column(name)
+		if err != nil {
+			return nil, err
+		}
+		if !containsString(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+// roleVariables mengumpulkan variabel dependen, independen, moderasi, dan mediasi proyek yang ada di data
+func (req Request) roleVariables() []string {
+	var names []string
+	for _, group := range [][]string{req.Variables.Dependent, req.Variables.Independent, req.Variables.Mediating, req.Variables.Moderating} {
+		for _, name := range group {
+			if column := matchColumn(name, req.Summary.ColumnNames); column != "" && !containsString(names, column) {
+				names = append(names, column)
+			}
+		}
+	}
+	return names
+}
+
+// columnsWithScale mengambil kolom dengan skala tertentu
+func (req Request) columnsWithScale(candidates []string, accept func(VariableProfile) bool) []string {
+	var columns []string
+	for _, name := range candidates {
+		if p := req.profile(name); p.Available && accept(p) {
+			columns = append(columns, p.Name)
+		}
+	}
+	return columns
+}
+
+// pair menentukan dua variabel berpasangan: opsi "variables", pasangan pre/post, atau dua variabel dependen pertama
+func (req Request) pair() (string, string, error) {
+	names := optStrings(req.Options, "variables")
+	if len(names) == 0 {
+		if pairs := PrePostPairs(req.Summary.ColumnNames); len(pairs) > 0 {
+			names = pairs[0][:]
+		} else {
+			names = req.Variables.Dependent
+		}
+	}
+	if len(names) < 2 {
+		return "", "", errors.New("paired test needs two variables: set the \"variables\" option, e.g. [\"pretest\", \"posttest\"]")
+	}
+	a, err := req.column(names[0])
+	if err != nil {
+		return "", "", err
+	}
+	b, err := req.column(names[1])
+	if err != nil {
+		return "", "", err
+	}
+	return a, b, nil
+}
+
+// --- opsi ---
+
+func optString(opts map[string]interface{}, key string) string {
+	s, _ := opts[key].(string)
+	return strings.TrimSpace(s)
+}
+
+func optStrings(opts map[string]interface{}, key string) []string {
+	switch v := Normalize(opts[key]).(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		var out []string
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+		return out
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func optFloat(opts map[string]interface{}, key string, def float64) float64 {
+	if f, ok := Float(opts[key]); ok {
+		return f
+	}
+	return def
+}
+
+func optBool(opts map[string]interface{}, key string) (bool, bool) {
+	b, ok := opts[key].(bool)
+	return b, ok
+}
@@ -0,0 +1,910 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// runDescriptive menghitung statistik deskriptif untuk variabel numerik dan frekuensi untuk variabel kategorik
+func runDescriptive(data Data, req Request, out *output) error {
+	columns, err := req.variableList(req.Summary.ColumnNames)
+	if err != nil {
+		return err
+	}
+
+	desc := out.table("descriptives", "Descriptive Statistics", "Variable", "N", "Missing", "Mean", "SD", "Min", "Q1", "Median", "Q3", "Max", "Skewness", "Kurtosis")
+	freq := out.table("frequencies", "Frequency Distribution", "Variable", "Category", "Frequency", "Percent")
+	n := 0
+	for _, column := range columns {
+		p := req.profile(column)
+		missing := float64(req.Summary.MissingCount[column])
+		if p.IsMetric() || p.Scale == ScaleOrdinal {
+			if values, err := data.CompleteCases(column); err == nil {
+				x := values[0]
+				lo, hi := MinMax(x)
+				desc.add(column, float64(len(x)), missing, Mean(x), StdDev(x), lo, Quantile(x, 0.25), Median(x), Quantile(x, 0.75), hi, Skewness(x), Kurtosis(x))
+				if len(x) > n {
+					n = len(x)
+				}
+				if p.IsMetric() {
+					continue
+				}
+			}
+		}
+
+		counts := req.Summary.Frequencies[column]
+		total := 0
+		for _, c := range counts {
+			total += c
+		}
+		for _, level := range sortedKeys(counts) {
+			freq.add(column, level, float64(counts[level]), 100*float64(counts[level])/float64(total))
+		}
+	}
+	out.set(KeyVariables, columns)
+	out.set(KeyN, float64(req.Summary.Rows))
+	return nil
+}
+
+// runNormality menjalankan uji Shapiro-Wilk untuk setiap variabel interval/rasio
+func runNormality(data Data, req Request, out *output) error {
+	fallback := req.columnsWithScale(req.roleVariables(), VariableProfile.IsMetric)
+	if len(fallback) == 0 {
+		fallback = req.columnsWithScale(req.Summary.ColumnNames, VariableProfile.IsMetric)
+	}
+	columns, err := req.variableList(fallback)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return errors.New("no interval/ratio variables to test for normality")
+	}
+
+	alpha := req.alpha()
+	t := out.table("normality", "Shapiro-Wilk Normality Test", "Variable", "N", "W", "p", "Normal")
+	var tests []interface{}
+	for _, column := range columns {
+		values, err := data.CompleteCases(column)
+		if err != nil {
+			return err
+		}
+		sw, err := ShapiroWilk(values[0])
+		if err != nil {
+			out.note("%s: %v", column, err)
+			continue
+		}
+		normal := sw.P > alpha
+		t.add(column, float64(sw.N), sw.W, sw.P, normal)
+		tests = append(tests, map[string]interface{}{
+			KeyVariables: []string{column}, KeyN: float64(sw.N),
+			KeyStatisticName: "W", KeyStatistic: sw.W, KeyPValue: sw.P, KeySignificant: !normal,
+		})
+		if len(columns) == 1 {
+			out.test("W", sw.W, sw.P, alpha)
+			out.set(KeyN, float64(sw.N))
+		}
+	}
+	if len(tests) == 0 {
+		return errors.New("normality test could not be computed for any variable")
+	}
+	out.set(KeyVariables, columns)
+	out.set(KeyTests, tests)
+	return nil
+}
+
+// runOneSampleTTest membandingkan rata-rata dengan nilai uji (opsi "test_value", default 0)
+func runOneSampleTTest(data Data, req Request, out *output) error {
+	column, err := req.dependent()
+	if name := optString(req.Options, "variable"); name != "" {
+		column, err = req.column(name)
+	}
+	if err != nil {
+		return err
+	}
+	values, err := data.CompleteCases(column)
+	if err != nil {
+		return err
+	}
+	mu := optFloat(req.Options, "test_value", 0)
+	res, err := OneSampleTTest(values[0], mu, req.alpha())
+	if err != nil {
+		return err
+	}
+
+	x := values[0]
+	out.set(KeyVariables, []string{column})
+	out.set(KeyN, float64(len(x)))
+	out.set("test_value", mu)
+	out.set("mean", Mean(x))
+	out.set("sd", StdDev(x))
+	out.test("t", res.T, res.P, req.alpha())
+	out.set(KeyDF, res.DF)
+	out.set("mean_difference", res.MeanDifference)
+	out.set("ci_lower", res.CILower)
+	out.set("ci_upper", res.CIUpper)
+	out.effect("cohens_d", res.CohensD)
+
+	out.table("one_sample_statistics", "One-Sample Statistics", "Variable", "N", "Mean", "SD", "SE").
+		add(column, float64(len(x)), Mean(x), StdDev(x), StdDev(x)/math.Sqrt(float64(len(x))))
+	out.table("one_sample_test", "One-Sample t-Test", "Test value", "t", "df", "p", "Mean difference", "CI lower", "CI upper", "Cohen's d").
+		add(mu, res.T, res.DF, res.P, res.MeanDifference, res.CILower, res.CIUpper, res.CohensD)
+	return nil
+}
+
+// twoGroups mengambil variabel dependen dan pengelompok dengan tepat dua kelompok
+func twoGroups(data Data, req Request) (string, string, []string, [][]float64, error) {
+	dv, err := req.dependent()
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	group, err := req.grouping(func(levels int) bool { return levels == 2 })
+	if err != nil {
+		// DV dikotomis dengan IV metrik: bandingkan IV antar kategori DV
+		if p := req.profile(dv); p.Scale == ScaleNominal && p.Levels == 2 {
+			for _, name := range req.Variables.Independent {
+				if iv := req.profile(name); iv.Available && iv.IsMetric() {
+					dv, group, err = iv.Name, dv, nil
+					break
+				}
+			}
+		}
+		if err != nil {
+			return "", "", nil, nil, err
+		}
+	}
+
+	labels, groups, err := data.Groups(dv, group)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+	if selected := optStrings(req.Options, "groups"); len(selected) == 2 {
+		labels, groups = pickGroups(labels, groups, selected)
+	}
+	if len(groups) != 2 {
+		return "", "", nil, nil, fmt.Errorf("%s has %d groups; this test needs exactly 2 (use the \"groups\" option to pick two)", group, len(groups))
+	}
+	return dv, group, labels, groups, nil
+}
+
+func pickGroups(labels []string, groups [][]float64, selected []string) ([]string, [][]float64) {
+	var outLabels []string
+	var outGroups [][]float64
+	for _, want := range selected {
+		for i, label := range labels {
+			if label == want {
+				outLabels = append(outLabels, label)
+				outGroups = append(outGroups, groups[i])
+			}
+		}
+	}
+	return outLabels, outGroups
+}
+
+func groupTable(out *output, labels []string, groups [][]float64) {
+	t := out.table("group_statistics", "Group Statistics", "Group", "N", "Mean", "SD", "SE")
+	for i, g := range groups {
+		t.add(labels[i], float64(len(g)), Mean(g), StdDev(g), StdDev(g)/math.Sqrt(float64(len(g))))
+	}
+}
+
+// runIndependentTTest membandingkan rata-rata dua kelompok independen dengan uji Levene untuk memilih varian Student/Welch
+func runIndependentTTest(data Data, req Request, out *output) error {
+	dv, group, labels, groups, err := twoGroups(data, req)
+	if err != nil {
+		return err
+	}
+	alpha := req.alpha()
+
+	levene, err := LeveneTest(groups)
+	if err != nil {
+		return err
+	}
+	equalVar, explicit := optBool(req.Options, "equal_var")
+	if !explicit {
+		equalVar = levene.P > alpha
+	}
+
+	student, err := IndependentTTest(groups[0], groups[1], true, alpha)
+	if err != nil {
+		return err
+	}
+	welch, err := IndependentTTest(groups[0], groups[1], false, alpha)
+	if err != nil {
+		return err
+	}
+	res := welch
+	if equalVar {
+		res = student
+	}
+
+	out.set(KeyVariables, []string{dv, group})
+	out.set("groups", labels)
+	out.set(KeyN, float64(len(groups[0])+len(groups[1])))
+	out.test("t", res.T, res.P, alpha)
+	out.set(KeyDF, res.DF)
+	out.set("mean_difference", res.MeanDifference)
+	out.set("ci_lower", res.CILower)
+	out.set("ci_upper", res.CIUpper)
+	out.set("equal_variances_assumed", equalVar)
+	out.set("levene_f", levene.F)
+	out.set("levene_p", levene.P)
+	out.effect("cohens_d", res.CohensD)
+
+	groupTable(out, labels, groups)
+	t := out.table("t_test", "Independent Samples t-Test", "Variances", "Levene F", "Levene p", "t", "df", "p", "Mean difference", "CI lower", "CI upper")
+	t.add("Equal variances assumed", levene.F, levene.P, student.T, student.DF, student.P, student.MeanDifference, student.CILower, student.CIUpper)
+	t.add("Equal variances not assumed", nil, nil, welch.T, welch.DF, welch.P, welch.MeanDifference, welch.CILower, welch.CIUpper)
+	if !equalVar {
+		out.note("Levene test p = %.4f < %.2f; Welch correction is used", levene.P, alpha)
+	}
+	return nil
+}
+
+// runPairedTTest membandingkan rata-rata dua pengukuran berpasangan
+func runPairedTTest(data Data, req Request, out *output) error {
+	a, b, err := req.pair()
+	if err != nil {
+		return err
+	}
+	values, err := data.CompleteCases(a, b)
+	if err != nil {
+		return err
+	}
+	res, err := PairedTTest(values[0], values[1], req.alpha())
+	if err != nil {
+		return err
+	}
+
+	n := float64(len(values[0]))
+	out.set(KeyVariables, []string{a, b})
+	out.set(KeyN, n)
+	out.test("t", res.T, res.P, req.alpha())
+	out.set(KeyDF, res.DF)
+	out.set("mean_difference", res.MeanDifference)
+	out.set("ci_lower", res.CILower)
+	out.set("ci_upper", res.CIUpper)
+	out.effect("cohens_d", res.CohensD)
+	if corr, err := Pearson(values[0], values[1]); err == nil {
+		out.set("correlation", corr.R)
+	}
+
+	t := out.table("paired_statistics", "Paired Samples Statistics", "Variable", "N", "Mean", "SD", "SE")
+	for i, name := range []string{a, b} {
+		t.add(name, n, Mean(values[i]), StdDev(values[i]), StdDev(values[i])/math.Sqrt(n))
+	}
+	out.table("paired_test", "Paired Samples t-Test", "Pair", "Mean difference", "t", "df", "p", "CI lower", "CI upper", "Cohen's d").
+		add(a+" - "+b, res.MeanDifference, res.T, res.DF, res.P, res.CILower, res.CIUpper, res.CohensD)
+	return nil
+}
+
+// runANOVA menjalankan ANOVA satu arah dengan uji Levene dan post-hoc Bonferroni
+func runANOVA(data Data, req Request, out *output) error {
+	dv, err := req.dependent()
+	if err != nil {
+		return err
+	}
+	group, err := req.grouping(func(levels int) bool { return levels >= 3 })
+	if err != nil {
+		if group, err = req.grouping(func(levels int) bool { return levels >= 2 }); err != nil {
+			return err
+		}
+	}
+	labels, groups, err := data.Groups(dv, group)
+	if err != nil {
+		return err
+	}
+	res, err := OneWayANOVA(groups)
+	if err != nil {
+		return err
+	}
+	alpha := req.alpha()
+
+	total := 0
+	for _, g := range groups {
+		total += len(g)
+	}
+	out.set(KeyVariables, []string{dv, group})
+	out.set("groups", labels)
+	out.set(KeyN, float64(total))
+	out.test("F", res.F, res.P, alpha)
+	out.set(KeyDF1, res.DFBetween)
+	out.set(KeyDF2, res.DFWithin)
+	out.effect("eta_squared", res.EtaSq)
+	if levene, err := LeveneTest(groups); err == nil {
+		out.set("levene_f", levene.F)
+		out.set("levene_p", levene.P)
+		if levene.P < alpha {
+			out.note("Levene test p = %.4f < %.2f; homogeneity of variances is violated, consider Kruskal-Wallis", levene.P, alpha)
+		}
+	}
+
+	groupTable(out, labels, groups)
+	t := out.table("anova", "One-Way ANOVA", "Source", "Sum of Squares", "df", "Mean Square", "F", "p")
+	t.add("Between Groups", res.SSBetween, res.DFBetween, res.MSBetween, res.F, res.P)
+	t.add("Within Groups", res.SSWithin, res.DFWithin, res.MSWithin, nil, nil)
+	t.add("Total", res.SSBetween+res.SSWithin, res.DFBetween+res.DFWithin, nil, nil, nil)
+
+	// Post-hoc Bonferroni dengan MS within gabungan
+	comparisons := float64(len(groups) * (len(groups) - 1) / 2)
+	post := out.table("post_hoc", "Post-hoc Comparisons (Bonferroni)", "Group I", "Group J", "Mean difference", "SE", "t", "p")
+	for i := 0; i < len(groups); i++ {
+		for j := i + 1; j < len(groups); j++ {
+			diff := Mean(groups[i]) - Mean(groups[j])
+			se := math.Sqrt(res.MSWithin * (1/float64(len(groups[i])) + 1/float64(len(groups[j]))))
+			tv := diff / se
+			p := math.Min(1, TwoTailedTP(tv, res.DFWithin)*comparisons)
+			post.add(labels[i], labels[j], diff, se, tv, p)
+		}
+	}
+	return nil
+}
+
+// runMannWhitney membandingkan distribusi peringkat dua kelompok independen
+func runMannWhitney(data Data, req Request, out *output) error {
+	dv, group, labels, groups, err := twoGroups(data, req)
+	if err != nil {
+		return err
+	}
+	res, err := MannWhitneyU(groups[0], groups[1])
+	if err != nil {
+		return err
+	}
+
+	out.set(KeyVariables, []string{dv, group})
+	out.set("groups", labels)
+	out.set(KeyN, float64(len(groups[0])+len(groups[1])))
+	out.test("U", res.Statistic, res.P, req.alpha())
+	out.set("z", res.Z)
+	out.effect("r", res.EffectR)
+
+	t := out.table("ranks", "Ranks", "Group", "N", "Mean rank", "Median")
+	for i, g := range groups {
+		t.add(labels[i], float64(len(g)), res.MeanRanks[i], Median(g))
+	}
+	return nil
+}
+
+// runWilcoxon membandingkan dua pengukuran berpasangan berbasis peringkat
+func runWilcoxon(data Data, req Request, out *output) error {
+	a, b, err := req.pair()
+	if err != nil {
+		return err
+	}
+	values, err := data.CompleteCases(a, b)
+	if err != nil {
+		return err
+	}
+	res, err := WilcoxonSignedRank(values[1], values[0])
+	if err != nil {
+		return err
+	}
+
+	out.set(KeyVariables, []string{a, b})
+	out.set(KeyN, float64(len(values[0])))
+	out.test("W", res.Statistic, res.P, req.alpha())
+	out.set("z", res.Z)
+	out.effect("r", res.EffectR)
+
+	t := out.table("paired_medians", "Paired Samples Medians", "Variable", "N", "Median", "Mean")
+	for i, name := range []string{a, b} {
+		t.add(name, float64(len(values[i])), Median(values[i]), Mean(values[i]))
+	}
+	return nil
+}
+
+// runKruskalWallis membandingkan distribusi peringkat beberapa kelompok independen
+func runKruskalWallis(data Data, req Request, out *output) error {
+	dv, err := req.dependent()
+	if err != nil {
+		return err
+	}
+	group, err := req.grouping(func(levels int) bool { return levels >= 2 })
+	if err != nil {
+		return err
+	}
+	labels, groups, err := data.Groups(dv, group)
+	if err != nil {
+		return err
+	}
+	res, err := KruskalWallis(groups)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, g := range groups {
+		total += len(g)
+	}
+	out.set(KeyVariables, []string{dv, group})
+	out.set("groups", labels)
+	out.set(KeyN, float64(total))
+	out.test("H", res.Statistic, res.P, req.alpha())
+	out.set(KeyDF, res.DF)
+	out.effect("epsilon_squared", res.EffectR)
+
+	t := out.table("ranks", "Ranks", "Group", "N", "Mean rank", "Median")
+	for i, g := range groups {
+		t.add(labels[i], float64(len(g)), res.MeanRanks[i], Median(g))
+	}
+	return nil
+}
+
+// runChiSquare menguji independensi dua variabel kategorik
+func runChiSquare(data Data, req Request, out *output) error {
+	names := optStrings(req.Options, "variables")
+	if len(names) < 2 {
+		nominal := req.columnsWithScale(req.roleVariables(), VariableProfile.IsGrouping)
+		if len(nominal) < 2 {
+			nominal = req.columnsWithScale(req.Summary.ColumnNames, VariableProfile.IsGrouping)
+		}
+		names = nominal
+	}
+	if len(names) < 2 {
+		return errors.New("chi-square needs two categorical variables: set the \"variables\" option")
+	}
+	a, err := req.column(names[0])
+	if err != nil {
+		return err
+	}
+	b, err := req.column(names[1])
+	if err != nil {
+		return err
+	}
+
+	x, y, err := data.CategoryPairs(a, b)
+	if err != nil {
+		return err
+	}
+	res, err := ChiSquareIndependence(x, y)
+	if err != nil {
+		return err
+	}
+
+	out.set(KeyVariables, []string{a, b})
+	out.set(KeyN, float64(res.N))
+	out.test("chi2", res.ChiSq, res.P, req.alpha())
+	out.set(KeyDF, res.DF)
+	out.effect("cramers_v", res.CramersV)
+	out.set("low_expected_percent", res.LowExpectedPct)
+	if res.LowExpectedPct > 20 {
+		out.note("%.1f%% of cells have expected count below 5; consider Fisher's exact test or merging categories", res.LowExpectedPct)
+	}
+
+	columns := append([]string{a + " \\ " + b}, res.ColLabels...)
+	observed := out.table("crosstab", "Crosstabulation (Observed)", columns...)
+	expected := out.table("expected", "Expected Counts", columns...)
+	for i, label := range res.RowLabels {
+		obsRow := []interface{}{label}
+		expRow := []interface{}{label}
+		for j := range res.ColLabels {
+			obsRow = append(obsRow, res.Observed[i][j])
+			expRow = append(expRow, res.Expected[i][j])
+		}
+		observed.add(obsRow...)
+		expected.add(expRow...)
+	}
+	return nil
+}
+
+// runCorrelation menghitung korelasi Pearson atau Spearman antar variabel (pairwise deletion)
+func runCorrelation(data Data, req Request, out *output) error {
+	spearman := req.Method == MethodSpearman
+	accept := VariableProfile.IsMetric
+	statName, effectName, corr := "r", "r", Pearson
+	if spearman {
+		accept = func(p VariableProfile) bool { return p.IsMetric() || p.Scale == ScaleOrdinal }
+		statName, effectName, corr = "rho", "rho", Spearman
+	}
+
+	fallback := req.columnsWithScale(req.roleVariables(), accept)
+	if len(fallback) < 2 {
+		fallback = req.columnsWithScale(req.Summary.ColumnNames, accept)
+	}
+	columns, err := req.variableList(fallback)
+	if err != nil {
+		return err
+	}
+	if len(columns) < 2 {
+		return errors.New("correlation needs at least two numeric variables")
+	}
+
+	alpha := req.alpha()
+	header := append([]string{"Variable"}, columns...)
+	matrix := out.table("correlation_matrix", "Correlation Matrix", header...)
+	pMatrix := out.table("p_values", "Significance (2-tailed)", header...)
+	nMatrix := out.table("pairwise_n", "Pairwise N", header...)
+
+	var tests []interface{}
+	for i, a := range columns {
+		rRow := []interface{}{a}
+		pRow := []interface{}{a}
+		nRow := []interface{}{a}
+		for j, b := range columns {
+			if i == j {
+				rRow, pRow, nRow = append(rRow, 1.0), append(pRow, nil), append(nRow, nil)
+				continue
+			}
+			values, err := data.CompleteCases(a, b)
+			if err != nil {
+				return err
+			}
+			res, err := corr(values[0], values[1])
+			if err != nil {
+				rRow, pRow, nRow = append(rRow, nil), append(pRow, nil), append(nRow, float64(len(values[0])))
+				if j > i {
+					out.note("%s - %s: %v", a, b, err)
+				}
+				continue
+			}
+			rRow, pRow, nRow = append(rRow, res.R), append(pRow, res.P), append(nRow, float64(res.N))
+			if j > i {
+				tests = append(tests, map[string]interface{}{
+					KeyVariables: []string{a, b}, KeyN: float64(res.N), KeyStatisticName: statName,
+					KeyStatistic: num(res.R), KeyDF: res.DF, KeyPValue: num(res.P), KeySignificant: res.P < alpha,
+				})
+				if len(columns) == 2 {
+					out.test(statName, res.R, res.P, alpha)
+					out.set(KeyDF, res.DF)
+					out.set(KeyN, float64(res.N))
+					out.effect(effectName, res.R)
+				}
+			}
+		}
+		matrix.add(rRow...)
+		pMatrix.add(pRow...)
+		nMatrix.add(nRow...)
+	}
+	if len(tests) == 0 {
+		return errors.New("no correlation could be computed")
+	}
+	out.set(KeyVariables, columns)
+	out.set(KeyTests, tests)
+	return nil
+}
+
+// regressionPredictors menentukan prediktor: opsi "independent" atau variabel independen dan kontrol proyek
+// Prediktor non-numerik dilewati dan dikembalikan sebagai skipped.
+func regressionPredictors(req Request, dv string) ([]string, []string, error) {
+	fallback := append(append([]string{}, req.Variables.Independent...), req.Variables.Control...)
+	names := optStrings(req.Options, "independent")
+	if len(names) == 0 {
+		names = fallback
+	}
+	var predictors, skipped []string
+	for _, name := range names {
+		column, err := req.column(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if column == dv || containsString(predictors, column) {
+			continue
+		}
+		if t := req.Summary.ColumnTypes[column]; t != "" && t != "numeric" {
+			skipped = append(skipped, column)
+			continue
+		}
+		predictors = append(predictors, column)
+	}
+	if len(predictors) == 0 && len(skipped) > 0 {
+		return nil, nil, fmt.Errorf("predictors %s are not numeric; dummy-code categorical predictors as 0/1 columns first", strings.Join(skipped, ", "))
+	}
+	if len(predictors) == 0 {
+		return nil, nil, errors.New("regression needs at least one predictor: declare independent variables or set the \"independent\" option")
+	}
+	return predictors, skipped, nil
+}
+
+// runRegression menjalankan regresi linear sederhana/berganda beserta VIF
+func runRegression(data Data, req Request, out *output) error {
+	dv, err := req.dependent()
+	if err != nil {
+		return err
+	}
+	predictors, skipped, err := regressionPredictors(req, dv)
+	if err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		out.note("Non-numeric predictors %s were excluded; dummy-code them as 0/1 columns to include them", strings.Join(skipped, ", "))
+	}
+	values, err := data.CompleteCases(append([]string{dv}, predictors...)...)
+	if err != nil {
+		return err
+	}
+	res, err := OLS(values[0], values[1:], predictors, req.alpha())
+	if err != nil {
+		return err
+	}
+
+	out.set(KeyVariables, append([]string{dv}, predictors...))
+	out.set("dependent", dv)
+	out.set("predictors", predictors)
+	out.set(KeyN, float64(res.N))
+	out.test("F", res.F, res.P, req.alpha())
+	out.set(KeyDF1, res.DF1)
+	out.set(KeyDF2, res.DF2)
+	out.set("r", math.Sqrt(res.R2))
+	out.set("r_squared", res.R2)
+	out.set("adj_r_squared", res.AdjR2)
+	out.set("std_error_estimate", res.SE)
+	out.effect("r_squared", res.R2)
+
+	vif := varianceInflation(values[1:], predictors, req.alpha())
+	coefficientTable(out, res, vif)
+	modelTables(out, res)
+	for i, v := range vif {
+		if v > 10 {
+			out.note("VIF for %s is %.2f (> 10); multicollinearity is likely", predictors[i], v)
+		}
+	}
+	if sw, err := ShapiroWilk(res.Residuals); err == nil {
+		out.set("residual_normality_w", sw.W)
+		out.set("residual_normality_p", sw.P)
+		if sw.P < req.alpha() {
+			out.note("Residuals deviate from normality (Shapiro-Wilk p = %.4f)", sw.P)
+		}
+	}
+	return nil
+}
+
+// varianceInflation menghitung VIF tiap prediktor
+func varianceInflation(predictors [][]float64, names []string, alpha float64) []float64 {
+	vif := make([]float64, len(predictors))
+	for i := range predictors {
+		if len(predictors) == 1 {
+			vif[i] = 1
+			continue
+		}
+		var others [][]float64
+		var otherNames []string
+		for j := range predictors {
+			if j != i {
+				others = append(others, predictors[j])
+				otherNames = append(otherNames, names[j])
+			}
+		}
+		res, err := OLS(predictors[i], others, otherNames, alpha)
+		if err != nil {
+			vif[i] = math.Inf(1)
+			continue
+		}
+		vif[i] = 1 / (1 - res.R2)
+	}
+	return vif
+}
+
+func coefficientTable(out *output, res RegressionResult, vif []float64) {
+	t := out.table("coefficients", "Coefficients", "Term", "B", "SE", "Beta", "t", "p", "CI lower", "CI upper", "VIF")
+	for i, term := range res.Terms {
+		var v interface{}
+		if i > 0 && i-1 < len(vif) {
+			v = num(vif[i-1])
+		}
+		t.add(term, res.Coefficients[i], res.StdErrors[i], res.Betas[i], res.TValues[i], res.PValues[i], res.CILower[i], res.CIUpper[i], v)
+	}
+}
+
+func modelTables(out *output, res RegressionResult) {
+	out.table("model_summary", "Model Summary", "R", "R Square", "Adjusted R Square", "Std. Error of the Estimate").
+		add(math.Sqrt(res.R2), res.R2, res.AdjR2, res.SE)
+	t := out.table("anova", "ANOVA", "Source", "Sum of Squares", "df", "Mean Square", "F", "p")
+	t.add("Regression", res.SSRegression, res.DF1, res.SSRegression/res.DF1, res.F, res.P)
+	t.add("Residual", res.SSResidual, res.DF2, res.SSResidual/res.DF2, nil, nil)
+	t.add("Total", res.SSRegression+res.SSResidual, res.DF1+res.DF2, nil, nil, nil)
+}
+
+// roleVariable mengambil variabel dari opsi atau peran proyek pertama yang ada di data
+func roleVariable(req Request, option string, role []string, label string) (string, error) {
+	if name := optString(req.Options, option); name != "" {
+		return req.column(name)
+	}
+	for _, name := range role {
+		if column := matchColumn(name, req.Summary.ColumnNames); column != "" {
+			return column, nil
+		}
+	}
+	return "", fmt.Errorf("no %s variable: declare it in the project or set the %q option", label, option)
+}
+
+// runModeration menguji efek moderasi dengan regresi berinteraksi (prediktor di-centering secara default)
+func runModeration(data Data, req Request, out *output) error {
+	dv, err := req.dependent()
+	if err != nil {
+		return err
+	}
+	x, err := roleVariable(req, "independent", req.Variables.Independent, "independent")
+	if err != nil {
+		return err
+	}
+	w, err := roleVariable(req, "moderator", req.Variables.Moderating, "moderating")
+	if err != nil {
+		return err
+	}
+	values, err := data.CompleteCases(dv, x, w)
+	if err != nil {
+		return err
+	}
+
+	xs, ws := values[1], values[2]
+	center := true
+	if c, ok := optBool(req.Options, "center"); ok {
+		center = c
+	}
+	if center {
+		xs, ws = centered(xs), centered(ws)
+	}
+	interaction := make([]float64, len(xs))
+	for i := range xs {
+		interaction[i] = xs[i] * ws[i]
+	}
+	term := x + " x " + w
+
+	alpha := req.alpha()
+	reduced, err := OLS(values[0], [][]float64{xs, ws}, []string{x, w}, alpha)
+	if err != nil {
+		return err
+	}
+	full, err := OLS(values[0], [][]float64{xs, ws, interaction}, []string{x, w, term}, alpha)
+	if err != nil {
+		return err
+	}
+
+	r2Change := full.R2 - reduced.R2
+	fChange := r2Change / ((1 - full.R2) / full.DF2)
+	out.set(KeyVariables, []string{dv, x, w})
+	out.set("dependent", dv)
+	out.set("independent", x)
+	out.set("moderator", w)
+	out.set("centered", center)
+	out.set(KeyN, float64(full.N))
+	out.test("t", full.TValues[3], full.PValues[3], alpha)
+	out.set(KeyDF, full.DF2)
+	out.set("interaction_b", full.Coefficients[3])
+	out.set("interaction_se", full.StdErrors[3])
+	out.set("r_squared", full.R2)
+	out.set("r_squared_change", r2Change)
+	out.set("f_change", fChange)
+	out.set("f_change_p", FSurvival(fChange, 1, full.DF2))
+	out.effect("r_squared_change", r2Change)
+
+	coefficientTable(out, full, varianceInflation([][]float64{xs, ws, interaction}, []string{x, w, term}, alpha))
+	modelTables(out, full)
+	out.table("r_squared_change", "Model Comparison", "Model", "R Square", "R Square Change", "F Change", "df1", "df2", "p").
+		add("Without interaction", reduced.R2, nil, nil, nil, nil, nil)
+	out.tables[len(out.tables)-1].add("With interaction", full.R2, r2Change, fChange, 1.0, full.DF2, FSurvival(fChange, 1, full.DF2))
+	return nil
+}
+
+func centered(x []float64) []float64 {
+	m := Mean(x)
+	c := make([]float64, len(x))
+	for i, v := range x {
+		c[i] = v - m
+	}
+	return c
+}
+
+// runMediation menguji efek tidak langsung X → M → Y dengan pendekatan Baron-Kenny dan uji Sobel
+func runMediation(data Data, req Request, out *output) error {
+	dv, err := req.dependent()
+	if err != nil {
+		return err
+	}
+	x, err := roleVariable(req, "independent", req.Variables.Independent, "independent")
+	if err != nil {
+		return err
+	}
+	m, err := roleVariable(req, "mediator", req.Variables.Mediating, "mediating")
+	if err != nil {
+		return err
+	}
+	if x == m || x == dv || m == dv {
+		return errors.New("mediation needs three distinct variables (independent, mediator, dependent)")
+	}
+	values, err := data.CompleteCases(dv, x, m)
+	if err != nil {
+		return err
+	}
+	y, xs, ms := values[0], values[1], values[2]
+	alpha := req.alpha()
+
+	pathA, err := OLS(ms, [][]float64{xs}, []string{x}, alpha)
+	if err != nil {
+		return err
+	}
+	pathBC, err := OLS(y, [][]float64{xs, ms}, []string{x, m}, alpha)
+	if err != nil {
+		return err
+	}
+	total, err := OLS(y, [][]float64{xs}, []string{x}, alpha)
+	if err != nil {
+		return err
+	}
+
+	a, sa := pathA.Coefficients[1], pathA.StdErrors[1]
+	b, sb := pathBC.Coefficients[2], pathBC.StdErrors[2]
+	indirect := a * b
+	sobelSE := math.Sqrt(b*b*sa*sa + a*a*sb*sb)
+	z := indirect / sobelSE
+	p := TwoTailedNormalP(z)
+
+	out.set(KeyVariables, []string{dv, x, m})
+	out.set("dependent", dv)
+	out.set("independent", x)
+	out.set("mediator", m)
+	out.set(KeyN, float64(len(y)))
+	out.test("z", z, p, alpha)
+	out.set("indirect_effect", indirect)
+	out.set("indirect_se", sobelSE)
+	out.set("indirect_ci_lower", indirect-NormalQuantile(1-alpha/2)*sobelSE)
+	out.set("indirect_ci_upper", indirect+NormalQuantile(1-alpha/2)*sobelSE)
+	out.set("direct_effect", pathBC.Coefficients[1])
+	out.set("total_effect", total.Coefficients[1])
+	if total.Coefficients[1] != 0 {
+		out.set("proportion_mediated", indirect/total.Coefficients[1])
+	}
+
+	t := out.table("paths", "Mediation Paths", "Path", "Effect", "B", "SE", "t", "p")
+	t.add("a", x+" -> "+m, a, sa, pathA.TValues[1], pathA.PValues[1])
+	t.add("b", m+" -> "+dv, b, sb, pathBC.TValues[2], pathBC.PValues[2])
+	t.add("c'", x+" -> "+dv+" (direct)", pathBC.Coefficients[1], pathBC.StdErrors[1], pathBC.TValues[1], pathBC.PValues[1])
+	t.add("c", x+" -> "+dv+" (total)", total.Coefficients[1], total.StdErrors[1], total.TValues[1], total.PValues[1])
+	out.table("sobel", "Sobel Test", "Indirect effect", "SE", "z", "p").add(indirect, sobelSE, z, p)
+	out.note("Sobel test assumes a normally distributed indirect effect; bootstrap confidence intervals are more accurate for small samples")
+	return nil
+}
+
+// runReliability menghitung Cronbach's alpha dan statistik item-total
+func runReliability(data Data, req Request, out *output) error {
+	fallback := optStrings(req.Options, "items")
+	if len(fallback) == 0 {
+		if groups := ItemGroups(req.Summary); len(groups) > 0 {
+			fallback = groups[0].Items
+		} else {
+			fallback = req.columnsWithScale(req.Summary.ColumnNames, func(p VariableProfile) bool { return p.Scale == ScaleOrdinal })
+		}
+	}
+	items, err := req.variableList(fallback)
+	if err != nil {
+		return err
+	}
+	if len(items) < 2 {
+		return errors.New("reliability needs at least two items: set the \"variables\" option")
+	}
+	values, err := data.CompleteCases(items...)
+	if err != nil {
+		return err
+	}
+	res, err := CronbachAlpha(values)
+	if err != nil {
+		return err
+	}
+
+	out.set(KeyVariables, items)
+	out.set(KeyN, float64(res.N))
+	out.set("n_items", float64(len(items)))
+	out.set(KeyStatisticName, "alpha")
+	out.set(KeyStatistic, res.Alpha)
+	out.effect("cronbach_alpha", res.Alpha)
+
+	t := out.table("item_total", "Item-Total Statistics", "Item", "Mean", "SD", "Corrected item-total r", "Alpha if item deleted")
+	for i, item := range items {
+		t.add(item, Mean(values[i]), StdDev(values[i]), res.ItemTotal[i], res.AlphaIfDeleted[i])
+		if res.ItemTotal[i] < 0.3 {
+			out.note("Item %s has corrected item-total correlation %.3f (< 0.30)", item, res.ItemTotal[i])
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
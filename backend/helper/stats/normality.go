@@ -0,0 +1,109 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// ShapiroWilkResult menyimpan hasil uji normalitas Shapiro-Wilk
+type ShapiroWilkResult struct {
+	W float64
+	P float64
+	N int
+}
+
+// poly mengevaluasi polinomial cc[0] + cc[1]x + ... + cc[n-1]x^(n-1)
+func poly(cc []float64, x float64) float64 {
+	result := 0.0
+	for i := len(cc) - 1; i >= 0; i-- {
+		result = result*x + cc[i]
+	}
+	return result
+}
+
+// ShapiroWilk menguji normalitas dengan algoritma Royston (1995, AS R94), sama dengan shapiro.test di R
+func ShapiroWilk(x []float64) (ShapiroWilkResult, error) {
+	n := len(x)
+	if n < 3 || n > 5000 {
+		return ShapiroWilkResult{}, errors.New("Shapiro-Wilk needs between 3 and 5000 observations")
+	}
+	s := Sorted(x)
+	if s[0] == s[n-1] {
+		return ShapiroWilkResult{}, errors.New("all values are identical")
+	}
+
+	an := float64(n)
+	nn2 := n / 2
+	a := make([]float64, nn2+1) // berbasis 1
+
+	if n == 3 {
+		a[1] = math.Sqrt(0.5)
+	} else {
+		c1 := []float64{0, 0.221157, -0.147981, -2.07119, 4.434685, -2.706056}
+		c2 := []float64{0, 0.042981, -0.293762, -1.752461, 5.682633, -3.582633}
+
+		m := make([]float64, nn2+1)
+		summ2 := 0.0
+		for i := 1; i <= nn2; i++ {
+			m[i] = NormalQuantile((float64(i) - 0.375) / (an + 0.25))
+			summ2 += m[i] * m[i]
+		}
+		summ2 *= 2
+		ssumm2 := math.Sqrt(summ2)
+		rsn := 1 / math.Sqrt(an)
+		a1 := poly(c1, rsn) - m[1]/ssumm2
+
+		var i1 int
+		var fac float64
+		if n > 5 {
+			i1 = 3
+			a2 := -m[2]/ssumm2 + poly(c2, rsn)
+			fac = math.Sqrt((summ2 - 2*m[1]*m[1] - 2*m[2]*m[2]) / (1 - 2*a1*a1 - 2*a2*a2))
+			a[2] = a2
+		} else {
+			i1 = 2
+			fac = math.Sqrt((summ2 - 2*m[1]*m[1]) / (1 - 2*a1*a1))
+		}
+		a[1] = a1
+		for i := i1; i <= nn2; i++ {
+			a[i] = -m[i] / fac
+		}
+	}
+
+	mean := Mean(s)
+	ss := 0.0
+	for _, v := range s {
+		ss += (v - mean) * (v - mean)
+	}
+	num := 0.0
+	for i := 1; i <= nn2; i++ {
+		num += a[i] * (s[n-i] - s[i-1])
+	}
+	w := math.Min(1, num*num/ss)
+
+	result := ShapiroWilkResult{W: w, N: n}
+	if n == 3 {
+		const pi6, stqr = 1.90985931710274, 1.04719755119660
+		result.P = math.Max(0, pi6*(math.Asin(math.Sqrt(w))-stqr))
+		return result, nil
+	}
+
+	y := math.Log(1 - w)
+	var mu, sigma float64
+	if n <= 11 {
+		gamma := poly([]float64{-2.273, 0.459}, an)
+		if y >= gamma {
+			result.P = 1e-99
+			return result, nil
+		}
+		y = -math.Log(gamma - y)
+		mu = poly([]float64{0.544, -0.39978, 0.025054, -6.714e-4}, an)
+		sigma = math.Exp(poly([]float64{1.3822, -0.77857, 0.062767, -0.0020322}, an))
+	} else {
+		xx := math.Log(an)
+		mu = poly([]float64{-1.5861, -0.31082, -0.083751, 0.0038915}, xx)
+		sigma = math.Exp(poly([]float64{-0.4803, -0.082676, 0.0030302}, xx))
+	}
+	result.P = 1 - NormalCDF((y-mu)/sigma)
+	return result, nil
+}
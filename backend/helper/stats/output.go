@@ -0,0 +1,244 @@
+package stats
+
+import (
+	"math"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kunci standar pada MethodResult.RawOutput
+const (
+	KeyMethod          = "method"
+	KeyMethodName      = "method_name"
+	KeyVariables       = "variables"
+	KeyN               = "n"
+	KeyAlpha           = "alpha"
+	KeyStatisticName   = "statistic_name"
+	KeyStatistic       = "statistic"
+	KeyDF              = "df"
+	KeyDF1             = "df1"
+	KeyDF2             = "df2"
+	KeyPValue          = "p_value"
+	KeySignificant     = "significant"
+	KeyEffectSizeName  = "effect_size_name"
+	KeyEffectSize      = "effect_size"
+	KeyEffectSizeLabel = "effect_size_label"
+	KeyTables          = "tables"
+	KeyTests           = "tests"
+	KeyNotes           = "notes"
+	KeyError           = "error"
+)
+
+// Table adalah tabel hasil analisis di dalam RawOutput
+type Table struct {
+	Name    string          `json:"name" bson:"name"`
+	Title   string          `json:"title" bson:"title"`
+	Columns []string        `json:"columns" bson:"columns"`
+	Rows    [][]interface{} `json:"rows" bson:"rows"`
+}
+
+// newTable membuat tabel kosong dengan kolom tertentu
+func newTable(name, title string, columns ...string) *Table {
+	return &Table{Name: name, Title: title, Columns: columns}
+}
+
+// add menambah satu baris; nilai float dibersihkan dari NaN/Inf
+func (t *Table) add(values ...interface{}) {
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		if f, ok := v.(float64); ok {
+			row[i] = num(f)
+			continue
+		}
+		row[i] = v
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// toMap mengubah tabel menjadi map agar aman disimpan di RawOutput
+func (t *Table) toMap() map[string]interface{} {
+	rows := make([]interface{}, len(t.Rows))
+	for i, row := range t.Rows {
+		rows[i] = row
+	}
+	return map[string]interface{}{
+		"name":    t.Name,
+		"title":   t.Title,
+		"columns": t.Columns,
+		"rows":    rows,
+	}
+}
+
+// num mengubah NaN/Inf menjadi nil karena tidak dapat di-encode ke JSON
+func num(v float64) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return v
+}
+
+// Normalize mengubah nilai hasil decode BSON (primitive.D, primitive.A, int32, ...) menjadi
+// bentuk Go standar (map, slice, float64) sehingga RawOutput dapat dibaca seragam
+func Normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case primitive.D:
+		m := make(map[string]interface{}, len(t))
+		for _, e := range t {
+			m[e.Key] = Normalize(e.Value)
+		}
+		return m
+	case primitive.M:
+		return Normalize(map[string]interface{}(t))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[k] = Normalize(val)
+		}
+		return m
+	case primitive.A:
+		return Normalize([]interface{}(t))
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = Normalize(val)
+		}
+		return out
+	case []map[string]interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = Normalize(val)
+		}
+		return out
+	case []string:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = val
+		}
+		return out
+	case [][]interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = Normalize(val)
+		}
+		return out
+	case int:
+		return float64(t)
+	case int32:
+		return float64(t)
+	case int64:
+		return float64(t)
+	}
+	return v
+}
+
+// NormalizeOutput menormalkan seluruh RawOutput
+func NormalizeOutput(raw map[string]interface{}) map[string]interface{} {
+	if raw == nil {
+		return nil
+	}
+	return Normalize(raw).(map[string]interface{})
+}
+
+// Float membaca nilai numerik dari berbagai tipe
+func Float(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, !math.IsNaN(t)
+	case float32:
+		return float64(t), true
+	case int:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	}
+	return 0, false
+}
+
+// Number membaca nilai numerik dari RawOutput berdasarkan kunci
+func Number(raw map[string]interface{}, key string) (float64, bool) {
+	return Float(raw[key])
+}
+
+// String membaca nilai string dari RawOutput berdasarkan kunci
+func String(raw map[string]interface{}, key string) string {
+	s, _ := raw[key].(string)
+	return s
+}
+
+// Strings membaca daftar string dari RawOutput berdasarkan kunci
+func Strings(raw map[string]interface{}, key string) []string {
+	switch t := Normalize(raw[key]).(type) {
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Tables membaca daftar tabel dari RawOutput
+func Tables(raw map[string]interface{}) []Table {
+	list, _ := Normalize(raw[KeyTables]).([]interface{})
+	tables := make([]Table, 0, len(list))
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t := Table{Name: String(m, "name"), Title: String(m, "title"), Columns: Strings(m, "columns")}
+		rows, _ := m["rows"].([]interface{})
+		for _, r := range rows {
+			if cells, ok := r.([]interface{}); ok {
+				t.Rows = append(t.Rows, cells)
+			}
+		}
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// FindTable mencari tabel berdasarkan nama
+func FindTable(raw map[string]interface{}, name string) (Table, bool) {
+	for _, t := range Tables(raw) {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// Numbers mengumpulkan seluruh nilai numerik pada RawOutput (dipakai untuk verifikasi angka)
+func Numbers(raw map[string]interface{}) []float64 {
+	var values []float64
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(t))
+			for k := range t {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(t[k])
+			}
+		case []interface{}:
+			for _, item := range t {
+				walk(item)
+			}
+		default:
+			if f, ok := Float(t); ok {
+				values = append(values, f)
+			}
+		}
+	}
+	walk(Normalize(raw))
+	return values
+}
@@ -0,0 +1,454 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/research-data-analysis/helper/i18n"
+	"github.com/research-data-analysis/model"
+)
+
+// Skala pengukuran variabel
+const (
+	ScaleNominal  = "nominal"
+	ScaleOrdinal  = "ordinal"
+	ScaleInterval = "interval"
+	ScaleRatio    = "ratio"
+)
+
+// Sumber rekomendasi
+const (
+	SourceRules = "rules"
+	SourceAI    = "ai"
+)
+
+// smallGroupSize adalah ukuran kelompok di bawah mana uji nonparametrik ikut direkomendasikan
+const smallGroupSize = 30
+
+var (
+	itemPattern = regexp.MustCompile(`^(.*?)[\s._-]*\d+$`)
+	prePattern  = regexp.MustCompile(`(?i)^(pre|pretest|pre_test|sebelum)[\s._-]*(.*)$|^(.*?)[\s._-]*(pre|pretest|pre_test|sebelum)$`)
+)
+
+// VariableProfile merangkum informasi satu variabel yang relevan untuk pemilihan metode
+type VariableProfile struct {
+	Name      string `json:"name"`
+	Role      string `json:"role,omitempty"`
+	Scale     string `json:"scale"`
+	Levels    int    `json:"levels"`
+	N         int    `json:"n"`
+	MinGroup  int    `json:"min_group,omitempty"`
+	Declared  bool   `json:"declared,omitempty"`
+	Available bool   `json:"available"`
+}
+
+// IsMetric mengecek apakah skala interval atau rasio
+func (v VariableProfile) IsMetric() bool {
+	return v.Scale == ScaleInterval || v.Scale == ScaleRatio
+}
+
+// IsGrouping mengecek apakah variabel dapat dipakai sebagai pengelompok
+func (v VariableProfile) IsGrouping() bool {
+	return (v.Scale == ScaleNominal || v.Scale == ScaleOrdinal) && v.Levels >= 2 && v.Levels <= 10
+}
+
+// ProfileVariable membentuk profil variabel dari ringkasan data dan skala yang dideklarasikan pengguna
+func ProfileVariable(name, role string, vars model.Variables, summary model.DataSummary) VariableProfile {
+	profile := VariableProfile{Name: name, Role: role}
+
+	column := matchColumn(name, summary.ColumnNames)
+	if column == "" {
+		return profile
+	}
+	profile.Name = column
+	profile.Available = true
+	profile.Scale = summary.Scales[column]
+	profile.Levels = summary.Levels[column]
+	profile.N = summary.Rows - summary.MissingCount[column]
+
+	for declaredName, scale := range vars.Scales {
+		if strings.EqualFold(declaredName, column) && scale != "" {
+			profile.Scale = strings.ToLower(scale)
+			profile.Declared = true
+		}
+	}
+
+	if frequencies, ok := summary.Frequencies[column]; ok && len(frequencies) > 0 {
+		profile.MinGroup = -1
+		for _, count := range frequencies {
+			if profile.MinGroup < 0 || count < profile.MinGroup {
+				profile.MinGroup = count
+			}
+		}
+	}
+	return profile
+}
+
+// matchColumn mencocokkan nama variabel proyek dengan nama kolom data (tidak peka huruf besar/kecil)
+func matchColumn(name string, columns []string) string {
+	name = strings.TrimSpace(name)
+	for _, column := range columns {
+		if strings.EqualFold(column, name) {
+			return column
+		}
+	}
+	return ""
+}
+
+// recommender mengumpulkan rekomendasi dan menggabungkan metode yang sama
+type recommender struct {
+	lang  string
+	order []string
+	items map[string]*model.Recommendation
+}
+
+func (r *recommender) add(methodID, reason string, variables ...string) {
+	method, ok := LookupMethod(methodID)
+	if !ok {
+		return
+	}
+	rec, exists := r.items[method.ID]
+	if !exists {
+		rec = &model.Recommendation{
+			Method:      method.Name,
+			MethodID:    method.ID,
+			Category:    method.Category,
+			Assumptions: method.AssumptionsIn(r.lang),
+			Source:      SourceRules,
+		}
+		r.items[method.ID] = rec
+		r.order = append(r.order, method.ID)
+	}
+	if reason != "" && !strings.Contains(rec.Reasoning, reason) {
+		if rec.Reasoning != "" {
+			rec.Reasoning += " "
+		}
+		rec.Reasoning += reason
+	}
+	for _, v := range variables {
+		if !containsString(rec.Variables, v) {
+			rec.Variables = append(rec.Variables, v)
+		}
+	}
+}
+
+func (r *recommender) has(methodID string) bool {
+	_, ok := r.items[methodID]
+	return ok
+}
+
+// Recommend menghasilkan rekomendasi metode secara deterministik berdasarkan skala pengukuran,
+// jumlah kelompok, ukuran sampel, dan peran variabel pada proyek. Alasan ditulis dalam bahasa lang.
+func Recommend(project model.Project, summary model.DataSummary, lang string) []model.Recommendation {
+	r := &recommender{lang: lang, items: make(map[string]*model.Recommendation)}
+
+	r.add(MethodDescriptive, i18n.T(r.lang, "rec.descriptive", summary.Rows, summary.Columns))
+
+	dependents := profiles(project.Variables.Dependent, "dependent", project, summary)
+	independents := profiles(project.Variables.Independent, "independent", project, summary)
+
+	for _, dv := range dependents {
+		for _, iv := range independents {
+			recommendPair(r, dv, iv, summary.Rows)
+		}
+
+		var metricPredictors []string
+		for _, iv := range independents {
+			if iv.IsMetric() || iv.Scale == ScaleOrdinal {
+				metricPredictors = append(metricPredictors, iv.Name)
+			}
+		}
+		if dv.IsMetric() && len(metricPredictors) >= 2 {
+			reason := i18n.T(r.lang, "rec.multiple_regression", dv.Name, dv.Scale, len(metricPredictors), strings.Join(metricPredictors, ", "))
+			if summary.Rows < 10*len(metricPredictors)+10 {
+				reason += i18n.T(r.lang, "rec.small_regression", summary.Rows)
+			}
+			r.add(MethodLinearRegression, reason, append([]string{dv.Name}, metricPredictors...)...)
+		}
+
+		for _, mod := range profiles(project.Variables.Moderating, "moderating", project, summary) {
+			if dv.IsMetric() && len(independents) > 0 {
+				r.add(MethodModeration, i18n.T(r.lang, "rec.moderation", mod.Name, mod.Name, independents[0].Name, dv.Name), dv.Name, independents[0].Name, mod.Name)
+			}
+		}
+		for _, med := range profiles(project.Variables.Mediating, "mediating", project, summary) {
+			if dv.IsMetric() && med.IsMetric() && len(independents) > 0 {
+				r.add(MethodMediation, i18n.T(r.lang, "rec.mediation", med.Name, independents[0].Name, med.Name, dv.Name), dv.Name, independents[0].Name, med.Name)
+			}
+		}
+	}
+
+	if len(dependents) == 0 || len(independents) == 0 {
+		recommendWithoutRoles(r, project, summary)
+	}
+	recommendPaired(r, project, summary)
+	recommendReliability(r, summary)
+
+	// Uji parametrik memerlukan pemeriksaan normalitas pada variabel interval/rasio yang terlibat
+	var parametric, metricVars []string
+	for _, id := range []string{MethodIndependentTTest, MethodPairedTTest, MethodOneWayANOVA, MethodPearson, MethodLinearRegression} {
+		rec, ok := r.items[id]
+		if !ok {
+			continue
+		}
+		parametric = append(parametric, rec.Method)
+		for _, v := range rec.Variables {
+			if ProfileVariable(v, "", project.Variables, summary).IsMetric() && !containsString(metricVars, v) {
+				metricVars = append(metricVars, v)
+			}
+		}
+	}
+	if len(parametric) > 0 && len(metricVars) > 0 {
+		r.add(MethodNormality, i18n.T(r.lang, "rec.normality", strings.Join(parametric, ", ")), metricVars...)
+	}
+
+	return r.ranked(summary.Rows)
+}
+
+// recommendPair memilih metode untuk satu pasangan variabel dependen dan independen
+func recommendPair(r *recommender, dv, iv VariableProfile, n int) {
+	vars := []string{dv.Name, iv.Name}
+	small := iv.MinGroup > 0 && iv.MinGroup < smallGroupSize
+
+	switch {
+	case dv.IsMetric() && iv.IsGrouping() && iv.Levels == 2:
+		r.add(MethodIndependentTTest, i18n.T(r.lang, "rec.independent_t", dv.Name, dv.Scale, iv.Name), vars...)
+		if small {
+			r.add(MethodMannWhitney, i18n.T(r.lang, "rec.mann_whitney_small", iv.Name, iv.MinGroup), vars...)
+		}
+	case dv.IsMetric() && iv.Scale == ScaleNominal && iv.IsGrouping():
+		r.add(MethodOneWayANOVA, i18n.T(r.lang, "rec.anova", dv.Name, dv.Scale, iv.Name, iv.Levels), vars...)
+		if small {
+			r.add(MethodKruskalWallis, i18n.T(r.lang, "rec.kruskal_small", iv.Name, iv.MinGroup), vars...)
+		}
+	case dv.IsMetric() && iv.IsMetric():
+		r.add(MethodPearson, i18n.T(r.lang, "rec.pearson", dv.Name, iv.Name, iv.Scale), vars...)
+		r.add(MethodLinearRegression, i18n.T(r.lang, "rec.regression", iv.Name, dv.Name), vars...)
+		if n < smallGroupSize {
+			r.add(MethodSpearman, i18n.T(r.lang, "rec.spearman_small", n), vars...)
+		}
+	case dv.IsMetric() && iv.Scale == ScaleOrdinal:
+		r.add(MethodSpearman, i18n.T(r.lang, "rec.spearman_ordinal_iv", iv.Name, iv.Levels, dv.Name), vars...)
+	case dv.Scale == ScaleOrdinal && iv.IsGrouping() && iv.Levels == 2:
+		r.add(MethodMannWhitney, i18n.T(r.lang, "rec.mann_whitney", dv.Name, iv.Name), vars...)
+	case dv.Scale == ScaleOrdinal && iv.Scale == ScaleNominal && iv.IsGrouping():
+		r.add(MethodKruskalWallis, i18n.T(r.lang, "rec.kruskal", dv.Name, iv.Name, iv.Levels), vars...)
+	case dv.Scale == ScaleOrdinal && (iv.IsMetric() || iv.Scale == ScaleOrdinal):
+		r.add(MethodSpearman, i18n.T(r.lang, "rec.spearman_ordinal_dv", dv.Name, iv.Name), vars...)
+	case dv.Scale == ScaleNominal && iv.IsGrouping() && dv.Levels <= 10:
+		r.add(MethodChiSquare, i18n.T(r.lang, "rec.chi_square", dv.Name, dv.Levels, iv.Name, iv.Levels), vars...)
+	case dv.Scale == ScaleNominal && dv.Levels == 2 && iv.IsMetric():
+		r.add(MethodIndependentTTest, i18n.T(r.lang, "rec.dichotomous_dv", dv.Name, iv.Name, iv.Scale, iv.Name, dv.Name), iv.Name, dv.Name)
+	}
+}
+
+// recommendWithoutRoles memberi rekomendasi eksploratif jika peran variabel belum lengkap
+func recommendWithoutRoles(r *recommender, project model.Project, summary model.DataSummary) {
+	var metric, ordinal []string
+	for _, column := range summary.ColumnNames {
+		profile := ProfileVariable(column, "", project.Variables, summary)
+		switch {
+		case profile.IsMetric():
+			metric = append(metric, column)
+		case profile.Scale == ScaleOrdinal:
+			ordinal = append(ordinal, column)
+		}
+	}
+
+	if len(metric) >= 2 {
+		r.add(MethodPearson, i18n.T(r.lang, "rec.pearson_matrix", len(metric)), metric...)
+	}
+	if len(ordinal) >= 2 || (len(ordinal) >= 1 && len(metric) >= 1) {
+		r.add(MethodSpearman, i18n.T(r.lang, "rec.spearman_explore"), append(ordinal, metric...)...)
+	}
+}
+
+// recommendPaired merekomendasikan uji berpasangan untuk kolom pre/post
+func recommendPaired(r *recommender, project model.Project, summary model.DataSummary) {
+	for _, pair := range PrePostPairs(summary.ColumnNames) {
+		column, other := pair[0], pair[1]
+		pre := ProfileVariable(column, "", project.Variables, summary)
+		post := ProfileVariable(other, "", project.Variables, summary)
+		if pre.IsMetric() && post.IsMetric() {
+			r.add(MethodPairedTTest, i18n.T(r.lang, "rec.paired_t", column, other), column, other)
+			if summary.Rows < smallGroupSize {
+				r.add(MethodWilcoxon, i18n.T(r.lang, "rec.wilcoxon_small", summary.Rows), column, other)
+			}
+		} else if pre.Scale == ScaleOrdinal || post.Scale == ScaleOrdinal {
+			r.add(MethodWilcoxon, i18n.T(r.lang, "rec.wilcoxon_ordinal", column, other), column, other)
+		}
+	}
+}
+
+// PrePostPairs mendeteksi pasangan kolom pengukuran berulang (mis. pretest/posttest, pre_skor/post_skor)
+func PrePostPairs(columns []string) [][2]string {
+	var pairs [][2]string
+	for _, column := range columns {
+		m := prePattern.FindStringSubmatch(column)
+		if m == nil {
+			continue
+		}
+		stem := strings.ToLower(m[2] + m[3])
+		for _, other := range columns {
+			lower := strings.ToLower(other)
+			if other == column || !(strings.Contains(lower, "post") || strings.Contains(lower, "sesudah")) {
+				continue
+			}
+			if stem != "" && !strings.Contains(lower, stem) {
+				continue
+			}
+			pairs = append(pairs, [2]string{column, other})
+			break
+		}
+	}
+	return pairs
+}
+
+// recommendReliability merekomendasikan uji reliabilitas untuk kelompok item kuesioner
+func recommendReliability(r *recommender, summary model.DataSummary) {
+	for _, group := range ItemGroups(summary) {
+		r.add(MethodReliability, i18n.T(r.lang, "rec.reliability", len(group.Items), group.Prefix), group.Items...)
+	}
+}
+
+// ItemGroup adalah sekumpulan item kuesioner dengan awalan nama yang sama
+type ItemGroup struct {
+	Prefix string
+	Items  []string
+}
+
+// ItemGroups mendeteksi kelompok item kuesioner (mis. X1.1, X1.2, X1.3) berskala ordinal, minimal 3 item
+func ItemGroups(summary model.DataSummary) []ItemGroup {
+	groups := make(map[string][]string)
+	var prefixes []string
+	for _, column := range summary.ColumnNames {
+		if summary.Scales[column] != ScaleOrdinal {
+			continue
+		}
+		m := itemPattern.FindStringSubmatch(column)
+		if m == nil || m[1] == "" {
+			continue
+		}
+		prefix := m[1]
+		if _, ok := groups[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+		groups[prefix] = append(groups[prefix], column)
+	}
+
+	var result []ItemGroup
+	for _, prefix := range prefixes {
+		if len(groups[prefix]) >= 3 {
+			result = append(result, ItemGroup{Prefix: prefix, Items: groups[prefix]})
+		}
+	}
+	return result
+}
+
+// ranked mengurutkan rekomendasi: deskriptif, asumsi, uji utama, lalu alternatif nonparametrik
+func (r *recommender) ranked(n int) []model.Recommendation {
+	rank := map[string]int{
+		CategoryDescriptive:   0,
+		CategoryReliability:   1,
+		CategoryAssumption:    2,
+		CategoryInferential:   3,
+		CategoryRegression:    3,
+		CategoryCorrelation:   4,
+		CategoryNonparametric: 5,
+	}
+	if n > 0 && n < smallGroupSize {
+		// Sampel kecil: dahulukan uji nonparametrik
+		rank[CategoryNonparametric] = 3
+	}
+
+	ids := append([]string(nil), r.order...)
+	sort.SliceStable(ids, func(i, j int) bool {
+		return rank[r.items[ids[i]].Category] < rank[r.items[ids[j]].Category]
+	})
+
+	recommendations := make([]model.Recommendation, 0, len(ids))
+	for i, id := range ids {
+		rec := *r.items[id]
+		rec.Priority = i + 1
+		recommendations = append(recommendations, rec)
+	}
+	return recommendations
+}
+
+// profiles membentuk profil untuk daftar variabel dengan peran tertentu, melewati yang tidak ada di data
+func profiles(names []string, role string, project model.Project, summary model.DataSummary) []VariableProfile {
+	var result []VariableProfile
+	for _, name := range names {
+		if p := ProfileVariable(name, role, project.Variables, summary); p.Available {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// DescribeDataContext menyusun ringkasan variabel dan rekomendasi berbasis aturan sebagai konteks prompt AI
+func DescribeDataContext(project model.Project, summary model.DataSummary, recommendations []model.Recommendation, lang string) string {
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "data.sample", summary.Rows, summary.Columns) + "\n")
+
+	roles := map[string]string{}
+	for _, group := range []struct {
+		role  string
+		names []string
+	}{
+		{i18n.T(lang, "role.dependent"), project.Variables.Dependent},
+		{i18n.T(lang, "role.independent"), project.Variables.Independent},
+		{i18n.T(lang, "role.control"), project.Variables.Control},
+		{i18n.T(lang, "role.moderating"), project.Variables.Moderating},
+		{i18n.T(lang, "role.mediating"), project.Variables.Mediating},
+	} {
+		for _, name := range group.names {
+			column := matchColumn(name, summary.ColumnNames)
+			if column == "" {
+				continue
+			}
+			if roles[column] != "" {
+				roles[column] += "/"
+			}
+			roles[column] += group.role
+		}
+	}
+
+	b.WriteString(i18n.T(lang, "data.variables") + "\n")
+	for _, column := range summary.ColumnNames {
+		p := ProfileVariable(column, "", project.Variables, summary)
+		b.WriteString(i18n.T(lang, "data.variable", column, summary.ColumnTypes[column], p.Scale, p.Levels, summary.MissingCount[column]))
+		if role, ok := roles[column]; ok {
+			b.WriteString(i18n.T(lang, "data.role", role))
+		}
+		if p.MinGroup > 0 && p.IsGrouping() {
+			b.WriteString(i18n.T(lang, "data.smallest", p.MinGroup))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, name := range append(append([]string{}, project.Variables.Dependent...), project.Variables.Independent...) {
+		if matchColumn(name, summary.ColumnNames) == "" {
+			b.WriteString(i18n.T(lang, "data.missing_var", name) + "\n")
+		}
+	}
+
+	if len(recommendations) > 0 {
+		b.WriteString("\n" + i18n.T(lang, "data.rules") + "\n")
+		for _, rec := range recommendations {
+			fmt.Fprintf(&b, "%d. %s [%s] - %s\n", rec.Priority, rec.Method, strings.Join(rec.Variables, ", "), rec.Reasoning)
+		}
+	}
+	return b.String()
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// RegressionResult menyimpan hasil regresi linear OLS
+type RegressionResult struct {
+	Terms        []string
+	Coefficients []float64
+	StdErrors    []float64
+	TValues      []float64
+	PValues      []float64
+	Betas        []float64
+	CILower      []float64
+	CIUpper      []float64
+	R2           float64
+	AdjR2        float64
+	F            float64
+	DF1          float64
+	DF2          float64
+	P            float64
+	SSRegression float64
+	SSResidual   float64
+	SE           float64
+	N            int
+	Fitted       []float64
+	Residuals    []float64
+}
+
+// OLS mengestimasi regresi linear y = b0 + b1*x1 + ... dengan intercept.
+// predictors berisi satu slice per prediktor, names memberi nama tiap prediktor.
+func OLS(y []float64, predictors [][]float64, names []string, alpha float64) (RegressionResult, error) {
+	n := len(y)
+	k := len(predictors)
+	if k == 0 {
+		return RegressionResult{}, errors.New("regression needs at least one predictor")
+	}
+	if n <= k+1 {
+		return RegressionResult{}, errors.New("not enough observations for the number of predictors")
+	}
+	for _, x := range predictors {
+		if len(x) != n {
+			return RegressionResult{}, errors.New("predictors and outcome must have equal length")
+		}
+	}
+
+	p := k + 1
+	// X'X dan X'y
+	xtx := make([][]float64, p)
+	for i := range xtx {
+		xtx[i] = make([]float64, p)
+	}
+	xty := make([]float64, p)
+	row := make([]float64, p)
+	for obs := 0; obs < n; obs++ {
+		row[0] = 1
+		for j := 0; j < k; j++ {
+			row[j+1] = predictors[j][obs]
+		}
+		for i := 0; i < p; i++ {
+			xty[i] += row[i] * y[obs]
+			for j := 0; j < p; j++ {
+				xtx[i][j] += row[i] * row[j]
+			}
+		}
+	}
+
+	inv, err := invert(xtx)
+	if err != nil {
+		return RegressionResult{}, errors.New("predictors are perfectly collinear")
+	}
+	coef := make([]float64, p)
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			coef[i] += inv[i][j] * xty[j]
+		}
+	}
+
+	my := Mean(y)
+	fitted := make([]float64, n)
+	residuals := make([]float64, n)
+	var ssr, sse float64
+	for obs := 0; obs < n; obs++ {
+		yhat := coef[0]
+		for j := 0; j < k; j++ {
+			yhat += coef[j+1] * predictors[j][obs]
+		}
+		fitted[obs] = yhat
+		residuals[obs] = y[obs] - yhat
+		ssr += (yhat - my) * (yhat - my)
+		sse += residuals[obs] * residuals[obs]
+	}
+
+	df1, df2 := float64(k), float64(n-k-1)
+	mse := sse / df2
+	sst := ssr + sse
+	if sst == 0 {
+		return RegressionResult{}, errors.New("outcome has zero variance")
+	}
+	r2 := ssr / sst
+	f := (ssr / df1) / mse
+	crit := StudentTQuantile(1-alpha/2, df2)
+
+	result := RegressionResult{
+		Terms:        append([]string{"(Intercept)"}, names...),
+		Coefficients: coef,
+		R2:           r2,
+		AdjR2:        1 - (1-r2)*float64(n-1)/df2,
+		F:            f,
+		DF1:          df1,
+		DF2:          df2,
+		P:            FSurvival(f, df1, df2),
+		SSRegression: ssr,
+		SSResidual:   sse,
+		SE:           math.Sqrt(mse),
+		N:            n,
+		Fitted:       fitted,
+		Residuals:    residuals,
+	}
+
+	sdy := StdDev(y)
+	for i := 0; i < p; i++ {
+		se := math.Sqrt(mse * inv[i][i])
+		t := coef[i] / se
+		result.StdErrors = append(result.StdErrors, se)
+		result.TValues = append(result.TValues, t)
+		result.PValues = append(result.PValues, TwoTailedTP(t, df2))
+		result.CILower = append(result.CILower, coef[i]-crit*se)
+		result.CIUpper = append(result.CIUpper, coef[i]+crit*se)
+		if i == 0 {
+			result.Betas = append(result.Betas, math.NaN())
+		} else {
+			result.Betas = append(result.Betas, coef[i]*StdDev(predictors[i-1])/sdy)
+		}
+	}
+	return result, nil
+}
+
+// invert menghitung invers matriks dengan eliminasi Gauss-Jordan dan pivot parsial
+func invert(m [][]float64) ([][]float64, error) {
+	n := len(m)
+	a := make([][]float64, n)
+	for i := range m {
+		a[i] = make([]float64, 2*n)
+		copy(a[i], m[i])
+		a[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errors.New("matrix is singular")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+
+		div := a[col][col]
+		for j := range a[col] {
+			a[col][j] /= div
+		}
+		for r := 0; r < n; r++ {
+			if r == col || a[r][col] == 0 {
+				continue
+			}
+			factor := a[r][col]
+			for j := range a[r] {
+				a[r][j] -= factor * a[col][j]
+			}
+		}
+	}
+
+	inv := make([][]float64, n)
+	for i := range a {
+		inv[i] = a[i][n:]
+	}
+	return inv, nil
+}
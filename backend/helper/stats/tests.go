@@ -0,0 +1,500 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TTestResult menyimpan hasil uji t
+type TTestResult struct {
+	T              float64
+	DF             float64
+	P              float64
+	MeanDifference float64
+	CILower        float64
+	CIUpper        float64
+	CohensD        float64
+}
+
+// GroupStats menyimpan statistik ringkas satu kelompok
+type GroupStats struct {
+	Label    string
+	N        int
+	Mean     float64
+	SD       float64
+	Median   float64
+	MeanRank float64
+}
+
+func groupStats(label string, x []float64) GroupStats {
+	return GroupStats{Label: label, N: len(x), Mean: Mean(x), SD: StdDev(x), Median: Median(x)}
+}
+
+// OneSampleTTest menguji apakah rata-rata berbeda dari mu
+func OneSampleTTest(x []float64, mu, alpha float64) (TTestResult, error) {
+	n := float64(len(x))
+	if n < 2 {
+		return TTestResult{}, errors.New("one-sample t-test needs at least 2 observations")
+	}
+	sd := StdDev(x)
+	if sd == 0 {
+		return TTestResult{}, errors.New("variable has zero variance")
+	}
+	se := sd / math.Sqrt(n)
+	diff := Mean(x) - mu
+	df := n - 1
+	crit := StudentTQuantile(1-alpha/2, df)
+	t := diff / se
+	return TTestResult{
+		T: t, DF: df, P: TwoTailedTP(t, df),
+		MeanDifference: diff,
+		CILower:        diff - crit*se,
+		CIUpper:        diff + crit*se,
+		CohensD:        diff / sd,
+	}, nil
+}
+
+// IndependentTTest membandingkan rata-rata dua kelompok; equalVar=false menggunakan koreksi Welch
+func IndependentTTest(x, y []float64, equalVar bool, alpha float64) (TTestResult, error) {
+	n1, n2 := float64(len(x)), float64(len(y))
+	if n1 < 2 || n2 < 2 {
+		return TTestResult{}, errors.New("each group needs at least 2 observations")
+	}
+	v1, v2 := Variance(x), Variance(y)
+	diff := Mean(x) - Mean(y)
+	pooled := ((n1-1)*v1 + (n2-1)*v2) / (n1 + n2 - 2)
+	if pooled == 0 {
+		return TTestResult{}, errors.New("both groups have zero variance")
+	}
+
+	var se, df float64
+	if equalVar {
+		se = math.Sqrt(pooled * (1/n1 + 1/n2))
+		df = n1 + n2 - 2
+	} else {
+		a, b := v1/n1, v2/n2
+		se = math.Sqrt(a + b)
+		df = (a + b) * (a + b) / (a*a/(n1-1) + b*b/(n2-1))
+	}
+	t := diff / se
+	crit := StudentTQuantile(1-alpha/2, df)
+	return TTestResult{
+		T: t, DF: df, P: TwoTailedTP(t, df),
+		MeanDifference: diff,
+		CILower:        diff - crit*se,
+		CIUpper:        diff + crit*se,
+		CohensD:        diff / math.Sqrt(pooled),
+	}, nil
+}
+
+// PairedTTest membandingkan rata-rata dua pengukuran berpasangan
+func PairedTTest(x, y []float64, alpha float64) (TTestResult, error) {
+	if len(x) != len(y) {
+		return TTestResult{}, errors.New("paired samples must have equal length")
+	}
+	diffs := make([]float64, len(x))
+	for i := range x {
+		diffs[i] = x[i] - y[i]
+	}
+	return OneSampleTTest(diffs, 0, alpha)
+}
+
+// LeveneResult menyimpan hasil uji homogenitas varians
+type LeveneResult struct {
+	F   float64
+	DF1 float64
+	DF2 float64
+	P   float64
+}
+
+// LeveneTest menguji homogenitas varians dengan uji Levene berbasis rata-rata, sama dengan output SPSS
+// (varian Brown-Forsythe memakai median)
+func LeveneTest(groups [][]float64) (LeveneResult, error) {
+	deviations := make([][]float64, len(groups))
+	for i, g := range groups {
+		m := Mean(g)
+		for _, v := range g {
+			deviations[i] = append(deviations[i], math.Abs(v-m))
+		}
+	}
+	anova, err := OneWayANOVA(deviations)
+	if err != nil {
+		return LeveneResult{}, err
+	}
+	return LeveneResult{F: anova.F, DF1: anova.DFBetween, DF2: anova.DFWithin, P: anova.P}, nil
+}
+
+// ANOVAResult menyimpan hasil ANOVA satu arah
+type ANOVAResult struct {
+	SSBetween float64
+	SSWithin  float64
+	DFBetween float64
+	DFWithin  float64
+	MSBetween float64
+	MSWithin  float64
+	F         float64
+	P         float64
+	EtaSq     float64
+}
+
+// OneWayANOVA membandingkan rata-rata beberapa kelompok
+func OneWayANOVA(groups [][]float64) (ANOVAResult, error) {
+	if len(groups) < 2 {
+		return ANOVAResult{}, errors.New("ANOVA needs at least 2 groups")
+	}
+	var all []float64
+	for _, g := range groups {
+		if len(g) == 0 {
+			return ANOVAResult{}, errors.New("ANOVA groups must not be empty")
+		}
+		all = append(all, g...)
+	}
+	grand := Mean(all)
+
+	var ssb, ssw float64
+	for _, g := range groups {
+		m := Mean(g)
+		ssb += float64(len(g)) * (m - grand) * (m - grand)
+		for _, v := range g {
+			ssw += (v - m) * (v - m)
+		}
+	}
+	dfb := float64(len(groups) - 1)
+	dfw := float64(len(all) - len(groups))
+	if dfw <= 0 {
+		return ANOVAResult{}, errors.New("not enough observations for ANOVA")
+	}
+	msb, msw := ssb/dfb, ssw/dfw
+	f := math.Inf(1)
+	if msw > 0 {
+		f = msb / msw
+	}
+	return ANOVAResult{
+		SSBetween: ssb, SSWithin: ssw,
+		DFBetween: dfb, DFWithin: dfw,
+		MSBetween: msb, MSWithin: msw,
+		F: f, P: FSurvival(f, dfb, dfw),
+		EtaSq: ssb / (ssb + ssw),
+	}, nil
+}
+
+// Ranks memberi peringkat dengan rata-rata untuk nilai kembar; mengembalikan juga faktor koreksi ties (sum t^3 - t)
+func Ranks(x []float64) ([]float64, float64) {
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return x[idx[a]] < x[idx[b]] })
+
+	ranks := make([]float64, len(x))
+	tieSum := 0.0
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && x[idx[j+1]] == x[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[idx[k]] = avg
+		}
+		t := float64(j - i + 1)
+		tieSum += t*t*t - t
+		i = j + 1
+	}
+	return ranks, tieSum
+}
+
+// RankTestResult menyimpan hasil uji berbasis peringkat
+type RankTestResult struct {
+	Statistic float64
+	Z         float64
+	DF        float64
+	P         float64
+	EffectR   float64
+	MeanRanks []float64
+}
+
+// MannWhitneyU membandingkan dua kelompok independen (aproksimasi normal dengan koreksi ties dan kontinuitas)
+func MannWhitneyU(x, y []float64) (RankTestResult, error) {
+	n1, n2 := float64(len(x)), float64(len(y))
+	if n1 < 1 || n2 < 1 {
+		return RankTestResult{}, errors.New("each group needs at least 1 observation")
+	}
+	ranks, tieSum := Ranks(append(append([]float64{}, x...), y...))
+	r1 := Sum(ranks[:len(x)])
+	u1 := r1 - n1*(n1+1)/2
+	u := math.Min(u1, n1*n2-u1)
+
+	n := n1 + n2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - tieSum/(n*(n-1))))
+	if sigma == 0 {
+		return RankTestResult{}, errors.New("all values are tied")
+	}
+	// Tanpa koreksi kontinuitas, sama dengan output SPSS
+	z := (u - n1*n2/2) / sigma
+	return RankTestResult{
+		Statistic: u,
+		Z:         z,
+		P:         TwoTailedNormalP(z),
+		EffectR:   math.Abs(z) / math.Sqrt(n),
+		MeanRanks: []float64{r1 / n1, (Sum(ranks) - r1) / n2},
+	}, nil
+}
+
+// WilcoxonSignedRank menguji selisih pasangan (aproksimasi normal, selisih nol dibuang)
+func WilcoxonSignedRank(x, y []float64) (RankTestResult, error) {
+	if len(x) != len(y) {
+		return RankTestResult{}, errors.New("paired samples must have equal length")
+	}
+	var diffs, abs []float64
+	for i := range x {
+		d := x[i] - y[i]
+		if d != 0 {
+			diffs = append(diffs, d)
+			abs = append(abs, math.Abs(d))
+		}
+	}
+	n := float64(len(diffs))
+	if n < 1 {
+		return RankTestResult{}, errors.New("all paired differences are zero")
+	}
+	ranks, tieSum := Ranks(abs)
+	wPlus := 0.0
+	for i, d := range diffs {
+		if d > 0 {
+			wPlus += ranks[i]
+		}
+	}
+	mean := n * (n + 1) / 4
+	sigma := math.Sqrt(n*(n+1)*(2*n+1)/24 - tieSum/48)
+	if sigma == 0 {
+		return RankTestResult{}, errors.New("all differences are tied")
+	}
+	z := (wPlus - mean) / sigma
+	return RankTestResult{
+		Statistic: wPlus,
+		Z:         z,
+		P:         TwoTailedNormalP(z),
+		EffectR:   math.Abs(z) / math.Sqrt(float64(len(x))),
+	}, nil
+}
+
+// KruskalWallis membandingkan beberapa kelompok independen berbasis peringkat
+func KruskalWallis(groups [][]float64) (RankTestResult, error) {
+	if len(groups) < 2 {
+		return RankTestResult{}, errors.New("Kruskal-Wallis needs at least 2 groups")
+	}
+	var all []float64
+	for _, g := range groups {
+		if len(g) == 0 {
+			return RankTestResult{}, errors.New("groups must not be empty")
+		}
+		all = append(all, g...)
+	}
+	ranks, tieSum := Ranks(all)
+	n := float64(len(all))
+
+	h := 0.0
+	meanRanks := make([]float64, len(groups))
+	offset := 0
+	for i, g := range groups {
+		r := Sum(ranks[offset : offset+len(g)])
+		offset += len(g)
+		meanRanks[i] = r / float64(len(g))
+		h += r * r / float64(len(g))
+	}
+	h = 12/(n*(n+1))*h - 3*(n+1)
+	correction := 1 - tieSum/(n*n*n-n)
+	if correction > 0 {
+		h /= correction
+	}
+	df := float64(len(groups) - 1)
+	return RankTestResult{
+		Statistic: h,
+		DF:        df,
+		P:         ChiSquareSurvival(h, df),
+		EffectR:   h / (n - 1), // epsilon squared
+		MeanRanks: meanRanks,
+	}, nil
+}
+
+// ChiSquareResult menyimpan hasil uji chi-square independensi
+type ChiSquareResult struct {
+	ChiSq          float64
+	DF             float64
+	P              float64
+	CramersV       float64
+	N              int
+	RowLabels      []string
+	ColLabels      []string
+	Observed       [][]float64
+	Expected       [][]float64
+	LowExpectedPct float64
+}
+
+// ChiSquareIndependence menguji independensi dua variabel kategorik dari pasangan nilai
+func ChiSquareIndependence(a, b []string) (ChiSquareResult, error) {
+	if len(a) != len(b) || len(a) == 0 {
+		return ChiSquareResult{}, errors.New("chi-square needs paired categorical observations")
+	}
+	rowIndex, colIndex := map[string]int{}, map[string]int{}
+	var rows, cols []string
+	for i := range a {
+		if _, ok := rowIndex[a[i]]; !ok {
+			rowIndex[a[i]] = len(rows)
+			rows = append(rows, a[i])
+		}
+		if _, ok := colIndex[b[i]]; !ok {
+			colIndex[b[i]] = len(cols)
+			cols = append(cols, b[i])
+		}
+	}
+	if len(rows) < 2 || len(cols) < 2 {
+		return ChiSquareResult{}, errors.New("both variables need at least 2 categories")
+	}
+
+	observed := make([][]float64, len(rows))
+	for i := range observed {
+		observed[i] = make([]float64, len(cols))
+	}
+	for i := range a {
+		observed[rowIndex[a[i]]][colIndex[b[i]]]++
+	}
+
+	n := float64(len(a))
+	rowTotals := make([]float64, len(rows))
+	colTotals := make([]float64, len(cols))
+	for i := range rows {
+		for j := range cols {
+			rowTotals[i] += observed[i][j]
+			colTotals[j] += observed[i][j]
+		}
+	}
+
+	chi, low := 0.0, 0
+	expected := make([][]float64, len(rows))
+	for i := range rows {
+		expected[i] = make([]float64, len(cols))
+		for j := range cols {
+			e := rowTotals[i] * colTotals[j] / n
+			expected[i][j] = e
+			if e < 5 {
+				low++
+			}
+			chi += (observed[i][j] - e) * (observed[i][j] - e) / e
+		}
+	}
+	df := float64((len(rows) - 1) * (len(cols) - 1))
+	k := math.Min(float64(len(rows)), float64(len(cols)))
+	return ChiSquareResult{
+		ChiSq: chi, DF: df, P: ChiSquareSurvival(chi, df),
+		CramersV:       math.Sqrt(chi / (n * (k - 1))),
+		N:              len(a),
+		RowLabels:      rows,
+		ColLabels:      cols,
+		Observed:       observed,
+		Expected:       expected,
+		LowExpectedPct: 100 * float64(low) / float64(len(rows)*len(cols)),
+	}, nil
+}
+
+// CorrelationResult menyimpan hasil uji korelasi
+type CorrelationResult struct {
+	R  float64
+	T  float64
+	DF float64
+	P  float64
+	N  int
+}
+
+// Pearson menghitung korelasi Pearson beserta uji signifikansinya
+func Pearson(x, y []float64) (CorrelationResult, error) {
+	if len(x) != len(y) || len(x) < 3 {
+		return CorrelationResult{}, errors.New("correlation needs at least 3 paired observations")
+	}
+	mx, my := Mean(x), Mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		sxy += (x[i] - mx) * (y[i] - my)
+		sxx += (x[i] - mx) * (x[i] - mx)
+		syy += (y[i] - my) * (y[i] - my)
+	}
+	if sxx == 0 || syy == 0 {
+		return CorrelationResult{}, errors.New("variable has zero variance")
+	}
+	r := math.Max(-1, math.Min(1, sxy/math.Sqrt(sxx*syy)))
+	df := float64(len(x) - 2)
+	t := r * math.Sqrt(df/math.Max(1e-300, 1-r*r))
+	return CorrelationResult{R: r, T: t, DF: df, P: TwoTailedTP(t, df), N: len(x)}, nil
+}
+
+// Spearman menghitung korelasi peringkat Spearman (Pearson atas peringkat)
+func Spearman(x, y []float64) (CorrelationResult, error) {
+	rx, _ := Ranks(x)
+	ry, _ := Ranks(y)
+	return Pearson(rx, ry)
+}
+
+// CronbachResult menyimpan hasil uji reliabilitas
+type CronbachResult struct {
+	Alpha          float64
+	ItemTotal      []float64
+	AlphaIfDeleted []float64
+	N              int
+}
+
+// CronbachAlpha menghitung reliabilitas konsistensi internal dari item-item (kolom)
+func CronbachAlpha(items [][]float64) (CronbachResult, error) {
+	k := len(items)
+	if k < 2 {
+		return CronbachResult{}, errors.New("reliability needs at least 2 items")
+	}
+	n := len(items[0])
+	if n < 2 {
+		return CronbachResult{}, errors.New("reliability needs at least 2 respondents")
+	}
+
+	alpha := func(cols [][]float64) float64 {
+		totals := make([]float64, n)
+		itemVar := 0.0
+		for _, col := range cols {
+			itemVar += Variance(col)
+			for i, v := range col {
+				totals[i] += v
+			}
+		}
+		kk := float64(len(cols))
+		return kk / (kk - 1) * (1 - itemVar/Variance(totals))
+	}
+
+	result := CronbachResult{Alpha: alpha(items), N: n}
+	for i := range items {
+		rest := make([]float64, n)
+		var others [][]float64
+		for j, col := range items {
+			if j == i {
+				continue
+			}
+			others = append(others, col)
+			for r, v := range col {
+				rest[r] += v
+			}
+		}
+		corr, err := Pearson(items[i], rest)
+		if err != nil {
+			corr.R = math.NaN()
+		}
+		result.ItemTotal = append(result.ItemTotal, corr.R)
+		if len(others) >= 2 {
+			result.AlphaIfDeleted = append(result.AlphaIfDeleted, alpha(others))
+		} else {
+			result.AlphaIfDeleted = append(result.AlphaIfDeleted, math.NaN())
+		}
+	}
+	if math.IsNaN(result.Alpha) || math.IsInf(result.Alpha, 0) {
+		return CronbachResult{}, fmt.Errorf("reliability is undefined for items with zero total variance")
+	}
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLeveneTest(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups [][]float64
+		wantF  float64
+		wantP  float64
+	}{
+		// Simpangan dari rata-rata: {2,1,0,1,2} dan {4,2,0,2,4}; F = 3.6 / (14 / 8)
+		{"unequal spread", [][]float64{{1, 2, 3, 4, 5}, {2, 4, 6, 8, 10}}, 2.0571428571, 0.1894},
+		// Rata-rata simpangan sama (2.4) bila berpusat pada mean; berpusat pada median F > 0
+		{"mean-centred", [][]float64{{1, 2, 3, 4, 10}, {2, 4, 6, 8, 10}}, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := LeveneTest(tt.groups)
+			if err != nil {
+				t.Fatalf("LeveneTest: %v", err)
+			}
+			if math.Abs(res.F-tt.wantF) > 1e-6 {
+				t.Errorf("F = %.6f, want %.6f", res.F, tt.wantF)
+			}
+			if math.Abs(res.P-tt.wantP) > 1e-3 {
+				t.Errorf("p = %.4f, want %.4f", res.P, tt.wantP)
+			}
+			if res.DF1 != 1 || res.DF2 != 8 {
+				t.Errorf("df = (%v, %v), want (1, 8)", res.DF1, res.DF2)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// gcsBackend menyimpan objek di Google Cloud Storage dengan satu client bersama
+type gcsBackend struct {
+	bucket string
+
+	once   sync.Once
+	client *storage.Client
+	err    error
+}
+
+func newGCSBackend(bucket string) *gcsBackend {
+	return &gcsBackend{bucket: bucket}
+}
+
+// handle membuat client sekali (lazy) agar server tetap bisa start tanpa kredensial GCP
+func (g *gcsBackend) handle() (*storage.BucketHandle, error) {
+	g.once.Do(func() {
+		g.client, g.err = storage.NewClient(context.Background())
+	})
+	if g.err != nil {
+		return nil, fmt.Errorf("storage.NewClient: %v", g.err)
+	}
+	return g.client.Bucket(g.bucket), nil
+}
+
+func (g *gcsBackend) Put(ctx context.Context, name string, data io.Reader, contentType string) error {
+	bucket, err := g.handle()
+	if err != nil {
+		return err
+	}
+
+	wc := bucket.Object(name).NewWriter(ctx)
+	wc.ContentType = contentType
+
+	if _, err := io.Copy(wc, data); err != nil {
+		wc.Close()
+		return fmt.Errorf("io.Copy: %v", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("Writer.Close: %v", err)
+	}
+	return nil
+}
+
+func (g *gcsBackend) Get(ctx context.Context, name string) ([]byte, error) {
+	bucket, err := g.handle()
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := bucket.Object(name).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Object.NewReader: %v", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %v", err)
+	}
+	return data, nil
+}
+
+func (g *gcsBackend) Delete(ctx context.Context, name string) error {
+	bucket, err := g.handle()
+	if err != nil {
+		return err
+	}
+
+	err = bucket.Object(name).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("Object.Delete: %v", err)
+	}
+	return nil
+}
+
+func (g *gcsBackend) SignedURL(ctx context.Context, name string, expiration time.Duration) (string, error) {
+	bucket, err := g.handle()
+	if err != nil {
+		return "", err
+	}
+
+	opts := &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  "GET",
+		Expires: time.Now().Add(expiration),
+	}
+	url, err := bucket.SignedURL(name, opts)
+	if err != nil {
+		return "", fmt.Errorf("Bucket.SignedURL: %v", err)
+	}
+	return url, nil
+}
+
+func (g *gcsBackend) List(ctx context.Context, prefix string) ([]Object, error) {
+	bucket, err := g.handle()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	it := bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Bucket.Objects: %v", err)
+		}
+		objects = append(objects, Object{Name: attrs.Name, Size: attrs.Size, Updated: attrs.Updated})
+	}
+	return objects, nil
+}
+
+// URL mempertahankan format URL publik lama (tanpa escape) agar data yang sudah tersimpan tetap cocok
+func (g *gcsBackend) URL(name string) string {
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", g.bucket, name)
+}
+
+func (g *gcsBackend) Name(fileURL string) (string, bool) {
+	prefix := g.URL("")
+	if !strings.HasPrefix(fileURL, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(fileURL, prefix), true
+}
@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localBackend menyimpan objek sebagai file di disk; dipakai untuk menjalankan stack secara lokal
+type localBackend struct {
+	root    string
+	baseURL string
+	key     []byte
+}
+
+func newLocalBackend(root, baseURL, signingKey string) (*localBackend, error) {
+	if root == "" {
+		return nil, errors.New("storage: STORAGE_LOCAL_PATH is empty")
+	}
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %v", err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: %v", err)
+	}
+	return &localBackend{
+		root:    abs,
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/",
+		key:     []byte(signingKey),
+	}, nil
+}
+
+// path memetakan nama objek ke path file di bawah root
+func (l *localBackend) path(name string) (string, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(name)), nil
+}
+
+func (l *localBackend) Put(ctx context.Context, name string, data io.Reader, contentType string) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("os.MkdirAll: %v", err)
+	}
+
+	// Tulis ke file sementara lalu rename agar pembaca tidak melihat file setengah jadi
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %v", err)
+	}
+	if _, err := io.Copy(tmp, data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("io.Copy: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("File.Close: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("os.Rename: %v", err)
+	}
+	return nil
+}
+
+func (l *localBackend) Get(ctx context.Context, name string) ([]byte, error) {
+	path, err := l.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %v", err)
+	}
+	return data, nil
+}
+
+func (l *localBackend) Delete(ctx context.Context, name string) error {
+	path, err := l.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("os.Remove: %v", err)
+	}
+	return nil
+}
+
+// SignedURL menghasilkan URL endpoint /files dengan tanda tangan HMAC dan waktu kedaluwarsa
+func (l *localBackend) SignedURL(ctx context.Context, name string, expiration time.Duration) (string, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(expiration).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", l.sign(name, expires))
+	return l.URL(name) + "?" + q.Encode(), nil
+}
+
+func (l *localBackend) sign(name, expires string) string {
+	mac := hmac.New(sha256.New, l.key)
+	mac.Write([]byte(name + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *localBackend) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	err := filepath.WalkDir(l.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, Object{Name: name, Size: info.Size(), Updated: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("filepath.WalkDir: %v", err)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	return objects, nil
+}
+
+func (l *localBackend) URL(name string) string {
+	return l.baseURL + escapePath(name)
+}
+
+func (l *localBackend) Name(fileURL string) (string, bool) {
+	return trimURL(fileURL, l.baseURL)
+}
+
+// serve mengirim file jika tanda tangan valid dan belum kedaluwarsa
+func (l *localBackend) serve(w http.ResponseWriter, r *http.Request, name string) (int, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+
+	expires := r.URL.Query().Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return http.StatusForbidden, errors.New("Signed URL expired or invalid")
+	}
+	if !hmac.Equal([]byte(r.URL.Query().Get("signature")), []byte(l.sign(name, expires))) {
+		return http.StatusForbidden, errors.New("Invalid signature")
+	}
+
+	data, err := l.Get(r.Context(), name)
+	if errors.Is(err, ErrNotFound) {
+		return http.StatusNotFound, errors.New("File not found")
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	info, _ := os.Stat(filepath.Join(l.root, filepath.FromSlash(name)))
+	modTime := time.Time{}
+	if info != nil {
+		modTime = info.ModTime()
+	}
+	http.ServeContent(w, r, filepath.Base(name), modTime, bytes.NewReader(data))
+	return http.StatusOK, nil
+}
+
+// ServeLocal melayani signed URL backend local; backend lain mengembalikan 404
+func ServeLocal(w http.ResponseWriter, r *http.Request, name string) (int, error) {
+	b, err := Default()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	l, ok := b.(*localBackend)
+	if !ok {
+		return http.StatusNotFound, errors.New("File serving is only available for local storage")
+	}
+	return l.serve(w, r, name)
+}
@@ -2,96 +2,171 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	"cloud.google.com/go/storage"
 	"github.com/research-data-analysis/config"
 )
 
-// UploadFile mengupload file ke Google Cloud Storage
-func UploadFile(ctx context.Context, fileName string, data io.Reader, contentType string) (string, error) {
-	client, err := storage.NewClient(ctx)
-	if err != nil {
-		return "", fmt.Errorf("storage.NewClient: %v", err)
-	}
-	defer client.Close()
+// ErrNotFound dikembalikan backend jika objek tidak ada
+var ErrNotFound = errors.New("storage: object not found")
 
-	bucket := client.Bucket(config.GetGCSBucket())
-	obj := bucket.Object(fileName)
+// Object adalah metadata satu objek di storage
+type Object struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	Updated time.Time `json:"updated"`
+}
 
-	wc := obj.NewWriter(ctx)
-	wc.ContentType = contentType
+// Backend adalah penyimpanan objek untuk file upload, gambar chart, dan laporan
+type Backend interface {
+	// Put menyimpan objek, menimpa objek lama dengan nama yang sama
+	Put(ctx context.Context, name string, data io.Reader, contentType string) error
+	// Get membaca seluruh isi objek
+	Get(ctx context.Context, name string) ([]byte, error)
+	// Delete menghapus objek
+	Delete(ctx context.Context, name string) error
+	// SignedURL menghasilkan URL akses sementara
+	SignedURL(ctx context.Context, name string, expiration time.Duration) (string, error)
+	// List mengembalikan objek dengan awalan nama tertentu
+	List(ctx context.Context, prefix string) ([]Object, error)
+	// URL adalah URL tetap objek yang disimpan di database
+	URL(name string) string
+	// Name adalah kebalikan URL; ok false jika URL bukan milik backend ini
+	Name(fileURL string) (string, bool)
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultBackend Backend
+	defaultErr     error
+)
+
+// Default mengembalikan backend sesuai konfigurasi; instance (dan client-nya) dibuat sekali lalu dipakai bersama
+func Default() (Backend, error) {
+	defaultOnce.Do(func() {
+		defaultBackend, defaultErr = New(config.GetConfig().Storage)
+	})
+	return defaultBackend, defaultErr
+}
 
-	if _, err := io.Copy(wc, data); err != nil {
-		return "", fmt.Errorf("io.Copy: %v", err)
+// New membuat backend dari konfigurasi storage
+func New(cfg *config.StorageConfig) (Backend, error) {
+	if cfg == nil {
+		return nil, errors.New("storage: configuration is missing")
 	}
-	if err := wc.Close(); err != nil {
-		return "", fmt.Errorf("Writer.Close: %v", err)
+	switch cfg.Backend {
+	case "gcs":
+		return newGCSBackend(config.GetGCSBucket()), nil
+	case "local":
+		return newLocalBackend(cfg.LocalPath, cfg.PublicURL, cfg.SigningKey)
+	case "s3":
+		return newS3Backend(cfg)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
 	}
+}
 
-	// Return public URL
-	publicURL := fmt.Sprintf("https://storage.googleapis.com/%s/%s", config.GetGCSBucket(), fileName)
-	return publicURL, nil
+// UploadFile menyimpan file ke backend storage dan mengembalikan URL-nya
+func UploadFile(ctx context.Context, fileName string, data io.Reader, contentType string) (string, error) {
+	b, err := Default()
+	if err != nil {
+		return "", err
+	}
+	if err := b.Put(ctx, fileName, data, contentType); err != nil {
+		return "", err
+	}
+	return b.URL(fileName), nil
 }
 
 // GetSignedURL menghasilkan signed URL untuk akses sementara
 func GetSignedURL(ctx context.Context, fileName string, expiration time.Duration) (string, error) {
-	client, err := storage.NewClient(ctx)
+	b, err := Default()
 	if err != nil {
-		return "", fmt.Errorf("storage.NewClient: %v", err)
+		return "", err
 	}
-	defer client.Close()
+	return b.SignedURL(ctx, fileName, expiration)
+}
 
-	opts := &storage.SignedURLOptions{
-		Scheme:  storage.SigningSchemeV4,
-		Method:  "GET",
-		Expires: time.Now().Add(expiration),
+// DeleteFile menghapus file dari backend storage
+func DeleteFile(ctx context.Context, fileName string) error {
+	b, err := Default()
+	if err != nil {
+		return err
 	}
+	return b.Delete(ctx, fileName)
+}
 
-	url, err := client.Bucket(config.GetGCSBucket()).SignedURL(fileName, opts)
+// DownloadFile mengunduh file dari backend storage
+func DownloadFile(ctx context.Context, fileName string) ([]byte, error) {
+	b, err := Default()
 	if err != nil {
-		return "", fmt.Errorf("Bucket.SignedURL: %v", err)
+		return nil, err
 	}
-
-	return url, nil
+	return b.Get(ctx, fileName)
 }
 
-// DeleteFile menghapus file dari GCS
-func DeleteFile(ctx context.Context, fileName string) error {
-	client, err := storage.NewClient(ctx)
+// ListFiles mengembalikan file dengan awalan nama tertentu
+func ListFiles(ctx context.Context, prefix string) ([]Object, error) {
+	b, err := Default()
 	if err != nil {
-		return fmt.Errorf("storage.NewClient: %v", err)
+		return nil, err
 	}
-	defer client.Close()
+	return b.List(ctx, prefix)
+}
 
-	obj := client.Bucket(config.GetGCSBucket()).Object(fileName)
-	if err := obj.Delete(ctx); err != nil {
-		return fmt.Errorf("Object.Delete: %v", err)
+// ObjectName mengambil nama objek dari URL hasil UploadFile; nilai selain URL backend dikembalikan apa adanya
+func ObjectName(fileURL string) string {
+	b, err := Default()
+	if err != nil {
+		return fileURL
 	}
-
-	return nil
+	if name, ok := b.Name(fileURL); ok {
+		return name
+	}
+	return fileURL
 }
 
-// DownloadFile mengunduh file dari GCS
-func DownloadFile(ctx context.Context, fileName string) ([]byte, error) {
-	client, err := storage.NewClient(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("storage.NewClient: %v", err)
+// cleanName menolak nama objek kosong, absolut, atau yang keluar dari root (..)
+func cleanName(name string) (string, error) {
+	name = strings.TrimPrefix(strings.ReplaceAll(name, "\\", "/"), "/")
+	if name == "" {
+		return "", errors.New("storage: object name is empty")
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." || part == "." {
+			return "", fmt.Errorf("storage: invalid object name %q", name)
+		}
 	}
-	defer client.Close()
+	return name, nil
+}
 
-	rc, err := client.Bucket(config.GetGCSBucket()).Object(fileName).NewReader(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("Object.NewReader: %v", err)
+// escapePath meng-escape setiap segmen nama objek tanpa mengubah pemisah "/"
+func escapePath(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
 	}
-	defer rc.Close()
+	return strings.Join(parts, "/")
+}
 
-	data, err := io.ReadAll(rc)
+// trimURL memotong base dari URL lalu meng-unescape sisanya
+func trimURL(fileURL, base string) (string, bool) {
+	if base == "" || !strings.HasPrefix(fileURL, base) {
+		return "", false
+	}
+	rest := strings.TrimPrefix(fileURL, base)
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	name, err := url.PathUnescape(rest)
 	if err != nil {
-		return nil, fmt.Errorf("io.ReadAll: %v", err)
+		return rest, true
 	}
-
-	return data, nil
+	return name, true
 }
@@ -0,0 +1,125 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Secret SHA1 dari Appendix B RFC 6238 ("12345678901234567890") dalam base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Vektor RFC 6238 memakai 8 digit; code 6 digit adalah 6 digit terakhirnya
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestGenerateRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	for _, tt := range rfcVectors {
+		if got := generate(key, tt.unix/period); got != tt.code {
+			t.Errorf("generate(T=%d) = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestValidateRFC6238(t *testing.T) {
+	for _, tt := range rfcVectors {
+		step, ok := Validate(rfcSecret, tt.code, time.Unix(tt.unix, 0), 0)
+		if !ok || step != tt.unix/period {
+			t.Errorf("Validate(T=%d) = %d, %v; want %d, true", tt.unix, step, ok, tt.unix/period)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Unix(1111111109, 0)
+	current := now.Unix() / period
+	code := generate(key, current)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		offset   time.Duration
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfcSecret, code, 0, 0, current, true},
+		{"lowercase secret", strings.ToLower(rfcSecret), code, 0, 0, current, true},
+		{"code with spaces", rfcSecret, code[:3] + " " + code[3:], 0, 0, current, true},
+		{"clock one step ahead", rfcSecret, code, period * time.Second, 0, current, true},
+		{"clock one step behind", rfcSecret, code, -period * time.Second, 0, current, true},
+		{"clock two steps ahead", rfcSecret, code, 2 * period * time.Second, 0, 0, false},
+		{"clock two steps behind", rfcSecret, code, -2 * period * time.Second, 0, 0, false},
+		{"replay of last step", rfcSecret, code, 0, current, 0, false},
+		{"replay of older step", rfcSecret, code, 0, current + 1, 0, false},
+		{"step after last step", rfcSecret, code, 0, current - 1, current, true},
+		{"wrong code", rfcSecret, "000000", 0, 0, 0, false},
+		{"too short", rfcSecret, code[:5], 0, 0, 0, false},
+		{"too long", rfcSecret, code + "0", 0, 0, 0, false},
+		{"empty code", rfcSecret, "", 0, 0, 0, false},
+		{"invalid secret", "not-base32!", code, 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now.Add(tt.offset), tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate = %d, %v; want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateSecret()
+	if a == b {
+		t.Errorf("GenerateSecret returned the same secret twice: %s", a)
+	}
+	key, err := encoding.DecodeString(a)
+	if err != nil || len(key) != secretLength {
+		t.Errorf("GenerateSecret = %q: decoded %d bytes, err %v; want %d bytes", a, len(key), err, secretLength)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Research Data", "user@example.com", rfcSecret)
+	u, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("url.Parse(%q): %v", uri, err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Errorf("URI scheme/host = %s/%s, want otpauth/totp", u.Scheme, u.Host)
+	}
+	if u.Path != "/Research Data:user@example.com" {
+		t.Errorf("URI label = %q", u.Path)
+	}
+	want := map[string]string{
+		"secret":    rfcSecret,
+		"issuer":    "Research Data",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	}
+	q := u.Query()
+	for k, v := range want {
+		if got := q.Get(k); got != v {
+			t.Errorf("URI %s = %q, want %q", k, got, v)
+		}
+	}
+}
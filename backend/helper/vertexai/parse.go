@@ -0,0 +1,261 @@
+package vertexai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/research-data-analysis/model"
+)
+
+var (
+	codeFencePattern     = regexp.MustCompile("(?s)```[a-zA-Z]*\\s*(.*?)```")
+	trailingCommaPattern = regexp.MustCompile(`,\s*([}\]])`)
+	lineCommentPattern   = regexp.MustCompile(`(?m)^\s*//.*$`)
+)
+
+// ExtractJSON mengambil blok JSON dari teks respons model (menghapus markdown fence dan teks pengantar)
+func ExtractJSON(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if match := codeFencePattern.FindStringSubmatch(text); match != nil {
+		text = strings.TrimSpace(match[1])
+	}
+
+	start := strings.IndexAny(text, "{[")
+	if start < 0 {
+		return "", errors.New("no JSON object found in model response")
+	}
+
+	if end := matchingBracket(text, start); end > 0 {
+		return text[start : end+1], nil
+	}
+	// Respons terpotong, biarkan RepairJSON menutup kurung yang hilang
+	return text[start:], nil
+}
+
+// matchingBracket mencari posisi kurung penutup pasangan dari kurung di posisi start, -1 jika tidak ada
+func matchingBracket(s string, start int) int {
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// RepairJSON memperbaiki kesalahan sintaks kecil yang sering muncul pada output model
+func RepairJSON(s string) string {
+	replacer := strings.NewReplacer(
+		"“", `"`, "”", `"`,
+		"‘", "'", "’", "'",
+		" ", " ",
+	)
+	s = replacer.Replace(s)
+	s = lineCommentPattern.ReplaceAllString(s, "")
+	s = trailingCommaPattern.ReplaceAllString(s, "$1")
+
+	// Tutup string dan kurung yang belum ditutup (respons terpotong)
+	var stack []byte
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if inString {
+		s += `"`
+	}
+	s = strings.TrimRight(strings.TrimSpace(s), ",")
+	for i := len(stack) - 1; i >= 0; i-- {
+		s += string(stack[i])
+	}
+	return trailingCommaPattern.ReplaceAllString(s, "$1")
+}
+
+// DecodeJSON mengekstrak, memperbaiki, lalu mendekode JSON dari respons model ke target
+func DecodeJSON(text string, target interface{}) error {
+	raw, err := ExtractJSON(text)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(raw), target); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(RepairJSON(raw)), target); err != nil {
+		return fmt.Errorf("invalid JSON in model response: %v", err)
+	}
+	return nil
+}
+
+// rawRecommendation menampung rekomendasi dengan tipe longgar sebelum divalidasi
+type rawRecommendation struct {
+	Method      string      `json:"method"`
+	Category    string      `json:"category"`
+	Reasoning   string      `json:"reasoning"`
+	Priority    interface{} `json:"priority"`
+	Assumptions interface{} `json:"assumptions"`
+}
+
+// ParseRecommendations mengubah respons model menjadi daftar rekomendasi yang tervalidasi skemanya
+func ParseRecommendations(text string) ([]model.Recommendation, error) {
+	var wrapped struct {
+		Recommendations []rawRecommendation `json:"recommendations"`
+	}
+	var items []rawRecommendation
+
+	if err := DecodeJSON(text, &wrapped); err == nil && len(wrapped.Recommendations) > 0 {
+		items = wrapped.Recommendations
+	} else if err := DecodeJSON(text, &items); err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("response contains no recommendations")
+	}
+
+	var problems []string
+	recommendations := make([]model.Recommendation, 0, len(items))
+	for i, item := range items {
+		rec := model.Recommendation{
+			Method:      strings.TrimSpace(item.Method),
+			Category:    strings.ToLower(strings.TrimSpace(item.Category)),
+			Reasoning:   strings.TrimSpace(item.Reasoning),
+			Priority:    parsePriority(item.Priority),
+			Assumptions: flattenText(item.Assumptions),
+		}
+		if rec.Method == "" {
+			problems = append(problems, fmt.Sprintf("recommendation %d: field \"method\" is empty", i+1))
+			continue
+		}
+		if rec.Reasoning == "" {
+			problems = append(problems, fmt.Sprintf("recommendation %d (%s): field \"reasoning\" is empty", i+1, rec.Method))
+			continue
+		}
+		if rec.Priority <= 0 {
+			rec.Priority = i + 1
+		}
+		recommendations = append(recommendations, rec)
+	}
+
+	if len(recommendations) == 0 {
+		return nil, fmt.Errorf("no valid recommendations: %s", strings.Join(problems, "; "))
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Priority < recommendations[j].Priority
+	})
+	return recommendations, nil
+}
+
+// parsePriority menerima prioritas berupa angka atau string
+func parsePriority(v interface{}) int {
+	switch p := v.(type) {
+	case float64:
+		return int(p)
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// flattenText menerima string atau array string dan menggabungkannya
+func flattenText(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strings.TrimSpace(t)
+	case []interface{}:
+		parts := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				parts = append(parts, strings.TrimSpace(s))
+			}
+		}
+		return strings.Join(parts, "; ")
+	}
+	return ""
+}
+
+// Interpretation adalah interpretasi terstruktur satu hasil analisis
+type Interpretation struct {
+	Interpretation        string `json:"interpretation"`
+	EffectSize            string `json:"effect_size"`
+	PracticalImplications string `json:"practical_implications"`
+	Conclusion            string `json:"conclusion"`
+}
+
+// ParseInterpretation mengubah respons model menjadi Interpretation
+func ParseInterpretation(text string) (Interpretation, error) {
+	var raw struct {
+		Interpretation        interface{} `json:"interpretation"`
+		EffectSize            interface{} `json:"effect_size"`
+		PracticalImplications interface{} `json:"practical_implications"`
+		Conclusion            interface{} `json:"conclusion"`
+	}
+	if err := DecodeJSON(text, &raw); err != nil {
+		return Interpretation{}, err
+	}
+
+	interp := Interpretation{
+		Interpretation:        flattenText(raw.Interpretation),
+		EffectSize:            flattenText(raw.EffectSize),
+		PracticalImplications: flattenText(raw.PracticalImplications),
+		Conclusion:            flattenText(raw.Conclusion),
+	}
+	if interp.Interpretation == "" {
+		return interp, errors.New("field \"interpretation\" is empty")
+	}
+	if interp.Conclusion == "" {
+		return interp, errors.New("field \"conclusion\" is empty")
+	}
+	return interp, nil
+}
@@ -0,0 +1,155 @@
+package vertexai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"plain object", `{"a": 1}`, `{"a": 1}`, false},
+		{"json fence", "```json\n{\"a\": 1}\n```", `{"a": 1}`, false},
+		{"bare fence with prose", "Here you go:\n```\n[1, 2]\n```\nThanks", `[1, 2]`, false},
+		{"prose around object", `Sure! {"a": {"b": "}"}} Hope this helps.`, `{"a": {"b": "}"}}`, false},
+		{"truncated", `{"a": [1, 2`, `{"a": [1, 2`, false},
+		{"no json", "I cannot help with that.", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractJSON(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExtractJSON error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExtractJSON = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"trailing commas", `{"a": [1, 2,], "b": 3,}`, `{"a": [1, 2], "b": 3}`},
+		{"smart quotes", `{“a”: “b”}`, `{"a": "b"}`},
+		{"line comment", "{\n// note\n\"a\": 1\n}", "{\n\n\"a\": 1\n}"},
+		{"unclosed string and brackets", `{"a": [{"b": "c`, `{"a": [{"b": "c"}]}`},
+		{"truncated after comma", `{"a": 1,`, `{"a": 1}`},
+		{"brace inside string", `{"a": "}{"`, `{"a": "}{"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RepairJSON(tt.in)
+			if got != tt.want {
+				t.Errorf("RepairJSON = %q, want %q", got, tt.want)
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("RepairJSON produced invalid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestParseRecommendations(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantMethods []string
+		wantErr     bool
+	}{
+		{
+			name:        "wrapped object",
+			text:        `{"recommendations": [{"method": "t-test", "reasoning": "two groups", "priority": 1}]}`,
+			wantMethods: []string{"t-test"},
+		},
+		{
+			name:        "bare array sorted by priority",
+			text:        `[{"method": "B", "reasoning": "r", "priority": "2"}, {"method": "A", "reasoning": "r", "priority": 1}]`,
+			wantMethods: []string{"A", "B"},
+		},
+		{
+			name:        "fenced with trailing comma",
+			text:        "```json\n{\"recommendations\": [{\"method\": \"ANOVA\", \"reasoning\": \"three groups\", \"assumptions\": [\"normality\", \"equal variances\"],},]}\n```",
+			wantMethods: []string{"ANOVA"},
+		},
+		{
+			name:        "truncated response",
+			text:        `{"recommendations": [{"method": "Pearson", "reasoning": "two metric variables"}, {"method": "Spear`,
+			wantMethods: []string{"Pearson"},
+		},
+		{
+			name:        "invalid entries skipped",
+			text:        `[{"method": "", "reasoning": "r"}, {"method": "Chi-Square"}, {"method": "Mann-Whitney", "reasoning": "ordinal"}]`,
+			wantMethods: []string{"Mann-Whitney"},
+		},
+		{name: "all entries invalid", text: `[{"method": "X"}]`, wantErr: true},
+		{name: "empty list", text: `{"recommendations": []}`, wantErr: true},
+		{name: "not json", text: "Use a t-test.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, err := ParseRecommendations(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRecommendations error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(recs) != len(tt.wantMethods) {
+				t.Fatalf("ParseRecommendations = %+v, want methods %v", recs, tt.wantMethods)
+			}
+			for i, rec := range recs {
+				if rec.Method != tt.wantMethods[i] {
+					t.Errorf("recommendation %d method = %q, want %q", i, rec.Method, tt.wantMethods[i])
+				}
+				if rec.Priority <= 0 {
+					t.Errorf("recommendation %d priority = %d, want positive", i, rec.Priority)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInterpretation(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    Interpretation
+		wantErr bool
+	}{
+		{
+			name: "fenced object",
+			text: "```json\n{\"interpretation\": \"t(38) = 2.35\", \"effect_size\": \"d = 0.74\", \"conclusion\": \"Significant.\"}\n```",
+			want: Interpretation{Interpretation: "t(38) = 2.35", EffectSize: "d = 0.74", Conclusion: "Significant."},
+		},
+		{
+			name: "array fields flattened",
+			text: `{"interpretation": ["First.", "Second."], "practical_implications": ["Use it."], "conclusion": "Done."}`,
+			want: Interpretation{Interpretation: "First.; Second.", PracticalImplications: "Use it.", Conclusion: "Done."},
+		},
+		{
+			name: "smart quotes and trailing comma",
+			text: `{“interpretation”: “Text.”, “conclusion”: “End.”,}`,
+			want: Interpretation{Interpretation: "Text.", Conclusion: "End."},
+		},
+		{name: "missing conclusion", text: `{"interpretation": "Text."}`, wantErr: true},
+		{name: "missing interpretation", text: `{"conclusion": "End."}`, wantErr: true},
+		{name: "not json", text: "The result is significant.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseInterpretation(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInterpretation error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseInterpretation = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
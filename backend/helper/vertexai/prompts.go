@@ -0,0 +1,61 @@
+package vertexai
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/research-data-analysis/helper/i18n"
+)
+
+// promptFiles berisi template prompt per bahasa: prompts/<lang>/<name>.tmpl
+//
+//go:embed prompts/*/*.tmpl
+var promptFiles embed.FS
+
+var (
+	promptSetsOnce sync.Once
+	promptSets     map[string]*template.Template
+	promptSetsErr  error
+)
+
+var promptFuncs = template.FuncMap{
+	// bullets menulis daftar sebagai baris "- item"
+	"bullets": func(items []string) string {
+		return "- " + strings.Join(items, "\n- ")
+	},
+	"trim": strings.TrimSpace,
+}
+
+// loadPromptSets mem-parse seluruh template prompt untuk setiap bahasa yang didukung
+func loadPromptSets() (map[string]*template.Template, error) {
+	promptSetsOnce.Do(func() {
+		promptSets = make(map[string]*template.Template)
+		for _, lang := range i18n.Supported() {
+			set, err := template.New(lang).Funcs(promptFuncs).ParseFS(promptFiles, "prompts/"+lang+"/*.tmpl")
+			if err != nil {
+				promptSetsErr = fmt.Errorf("failed to parse %s prompt templates: %v", lang, err)
+				return
+			}
+			promptSets[lang] = set
+		}
+	})
+	return promptSets, promptSetsErr
+}
+
+// renderPrompt merender template prompt name (tanpa ekstensi) dalam bahasa lang
+func renderPrompt(lang, name string, data interface{}) (string, error) {
+	sets, err := loadPromptSets()
+	if err != nil {
+		return "", err
+	}
+	set := sets[i18n.Resolve(lang)]
+	var buf bytes.Buffer
+	if err := set.ExecuteTemplate(&buf, name+".tmpl", data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %v", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,533 @@
+package vertexai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/research-data-analysis/helper/stats"
+	"github.com/research-data-analysis/model"
+)
+
+var (
+	// numberPattern menangkap angka termasuk format desimal koma dan gaya APA tanpa nol (".045")
+	numberPattern = regexp.MustCompile(`(?:\d+(?:[.,]\d+)*|[.,]\d+)`)
+	// pClaimPattern menangkap klaim nilai p, mis. "p < .05", "p-value = 0,012", "nilai p ≤ 0.001"
+	pClaimPattern = regexp.MustCompile(`(?i)\bp(?:[- ]?value)?\s*(<=|>=|≤|≥|<|>|=)\s*((?:\d+(?:[.,]\d+)?|[.,]\d+))`)
+	// negativeSignificance menangkap penyangkalan signifikansi, mis. "tidak terdapat perbedaan yang signifikan"
+	negativeSignificance = regexp.MustCompile(`(?i)\b(?:(?:tidak|tak|bukan|not|no|non|kurang)\b[\s-]+(?:\S+\s+){0,3}?(?:signifikan|significant)|(?:in|non-?)signifi\w*)`)
+	positiveSignificance = regexp.MustCompile(`(?i)signifikan|significant`)
+)
+
+// GenerateAnalysisInterpretation meminta interpretasi (dalam bahasa lang) yang didasarkan pada output statistik hasil komputasi
+func GenerateAnalysisInterpretation(lang, method string, rawOutput map[string]interface{}, context string) (string, error) {
+	results, err := json.MarshalIndent(rawOutput, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode analysis output: %v", err)
+	}
+
+	prompt, err := renderPrompt(lang, "interpretation", map[string]interface{}{
+		"Context": context,
+		"Method":  method,
+		"Results": string(results),
+	})
+	if err != nil {
+		return "", err
+	}
+	return GenerateContent(prompt)
+}
+
+// GenerateVerifiedInterpretation menghasilkan interpretasi lalu memeriksa setiap angka dan klaim signifikansi
+// terhadap output statistik. Jika ditemukan angka yang tidak ada di output, model diminta memperbaiki
+// jawabannya hingga maxAttempts kali; bila tetap gagal, interpretasi dengan masalah paling sedikit
+// dikembalikan dengan Verification.Verified = false dan daftar masalahnya.
+func GenerateVerifiedInterpretation(lang, method string, rawOutput map[string]interface{}, context string, maxAttempts int) (Interpretation, model.Verification, error) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	raw, err := GenerateAnalysisInterpretation(lang, method, rawOutput, context)
+	if err != nil {
+		return Interpretation{}, model.Verification{}, err
+	}
+
+	var best Interpretation
+	var bestIssues []string
+	parsed := false
+	for attempt := 1; ; attempt++ {
+		interp, err := ParseInterpretation(raw)
+		var issues []string
+		if err != nil {
+			issues = []string{err.Error()}
+		} else {
+			issues = VerifyInterpretation(interp, rawOutput, context)
+			if !parsed || len(issues) < len(bestIssues) {
+				best, bestIssues, parsed = interp, issues, true
+			}
+		}
+
+		if err == nil && len(issues) == 0 {
+			return interp, model.Verification{Verified: true, Attempts: attempt}, nil
+		}
+		if attempt >= maxAttempts {
+			if !parsed {
+				return Interpretation{}, model.Verification{Attempts: attempt, Issues: issues}, fmt.Errorf("invalid interpretation after %d attempts: %s", attempt, strings.Join(issues, "; "))
+			}
+			return best, model.Verification{Attempts: attempt, Issues: bestIssues}, nil
+		}
+
+		corrective, err := renderPrompt(lang, "interpretation_corrective", map[string]interface{}{
+			"Issues":   issues,
+			"Previous": raw,
+		})
+		if err != nil {
+			return Interpretation{}, model.Verification{}, err
+		}
+
+		raw, err = GenerateContent(corrective)
+		if err != nil {
+			if parsed {
+				return best, model.Verification{Attempts: attempt, Issues: bestIssues}, nil
+			}
+			return Interpretation{}, model.Verification{}, err
+		}
+	}
+}
+
+// VerifyInterpretation memeriksa angka, klaim nilai p, dan klaim signifikansi pada interpretasi.
+// Angka dianggap valid jika cocok (dengan toleransi pembulatan) dengan salah satu angka di rawOutput
+// atau muncul di teks konteks. Mengembalikan daftar masalah; kosong berarti lolos.
+func VerifyInterpretation(interp Interpretation, rawOutput map[string]interface{}, context string) []string {
+	raw := stats.NormalizeOutput(rawOutput)
+	known := stats.Numbers(raw)
+	allowed := contextNumbers(context)
+
+	alphas := alphaThresholds(raw)
+	pValues := collectPValues(raw)
+
+	var issues []string
+	seen := map[string]bool{}
+	for _, text := range []string{interp.Interpretation, interp.EffectSize, interp.PracticalImplications, interp.Conclusion} {
+		claims := pClaimPattern.FindAllStringSubmatchIndex(text, -1)
+		for _, loc := range numberPattern.FindAllStringIndex(text, -1) {
+			token := text[loc[0]:loc[1]]
+			if seen[token] || isPartOfWord(text, loc) || insideClaim(claims, loc) || isThreshold(text, loc, alphas) {
+				continue
+			}
+			seen[token] = true
+			percent := strings.HasPrefix(strings.TrimSpace(text[loc[1]:]), "%") || strings.HasPrefix(strings.TrimSpace(text[loc[1]:]), "persen")
+			if !numberSupported(token, percent, confidenceContext(text, loc), known, allowed) {
+				issues = append(issues, fmt.Sprintf("number %s does not appear in the computed output", token))
+			}
+		}
+		issues = append(issues, checkPClaims(text, claims, pValues)...)
+	}
+
+	if significant, ok := raw[stats.KeySignificant].(bool); ok {
+		if claim, ok := significanceClaim(interp.Conclusion); ok && claim != significant {
+			if significant {
+				issues = append(issues, fmt.Sprintf("conclusion says the result is not significant, but p = %s < alpha", formatNumber(raw[stats.KeyPValue])))
+			} else {
+				issues = append(issues, fmt.Sprintf("conclusion says the result is significant, but p = %s >= alpha", formatNumber(raw[stats.KeyPValue])))
+			}
+		}
+	}
+	return issues
+}
+
+// isPartOfWord mengecek apakah angka merupakan bagian dari nama (mis. "item3", "X1", "H0")
+func isPartOfWord(text string, loc []int) bool {
+	if loc[0] > 0 {
+		c := text[loc[0]-1]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			return true
+		}
+	}
+	if loc[1] < len(text) {
+		c := text[loc[1]]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+			return true
+		}
+	}
+	return false
+}
+
+// isThreshold mengecek apakah angka adalah batas alpha setelah tanda perbandingan (mis. "< .05", "≤ 0,01").
+// Hanya alpha konvensional dan alpha analisis yang dilewati; angka lain setelah "<" atau ">" (mis. "t > 5.2")
+// tetap diperiksa. Klaim nilai p diperiksa terpisah oleh checkPClaims.
+func isThreshold(text string, loc []int, alphas []float64) bool {
+	before := strings.TrimRight(text[:loc[0]], " ")
+	comparison := false
+	for _, op := range []string{"<", ">", "≤", "≥"} {
+		if strings.HasSuffix(before, op) {
+			comparison = true
+			break
+		}
+	}
+	if !comparison {
+		return false
+	}
+	candidates, _ := readNumber(text[loc[0]:loc[1]])
+	for _, x := range candidates {
+		for _, alpha := range alphas {
+			if math.Abs(x-alpha) < 1e-9 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// alphaThresholds mengembalikan batas alpha konvensional ditambah alpha yang dipakai analisis
+func alphaThresholds(raw map[string]interface{}) []float64 {
+	alphas := []float64{0.05, 0.01, 0.001}
+	if alpha, ok := stats.Float(raw[stats.KeyAlpha]); ok {
+		alphas = append(alphas, alpha)
+	}
+	return alphas
+}
+
+// insideClaim mengecek apakah angka pada loc adalah bagian dari klaim nilai p
+func insideClaim(claims [][]int, loc []int) bool {
+	for _, c := range claims {
+		if loc[0] >= c[0] && loc[1] <= c[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// confidenceLevels adalah tingkat kepercayaan umum yang boleh disebut tanpa ada di output
+var confidenceLevels = []float64{90, 95, 99}
+
+// confidenceWords menandai angka sebagai tingkat kepercayaan, mis. "95% CI" atau "interval kepercayaan 95"
+var confidenceWords = regexp.MustCompile(`(?i)\bci\b|confidence|kepercayaan|keyakinan`)
+
+// confidenceContext mengecek apakah angka pada loc ditulis sebagai tingkat kepercayaan: diikuti "%" atau
+// berdekatan dengan "CI"/"confidence"/"kepercayaan" (paling jauh 25 byte di kiri atau kanan)
+func confidenceContext(text string, loc []int) bool {
+	rest := strings.TrimSpace(text[loc[1]:])
+	if strings.HasPrefix(rest, "%") || strings.HasPrefix(rest, "persen") {
+		return true
+	}
+	return confidenceWords.MatchString(text[max(0, loc[0]-25):loc[0]]) || confidenceWords.MatchString(text[loc[1]:min(len(text), loc[1]+25)])
+}
+
+// numberSupported mengecek apakah token angka didukung output statistik atau konteks.
+// Tanpa pengecualian untuk bilangan bulat kecil: df, n, atau jumlah kelompok harus ada di output;
+// hanya tingkat kepercayaan 90/95/99 yang diterima bila ditulis sebagai tingkat kepercayaan.
+func numberSupported(token string, percent, confidence bool, known []float64, allowed map[string]bool) bool {
+	if allowed[token] {
+		return true
+	}
+	candidates, decimals := readNumber(token)
+	for i, x := range candidates {
+		if confidence && decimals[i] == 0 && containsFloat(confidenceLevels, x) {
+			return true
+		}
+		for _, v := range known {
+			if matchesRounded(x, decimals[i], v) || matchesRounded(x, decimals[i], math.Abs(v)) {
+				return true
+			}
+			if percent && (matchesRounded(x, decimals[i], 100*v) || matchesRounded(x, decimals[i], 100*math.Abs(v))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFloat(values []float64, x float64) bool {
+	for _, v := range values {
+		if v == x {
+			return true
+		}
+	}
+	return false
+}
+
+// readNumber membaca token angka sebagai format titik desimal dan koma desimal (bahasa Indonesia)
+func readNumber(token string) ([]float64, []int) {
+	var values []float64
+	var decimals []int
+	add := func(s string) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			d := 0
+			if i := strings.IndexByte(s, '.'); i >= 0 {
+				d = len(s) - i - 1
+			}
+			values = append(values, f)
+			decimals = append(decimals, d)
+		}
+	}
+
+	switch {
+	case strings.Count(token, ",") == 0:
+		add(token)
+		if strings.Count(token, ".") >= 1 {
+			// Titik sebagai pemisah ribuan (1.234)
+			add(strings.ReplaceAll(token, ".", ""))
+		}
+	case strings.Count(token, ".") == 0:
+		if strings.Count(token, ",") == 1 {
+			add(strings.Replace(token, ",", ".", 1))
+		}
+		add(strings.ReplaceAll(token, ",", ""))
+	default:
+		if strings.LastIndex(token, ",") > strings.LastIndex(token, ".") {
+			add(strings.ReplaceAll(strings.ReplaceAll(token, ".", ""), ",", "."))
+		} else {
+			add(strings.ReplaceAll(token, ",", ""))
+		}
+	}
+	return values, decimals
+}
+
+// matchesRounded mengecek apakah x (ditulis dengan d desimal) adalah pembulatan atau pemotongan dari v
+func matchesRounded(x float64, d int, v float64) bool {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return false
+	}
+	step := math.Pow(10, -float64(d))
+	return math.Abs(x-v) <= step/2+1e-9 || (math.Abs(v) >= math.Abs(x) && math.Abs(v)-math.Abs(x) < step && math.Signbit(v) == math.Signbit(x))
+}
+
+// contextNumbers mengumpulkan token angka yang ada pada teks konteks (hipotesis, nama variabel)
+func contextNumbers(context string) map[string]bool {
+	allowed := map[string]bool{}
+	for _, token := range numberPattern.FindAllString(context, -1) {
+		allowed[token] = true
+	}
+	return allowed
+}
+
+// pValue adalah nilai p di output beserta label uji yang menghasilkannya (nama variabel, baris tabel,
+// atau nama uji seperti "levene"); primary menandai nilai p uji utama
+type pValue struct {
+	labels  []string
+	value   float64
+	primary bool
+}
+
+// sentenceBoundary menandai awal kalimat; titik desimal (".05") tidak diikuti spasi sehingga tidak cocok.
+// Titik dua bukan batas karena sering memisahkan nama uji dari statistiknya ("Levene's test: p = .274").
+var sentenceBoundary = regexp.MustCompile(`[.!?;]\s|\n`)
+
+// checkPClaims memeriksa klaim "p < x", "p = x", dst. terhadap nilai p uji yang disebut pada kalimat klaim.
+// Uji dikenali dari label yang muncul di kalimat sebelum klaim; klaim tanpa nama uji dibandingkan dengan nilai p
+// uji utama, atau dengan semua nilai p jika output tidak punya uji utama.
+func checkPClaims(text string, claims [][]int, pValues []pValue) []string {
+	if len(pValues) == 0 {
+		return nil
+	}
+
+	var issues []string
+	for i, c := range claims {
+		claim := strings.TrimSpace(text[c[0]:c[1]])
+		op := text[c[2]:c[3]]
+		candidates, decimals := readNumber(text[c[4]:c[5]])
+		if len(candidates) == 0 {
+			continue
+		}
+		x, d := candidates[0], decimals[0]
+
+		from := 0
+		if i > 0 {
+			from = claims[i-1][1]
+		}
+		targets, named := claimTargets(claimSentence(text, from, c[0]), pValues)
+		supported := false
+		for _, p := range targets {
+			switch op {
+			case "<", "≤", "<=":
+				supported = p.value < x || matchesRounded(x, d, p.value)
+			case ">", "≥", ">=":
+				supported = p.value > x || matchesRounded(x, d, p.value)
+			default:
+				supported = matchesRounded(x, d, p.value)
+			}
+			if supported {
+				break
+			}
+		}
+		if supported {
+			continue
+		}
+		if named != "" {
+			issues = append(issues, fmt.Sprintf("claim %q does not match the computed p-value for %s", claim, named))
+		} else {
+			issues = append(issues, fmt.Sprintf("claim %q does not match any computed p-value", claim))
+		}
+	}
+	return issues
+}
+
+// claimSentence mengembalikan teks dari awal kalimat (atau akhir klaim sebelumnya, from) sampai posisi klaim,
+// agar klaim kedua dalam satu kalimat tidak mewarisi nama uji klaim pertama
+func claimSentence(text string, from, end int) string {
+	before := text[:end]
+	start := from
+	for _, loc := range sentenceBoundary.FindAllStringIndex(before, -1) {
+		start = max(start, loc[1])
+	}
+	return strings.ToLower(before[start:])
+}
+
+// claimTargets memilih nilai p yang dirujuk kalimat klaim: nilai dengan label terbanyak yang disebut di kalimat.
+// named berisi label uji tersebut, kosong jika kalimat tidak menyebut uji mana pun.
+func claimTargets(sentence string, pValues []pValue) ([]pValue, string) {
+	best, bestScore := []pValue(nil), 0
+	for _, p := range pValues {
+		score := 0
+		for _, label := range p.labels {
+			if mentions(sentence, label) {
+				score++
+			}
+		}
+		switch {
+		case score > bestScore:
+			best, bestScore = []pValue{p}, score
+		case score == bestScore && score > 0:
+			best = append(best, p)
+		}
+	}
+	if bestScore > 0 {
+		return best, strings.Join(best[0].labels, " / ")
+	}
+
+	for _, p := range pValues {
+		if p.primary {
+			return []pValue{p}, ""
+		}
+	}
+	return pValues, ""
+}
+
+// mentions mengecek apakah label (huruf kecil) disebut sebagai kata utuh di kalimat
+func mentions(sentence, label string) bool {
+	for from := 0; ; {
+		i := strings.Index(sentence[from:], label)
+		if i < 0 {
+			return false
+		}
+		i += from
+		end := i + len(label)
+		if !isWordByte(sentence, i-1) && !isWordByte(sentence, end) {
+			return true
+		}
+		from = i + 1
+	}
+}
+
+func isWordByte(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	c := s[i]
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// pLabel menormalkan nama variabel, baris, atau uji menjadi label; label terlalu pendek diabaikan
+func pLabel(name string) string {
+	label := strings.ToLower(strings.TrimSpace(strings.ReplaceAll(name, "_", " ")))
+	if len(label) < 2 {
+		return ""
+	}
+	return label
+}
+
+// collectPValues mengumpulkan seluruh nilai p dari output beserta labelnya: p_value utama, field *_p,
+// daftar tests, dan kolom tabel "p" (label dari sel teks pada baris yang sama)
+func collectPValues(raw map[string]interface{}) []pValue {
+	var values []pValue
+	add := func(v interface{}, primary bool, names ...string) {
+		f, ok := stats.Float(v)
+		if !ok {
+			return
+		}
+		p := pValue{value: f, primary: primary}
+		for _, name := range names {
+			if label := pLabel(name); label != "" && !containsLabel(p.labels, label) {
+				p.labels = append(p.labels, label)
+			}
+		}
+		values = append(values, p)
+	}
+
+	add(raw[stats.KeyPValue], true)
+	for k, item := range raw {
+		if strings.HasSuffix(k, "_p") {
+			add(item, false, strings.TrimSuffix(k, "_p"))
+		}
+	}
+	tests, _ := raw[stats.KeyTests].([]interface{})
+	for _, item := range tests {
+		if test, ok := item.(map[string]interface{}); ok {
+			add(test[stats.KeyPValue], false, stats.Strings(test, stats.KeyVariables)...)
+		}
+	}
+
+	for _, table := range stats.Tables(raw) {
+		if table.Name == "p_values" {
+			// Matriks nilai p: label dari nama baris dan kolom
+			for _, row := range table.Rows {
+				for c := 1; c < len(row) && c < len(table.Columns); c++ {
+					rowName, _ := row[0].(string)
+					add(row[c], false, rowName, table.Columns[c])
+				}
+			}
+			continue
+		}
+		for c, column := range table.Columns {
+			name := strings.ToLower(column)
+			if name != "p" && !strings.HasSuffix(name, " p") && !strings.HasPrefix(name, "significance") {
+				continue
+			}
+			for _, row := range table.Rows {
+				if c >= len(row) {
+					continue
+				}
+				names := []string{table.Name, strings.TrimSuffix(name, " p")}
+				for _, cell := range row {
+					if text, ok := cell.(string); ok {
+						names = append(names, text)
+					}
+				}
+				add(row[c], false, names...)
+			}
+		}
+	}
+	return values
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// significanceClaim menentukan klaim signifikansi pada teks; ok = false jika tidak ada atau ambigu
+func significanceClaim(text string) (bool, bool) {
+	negatives := len(negativeSignificance.FindAllString(text, -1))
+	positives := len(positiveSignificance.FindAllString(text, -1)) - negatives
+	switch {
+	case negatives > 0 && positives == 0:
+		return false, true
+	case positives > 0 && negatives == 0:
+		return true, true
+	}
+	return false, false
+}
+
+func formatNumber(v interface{}) string {
+	if f, ok := stats.Float(v); ok {
+		return strconv.FormatFloat(f, 'g', 4, 64)
+	}
+	return "?"
+}
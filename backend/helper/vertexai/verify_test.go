@@ -0,0 +1,181 @@
+package vertexai
+
+import (
+	"strings"
+	"testing"
+)
+
+// tTestOutput adalah output uji t independen dengan tabel asumsi (Levene dan Shapiro-Wilk)
+func tTestOutput() map[string]interface{} {
+	return map[string]interface{}{
+		"method":            "independent_t_test",
+		"n":                 40,
+		"alpha":             0.05,
+		"statistic_name":    "t",
+		"statistic":         2.345,
+		"df":                38,
+		"p_value":           0.024,
+		"significant":       true,
+		"effect_size_name":  "Cohen's d",
+		"effect_size":       0.74,
+		"effect_size_label": "medium",
+		"tables": []interface{}{
+			map[string]interface{}{
+				"name":    "assumptions",
+				"columns": []interface{}{"Test", "Statistic", "p"},
+				"rows": []interface{}{
+					[]interface{}{"Levene", 1.23, 0.274},
+					[]interface{}{"Shapiro-Wilk", 0.97, 0.412},
+				},
+			},
+		},
+	}
+}
+
+func TestVerifyInterpretation(t *testing.T) {
+	const conclusion = "The difference is significant."
+	tests := []struct {
+		name           string
+		interpretation string
+		conclusion     string
+		context        string
+		wantIssue      string // kosong berarti lolos verifikasi
+	}{
+		{"reported statistics", "t(38) = 2.35, p = .024, d = 0.74 (medium) with n = 40.", conclusion, "", ""},
+		{"truncated and rounded", "The t statistic was 2.3 and d was .7.", conclusion, "", ""},
+		{"percentage of a proportion", "The effect corresponds to 74% of a standard deviation.", conclusion, "", ""},
+		{"decimal comma", "t(38) = 2,35; p = 0,024.", conclusion, "", ""},
+		{"confidence level with percent", "The 95% CI excludes zero.", conclusion, "", ""},
+		{"confidence level before CI", "The 99 CI excludes zero.", conclusion, "", ""},
+		{"confidence level in Indonesian", "Interval kepercayaan 90 tidak memuat nol.", conclusion, "", ""},
+		{"number from the research context", "The sample was followed for 12 weeks.", conclusion, "Hypothesis: scores rise after 12 weeks", ""},
+		{"conventional threshold", "The difference is below the .05 threshold, p < .05.", conclusion, "", ""},
+		{"assumption p-value named", "Levene's test was not significant, p = .274.", conclusion, "", ""},
+		{"fabricated df", "t(37) = 2.35, p = .024.", conclusion, "", "number 37"},
+		{"fabricated sample size", "A total of 8 participants took part.", conclusion, "", "number 8"},
+		{"fabricated group count", "Scores were compared across 3 groups.", conclusion, "", "number 3"},
+		{"confidence level without CI", "There were 95 participants.", conclusion, "", "number 95"},
+		{"fabricated statistic", "t(38) = 3.10, p = .024.", conclusion, "", "number 3.10"},
+		{"fabricated p-value", "The groups differ, p = .031.", conclusion, "", `claim "p = .031"`},
+		{"p-value of another test", "Levene's test showed p = .024.", conclusion, "", "levene"},
+		{"wrong direction", "The groups differ, p > .05.", conclusion, "", `claim "p > .05"`},
+		{"contradicting conclusion", "t(38) = 2.35, p = .024.", "The difference is not significant.", "", "conclusion says the result is not significant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interp := Interpretation{Interpretation: tt.interpretation, Conclusion: tt.conclusion}
+			issues := VerifyInterpretation(interp, tTestOutput(), tt.context)
+			if tt.wantIssue == "" {
+				if len(issues) > 0 {
+					t.Errorf("issues = %q, want none", issues)
+				}
+				return
+			}
+			if !containsIssue(issues, tt.wantIssue) {
+				t.Errorf("issues = %q, want one containing %q", issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+func containsIssue(issues []string, want string) bool {
+	for _, issue := range issues {
+		if strings.Contains(issue, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNumberSupported(t *testing.T) {
+	known := []float64{2.345, 38, 0.024, 0.74}
+	tests := []struct {
+		token      string
+		percent    bool
+		confidence bool
+		want       bool
+	}{
+		{"38", false, false, true},
+		{"2.35", false, false, true},
+		{"2.34", false, false, true},
+		{"2.4", false, false, false},
+		{"74", true, true, true},
+		{"74", false, false, false},
+		{"5", false, false, false},
+		{"10", false, false, false},
+		{"95", false, false, false},
+		{"95", true, true, true},
+		{"99", false, true, true},
+		{"97", false, true, false},
+		{"95.5", false, true, false},
+		{"12", false, false, true}, // ada di konteks
+	}
+	allowed := map[string]bool{"12": true}
+	for _, tt := range tests {
+		if got := numberSupported(tt.token, tt.percent, tt.confidence, known, allowed); got != tt.want {
+			t.Errorf("numberSupported(%q, percent=%v, confidence=%v) = %v, want %v", tt.token, tt.percent, tt.confidence, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPClaims(t *testing.T) {
+	pValues := []pValue{
+		{value: 0.024, primary: true},
+		{labels: []string{"assumptions", "levene"}, value: 0.274},
+		{labels: []string{"assumptions", "shapiro-wilk"}, value: 0.412},
+	}
+	tests := []struct {
+		name      string
+		text      string
+		wantIssue string
+	}{
+		{"primary p exact", "The groups differ, p = .024.", ""},
+		{"primary p below threshold", "The groups differ, p < .05.", ""},
+		{"named assumption test", "Levene's test: p = .274.", ""},
+		{"two claims in one sentence", "Levene's test gave p = .274 and the t-test gave p = .024.", ""},
+		{"named test in its own sentence", "Shapiro-Wilk was fine. The groups differ, p = .024.", ""},
+		{"wrong primary p", "The groups differ, p = .24.", "does not match any computed p-value"},
+		{"primary p claimed for a named test", "Shapiro-Wilk showed p = .024.", "shapiro-wilk"},
+		{"named test below a threshold it exceeds", "Levene's test was significant, p < .05.", "levene"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := pClaimPattern.FindAllStringSubmatchIndex(tt.text, -1)
+			if len(claims) == 0 {
+				t.Fatalf("no p claim found in %q", tt.text)
+			}
+			issues := checkPClaims(tt.text, claims, pValues)
+			if tt.wantIssue == "" {
+				if len(issues) > 0 {
+					t.Errorf("issues = %q, want none", issues)
+				}
+				return
+			}
+			if !containsIssue(issues, tt.wantIssue) {
+				t.Errorf("issues = %q, want one containing %q", issues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+func TestIsThreshold(t *testing.T) {
+	alphas := []float64{0.05, 0.01, 0.001, 0.1}
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"significant at < .05", true},
+		{"below ≤ 0,01", true},
+		{"custom alpha < .10", true},
+		{"t > 5.2", false},
+		{"value .05", false},
+		{"less than < .02", false},
+	}
+	for _, tt := range tests {
+		locs := numberPattern.FindAllStringIndex(tt.text, -1)
+		loc := locs[len(locs)-1]
+		if got := isThreshold(tt.text, loc, alphas); got != tt.want {
+			t.Errorf("isThreshold(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
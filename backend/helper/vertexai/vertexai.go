@@ -7,8 +7,10 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/research-data-analysis/config"
+	"github.com/research-data-analysis/model"
 )
 
 // GeminiRequest untuk request ke Vertex AI
@@ -49,7 +51,7 @@ type ErrorInfo struct {
 func getAccessToken() (string, error) {
 	// Di Google Cloud Functions, token bisa didapat dari metadata server
 	metadataURL := "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
-	
+
 	req, err := http.NewRequest("GET", metadataURL, nil)
 	if err != nil {
 		return "", err
@@ -142,63 +144,134 @@ func GenerateContent(prompt string) (string, error) {
 	return "", fmt.Errorf("no response from Gemini")
 }
 
-// GenerateResearchRecommendations menghasilkan rekomendasi metode penelitian
-func GenerateResearchRecommendations(context string) (string, error) {
-	prompt := fmt.Sprintf(`Anda adalah ahli metodologi penelitian dan statistik. Berdasarkan konteks penelitian berikut, berikan rekomendasi metode analisis yang sesuai.
-
-Konteks Penelitian:
-%s
-
-Berikan rekomendasi dalam format JSON dengan struktur berikut:
-{
-  "recommendations": [
-    {
-      "method": "nama metode analisis",
-      "category": "descriptive/inferential/correlation/regression",
-      "reasoning": "penjelasan mengapa metode ini cocok",
-      "priority": 1,
-      "assumptions": "asumsi yang perlu dipenuhi"
-    }
-  ]
-}
-
-Berikan minimal 3-5 rekomendasi metode yang relevan, diurutkan berdasarkan prioritas.`, context)
-
+// GenerateResearchRecommendations menghasilkan rekomendasi metode penelitian dalam bahasa lang
+func GenerateResearchRecommendations(lang, context string, supportedMethods []string) (string, error) {
+	prompt, err := renderPrompt(lang, "recommendations", map[string]interface{}{
+		"Context": context,
+		"Methods": supportedMethods,
+	})
+	if err != nil {
+		return "", err
+	}
 	return GenerateContent(prompt)
 }
 
-// GenerateAnalysisInterpretation menghasilkan interpretasi hasil analisis
-func GenerateAnalysisInterpretation(method, results string) (string, error) {
-	prompt := fmt.Sprintf(`Anda adalah ahli statistik penelitian. Interpretasikan hasil analisis berikut dalam bahasa yang mudah dipahami.
+// RecommendationValidator memvalidasi satu rekomendasi dan mengembalikan versi kanoniknya
+type RecommendationValidator func(model.Recommendation) (model.Recommendation, error)
 
-Metode Analisis: %s
-Hasil: %s
+// GenerateValidatedRecommendations meminta rekomendasi dan mem-parse-nya menjadi []model.Recommendation.
+// Jika respons tidak bisa di-parse atau tidak ada metode yang valid, model diminta memperbaiki
+// jawabannya dengan prompt korektif hingga maxAttempts kali. Mengembalikan juga respons mentah terakhir.
+func GenerateValidatedRecommendations(lang, context string, supportedMethods []string, validate RecommendationValidator, maxAttempts int) ([]model.Recommendation, string, error) {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
 
-Berikan interpretasi dalam format JSON:
-{
-  "interpretation": "penjelasan hasil dalam bahasa sederhana",
-  "effect_size": "interpretasi effect size jika ada",
-  "practical_implications": "implikasi praktis dari hasil",
-  "conclusion": "kesimpulan terkait hipotesis/tujuan penelitian"
-}`, method, results)
+	raw, err := GenerateResearchRecommendations(lang, context, supportedMethods)
+	if err != nil {
+		return nil, "", err
+	}
 
-	return GenerateContent(prompt)
+	for attempt := 1; ; attempt++ {
+		recommendations, problems := validateRecommendations(raw, validate)
+		if len(recommendations) > 0 {
+			return recommendations, raw, nil
+		}
+		if attempt >= maxAttempts {
+			return nil, raw, fmt.Errorf("invalid recommendations after %d attempts: %s", attempt, strings.Join(problems, "; "))
+		}
+
+		corrective, err := renderPrompt(lang, "recommendations_corrective", map[string]interface{}{
+			"Problems": problems,
+			"Previous": raw,
+			"Methods":  supportedMethods,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		raw, err = GenerateContent(corrective)
+		if err != nil {
+			return nil, "", err
+		}
+	}
 }
 
-// GenerateResearchSummary menghasilkan ringkasan penelitian
-func GenerateResearchSummary(analysisContext string) (string, error) {
-	prompt := fmt.Sprintf(`Anda adalah penulis akademis berpengalaman. Buat ringkasan komprehensif dari sesi analisis penelitian berikut.
+// validateRecommendations mem-parse respons lalu menjalankan validator pada setiap rekomendasi
+func validateRecommendations(raw string, validate RecommendationValidator) ([]model.Recommendation, []string) {
+	parsed, err := ParseRecommendations(raw)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if validate == nil {
+		return parsed, nil
+	}
 
-%s
+	var problems []string
+	valid := make([]model.Recommendation, 0, len(parsed))
+	for _, rec := range parsed {
+		checked, err := validate(rec)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		valid = append(valid, checked)
+	}
+	if len(valid) == 0 {
+		problems = append(problems, "none of the recommended methods is supported")
+	}
+	return valid, problems
+}
+
+// maxRefinementTurns membatasi jumlah giliran percakapan yang disertakan dalam prompt refinement
+const maxRefinementTurns = 20
+
+// GenerateRefinement membalas instruksi refinement dengan riwayat percakapan dan hasil terstruktur sebagai konteks
+func GenerateRefinement(lang, projectContext string, history []model.ConversationTurn, results []model.MethodResult, instruction string) (string, error) {
+	type resultContext struct {
+		Method         string                 `json:"method"`
+		RawOutput      map[string]interface{} `json:"raw_output,omitempty"`
+		Interpretation string                 `json:"interpretation,omitempty"`
+		Conclusion     string                 `json:"conclusion,omitempty"`
+		Error          string                 `json:"error,omitempty"`
+	}
+	structured := make([]resultContext, 0, len(results))
+	for _, result := range results {
+		structured = append(structured, resultContext{
+			Method:         result.Method,
+			RawOutput:      result.RawOutput,
+			Interpretation: result.Interpretation,
+			Conclusion:     result.Conclusion,
+			Error:          result.Error,
+		})
+	}
+	encoded, err := json.MarshalIndent(structured, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode analysis results: %v", err)
+	}
 
-Berikan ringkasan dalam format JSON:
-{
-  "executive_summary": "ringkasan eksekutif 2-3 paragraf",
-  "key_findings": ["temuan utama 1", "temuan utama 2", ...],
-  "methodology_notes": "catatan tentang metodologi yang digunakan",
-  "limitations": ["keterbatasan 1", "keterbatasan 2", ...],
-  "future_recommendations": ["rekomendasi penelitian lanjutan"]
-}`, analysisContext)
+	if len(history) > maxRefinementTurns {
+		history = history[len(history)-maxRefinementTurns:]
+	}
+	prompt, err := renderPrompt(lang, "refinement", map[string]interface{}{
+		"Context":     projectContext,
+		"Results":     string(encoded),
+		"History":     history,
+		"Instruction": instruction,
+	})
+	if err != nil {
+		return "", err
+	}
+	return GenerateContent(prompt)
+}
 
+// GenerateResearchSummary menghasilkan ringkasan penelitian dalam bahasa lang
+func GenerateResearchSummary(lang, analysisContext string) (string, error) {
+	prompt, err := renderPrompt(lang, "summary", map[string]interface{}{
+		"Context": analysisContext,
+	})
+	if err != nil {
+		return "", err
+	}
 	return GenerateContent(prompt)
-}
\ No newline at end of file
+}
@@ -0,0 +1,110 @@
+package watoken
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"aidanwoods.dev/go-paseto"
+)
+
+// ErrUnknownKey dikembalikan jika key ID di footer token tidak ada di key ring
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Key adalah satu pasangan kunci di key ring; PrivateKey kosong untuk kunci yang hanya dipakai verifikasi
+type Key struct {
+	ID         string
+	PrivateKey string
+	PublicKey  string
+}
+
+// KeyRing berisi kunci penandatangan saat ini dan semua kunci publik yang masih diterima
+type KeyRing struct {
+	current Key
+	keys    map[string]Key
+	order   []string
+}
+
+// footer adalah isi footer PASETO
+type footer struct {
+	Kid string `json:"kid"`
+}
+
+// KeyID menurunkan key ID dari public key (16 karakter hex pertama SHA-256) agar stabil tanpa disimpan
+func KeyID(publicKeyHex string) string {
+	sum := sha256.Sum256([]byte(publicKeyHex))
+	return hex.EncodeToString(sum[:8])
+}
+
+// NewKeyRing membuat key ring; current dipakai untuk menandatangani, verifyOnly hanya untuk verifikasi
+func NewKeyRing(current Key, verifyOnly ...Key) (*KeyRing, error) {
+	if current.PrivateKey == "" {
+		return nil, errors.New("current signing key has no private key")
+	}
+	kr := &KeyRing{current: current, keys: map[string]Key{}}
+	for _, k := range append([]Key{current}, verifyOnly...) {
+		if k.ID == "" {
+			k.ID = KeyID(k.PublicKey)
+		}
+		if _, ok := kr.keys[k.ID]; ok {
+			continue
+		}
+		kr.keys[k.ID] = k
+		kr.order = append(kr.order, k.ID)
+	}
+	kr.current = kr.keys[kr.order[0]]
+	return kr, nil
+}
+
+// CurrentID mengembalikan key ID kunci penandatangan saat ini
+func (kr *KeyRing) CurrentID() string {
+	return kr.current.ID
+}
+
+// KeyIDs mengembalikan semua key ID yang diterima, kunci saat ini lebih dulu
+func (kr *KeyRing) KeyIDs() []string {
+	return append([]string(nil), kr.order...)
+}
+
+// Encode menandatangani token dengan kunci saat ini; key ID disimpan di footer
+func (kr *KeyRing) Encode(id, name, jti string, ttl time.Duration) (string, error) {
+	f, err := json.Marshal(footer{Kid: kr.current.ID})
+	if err != nil {
+		return "", err
+	}
+	return encode(id, name, jti, kr.current.PrivateKey, ttl, f)
+}
+
+// Decode memverifikasi token dengan kunci sesuai key ID di footer;
+// token lama tanpa footer dicoba dengan setiap kunci di ring
+func (kr *KeyRing) Decode(tokenString string) (*Payload, error) {
+	raw, err := paseto.NewParser().UnsafeParseFooter(paseto.V4Public, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) > 0 {
+		var f footer
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, fmt.Errorf("invalid token footer: %v", err)
+		}
+		key, ok := kr.keys[f.Kid]
+		if !ok {
+			return nil, ErrUnknownKey
+		}
+		return Decode(key.PublicKey, tokenString)
+	}
+
+	err = ErrUnknownKey
+	for _, kid := range kr.order {
+		payload, decodeErr := Decode(kr.keys[kid].PublicKey, tokenString)
+		if decodeErr == nil {
+			return payload, nil
+		}
+		err = decodeErr
+	}
+	return nil, err
+}
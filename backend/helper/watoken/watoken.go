@@ -14,10 +14,21 @@ type Payload struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
 	Exp  string `json:"exp"`
+	Jti  string `json:"jti,omitempty"` // token ID untuk pengecekan revokasi; kosong pada token lama
 }
 
 // EncodeforHours mengenkode token dengan durasi dalam jam
 func EncodeforHours(id, name, privateKeyHex string, hours int) (string, error) {
+	return EncodeWithID(id, name, "", privateKeyHex, time.Duration(hours)*time.Hour)
+}
+
+// EncodeWithID mengenkode token berumur ttl dengan token ID (jti); jti kosong tidak disertakan
+func EncodeWithID(id, name, jti, privateKeyHex string, ttl time.Duration) (string, error) {
+	return encode(id, name, jti, privateKeyHex, ttl, nil)
+}
+
+// encode menandatangani token; footer (tidak terenkripsi tetapi ikut ditandatangani) opsional
+func encode(id, name, jti, privateKeyHex string, ttl time.Duration, footer []byte) (string, error) {
 	privateKeyBytes, err := hex.DecodeString(privateKeyHex)
 	if err != nil {
 		return "", err
@@ -34,9 +45,16 @@ func EncodeforHours(id, name, privateKeyHex string, hours int) (string, error) {
 	token := paseto.NewToken()
 	token.SetIssuedAt(time.Now())
 	token.SetNotBefore(time.Now())
-	token.SetExpiration(time.Now().Add(time.Duration(hours) * time.Hour))
+	token.SetExpiration(time.Now().Add(ttl))
 	token.SetString("id", id)
 	token.SetString("name", name)
+	if jti != "" {
+		token.SetJti(jti)
+	}
+
+	if len(footer) > 0 {
+		token.SetFooter(footer)
+	}
 
 	return token.V4Sign(key, nil), nil
 }
@@ -78,10 +96,14 @@ func Decode(publicKeyHex, tokenString string) (*Payload, error) {
 		return nil, err
 	}
 
+	// jti opsional
+	jti, _ := token.GetJti()
+
 	return &Payload{
 		Id:   id,
 		Name: name,
 		Exp:  exp.Format(time.RFC3339),
+		Jti:  jti,
 	}, nil
 }
 
@@ -23,8 +23,23 @@ type User struct {
 	FullName      string             `json:"fullName" bson:"full_name"`
 	Institution   string             `json:"institution" bson:"institution"`
 	ResearchField string             `json:"researchField" bson:"research_field"`
+	Language      string             `json:"language,omitempty" bson:"language,omitempty"` // preferensi bahasa output AI dan laporan: "id" atau "en"
+	EmailVerified bool               `json:"emailVerified" bson:"email_verified"`
+	VerifiedAt    *time.Time         `json:"emailVerifiedAt,omitempty" bson:"email_verified_at,omitempty"`
 	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+
+	// InstitutionID menautkan pengguna ke tenant; Institution berisi nama institusi tersebut
+	InstitutionID   primitive.ObjectID `json:"institutionId,omitempty" bson:"institution_id,omitempty"`
+	InstitutionRole string             `json:"institutionRole,omitempty" bson:"institution_role,omitempty"` // "admin" atau "member"
+
+	// Two-factor authentication (TOTP); secret dienkripsi, recovery code disimpan sebagai hash
+	TwoFactorEnabled       bool     `json:"twoFactorEnabled" bson:"two_factor_enabled,omitempty"`
+	TwoFactorSecret        string   `json:"-" bson:"two_factor_secret,omitempty"`
+	TwoFactorPending       string   `json:"-" bson:"two_factor_pending,omitempty"` // secret yang belum dikonfirmasi saat pendaftaran
+	TwoFactorLastStep      int64    `json:"-" bson:"two_factor_last_step,omitempty"`
+	TwoFactorSetupAttempts int      `json:"-" bson:"two_factor_setup_attempts,omitempty"` // percobaan konfirmasi secret pending
+	RecoveryCodes          []string `json:"-" bson:"recovery_codes,omitempty"`
 }
 
 // Variables untuk variabel penelitian
@@ -34,6 +49,8 @@ type Variables struct {
 	Control     []string `json:"control,omitempty" bson:"control,omitempty"`
 	Moderating  []string `json:"moderating,omitempty" bson:"moderating,omitempty"`
 	Mediating   []string `json:"mediating,omitempty" bson:"mediating,omitempty"`
+	// Scales menimpa skala pengukuran hasil inferensi (nominal/ordinal/interval/ratio) per variabel
+	Scales map[string]string `json:"scales,omitempty" bson:"scales,omitempty"`
 }
 
 // Project menyimpan informasi proyek penelitian
@@ -48,16 +65,41 @@ type Project struct {
 	Status       string             `json:"status" bson:"status"`
 	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+	Role         string             `json:"role,omitempty" bson:"-"` // peran pengguna yang meminta: owner, editor, atau viewer
+}
+
+// ProjectMember adalah kolaborator proyek selain pembuatnya (pembuat selalu owner lewat Project.UserID).
+// Undangan berstatus pending sampai diterima oleh pengguna dengan email terverifikasi yang sama.
+type ProjectMember struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ProjectID  primitive.ObjectID `json:"project_id" bson:"project_id"`
+	UserID     primitive.ObjectID `json:"user_id,omitempty" bson:"user_id,omitempty"` // kosong sampai undangan diterima
+	Email      string             `json:"email" bson:"email"`
+	Role       string             `json:"role" bson:"role"`     // "owner", "editor", "reviewer", atau "viewer"
+	Status     string             `json:"status" bson:"status"` // "pending" atau "active"
+	InvitedBy  primitive.ObjectID `json:"invited_by" bson:"invited_by"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	AcceptedAt *time.Time         `json:"accepted_at,omitempty" bson:"accepted_at,omitempty"`
+}
+
+// MemberRequest untuk mengundang kolaborator atau mengubah perannya
+type MemberRequest struct {
+	Email    string `json:"email,omitempty"`
+	Role     string `json:"role"`
+	Language string `json:"language,omitempty"`
 }
 
 // DataSummary untuk ringkasan data upload
 type DataSummary struct {
-	Rows         int                    `json:"rows" bson:"rows"`
-	Columns      int                    `json:"columns" bson:"columns"`
-	ColumnNames  []string               `json:"column_names" bson:"column_names"`
-	ColumnTypes  map[string]string      `json:"column_types" bson:"column_types"`
-	MissingCount map[string]int         `json:"missing_count" bson:"missing_count"`
-	Statistics   map[string]interface{} `json:"statistics,omitempty" bson:"statistics,omitempty"`
+	Rows         int                       `json:"rows" bson:"rows"`
+	Columns      int                       `json:"columns" bson:"columns"`
+	ColumnNames  []string                  `json:"column_names" bson:"column_names"`
+	ColumnTypes  map[string]string         `json:"column_types" bson:"column_types"`
+	Scales       map[string]string         `json:"scales,omitempty" bson:"scales,omitempty"`
+	Levels       map[string]int            `json:"levels,omitempty" bson:"levels,omitempty"`
+	Frequencies  map[string]map[string]int `json:"frequencies,omitempty" bson:"frequencies,omitempty"`
+	MissingCount map[string]int            `json:"missing_count" bson:"missing_count"`
+	Statistics   map[string]interface{}    `json:"statistics,omitempty" bson:"statistics,omitempty"`
 }
 
 // Upload menyimpan informasi file yang diupload
@@ -68,26 +110,42 @@ type Upload struct {
 	FileType    string             `json:"file_type" bson:"file_type"`
 	FileSize    int64              `json:"file_size" bson:"file_size"`
 	StorageURL  string             `json:"storage_url" bson:"storage_url"`
+	StoragePath string             `json:"storage_path,omitempty" bson:"storage_path,omitempty"`
 	DataSummary DataSummary        `json:"data_summary" bson:"data_summary"`
 	UploadedAt  time.Time          `json:"uploaded_at" bson:"uploaded_at"`
 }
 
 // Recommendation untuk rekomendasi metode analisis
 type Recommendation struct {
-	Method      string `json:"method" bson:"method"`
-	Category    string `json:"category" bson:"category"`
-	Reasoning   string `json:"reasoning" bson:"reasoning"`
-	Priority    int    `json:"priority" bson:"priority"`
-	Assumptions string `json:"assumptions" bson:"assumptions"`
+	Method      string   `json:"method" bson:"method"`
+	MethodID    string   `json:"method_id" bson:"method_id"`
+	Category    string   `json:"category" bson:"category"`
+	Reasoning   string   `json:"reasoning" bson:"reasoning"`
+	Priority    int      `json:"priority" bson:"priority"`
+	Assumptions string   `json:"assumptions" bson:"assumptions"`
+	Variables   []string `json:"variables,omitempty" bson:"variables,omitempty"`
+	Source      string   `json:"source,omitempty" bson:"source,omitempty"`
+}
+
+// Verification mencatat hasil pengecekan angka dan klaim signifikansi pada interpretasi AI.
+// Fallback berarti AI tidak menghasilkan interpretasi sehingga tidak ada yang diverifikasi.
+type Verification struct {
+	Verified bool     `json:"verified" bson:"verified"`
+	Attempts int      `json:"attempts" bson:"attempts"`
+	Issues   []string `json:"issues,omitempty" bson:"issues,omitempty"`
+	Fallback bool     `json:"fallback,omitempty" bson:"fallback,omitempty"` // interpretasi disusun dari output komputasi, bukan oleh AI
 }
 
 // MethodResult untuk hasil analisis
 type MethodResult struct {
 	Method         string                 `json:"method" bson:"method"`
+	MethodID       string                 `json:"method_id,omitempty" bson:"method_id,omitempty"`
 	RawOutput      map[string]interface{} `json:"raw_output" bson:"raw_output"`
 	Interpretation string                 `json:"interpretation" bson:"interpretation"`
 	EffectSize     string                 `json:"effect_size,omitempty" bson:"effect_size,omitempty"`
 	Conclusion     string                 `json:"conclusion" bson:"conclusion"`
+	Verification   *Verification          `json:"verification,omitempty" bson:"verification,omitempty"`
+	Error          string                 `json:"error,omitempty" bson:"error,omitempty"`
 }
 
 // Figure untuk gambar/chart hasil analisis
@@ -95,45 +153,180 @@ type Figure struct {
 	ID         string `json:"id" bson:"id"`
 	Title      string `json:"title" bson:"title"`
 	Type       string `json:"type" bson:"type"`
-	StorageURL string `json:"storage_url" bson:"storage_url"`
+	StorageURL string `json:"storage_url" bson:"storage_url"`             // PNG untuk laporan
+	SVGURL     string `json:"svg_url,omitempty" bson:"svg_url,omitempty"` // SVG untuk tampilan web
+}
+
+// ConversationTurn menyimpan satu giliran percakapan refinement (instruksi pengguna atau balasan model)
+type ConversationTurn struct {
+	Role       string                            `json:"role" bson:"role"` // "user" atau "assistant"
+	Content    string                            `json:"content" bson:"content"`
+	AnalysisID primitive.ObjectID                `json:"analysis_id" bson:"analysis_id"`
+	Rerun      []string                          `json:"rerun,omitempty" bson:"rerun,omitempty"`
+	Options    map[string]map[string]interface{} `json:"options,omitempty" bson:"options,omitempty"`
+	CreatedAt  time.Time                         `json:"created_at" bson:"created_at"`
 }
 
 // Analysis menyimpan informasi analisis
 type Analysis struct {
-	ID              primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
-	ProjectID       primitive.ObjectID `json:"project_id" bson:"project_id"`
-	UploadID        primitive.ObjectID `json:"upload_id" bson:"upload_id"`
-	Iteration       int                `json:"iteration" bson:"iteration"`
-	Status          string             `json:"status" bson:"status"`
-	Recommendations []Recommendation   `json:"recommendations" bson:"recommendations"`
-	SelectedMethods []string           `json:"selected_methods" bson:"selected_methods"`
-	Results         []MethodResult     `json:"results" bson:"results"`
-	Figures         []Figure           `json:"figures" bson:"figures"`
-	Summary         string             `json:"summary" bson:"summary"`
-	UserFeedback    string             `json:"user_feedback" bson:"user_feedback"`
-	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
-	CompletedAt     *time.Time         `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
-	Error           string             `json:"error,omitempty" bson:"error,omitempty"`
+	ID              primitive.ObjectID                `json:"_id,omitempty" bson:"_id,omitempty"`
+	ProjectID       primitive.ObjectID                `json:"project_id" bson:"project_id"`
+	UploadID        primitive.ObjectID                `json:"upload_id" bson:"upload_id"`
+	ParentID        primitive.ObjectID                `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Iteration       int                               `json:"iteration" bson:"iteration"`
+	Language        string                            `json:"language,omitempty" bson:"language,omitempty"`
+	Status          string                            `json:"status" bson:"status"`
+	Recommendations []Recommendation                  `json:"recommendations" bson:"recommendations"`
+	SelectedMethods []string                          `json:"selected_methods" bson:"selected_methods"`
+	MethodOptions   map[string]map[string]interface{} `json:"method_options,omitempty" bson:"method_options,omitempty"`
+	Results         []MethodResult                    `json:"results" bson:"results"`
+	Figures         []Figure                          `json:"figures" bson:"figures"`
+	Summary         string                            `json:"summary" bson:"summary"`
+	UserFeedback    string                            `json:"user_feedback" bson:"user_feedback"`
+	Conversation    []ConversationTurn                `json:"conversation,omitempty" bson:"conversation,omitempty"`
+	ReviewStatus    string                            `json:"review_status,omitempty" bson:"review_status,omitempty"` // draft (kosong), submitted, changes_requested, approved
+	ReviewHistory   []ReviewEvent                     `json:"review_history,omitempty" bson:"review_history,omitempty"`
+	CreatedAt       time.Time                         `json:"created_at" bson:"created_at"`
+	CompletedAt     *time.Time                        `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+	Error           string                            `json:"error,omitempty" bson:"error,omitempty"`
+
+	// SummaryVerification diisi jika ringkasan bukan balasan AI, mis. fallback saat refinement AI gagal
+	SummaryVerification *Verification `json:"summary_verification,omitempty" bson:"summary_verification,omitempty"`
 }
 
-// AuditLog untuk logging aktivitas
-type AuditLog struct {
-	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+// ReviewEvent mencatat satu perubahan status review analysis
+type ReviewEvent struct {
+	From      string             `json:"from" bson:"from"`
+	To        string             `json:"to" bson:"to"`
 	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
-	Action    string             `json:"action" bson:"action"`
-	Resource  string             `json:"resource" bson:"resource"`
-	Details   string             `json:"details" bson:"details"`
-	IP        string             `json:"ip" bson:"ip"`
-	UserAgent string             `json:"user_agent" bson:"user_agent"`
+	Note      string             `json:"note,omitempty" bson:"note,omitempty"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 }
 
+// ReviewRequest untuk mengajukan, menyetujui, atau meminta perbaikan analysis
+type ReviewRequest struct {
+	Action string `json:"action"` // submit, withdraw, request_changes, approve
+	Note   string `json:"note,omitempty"`
+}
+
+// Comment adalah komentar pada project, analysis, atau satu hasil metode (Method); ParentID membentuk thread
+type Comment struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ProjectID  primitive.ObjectID `json:"project_id" bson:"project_id"`
+	AnalysisID primitive.ObjectID `json:"analysis_id,omitempty" bson:"analysis_id,omitempty"`
+	Method     string             `json:"method,omitempty" bson:"method,omitempty"`
+	ParentID   primitive.ObjectID `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	AuthorName string             `json:"author_name" bson:"author_name"`
+	Body       string             `json:"body" bson:"body"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+	DeletedAt  *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"` // isi dikosongkan, thread tetap utuh
+	Replies    []*Comment         `json:"replies,omitempty" bson:"-"`
+}
+
+// CommentRequest untuk membuat atau mengubah komentar
+type CommentRequest struct {
+	AnalysisID string `json:"analysis_id,omitempty"`
+	Method     string `json:"method,omitempty"`
+	ParentID   string `json:"parent_id,omitempty"`
+	Body       string `json:"body"`
+}
+
+// Notification adalah notifikasi dalam aplikasi untuk satu pengguna
+type Notification struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Type       string             `json:"type" bson:"type"` // review_submitted, review_approved, review_changes_requested, comment, comment_reply
+	Message    string             `json:"message" bson:"message"`
+	ActorID    primitive.ObjectID `json:"actor_id" bson:"actor_id"`
+	ProjectID  primitive.ObjectID `json:"project_id" bson:"project_id"`
+	AnalysisID primitive.ObjectID `json:"analysis_id,omitempty" bson:"analysis_id,omitempty"`
+	CommentID  primitive.ObjectID `json:"comment_id,omitempty" bson:"comment_id,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	ReadAt     *time.Time         `json:"read_at,omitempty" bson:"read_at,omitempty"`
+}
+
+// InstitutionQuota membatasi pemakaian setiap anggota institusi; 0 berarti tanpa batas
+type InstitutionQuota struct {
+	MaxProjects         int   `json:"max_projects" bson:"max_projects"`
+	MaxStorageBytes     int64 `json:"max_storage_bytes" bson:"max_storage_bytes"`           // total ukuran file upload di project milik anggota
+	MaxAnalysesPerMonth int   `json:"max_analyses_per_month" bson:"max_analyses_per_month"` // analysis baru (rekomendasi dan refinement) per bulan kalender
+}
+
+// InstitutionDefaults adalah pengaturan bawaan anggota institusi, dipakai jika pengguna atau project tidak mengaturnya
+type InstitutionDefaults struct {
+	Language       string   `json:"language,omitempty" bson:"language,omitempty"`
+	Alpha          float64  `json:"alpha,omitempty" bson:"alpha,omitempty"`                     // tingkat signifikansi, mis. 0.05
+	ReportSections []string `json:"report_sections,omitempty" bson:"report_sections,omitempty"` // template laporan bawaan
+}
+
+// Institution adalah tenant (universitas atau lembaga) dengan admin, anggota, kuota, dan pengaturan bawaan
+type Institution struct {
+	ID        primitive.ObjectID  `json:"_id,omitempty" bson:"_id,omitempty"`
+	Name      string              `json:"name" bson:"name"`
+	Domains   []string            `json:"domains,omitempty" bson:"domains,omitempty"` // pemilik email terverifikasi di domain ini otomatis bergabung
+	Quota     InstitutionQuota    `json:"quota" bson:"quota"`
+	Defaults  InstitutionDefaults `json:"defaults" bson:"defaults"`
+	CreatedBy primitive.ObjectID  `json:"created_by" bson:"created_by"`
+	CreatedAt time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" bson:"updated_at"`
+
+	// Require2FA mewajibkan anggota mendaftarkan two-factor authentication sebelum bisa login
+	Require2FA bool `json:"require_2fa" bson:"require_2fa"`
+}
+
+// InstitutionRequest untuk membuat atau mengubah institusi; field kosong tidak diubah
+type InstitutionRequest struct {
+	Name       string               `json:"name,omitempty"`
+	Domains    []string             `json:"domains,omitempty"`
+	Quota      *InstitutionQuota    `json:"quota,omitempty"`
+	Defaults   *InstitutionDefaults `json:"defaults,omitempty"`
+	Require2FA *bool                `json:"require_2fa,omitempty"`
+	AdminEmail string               `json:"admin_email,omitempty"` // admin pertama saat institusi dibuat
+}
+
+// InstitutionMemberRequest untuk menambah anggota institusi atau mengubah perannya
+type InstitutionMemberRequest struct {
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role"`
+}
+
+// InstitutionUsage adalah pemakaian satu anggota terhadap kuota institusi
+type InstitutionUsage struct {
+	Projects          int   `json:"projects"`
+	StorageBytes      int64 `json:"storage_bytes"`
+	AnalysesThisMonth int   `json:"analyses_this_month"`
+}
+
+// AuditLog untuk logging aktivitas
+type AuditLog struct {
+	ID         primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`   // kosong untuk login gagal dengan email tak dikenal
+	Action     string             `json:"action" bson:"action"`     // mis. "auth.login", "project.delete"
+	Resource   string             `json:"resource" bson:"resource"` // jenis resource: user, project, upload, analysis, member, institution
+	ResourceID primitive.ObjectID `json:"resource_id,omitempty" bson:"resource_id,omitempty"`
+	Details    string             `json:"details" bson:"details"`
+	IP         string             `json:"ip" bson:"ip"`
+	UserAgent  string             `json:"user_agent" bson:"user_agent"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
 // LoginRequest untuk request login
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
+// TwoFactorRequest untuk pendaftaran, verifikasi, dan pengelolaan two-factor authentication.
+// MFAToken adalah token pra-autentikasi dari Login; Code adalah code TOTP 6 digit.
+type TwoFactorRequest struct {
+	MFAToken     string `json:"mfa_token,omitempty"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+	Password     string `json:"password,omitempty"`
+}
+
 // RegisterRequest untuk request registrasi
 type RegisterRequest struct {
 	Email         string `json:"email"`
@@ -141,6 +334,108 @@ type RegisterRequest struct {
 	FullName      string `json:"fullName"`
 	Institution   string `json:"institution"`
 	ResearchField string `json:"researchField"`
+	Language      string `json:"language,omitempty"`
+}
+
+// RefreshRequest untuk request refresh token dan logout
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenPair adalah access token berumur pendek beserta refresh token yang dirotasi setiap dipakai
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresIn        int64     `json:"expires_in"` // detik
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// Session adalah sesi login; refresh token hanya disimpan dalam bentuk hash SHA-256
+type Session struct {
+	ID           primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	UserID       primitive.ObjectID `json:"user_id" bson:"user_id"`
+	RefreshHash  string             `json:"-" bson:"refresh_hash"`
+	PreviousHash string             `json:"-" bson:"previous_hash,omitempty"` // hash sebelum rotasi, untuk deteksi pemakaian ulang
+	UserAgent    string             `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	IPAddress    string             `json:"ip_address,omitempty" bson:"ip_address,omitempty"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	LastUsedAt   time.Time          `json:"last_used_at" bson:"last_used_at"`
+	ExpiresAt    time.Time          `json:"expires_at" bson:"expires_at"`
+	RevokedAt    *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+// SigningKey adalah kunci penandatangan token di key ring; private key disimpan terenkripsi
+type SigningKey struct {
+	ID          primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	KeyID       string             `json:"kid" bson:"kid"`
+	PublicKey   string             `json:"public_key" bson:"public_key"`
+	PrivateKey  string             `json:"-" bson:"private_key,omitempty"` // kosong untuk kunci dari env atau yang sudah pensiun
+	Source      string             `json:"source" bson:"source"`           // "generated" atau "env"
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	ActivatesAt time.Time          `json:"activates_at" bson:"activates_at"`
+	RetireAt    *time.Time         `json:"retire_at,omitempty" bson:"retire_at,omitempty"`
+}
+
+// APIKey adalah kunci API pribadi untuk akses terprogram (mis. dari notebook); hanya hash SHA-256 yang disimpan
+type APIKey struct {
+	ID         primitive.ObjectID   `json:"_id,omitempty" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID   `json:"user_id" bson:"user_id"`
+	Name       string               `json:"name" bson:"name"`
+	Prefix     string               `json:"prefix" bson:"prefix"` // awal kunci agar mudah dikenali, mis. "rda_AbC12345"
+	KeyHash    string               `json:"-" bson:"key_hash"`
+	Scopes     []string             `json:"scopes" bson:"scopes"`                               // "read", "write", dan/atau "export"
+	ProjectIDs []primitive.ObjectID `json:"project_ids,omitempty" bson:"project_ids,omitempty"` // kosong berarti semua project pengguna
+	CreatedAt  time.Time            `json:"created_at" bson:"created_at"`
+	ExpiresAt  time.Time            `json:"expires_at" bson:"expires_at"`
+	LastUsedAt *time.Time           `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	LastUsedIP string               `json:"last_used_ip,omitempty" bson:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time           `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"`
+}
+
+// APIKeyRequest untuk membuat API key
+type APIKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ProjectIDs    []string `json:"project_ids,omitempty"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty"`
+}
+
+// UserToken adalah token sekali pakai untuk verifikasi email dan reset password; hanya hash yang disimpan
+type UserToken struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Purpose   string             `json:"purpose" bson:"purpose"` // "verify_email", "reset_password", "two_factor_login", atau "two_factor_setup"
+	TokenHash string             `json:"-" bson:"token_hash"`
+	Email     string             `json:"email" bson:"email"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	UsedAt    *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"`
+	Attempts  int                `json:"-" bson:"attempts,omitempty"` // percobaan code 2FA yang gagal untuk token pra-autentikasi
+}
+
+// VerifyEmailRequest untuk request verifikasi email
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ForgotPasswordRequest untuk request tautan reset password
+type ForgotPasswordRequest struct {
+	Email    string `json:"email"`
+	Language string `json:"language,omitempty"`
+}
+
+// ResetPasswordRequest untuk request password baru dengan token reset
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ProfileRequest untuk request update profil
+type ProfileRequest struct {
+	FullName      string `json:"fullName,omitempty"`
+	Institution   string `json:"institution,omitempty"`
+	ResearchField string `json:"researchField,omitempty"`
+	Language      string `json:"language,omitempty"`
 }
 
 // ProjectRequest untuk request project
@@ -157,23 +452,56 @@ type RecommendRequest struct {
 	UploadID string   `json:"upload_id"`
 	Context  string   `json:"context,omitempty"`
 	Specific []string `json:"specific,omitempty"`
+	Language string   `json:"language,omitempty"`
 }
 
 // ProcessRequest untuk request proses analisis
 type ProcessRequest struct {
 	AnalysisID      string   `json:"analysis_id"`
 	SelectedMethods []string `json:"selected_methods"`
+	// Options berisi opsi per metode (kunci: nama atau ID metode), mis. {"independent_t_test": {"group": "gender"}}
+	Options  map[string]map[string]interface{} `json:"options,omitempty"`
+	Language string                            `json:"language,omitempty"`
 }
 
 // RefineRequest untuk request refinement
 type RefineRequest struct {
-	Feedback    string   `json:"feedback"`
-	NewMethods  []string `json:"new_methods,omitempty"`
-	Adjustments string   `json:"adjustments,omitempty"`
+	Feedback     string   `json:"feedback"`
+	Instructions string   `json:"instructions,omitempty"` // alias lama untuk Feedback
+	NewMethods   []string `json:"new_methods,omitempty"`
+	Adjustments  string   `json:"adjustments,omitempty"`
+	// Rerun menjalankan ulang metode (NewMethods, atau seluruh metode terpilih jika kosong) sebelum membalas
+	Rerun bool `json:"rerun,omitempty"`
+	// Options mengganti opsi per metode (kunci: nama atau ID metode) untuk metode yang dijalankan ulang
+	Options  map[string]map[string]interface{} `json:"options,omitempty"`
+	Language string                            `json:"language,omitempty"`
 }
 
 // ExportRequest untuk request export
 type ExportRequest struct {
-	Format   string   `json:"format"`
-	Sections []string `json:"sections,omitempty"`
+	Format     string   `json:"format"`
+	Sections   []string `json:"sections,omitempty"`
+	Language   string   `json:"language,omitempty"`
+	TemplateID string   `json:"template_id,omitempty"`
+}
+
+// ReportTemplate menyimpan susunan bagian laporan yang dapat dipakai ulang dalam satu proyek
+type ReportTemplate struct {
+	ID        primitive.ObjectID `json:"_id,omitempty" bson:"_id,omitempty"`
+	ProjectID primitive.ObjectID `json:"project_id" bson:"project_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name      string             `json:"name" bson:"name"`
+	Sections  []string           `json:"sections" bson:"sections"`
+	Language  string             `json:"language,omitempty" bson:"language,omitempty"`
+	IsDefault bool               `json:"is_default" bson:"is_default"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// ReportTemplateRequest untuk membuat atau mengubah template laporan
+type ReportTemplateRequest struct {
+	Name      string   `json:"name"`
+	Sections  []string `json:"sections"`
+	Language  string   `json:"language,omitempty"`
+	IsDefault bool     `json:"is_default"`
 }
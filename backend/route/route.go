@@ -2,6 +2,7 @@ package route
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/research-data-analysis/config"
 	"github.com/research-data-analysis/controller"
@@ -40,6 +41,12 @@ func URL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	// Verifikasi API key (scope, masa berlaku, endpoint terlarang) sebelum routing
+	r, ok := controller.AuthenticateAPIKey(w, r)
+	if !ok {
+		return
+	}
+
 	method := r.Method
 	path := r.URL.Path
 
@@ -56,6 +63,36 @@ func URL(w http.ResponseWriter, r *http.Request) {
 		controller.Login(w, r)
 	case method == "GET" && path == "/auth/profile":
 		controller.GetProfile(w, r)
+	case method == "PUT" && path == "/auth/profile":
+		controller.UpdateProfile(w, r)
+	case method == "POST" && path == "/auth/refresh":
+		controller.RefreshToken(w, r)
+	case method == "POST" && path == "/auth/logout":
+		controller.Logout(w, r)
+	case method == "POST" && path == "/auth/logout-all":
+		controller.LogoutAll(w, r)
+	case method == "GET" && path == "/auth/sessions":
+		controller.GetSessions(w, r)
+	case method == "POST" && path == "/auth/verify-email":
+		controller.VerifyEmail(w, r)
+	case method == "POST" && path == "/auth/resend-verification":
+		controller.ResendVerification(w, r)
+	case method == "POST" && path == "/auth/forgot-password":
+		controller.ForgotPassword(w, r)
+	case method == "POST" && path == "/auth/reset-password":
+		controller.ResetPassword(w, r)
+	case method == "GET" && path == "/auth/2fa":
+		controller.GetTwoFactorStatus(w, r)
+	case method == "POST" && path == "/auth/2fa/setup":
+		controller.SetupTwoFactor(w, r)
+	case method == "POST" && path == "/auth/2fa/enable":
+		controller.EnableTwoFactor(w, r)
+	case method == "POST" && path == "/auth/2fa/verify":
+		controller.VerifyTwoFactor(w, r)
+	case method == "POST" && path == "/auth/2fa/disable":
+		controller.DisableTwoFactor(w, r)
+	case method == "POST" && path == "/auth/2fa/recovery-codes":
+		controller.RegenerateRecoveryCodes(w, r)
 
 	// Project endpoints
 	case method == "POST" && path == "/api/project":
@@ -76,6 +113,9 @@ func URL(w http.ResponseWriter, r *http.Request) {
 	case method == "POST" && at.URLParam(path, "/api/upload/:projectId"):
 		projectID := at.GetURLParam(path, "/api/upload/:projectId", "projectId")
 		controller.UploadData(w, r, projectID)
+	case method == "DELETE" && at.URLParam(path, "/api/upload/:uploadId"):
+		uploadID := at.GetURLParam(path, "/api/upload/:uploadId", "uploadId")
+		controller.DeleteUpload(w, r, uploadID)
 	case method == "GET" && at.URLParam(path, "/api/preview/:uploadId"):
 		uploadID := at.GetURLParam(path, "/api/preview/:uploadId", "uploadId")
 		controller.GetDataPreview(w, r, uploadID)
@@ -96,12 +136,131 @@ func URL(w http.ResponseWriter, r *http.Request) {
 	case method == "POST" && at.URLParam(path, "/api/refine/:analysisId"):
 		analysisID := at.GetURLParam(path, "/api/refine/:analysisId", "analysisId")
 		controller.RefineAnalysis(w, r, analysisID)
+	case method == "GET" && at.URLParam(path, "/api/lineage/:analysisId"):
+		analysisID := at.GetURLParam(path, "/api/lineage/:analysisId", "analysisId")
+		controller.GetAnalysisLineage(w, r, analysisID)
 
 	// Export endpoint
-	case method == "GET" && at.URLParam(path, "/api/export/:analysisId"):
+	case (method == "GET" || method == "POST") && at.URLParam(path, "/api/export/:analysisId"):
 		analysisID := at.GetURLParam(path, "/api/export/:analysisId", "analysisId")
 		controller.ExportResults(w, r, analysisID)
 
+	// Report template endpoints
+	case method == "GET" && at.URLParam(path, "/api/project/:id/templates"):
+		projectID := at.GetURLParam(path, "/api/project/:id/templates", "id")
+		controller.GetReportTemplates(w, r, projectID)
+	case method == "POST" && at.URLParam(path, "/api/project/:id/templates"):
+		projectID := at.GetURLParam(path, "/api/project/:id/templates", "id")
+		controller.CreateReportTemplate(w, r, projectID)
+	case method == "PUT" && at.URLParam(path, "/api/template/:id"):
+		templateID := at.GetURLParam(path, "/api/template/:id", "id")
+		controller.UpdateReportTemplate(w, r, templateID)
+	case method == "DELETE" && at.URLParam(path, "/api/template/:id"):
+		templateID := at.GetURLParam(path, "/api/template/:id", "id")
+		controller.DeleteReportTemplate(w, r, templateID)
+
+	// Project collaborator endpoints
+	case method == "GET" && at.URLParam(path, "/api/project/:id/members"):
+		projectID := at.GetURLParam(path, "/api/project/:id/members", "id")
+		controller.GetProjectMembers(w, r, projectID)
+	case method == "POST" && at.URLParam(path, "/api/project/:id/members"):
+		projectID := at.GetURLParam(path, "/api/project/:id/members", "id")
+		controller.InviteMember(w, r, projectID)
+	case method == "PUT" && at.URLParam(path, "/api/project/:id/members/:memberId"):
+		projectID := at.GetURLParam(path, "/api/project/:id/members/:memberId", "id")
+		memberID := at.GetURLParam(path, "/api/project/:id/members/:memberId", "memberId")
+		controller.UpdateMember(w, r, projectID, memberID)
+	case method == "DELETE" && at.URLParam(path, "/api/project/:id/members/:memberId"):
+		projectID := at.GetURLParam(path, "/api/project/:id/members/:memberId", "id")
+		memberID := at.GetURLParam(path, "/api/project/:id/members/:memberId", "memberId")
+		controller.RemoveMember(w, r, projectID, memberID)
+	case method == "GET" && path == "/api/invitations":
+		controller.GetInvitations(w, r)
+	case method == "POST" && at.URLParam(path, "/api/invitations/:id/accept"):
+		invitationID := at.GetURLParam(path, "/api/invitations/:id/accept", "id")
+		controller.RespondInvitation(w, r, invitationID, true)
+	case method == "POST" && at.URLParam(path, "/api/invitations/:id/decline"):
+		invitationID := at.GetURLParam(path, "/api/invitations/:id/decline", "id")
+		controller.RespondInvitation(w, r, invitationID, false)
+
+	// Comment, review, and notification endpoints
+	case method == "GET" && at.URLParam(path, "/api/project/:id/comments"):
+		projectID := at.GetURLParam(path, "/api/project/:id/comments", "id")
+		controller.GetComments(w, r, projectID)
+	case method == "POST" && at.URLParam(path, "/api/project/:id/comments"):
+		projectID := at.GetURLParam(path, "/api/project/:id/comments", "id")
+		controller.CreateComment(w, r, projectID)
+	case method == "PUT" && at.URLParam(path, "/api/comment/:id"):
+		commentID := at.GetURLParam(path, "/api/comment/:id", "id")
+		controller.UpdateComment(w, r, commentID)
+	case method == "DELETE" && at.URLParam(path, "/api/comment/:id"):
+		commentID := at.GetURLParam(path, "/api/comment/:id", "id")
+		controller.DeleteComment(w, r, commentID)
+	case method == "POST" && at.URLParam(path, "/api/review/:analysisId"):
+		analysisID := at.GetURLParam(path, "/api/review/:analysisId", "analysisId")
+		controller.ReviewAnalysis(w, r, analysisID)
+	case method == "GET" && path == "/api/notifications":
+		controller.GetNotifications(w, r)
+	case method == "POST" && path == "/api/notifications/read-all":
+		controller.MarkNotificationsRead(w, r, "")
+	case method == "POST" && at.URLParam(path, "/api/notifications/:id/read"):
+		notificationID := at.GetURLParam(path, "/api/notifications/:id/read", "id")
+		controller.MarkNotificationsRead(w, r, notificationID)
+
+	// Institution and admin console endpoints
+	case method == "GET" && path == "/api/institution":
+		controller.GetMyInstitution(w, r)
+	case method == "GET" && path == "/api/admin/institutions":
+		controller.GetInstitutions(w, r)
+	case method == "POST" && path == "/api/admin/institutions":
+		controller.CreateInstitution(w, r)
+	case method == "GET" && at.URLParam(path, "/api/admin/institutions/:id"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id", "id")
+		controller.GetInstitution(w, r, institutionID)
+	case method == "PUT" && at.URLParam(path, "/api/admin/institutions/:id"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id", "id")
+		controller.UpdateInstitution(w, r, institutionID)
+	case method == "DELETE" && at.URLParam(path, "/api/admin/institutions/:id"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id", "id")
+		controller.DeleteInstitution(w, r, institutionID)
+	case method == "GET" && at.URLParam(path, "/api/admin/institutions/:id/users"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id/users", "id")
+		controller.GetInstitutionUsers(w, r, institutionID)
+	case method == "POST" && at.URLParam(path, "/api/admin/institutions/:id/users"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id/users", "id")
+		controller.AddInstitutionUser(w, r, institutionID)
+	case method == "PUT" && at.URLParam(path, "/api/admin/institutions/:id/users/:userId"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id/users/:userId", "id")
+		userID := at.GetURLParam(path, "/api/admin/institutions/:id/users/:userId", "userId")
+		controller.UpdateInstitutionUser(w, r, institutionID, userID)
+	case method == "DELETE" && at.URLParam(path, "/api/admin/institutions/:id/users/:userId"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id/users/:userId", "id")
+		userID := at.GetURLParam(path, "/api/admin/institutions/:id/users/:userId", "userId")
+		controller.RemoveInstitutionUser(w, r, institutionID, userID)
+	case method == "GET" && at.URLParam(path, "/api/admin/institutions/:id/stats"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id/stats", "id")
+		controller.GetInstitutionStats(w, r, institutionID)
+	case method == "GET" && at.URLParam(path, "/api/admin/institutions/:id/activity"):
+		institutionID := at.GetURLParam(path, "/api/admin/institutions/:id/activity", "id")
+		controller.GetInstitutionActivity(w, r, institutionID)
+
+	// API key endpoints
+	case method == "GET" && path == "/api/keys":
+		controller.GetAPIKeys(w, r)
+	case method == "POST" && path == "/api/keys":
+		controller.CreateAPIKey(w, r)
+	case method == "DELETE" && at.URLParam(path, "/api/keys/:id"):
+		keyID := at.GetURLParam(path, "/api/keys/:id", "id")
+		controller.RevokeAPIKey(w, r, keyID)
+
+	// Audit log endpoint
+	case method == "GET" && path == "/api/audit-logs":
+		controller.GetAuditLogs(w, r)
+
+	// Stored files (signed URL backend local)
+	case (method == "GET" || method == "HEAD") && strings.HasPrefix(path, "/files/"):
+		controller.ServeStoredFile(w, r, strings.TrimPrefix(path, "/files/"))
+
 	// 404 Not Found
 	default:
 		NotFound(w, r)
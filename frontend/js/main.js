@@ -53,13 +53,29 @@ document.addEventListener('DOMContentLoaded', () => {
 
 function initApp() {
     // Check if user is logged in
+    // Refresh token lebih awet dari access token: perbarui sesi dulu sebelum navigasi
+    if (getCookie('refresh_token')) {
+        refreshSession((ok) => {
+            if (ok) {
+                loadUserProfile();
+            } else {
+                updateAuthUI(false);
+            }
+            navigateFromHash();
+        });
+        return;
+    }
+    
     const token = getCookie('token');
     if (token) {
         loadUserProfile();
     } else {
         updateAuthUI(false);
     }
-    
+    navigateFromHash();
+}
+
+function navigateFromHash() {
     // Navigate based on hash or default
     const hash = getHash();
     if (hash) {
@@ -69,6 +85,54 @@ function initApp() {
     }
 }
 
+// ===== Session Management =====
+let refreshTimer = null;
+
+function storeSession(data) {
+    const expiresIn = data.expires_in || 24 * 3600;
+    setCookieWithExpireHour('token', data.token, expiresIn / 3600);
+    if (data.refresh_token) {
+        const refreshHours = data.refresh_expires_at
+            ? (new Date(data.refresh_expires_at) - Date.now()) / 3600000
+            : 24 * 30;
+        setCookieWithExpireHour('refresh_token', data.refresh_token, refreshHours);
+        
+        // Perbarui access token satu menit sebelum kedaluwarsa
+        clearTimeout(refreshTimer);
+        refreshTimer = setTimeout(() => refreshSession(), Math.max(expiresIn - 60, 30) * 1000);
+    }
+}
+
+function clearSession() {
+    clearTimeout(refreshTimer);
+    deleteCookie('token');
+    deleteCookie('refresh_token');
+    currentUser = null;
+}
+
+function refreshSession(onDone) {
+    const refreshToken = getCookie('refresh_token');
+    if (!refreshToken) {
+        if (onDone) onDone(false);
+        return;
+    }
+    
+    postJSON(
+        `${API_BASE_URL}/auth/refresh`,
+        { refresh_token: refreshToken },
+        (response) => {
+            if (response.status === 200) {
+                storeSession(response.data.data);
+                if (onDone) onDone(true);
+            } else {
+                clearSession();
+                updateAuthUI(false);
+                if (onDone) onDone(false);
+            }
+        }
+    );
+}
+
 function setupHashNavigation() {
     onHashChange((event) => {
         const hash = getHash();
@@ -79,7 +143,23 @@ function setupHashNavigation() {
 }
 
 // ===== Navigation =====
-window.navigateTo = function(page) {
+window.navigateTo = function(target) {
+    // Hash bisa membawa parameter, mis. "reset-password?token=..."
+    const [page, query] = target.split('?');
+    const params = new URLSearchParams(query || '');
+    
+    // Tautan verifikasi email tidak punya halaman sendiri
+    if (page === 'verify-email') {
+        verifyEmail(params.get('token'));
+        return;
+    }
+    
+    // Tautan dari email/notifikasi membuka project berdasarkan ID
+    if (page === 'project' && params.get('id') && getCookie('token')) {
+        openProject(params.get('id'));
+        return;
+    }
+    
     // Hide all pages
     document.querySelectorAll('.page').forEach(p => p.classList.remove('active'));
     
@@ -98,12 +178,12 @@ window.navigateTo = function(page) {
     });
     
     // Update hash without triggering navigation
-    if (getHash() !== page) {
-        setHash(page);
+    if (getHash() !== target) {
+        setHash(target);
     }
     
     // Protected pages - require login
-    const protectedPages = ['dashboard', 'projects', 'new-project', 'project', 'profile'];
+    const protectedPages = ['dashboard', 'projects', 'new-project', 'project', 'profile', 'invitations', 'notifications', 'activity', 'api-keys', 'security'];
     if (protectedPages.includes(page) && !getCookie('token')) {
         showToast('Silakan login terlebih dahulu', 'warning');
         navigateTo('login');
@@ -113,6 +193,20 @@ window.navigateTo = function(page) {
     // Page-specific actions
     if (page === 'dashboard') {
         loadDashboard();
+    } else if (page === 'invitations') {
+        loadInvitations();
+    } else if (page === 'notifications') {
+        loadNotifications();
+    } else if (page === 'activity') {
+        loadActivity(1);
+    } else if (page === 'security') {
+        hide('recovery-codes-box');
+        loadTwoFactorStatus();
+    } else if (page === 'api-keys') {
+        hide('api-key-created');
+        loadAPIKeys();
+    } else if (page === 'reset-password') {
+        document.getElementById('reset-token').value = params.get('token') || '';
     }
 };
 
@@ -136,11 +230,12 @@ window.handleLogin = async function(event) {
         (response) => {
             hideLoading();
             if (response.status === 200) {
-                const data = response.data;
-                setCookieWithExpireHour('token', data.data.token, 24);
-                currentUser = data.data.user;
-                updateAuthUI(true);
-                showToast('Login berhasil!', 'success');
+                const data = response.data.data;
+                if (data.two_factor_required || data.two_factor_setup_required) {
+                    showTwoFactorStep(data);
+                    return;
+                }
+                finishLogin(data);
                 navigateTo('dashboard');
             } else {
                 showToast(response.data.message || 'Login gagal', 'error');
@@ -149,6 +244,204 @@ window.handleLogin = async function(event) {
     );
 };
 
+function finishLogin(data) {
+    storeSession(data);
+    currentUser = data.user;
+    updateAuthUI(true);
+    showToast('Login berhasil!', 'success');
+    hide('two-factor-form');
+    show('login-form');
+    pendingMFA = null;
+}
+
+// ===== Two-Factor Authentication =====
+// Token pra-autentikasi dari login sampai kode 2FA diverifikasi
+let pendingMFA = null;
+
+function showTwoFactorStep(data) {
+    pendingMFA = { token: data.mfa_token, setup: data.two_factor_setup_required };
+    hide('login-form');
+    show('two-factor-form');
+    setValue('two-factor-code', '');
+    if (!pendingMFA.setup) {
+        hide('two-factor-login-setup');
+        setInner('two-factor-info', 'Masukkan kode dari aplikasi authenticator Anda, atau salah satu recovery code.');
+        return;
+    }
+    
+    setInner('two-factor-info', 'Institusi Anda mewajibkan two-factor authentication. Daftarkan aplikasi authenticator untuk melanjutkan.');
+    postJSON(
+        `${API_BASE_URL}/auth/2fa/setup`,
+        { mfa_token: pendingMFA.token },
+        (response) => {
+            if (response.status !== 200) {
+                showToast(response.data.message || 'Gagal memulai pendaftaran 2FA', 'error');
+                return;
+            }
+            document.getElementById('two-factor-login-uri').href = response.data.data.otpauth_uri;
+            setInner('two-factor-login-secret', escapeHtml(response.data.data.secret));
+            show('two-factor-login-setup');
+        }
+    );
+}
+
+window.handleTwoFactor = function(event) {
+    event.preventDefault();
+    if (!pendingMFA) return;
+    
+    const input = getValue('two-factor-code').trim();
+    const isTOTP = /^\d{6}$/.test(input.replace(/\s/g, ''));
+    const url = pendingMFA.setup ? `${API_BASE_URL}/auth/2fa/enable` : `${API_BASE_URL}/auth/2fa/verify`;
+    const body = { mfa_token: pendingMFA.token };
+    if (isTOTP || pendingMFA.setup) {
+        body.code = input;
+    } else {
+        body.recovery_code = input;
+    }
+    
+    showLoading();
+    postJSON(url, body, (response) => {
+        hideLoading();
+        if (response.status !== 200) {
+            showToast(response.data.message || 'Kode tidak valid', 'error');
+            return;
+        }
+        const data = response.data.data;
+        const wasSetup = pendingMFA.setup;
+        finishLogin(data);
+        if (wasSetup) {
+            navigateTo('security');
+            showRecoveryCodes(data.recovery_codes);
+        } else {
+            navigateTo('dashboard');
+        }
+    });
+};
+
+function loadTwoFactorStatus() {
+    const token = getCookie('token');
+    if (!token) return;
+    
+    getJSON(
+        `${API_BASE_URL}/auth/2fa`,
+        (response) => {
+            if (response.status !== 200) return;
+            const status = response.data.data;
+            const required = status.required ? ' Institusi Anda mewajibkan 2FA.' : '';
+            if (status.enabled) {
+                setInner('two-factor-status', `2FA aktif. Sisa recovery code: ${status.recovery_codes_remaining}.${required}`);
+                hide('two-factor-enroll');
+                show('two-factor-manage');
+                document.getElementById('two-factor-disable-form').classList.toggle('hidden', status.required);
+            } else {
+                setInner('two-factor-status', `2FA belum aktif.${required}`);
+                show('two-factor-enroll');
+                show('two-factor-start');
+                hide('two-factor-enable-form');
+                hide('two-factor-manage');
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+}
+
+function showRecoveryCodes(codes) {
+    setInner('recovery-codes', (codes || []).map(escapeHtml).join('\n'));
+    show('recovery-codes-box');
+}
+
+window.startTwoFactorSetup = function() {
+    const token = getCookie('token');
+    postJSON(
+        `${API_BASE_URL}/auth/2fa/setup`,
+        {},
+        (response) => {
+            if (response.status !== 200) {
+                showToast(response.data.message || 'Gagal memulai pendaftaran 2FA', 'error');
+                return;
+            }
+            document.getElementById('two-factor-uri').href = response.data.data.otpauth_uri;
+            setInner('two-factor-secret', escapeHtml(response.data.data.secret));
+            hide('two-factor-start');
+            show('two-factor-enable-form');
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+window.handleEnableTwoFactor = function(event) {
+    event.preventDefault();
+    const token = getCookie('token');
+    postJSON(
+        `${API_BASE_URL}/auth/2fa/enable`,
+        { code: getValue('two-factor-enable-code') },
+        (response) => {
+            if (response.status !== 200) {
+                showToast(response.data.message || 'Kode tidak valid', 'error');
+                return;
+            }
+            setValue('two-factor-enable-code', '');
+            showToast('2FA berhasil diaktifkan', 'success');
+            showRecoveryCodes(response.data.data.recovery_codes);
+            loadTwoFactorStatus();
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+window.handleRegenerateRecoveryCodes = function(event) {
+    event.preventDefault();
+    const token = getCookie('token');
+    postJSON(
+        `${API_BASE_URL}/auth/2fa/recovery-codes`,
+        { code: getValue('recovery-regenerate-code') },
+        (response) => {
+            if (response.status !== 200) {
+                showToast(response.data.message || 'Kode tidak valid', 'error');
+                return;
+            }
+            setValue('recovery-regenerate-code', '');
+            showRecoveryCodes(response.data.data.recovery_codes);
+            loadTwoFactorStatus();
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+window.handleDisableTwoFactor = function(event) {
+    event.preventDefault();
+    const token = getCookie('token');
+    const input = getValue('two-factor-disable-code').trim();
+    const body = { password: getValue('two-factor-disable-password') };
+    if (/^\d{6}$/.test(input.replace(/\s/g, ''))) {
+        body.code = input;
+    } else {
+        body.recovery_code = input;
+    }
+    
+    postJSON(
+        `${API_BASE_URL}/auth/2fa/disable`,
+        body,
+        (response) => {
+            if (response.status !== 200) {
+                showToast(response.data.message || 'Gagal menonaktifkan 2FA', 'error');
+                return;
+            }
+            setValue('two-factor-disable-password', '');
+            setValue('two-factor-disable-code', '');
+            hide('recovery-codes-box');
+            showToast('2FA dinonaktifkan', 'success');
+            loadTwoFactorStatus();
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
 window.handleRegister = async function(event) {
     event.preventDefault();
     
@@ -183,10 +476,10 @@ window.handleRegister = async function(event) {
             hideLoading();
             if (response.status === 201) {
                 const data = response.data;
-                setCookieWithExpireHour('token', data.data.token, 24);
+                storeSession(data.data);
                 currentUser = data.data.user;
                 updateAuthUI(true);
-                showToast('Registrasi berhasil!', 'success');
+                showToast('Registrasi berhasil! Cek email Anda untuk verifikasi.', 'success');
                 navigateTo('dashboard');
             } else {
                 showToast(response.data.message || 'Registrasi gagal', 'error');
@@ -195,9 +488,100 @@ window.handleRegister = async function(event) {
     );
 };
 
+function verifyEmail(token) {
+    if (!token) {
+        showToast('Tautan verifikasi tidak valid', 'error');
+        navigateTo('home');
+        return;
+    }
+    
+    showLoading();
+    postJSON(
+        `${API_BASE_URL}/auth/verify-email`,
+        { token },
+        (response) => {
+            hideLoading();
+            if (response.status === 200) {
+                showToast('Email berhasil diverifikasi', 'success');
+                if (currentUser) currentUser.emailVerified = true;
+            } else {
+                showToast(response.data.message || 'Verifikasi email gagal', 'error');
+            }
+            navigateTo(getCookie('token') ? 'dashboard' : 'login');
+        }
+    );
+}
+
+window.handleForgotPassword = function(event) {
+    event.preventDefault();
+    
+    const email = getValue('forgot-email');
+    if (!email) {
+        showToast('Mohon isi email', 'error');
+        return;
+    }
+    
+    showLoading();
+    postJSON(
+        `${API_BASE_URL}/auth/forgot-password`,
+        { email },
+        (response) => {
+            hideLoading();
+            if (response.status === 200) {
+                showToast('Jika email terdaftar, tautan reset password telah dikirim', 'success');
+                navigateTo('login');
+            } else {
+                showToast(response.data.message || 'Gagal mengirim tautan reset', 'error');
+            }
+        }
+    );
+};
+
+window.handleResetPassword = function(event) {
+    event.preventDefault();
+    
+    const token = getValue('reset-token');
+    const password = getValue('reset-password-input');
+    const confirm = getValue('reset-password-confirm');
+    
+    if (!token) {
+        showToast('Tautan reset password tidak valid', 'error');
+        return;
+    }
+    if (password.length < 8) {
+        showToast('Password minimal 8 karakter', 'error');
+        return;
+    }
+    if (password !== confirm) {
+        showToast('Konfirmasi password tidak sama', 'error');
+        return;
+    }
+    
+    showLoading();
+    postJSON(
+        `${API_BASE_URL}/auth/reset-password`,
+        { token, password },
+        (response) => {
+            hideLoading();
+            if (response.status === 200) {
+                // Semua sesi dicabut oleh server, jadi sesi lokal ikut dihapus
+                clearSession();
+                updateAuthUI(false);
+                showToast('Password berhasil diubah, silakan login kembali', 'success');
+                navigateTo('login');
+            } else {
+                showToast(response.data.message || 'Reset password gagal', 'error');
+            }
+        }
+    );
+};
+
 window.logout = function() {
-    deleteCookie('token');
-    currentUser = null;
+    const refreshToken = getCookie('refresh_token');
+    if (refreshToken) {
+        postJSON(`${API_BASE_URL}/auth/logout`, { refresh_token: refreshToken }, () => {});
+    }
+    clearSession();
     updateAuthUI(false);
     showToast('Berhasil logout', 'success');
     navigateTo('home');
@@ -213,8 +597,9 @@ function loadUserProfile() {
             if (response.status === 200) {
                 currentUser = response.data.data;
                 updateAuthUI(true);
+                loadNotifications();
             } else {
-                deleteCookie('token');
+                clearSession();
                 updateAuthUI(false);
             }
         },
@@ -387,8 +772,136 @@ function renderProjectDetail(project) {
     
     // Update next steps based on status
     updateNextSteps(project.status);
+    
+    loadMembers(project);
+}
+
+// ===== Collaborators =====
+function loadMembers(project) {
+    const token = getCookie('token');
+    // Hanya owner yang dapat mengundang kolaborator
+    document.getElementById('invite-form').classList.toggle('hidden', project.role !== 'owner');
+    
+    getJSON(
+        `${API_BASE_URL}/api/project/${project._id}/members`,
+        (response) => {
+            if (response.status === 200) {
+                renderMembers(project, response.data.data.members || []);
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+}
+
+function renderMembers(project, members) {
+    const container = document.getElementById('members-list');
+    container.innerHTML = members.map(m => {
+        const isSelf = currentUser && m.user_id === currentUser._id;
+        const canRemove = !m.creator && (project.role === 'owner' || isSelf);
+        const status = m.status === 'pending' ? ' <small>(menunggu)</small>' : '';
+        return `
+            <div class="member-item">
+                <span>${escapeHtml(m.full_name || m.email)}${status}<br><small>${getRoleName(m.role)}</small></span>
+                ${canRemove ? `<button class="btn btn-ghost" onclick="removeMember('${m._id}')">${isSelf ? 'Keluar' : 'Hapus'}</button>` : ''}
+            </div>
+        `;
+    }).join('');
+}
+
+window.handleInviteMember = function(event) {
+    event.preventDefault();
+    if (!currentProject) return;
+    
+    const token = getCookie('token');
+    const email = getValue('invite-email');
+    const role = getValue('invite-role');
+    
+    postJSON(
+        `${API_BASE_URL}/api/project/${currentProject._id}/members`,
+        { email, role },
+        (response) => {
+            if (response.status === 201) {
+                showToast('Undangan terkirim', 'success');
+                setValue('invite-email', '');
+                loadMembers(currentProject);
+            } else {
+                showToast(response.data.message || 'Gagal mengirim undangan', 'error');
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+window.removeMember = async function(memberId) {
+    if (!currentProject) return;
+    
+    const response = await fetch(`${API_BASE_URL}/api/project/${currentProject._id}/members/${memberId}`, {
+        method: 'DELETE',
+        headers: { 'Authorization': `Bearer ${getCookie('token')}` }
+    });
+    const result = await response.json();
+    if (response.ok) {
+        showToast('Kolaborator dihapus', 'success');
+        loadMembers(currentProject);
+    } else {
+        showToast(result.message || 'Gagal menghapus kolaborator', 'error');
+    }
+};
+
+function loadInvitations() {
+    const token = getCookie('token');
+    if (!token) return;
+    
+    getJSON(
+        `${API_BASE_URL}/api/invitations`,
+        (response) => {
+            const container = document.getElementById('invitations-list');
+            if (response.status !== 200) {
+                container.innerHTML = '<p class="empty-state">Gagal memuat undangan</p>';
+                return;
+            }
+            const data = response.data.data;
+            const invitations = data.invitations || [];
+            if (invitations.length === 0) {
+                container.innerHTML = '<p class="empty-state">Tidak ada undangan</p>';
+                return;
+            }
+            const notice = data.email_verified ? '' : '<p><small>Verifikasi email Anda terlebih dahulu untuk menerima undangan.</small></p>';
+            container.innerHTML = notice + invitations.map(inv => `
+                <div class="member-item">
+                    <span>${escapeHtml(inv.project_title || '-')}<br><small>${getRoleName(inv.role)} &middot; ${escapeHtml(inv.invited_by || '')}</small></span>
+                    <span>
+                        <button class="btn btn-primary" onclick="respondInvitation('${inv._id}', true)">Terima</button>
+                        <button class="btn btn-ghost" onclick="respondInvitation('${inv._id}', false)">Tolak</button>
+                    </span>
+                </div>
+            `).join('');
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
 }
 
+window.respondInvitation = function(invitationId, accept) {
+    const token = getCookie('token');
+    postJSON(
+        `${API_BASE_URL}/api/invitations/${invitationId}/${accept ? 'accept' : 'decline'}`,
+        {},
+        (response) => {
+            if (response.status === 200) {
+                showToast(accept ? 'Undangan diterima' : 'Undangan ditolak', 'success');
+                loadInvitations();
+            } else {
+                showToast(response.data.message || 'Gagal memproses undangan', 'error');
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
 function updateNextSteps(status) {
     const steps = document.querySelectorAll('.step-item');
     const statusOrder = ['draft', 'uploaded', 'analyzing', 'completed'];
@@ -696,6 +1209,8 @@ window.processAnalysis = function() {
 function renderResults(results, summary) {
     hide('results-empty');
     show('results-content');
+    loadReview();
+    loadComments();
     
     const resultsList = document.getElementById('results-list');
     resultsList.innerHTML = results.map(result => `
@@ -720,6 +1235,279 @@ function renderResults(results, summary) {
     }
 }
 
+// ===== Review & Comments =====
+const reviewActionLabels = {
+    submit: 'Ajukan untuk Ditinjau',
+    withdraw: 'Tarik Pengajuan',
+    approve: 'Setujui',
+    request_changes: 'Minta Perbaikan'
+};
+
+function loadReview() {
+    if (!currentAnalysis || !currentAnalysis.id) return;
+    const token = getCookie('token');
+    
+    getJSON(
+        `${API_BASE_URL}/api/results/${currentAnalysis.id}`,
+        (response) => {
+            if (response.status === 200) {
+                renderReview(response.data.data.analysis);
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+}
+
+function renderReview(analysis) {
+    const status = analysis.review_status || 'draft';
+    const badge = document.getElementById('review-status');
+    badge.textContent = getReviewStatusName(status);
+    badge.className = `status-badge ${status}`;
+    
+    // Tombol sesuai peran: pembimbing memutuskan, editor/pemilik mengajukan
+    const role = currentProject ? currentProject.role : '';
+    let actions = [];
+    if (role === 'reviewer' && status === 'submitted') {
+        actions = ['approve', 'request_changes'];
+    } else if ((role === 'owner' || role === 'editor') && (status === 'draft' || status === 'changes_requested')) {
+        actions = ['submit'];
+    } else if ((role === 'owner' || role === 'editor') && status === 'submitted') {
+        actions = ['withdraw'];
+    }
+    setInner('review-actions', actions.map(a =>
+        `<button class="btn btn-secondary" onclick="reviewAnalysis('${a}')">${reviewActionLabels[a]}</button>`
+    ).join(''));
+}
+
+window.reviewAnalysis = function(action) {
+    const token = getCookie('token');
+    postJSON(
+        `${API_BASE_URL}/api/review/${currentAnalysis.id}`,
+        { action, note: getValue('review-note') },
+        (response) => {
+            if (response.status === 200) {
+                setValue('review-note', '');
+                renderReview(response.data.data);
+                showToast('Status tinjauan diperbarui', 'success');
+            } else {
+                showToast(response.data.message || 'Gagal memperbarui tinjauan', 'error');
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+function loadComments() {
+    if (!currentProject || !currentAnalysis || !currentAnalysis.id) return;
+    const token = getCookie('token');
+    
+    getJSON(
+        `${API_BASE_URL}/api/project/${currentProject._id}/comments?analysis_id=${currentAnalysis.id}`,
+        (response) => {
+            if (response.status === 200) {
+                const comments = response.data.data || [];
+                setInner('comments-list', comments.length ? comments.map(renderComment).join('') : '<p class="empty-state">Belum ada komentar</p>');
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+}
+
+function renderComment(comment) {
+    const body = comment.deleted_at ? '<em>Komentar dihapus</em>' : escapeHtml(comment.body);
+    const method = comment.method ? ` &middot; ${escapeHtml(comment.method)}` : '';
+    const replies = (comment.replies || []).map(renderComment).join('');
+    return `
+        <div class="comment">
+            <div class="member-item">
+                <span><small>${escapeHtml(comment.author_name || '-')} &middot; ${formatDate(comment.created_at)}${method}</small><br>${body}</span>
+                <button class="btn btn-ghost" onclick="replyComment('${comment._id}')">Balas</button>
+            </div>
+            ${replies ? `<div class="comment-replies">${replies}</div>` : ''}
+        </div>
+    `;
+}
+
+window.replyComment = function(commentId) {
+    setValue('comment-parent', commentId);
+    document.getElementById('comment-body').focus();
+};
+
+window.handleCreateComment = function(event) {
+    event.preventDefault();
+    if (!currentProject || !currentAnalysis) return;
+    const token = getCookie('token');
+    
+    postJSON(
+        `${API_BASE_URL}/api/project/${currentProject._id}/comments`,
+        {
+            analysis_id: currentAnalysis.id,
+            parent_id: getValue('comment-parent'),
+            body: getValue('comment-body')
+        },
+        (response) => {
+            if (response.status === 201) {
+                setValue('comment-body', '');
+                setValue('comment-parent', '');
+                loadComments();
+            } else {
+                showToast(response.data.message || 'Gagal mengirim komentar', 'error');
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+// ===== Notifications =====
+function loadNotifications() {
+    const token = getCookie('token');
+    if (!token) return;
+    
+    getJSON(
+        `${API_BASE_URL}/api/notifications`,
+        (response) => {
+            if (response.status !== 200) return;
+            const data = response.data.data;
+            const notifications = data.notifications || [];
+            setInner('notification-count', data.unread_count ? `(${data.unread_count})` : '');
+            setInner('notifications-list', notifications.length ? notifications.map(n => `
+                <div class="member-item ${n.read_at ? '' : 'notification-unread'}">
+                    <a href="#project?id=${n.project_id}" onclick="markNotificationRead('${n._id}')">${escapeHtml(n.message)}</a>
+                    <small>${formatDate(n.created_at)}</small>
+                </div>
+            `).join('') : '<p class="empty-state">Tidak ada notifikasi</p>');
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+}
+
+window.markNotificationRead = function(notificationId) {
+    const token = getCookie('token');
+    postJSON(`${API_BASE_URL}/api/notifications/${notificationId}/read`, {}, () => {}, 'Authorization', `Bearer ${token}`);
+};
+
+window.markAllNotificationsRead = function() {
+    const token = getCookie('token');
+    postJSON(
+        `${API_BASE_URL}/api/notifications/read-all`,
+        {},
+        () => loadNotifications(),
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+// ===== Activity =====
+let activityPage = 1;
+
+window.loadActivity = function(page) {
+    const token = getCookie('token');
+    if (!token || page < 1) return;
+    
+    getJSON(
+        `${API_BASE_URL}/api/audit-logs?page=${page}&limit=20`,
+        (response) => {
+            const container = document.getElementById('activity-list');
+            if (response.status !== 200) {
+                container.innerHTML = '<p class="empty-state">Gagal memuat aktivitas</p>';
+                return;
+            }
+            const data = response.data.data;
+            const logs = data.logs || [];
+            activityPage = data.page;
+            container.innerHTML = logs.length ? logs.map(log => `
+                <div class="member-item">
+                    <span>${escapeHtml(log.action)}${log.details ? `<br><small>${escapeHtml(log.details)}</small>` : ''}</span>
+                    <small>${formatDate(log.created_at)}<br>${escapeHtml(log.ip || '')}</small>
+                </div>
+            `).join('') : '<p class="empty-state">Belum ada aktivitas</p>';
+            document.getElementById('activity-prev').disabled = data.page <= 1;
+            document.getElementById('activity-next').disabled = data.page * data.limit >= data.total;
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+// ===== API Keys =====
+function loadAPIKeys() {
+    const token = getCookie('token');
+    if (!token) return;
+    
+    getJSON(
+        `${API_BASE_URL}/api/keys`,
+        (response) => {
+            const container = document.getElementById('api-keys-list');
+            if (response.status !== 200) {
+                container.innerHTML = '<p class="empty-state">Gagal memuat API key</p>';
+                return;
+            }
+            const keys = response.data.data || [];
+            container.innerHTML = keys.length ? keys.map(key => {
+                const expired = new Date(key.expires_at) <= new Date();
+                const status = key.revoked_at ? 'Dicabut' : (expired ? 'Kedaluwarsa' : `Berlaku s.d. ${formatDate(key.expires_at)}`);
+                const lastUsed = key.last_used_at ? `Terakhir dipakai ${formatDate(key.last_used_at)}` : 'Belum pernah dipakai';
+                return `
+                    <div class="member-item">
+                        <span>${escapeHtml(key.name)} <code>${escapeHtml(key.prefix)}…</code><br><small>${escapeHtml((key.scopes || []).join(', '))} &middot; ${status} &middot; ${lastUsed}</small></span>
+                        ${key.revoked_at || expired ? '' : `<button class="btn btn-ghost" onclick="revokeAPIKey('${key._id}')">Cabut</button>`}
+                    </div>
+                `;
+            }).join('') : '<p class="empty-state">Belum ada API key</p>';
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+}
+
+window.handleCreateAPIKey = function(event) {
+    event.preventDefault();
+    const token = getCookie('token');
+    const scopes = Array.from(document.querySelectorAll('input[name="api-key-scope"]:checked')).map(el => el.value);
+    
+    postJSON(
+        `${API_BASE_URL}/api/keys`,
+        {
+            name: getValue('api-key-name'),
+            scopes: scopes,
+            expires_in_days: parseInt(getValue('api-key-days'), 10) || 0
+        },
+        (response) => {
+            if (response.status === 201) {
+                setValue('api-key-name', '');
+                setValue('api-key-value', response.data.data.key);
+                show('api-key-created');
+                loadAPIKeys();
+            } else {
+                showToast(response.data.message || 'Gagal membuat API key', 'error');
+            }
+        },
+        'Authorization',
+        `Bearer ${token}`
+    );
+};
+
+window.revokeAPIKey = async function(keyId) {
+    if (!confirm('Cabut API key ini? Aplikasi yang memakainya tidak bisa mengakses API lagi.')) return;
+    
+    const response = await fetch(`${API_BASE_URL}/api/keys/${keyId}`, {
+        method: 'DELETE',
+        headers: { 'Authorization': `Bearer ${getCookie('token')}` }
+    });
+    const result = await response.json();
+    if (response.ok) {
+        showToast('API key dicabut', 'success');
+        loadAPIKeys();
+    } else {
+        showToast(result.message || 'Gagal mencabut API key', 'error');
+    }
+};
+
 // ===== Export Functions =====
 window.exportResults = function(format) {
     if (!currentAnalysis || !currentAnalysis.id) {
@@ -811,6 +1599,26 @@ function formatFileSize(bytes) {
     return Math.round(bytes / Math.pow(1024, i) * 100) / 100 + ' ' + sizes[i];
 }
 
+function getRoleName(role) {
+    const roles = {
+        owner: 'Pemilik',
+        editor: 'Editor',
+        reviewer: 'Pembimbing',
+        viewer: 'Pembaca'
+    };
+    return roles[role] || role;
+}
+
+function getReviewStatusName(status) {
+    const statuses = {
+        draft: 'Draft',
+        submitted: 'Menunggu Tinjauan',
+        changes_requested: 'Perlu Perbaikan',
+        approved: 'Disetujui'
+    };
+    return statuses[status] || status;
+}
+
 function getResearchTypeName(type) {
     const types = {
         'quantitative': 'Kuantitatif',